@@ -0,0 +1,389 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	leastPrivilegeWizard = "least_privilege"
+	// Generated policies are prefixed so that they don't collide with the policies of the users
+	leastPrivilegePrefix = "kiali-least-privilege-"
+	denyAllPolicyName    = leastPrivilegePrefix + "default-deny"
+	// Attribute produced by the attributegen classification of requests, reported as request_operation
+	operationIDAttribute = "istio_operationId"
+)
+
+// Terms of the attributegen conditions that translate into AuthorizationPolicy operation fields
+var (
+	pathEqualsTerm     = regexp.MustCompile(`^request\.url_path\s*==\s*['"]([^'"]*)['"]$`)
+	pathStartsWithTerm = regexp.MustCompile(`^request\.url_path\.startsWith\(['"]([^'"]*)['"]\)$`)
+	pathEndsWithTerm   = regexp.MustCompile(`^request\.url_path\.endsWith\(['"]([^'"]*)['"]\)$`)
+	methodEqualsTerm   = regexp.MustCompile(`^request\.method\s*==\s*['"]([^'"]*)['"]$`)
+)
+
+// workloadPeers is the traffic received by a workload, by peer
+type workloadPeers struct {
+	// principals allowed any operation: their traffic isn't classified, or is TCP
+	principals map[string]bool
+	// principals by operation
+	operations map[string]map[string]bool
+	plainText  map[string]bool
+}
+
+// requestOperations are the operation fields matching the requests classified with a given operation name
+type requestOperations map[string][]interface{}
+
+// GenerateAuthorizationPolicies builds, for each workload of the namespace that received traffic over the rate
+// interval, an ALLOW AuthorizationPolicy permitting exactly the observed peer identities and, when requests are
+// classified, their operations. With denyAll, an allow-nothing policy moves the namespace to default-deny.
+func (in *AuthorizationService) GenerateAuthorizationPolicies(namespace, rateInterval string, queryTime time.Time, denyAll bool) (*models.GeneratedAuthorizationPolicies, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	rates, err := in.prom.GetNamespacePeerRates(namespace, rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	workloads, err := fetchWorkloads(in.businessLayer, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	workloadLabels := make(map[string]map[string]string, len(workloads))
+	for _, w := range workloads {
+		workloadLabels[w.Name] = w.Labels
+	}
+	filters, err := in.getIstioObjects(namespace, kubernetes.EnvoyFilters)
+	if err != nil {
+		return nil, err
+	}
+	if rootNamespace := config.Get().IstioNamespace; namespace != rootNamespace {
+		rootFilters, err := in.getIstioObjects(rootNamespace, kubernetes.EnvoyFilters)
+		if err != nil && !errors.IsForbidden(err) {
+			return nil, err
+		}
+		filters = append(filters, rootFilters...)
+	}
+	return buildAuthorizationPolicies(namespace, rateInterval, rates, workloadLabels, operationClassification(filters), denyAll)
+}
+
+// ApplyAuthorizationPolicies creates the generated policies, or updates them when they already exist. Existing
+// policies not generated by Kiali are never replaced. When a policy can't be applied, the policies already applied
+// are restored.
+func (in *AuthorizationService) ApplyAuthorizationPolicies(namespace string, generated *models.GeneratedAuthorizationPolicies) error {
+	for _, p := range generated.Policies {
+		existing, err := in.k8s.GetIstioObject(namespace, kubernetes.AuthorizationPolicies, p.Name)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.GetObjectMeta().Labels[wizardLabel] != leastPrivilegeWizard {
+			resource := schema.GroupResource{Group: kubernetes.SecurityGroupVersion.Group, Resource: kubernetes.AuthorizationPolicies}
+			return errors.NewConflict(resource, p.Name, fmt.Errorf("the existing policy was not generated by Kiali"))
+		}
+	}
+	// The allow-nothing policy goes last, once the workloads are allowed their traffic
+	undos := []func() error{}
+	for _, p := range generated.Policies {
+		undo, err := createOrReplaceIstioObject(in.businessLayer, in.k8s, namespace, kubernetes.AuthorizationPolicies, p)
+		if err != nil {
+			for i := len(undos) - 1; i >= 0; i-- {
+				if undoErr := undos[i](); undoErr != nil {
+					log.Errorf("Cannot roll back the AuthorizationPolicies applied in namespace [%s]: %v", namespace, undoErr)
+				}
+			}
+			return err
+		}
+		undos = append(undos, undo)
+	}
+	generated.Applied = true
+	return nil
+}
+
+func buildAuthorizationPolicies(namespace, rateInterval string, rates model.Vector, workloadLabels map[string]map[string]string, operations requestOperations, denyAll bool) (*models.GeneratedAuthorizationPolicies, error) {
+	conf := config.Get()
+	generated := &models.GeneratedAuthorizationPolicies{
+		Namespace:    namespace,
+		RateInterval: rateInterval,
+		Policies:     []*kubernetes.GenericIstioObject{},
+		Warnings:     []string{},
+	}
+
+	peers := make(map[string]*workloadPeers)
+	for _, s := range rates {
+		m := s.Metric
+		destWl := string(m["destination_workload"])
+		if destWl == "" || destWl == "unknown" {
+			continue
+		}
+		p, ok := peers[destWl]
+		if !ok {
+			p = &workloadPeers{principals: map[string]bool{}, operations: map[string]map[string]bool{}, plainText: map[string]bool{}}
+			peers[destWl] = p
+		}
+		principal := string(m["source_principal"])
+		if principal == "" || principal == "unknown" {
+			p.plainText[fmt.Sprintf("%s/%s", m["source_workload_namespace"], m["source_workload"])] = true
+			continue
+		}
+		principal = strings.TrimPrefix(principal, "spiffe://")
+		operation := string(m["request_operation"])
+		if operation == "" || operation == "unknown" {
+			p.principals[principal] = true
+			continue
+		}
+		if _, ok := p.operations[operation]; !ok {
+			p.operations[operation] = map[string]bool{}
+		}
+		p.operations[operation][principal] = true
+	}
+
+	workloads := make([]string, 0, len(peers))
+	for w := range peers {
+		workloads = append(workloads, w)
+	}
+	sort.Strings(workloads)
+
+	for _, w := range workloads {
+		p := peers[w]
+		labels, found := workloadLabels[w]
+		if !found {
+			generated.Warnings = append(generated.Warnings, fmt.Sprintf("Workload %s received traffic but no longer exists: no policy generated", w))
+			continue
+		}
+		if len(p.plainText) > 0 {
+			generated.Warnings = append(generated.Warnings, fmt.Sprintf("Workload %s received plain text traffic from %s: it can't be allowed by peer identity, enable mTLS for these sources", w, strings.Join(sortedKeys(p.plainText), ", ")))
+		}
+		if len(p.principals) == 0 && len(p.operations) == 0 {
+			continue
+		}
+		// The app and version labels select the workload, other labels may change between deployments
+		matchLabels := map[string]interface{}{}
+		for _, l := range []string{conf.IstioLabels.AppLabelName, conf.IstioLabels.VersionLabelName} {
+			if v, ok := labels[l]; ok {
+				matchLabels[l] = v
+			}
+		}
+		if _, ok := matchLabels[conf.IstioLabels.AppLabelName]; !ok {
+			generated.Warnings = append(generated.Warnings, fmt.Sprintf("Workload %s has no %s label to select it: no policy generated", w, conf.IstioLabels.AppLabelName))
+			continue
+		}
+
+		operationNames := make([]string, 0, len(p.operations))
+		for operation := range p.operations {
+			operationNames = append(operationNames, operation)
+		}
+		sort.Strings(operationNames)
+		for _, operation := range operationNames {
+			if _, ok := operations[operation]; !ok {
+				generated.Warnings = append(generated.Warnings, fmt.Sprintf("Workload %s received %s requests: the operation can't be translated into methods and paths, its sources are allowed any operation", w, operation))
+				for principal := range p.operations[operation] {
+					p.principals[principal] = true
+				}
+			}
+		}
+		rules := []interface{}{}
+		if len(p.principals) > 0 {
+			rules = append(rules, map[string]interface{}{
+				"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{"principals": principalList(p.principals, nil)}}},
+			})
+		}
+		for _, operation := range operationNames {
+			to, ok := operations[operation]
+			if !ok {
+				continue
+			}
+			principals := principalList(p.operations[operation], p.principals)
+			if len(principals) == 0 {
+				continue
+			}
+			rules = append(rules, map[string]interface{}{
+				"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{"principals": principals}}},
+				"to":   to,
+			})
+		}
+		policy := &kubernetes.GenericIstioObject{
+			TypeMeta: meta_v1.TypeMeta{Kind: kubernetes.AuthorizationPoliciesType, APIVersion: kubernetes.ApiSecurityVersion},
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      leastPrivilegePrefix + w,
+				Namespace: namespace,
+				Labels:    map[string]string{wizardLabel: leastPrivilegeWizard},
+			},
+			Spec: map[string]interface{}{
+				"selector": map[string]interface{}{"matchLabels": matchLabels},
+				"action":   "ALLOW",
+				"rules":    rules,
+			},
+		}
+		generated.Policies = append(generated.Policies, policy)
+	}
+
+	if denyAll {
+		generated.Policies = append(generated.Policies, &kubernetes.GenericIstioObject{
+			TypeMeta: meta_v1.TypeMeta{Kind: kubernetes.AuthorizationPoliciesType, APIVersion: kubernetes.ApiSecurityVersion},
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      denyAllPolicyName,
+				Namespace: namespace,
+				Labels:    map[string]string{wizardLabel: leastPrivilegeWizard},
+			},
+			Spec: map[string]interface{}{},
+		})
+	}
+
+	documents := make([]string, 0, len(generated.Policies))
+	for _, p := range generated.Policies {
+		document, err := toYAML(p)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	generated.YAML = strings.Join(documents, "---\n")
+	return generated, nil
+}
+
+// principalList returns the sorted principals of a set, except the excluded ones
+func principalList(principals, excluded map[string]bool) []interface{} {
+	list := []interface{}{}
+	for _, principal := range sortedKeys(principals) {
+		if !excluded[principal] {
+			list = append(list, principal)
+		}
+	}
+	return list
+}
+
+// operationClassification returns the operation fields matching each request_operation value, from the
+// attributegen classifications of the EnvoyFilters. Operations whose conditions can't be translated into methods
+// and paths, e.g. regular expressions, are left out.
+func operationClassification(filters []kubernetes.IstioObject) requestOperations {
+	operations := requestOperations{}
+	untranslatable := map[string]bool{}
+	for _, f := range filters {
+		for _, c := range attributegenConfigurations(f.GetSpec()) {
+			for _, attribute := range c.Attributes {
+				if attribute.OutputAttribute != operationIDAttribute {
+					continue
+				}
+				for _, m := range attribute.Match {
+					if operation, ok := conditionOperation(m.Condition); ok {
+						operations[m.Value] = append(operations[m.Value], map[string]interface{}{"operation": operation})
+					} else {
+						untranslatable[m.Value] = true
+					}
+				}
+			}
+		}
+	}
+	for operation := range untranslatable {
+		delete(operations, operation)
+	}
+	return operations
+}
+
+// attributegenConfiguration is the configuration of the attributegen filter
+type attributegenConfiguration struct {
+	Attributes []struct {
+		OutputAttribute string `json:"output_attribute"`
+		Match           []struct {
+			Value     string `json:"value"`
+			Condition string `json:"condition"`
+		} `json:"match"`
+	} `json:"attributes"`
+}
+
+// attributegenConfigurations looks for the attributegen configurations in the patches of an EnvoyFilter: they are
+// JSON strings holding the attributes to generate.
+func attributegenConfigurations(v interface{}) []attributegenConfiguration {
+	configurations := []attributegenConfiguration{}
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for _, item := range value {
+			configurations = append(configurations, attributegenConfigurations(item)...)
+		}
+	case []interface{}:
+		for _, item := range value {
+			configurations = append(configurations, attributegenConfigurations(item)...)
+		}
+	case string:
+		if strings.Contains(value, operationIDAttribute) {
+			var c attributegenConfiguration
+			if err := json.Unmarshal([]byte(value), &c); err == nil {
+				configurations = append(configurations, c)
+			}
+		}
+	}
+	return configurations
+}
+
+// conditionOperation translates an attributegen condition into AuthorizationPolicy operation fields. Only
+// conjunctions of exact, prefix and suffix matches of the path, and exact matches of the method, are supported.
+func conditionOperation(condition string) (map[string]interface{}, bool) {
+	operation := map[string]interface{}{}
+	for _, term := range strings.Split(condition, "&&") {
+		term = strings.TrimSpace(term)
+		field, value := "", ""
+		if m := pathEqualsTerm.FindStringSubmatch(term); m != nil {
+			field, value = "paths", m[1]
+		} else if m := pathStartsWithTerm.FindStringSubmatch(term); m != nil {
+			field, value = "paths", m[1]+"*"
+		} else if m := pathEndsWithTerm.FindStringSubmatch(term); m != nil {
+			field, value = "paths", "*"+m[1]
+		} else if m := methodEqualsTerm.FindStringSubmatch(term); m != nil {
+			field, value = "methods", m[1]
+		}
+		// A field can hold a single pattern: two restrictions of the same field can't be expressed
+		if _, ok := operation[field]; field == "" || ok {
+			return nil, false
+		}
+		operation[field] = []interface{}{value}
+	}
+	return operation, len(operation) > 0
+}
+
+// toYAML marshals an Istio object in the form users would write it, without the fields set by the cluster
+func toYAML(o *kubernetes.GenericIstioObject) (string, error) {
+	metadata := yaml.MapSlice{{Key: "name", Value: o.Name}, {Key: "namespace", Value: o.Namespace}}
+	if len(o.Labels) > 0 {
+		metadata = append(metadata, yaml.MapItem{Key: "labels", Value: o.Labels})
+	}
+	if len(o.Annotations) > 0 {
+		metadata = append(metadata, yaml.MapItem{Key: "annotations", Value: o.Annotations})
+	}
+	out, err := yaml.Marshal(yaml.MapSlice{
+		{Key: "apiVersion", Value: o.APIVersion},
+		{Key: "kind", Value: o.Kind},
+		{Key: "metadata", Value: metadata},
+		{Key: "spec", Value: o.Spec},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
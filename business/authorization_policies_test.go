@@ -0,0 +1,115 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+)
+
+func TestBuildAuthorizationPolicies(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	rates := model.Vector{
+		&model.Sample{Metric: model.Metric{
+			"source_principal":          "spiffe://cluster.local/ns/bookinfo/sa/bookinfo-productpage",
+			"source_workload_namespace": "bookinfo",
+			"source_workload":           "productpage-v1",
+			"destination_workload":      "reviews-v1",
+			"request_operation":         "GetReviews",
+		}, Value: 1},
+		&model.Sample{Metric: model.Metric{
+			"source_principal":          "spiffe://cluster.local/ns/istio-system/sa/istio-ingressgateway-service-account",
+			"source_workload_namespace": "istio-system",
+			"source_workload":           "istio-ingressgateway",
+			"destination_workload":      "reviews-v1",
+			"request_operation":         "unknown",
+		}, Value: 1},
+		&model.Sample{Metric: model.Metric{
+			"source_principal":          "unknown",
+			"source_workload_namespace": "legacy",
+			"source_workload":           "cron",
+			"destination_workload":      "ratings-v1",
+		}, Value: 1},
+		&model.Sample{Metric: model.Metric{
+			"source_principal":          "spiffe://cluster.local/ns/bookinfo/sa/bookinfo-reviews",
+			"source_workload_namespace": "bookinfo",
+			"source_workload":           "reviews-v1",
+			"destination_workload":      "details-v1",
+		}, Value: 1},
+	}
+	labels := map[string]map[string]string{
+		"reviews-v1": {"app": "reviews", "version": "v1", "pod-template-hash": "5f8b7c"},
+		"ratings-v1": {"app": "ratings", "version": "v1"},
+	}
+
+	operations := requestOperations{
+		"GetReviews": []interface{}{map[string]interface{}{"operation": map[string]interface{}{"methods": []interface{}{"GET"}, "paths": []interface{}{"/reviews/*"}}}},
+	}
+
+	generated, err := buildAuthorizationPolicies("bookinfo", "1h", rates, labels, operations, true)
+	assert.NoError(err)
+	assert.Len(generated.Policies, 2)
+	assert.Equal([]string{
+		"Workload details-v1 received traffic but no longer exists: no policy generated",
+		"Workload ratings-v1 received plain text traffic from legacy/cron: it can't be allowed by peer identity, enable mTLS for these sources",
+	}, generated.Warnings)
+
+	reviews := generated.Policies[0]
+	assert.Equal("kiali-least-privilege-reviews-v1", reviews.Name)
+	assert.JSONEq(`{
+		"selector": {"matchLabels": {"app": "reviews", "version": "v1"}},
+		"action": "ALLOW",
+		"rules": [
+			{"from": [{"source": {"principals": ["cluster.local/ns/istio-system/sa/istio-ingressgateway-service-account"]}}]},
+			{
+				"from": [{"source": {"principals": ["cluster.local/ns/bookinfo/sa/bookinfo-productpage"]}}],
+				"to": [{"operation": {"methods": ["GET"], "paths": ["/reviews/*"]}}]
+			}
+		]
+	}`, toJSON(t, reviews.Spec))
+	assert.Equal(denyAllPolicyName, generated.Policies[1].Name)
+	assert.Empty(generated.Policies[1].Spec)
+
+	assert.Contains(generated.YAML, "apiVersion: security.istio.io/v1beta1\nkind: AuthorizationPolicy\nmetadata:\n  name: kiali-least-privilege-reviews-v1\n")
+	assert.Contains(generated.YAML, "---\napiVersion: security.istio.io/v1beta1\nkind: AuthorizationPolicy\nmetadata:\n  name: kiali-least-privilege-default-deny\n")
+}
+
+func TestOperationClassification(t *testing.T) {
+	assert := assert.New(t)
+
+	filter := &kubernetes.GenericIstioObject{
+		Spec: map[string]interface{}{
+			"configPatches": []interface{}{map[string]interface{}{
+				"patch": map[string]interface{}{
+					"value": map[string]interface{}{
+						"name": "istio.attributegen",
+						"typed_config": map[string]interface{}{
+							"value": map[string]interface{}{
+								"config": map[string]interface{}{
+									"configuration": map[string]interface{}{
+										"@type": "type.googleapis.com/google.protobuf.StringValue",
+										"value": `{"attributes": [{"output_attribute": "istio_operationId", "match": [
+											{"value": "ListReviews", "condition": "request.url_path == '/reviews' && request.method == 'GET'"},
+											{"value": "GetReview", "condition": "request.url_path.matches('^/reviews/[[:alnum:]]*$') && request.method == 'GET'"},
+											{"value": "CreateReview", "condition": "request.url_path.startsWith('/reviews/') && request.method == 'POST'"}
+										]}]}`,
+									},
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+
+	operations := operationClassification([]kubernetes.IstioObject{filter})
+	assert.Len(operations, 2)
+	assert.JSONEq(`[{"operation": {"methods": ["GET"], "paths": ["/reviews"]}}]`, toJSON(t, operations["ListReviews"]))
+	assert.JSONEq(`[{"operation": {"methods": ["POST"], "paths": ["/reviews/*"]}}]`, toJSON(t, operations["CreateReview"]))
+}
@@ -0,0 +1,625 @@
+package business
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// PeerAuthentication modes
+const (
+	MtlsModeStrict     = "STRICT"
+	MtlsModePermissive = "PERMISSIVE"
+	MtlsModeDisable    = "DISABLE"
+	mtlsModeUnset      = "UNSET"
+)
+
+var headerConditionKey = regexp.MustCompile(`^request\.headers\[(.+)\]$`)
+
+// AuthorizationService evaluates AuthorizationPolicies and PeerAuthentications for simulated requests, and generates
+// AuthorizationPolicies from observed traffic
+type AuthorizationService struct {
+	k8s           kubernetes.ClientInterface
+	prom          prometheus.ClientInterface
+	businessLayer *Layer
+}
+
+// authzSource is the resolved identity of the caller of a simulated request
+type authzSource struct {
+	namespace        string
+	serviceAccount   string
+	hasSidecar       bool
+	requestPrincipal string
+	ip               string
+	// observedMtls is true when the connection is known to be mutual TLS, regardless of the mTLS settings
+	observedMtls bool
+}
+
+// authzInput holds everything needed to simulate a request
+type authzInput struct {
+	source         authzSource
+	destNamespace  string
+	destLabels     map[string]string
+	request        models.AuthorizationRequestAttributes
+	policies       []kubernetes.IstioObject
+	peerAuthns     []kubernetes.IstioObject
+	meshPeerAuthns []kubernetes.IstioObject
+	autoMtls       bool
+	rootNamespace  string
+	trustDomain    string
+	// The mesh-wide policies of the root namespace are skipped when the user can't read them
+	rootPoliciesForbidden   bool
+	rootPeerAuthnsForbidden bool
+}
+
+// SimulateRequest tells whether the source can call the destination workload with the given request, according to
+// the AuthorizationPolicies and the PeerAuthentications applying to the destination.
+// Mutual TLS is assumed between workloads with sidecars, unless auto mTLS is disabled.
+func (in *AuthorizationService) SimulateRequest(req models.AuthorizationSimulationRequest) (models.AuthorizationSimulation, error) {
+	if req.Destination.Namespace == "" || req.Destination.Workload == "" {
+		return models.AuthorizationSimulation{}, errors.NewBadRequest("destination namespace and workload are required")
+	}
+	if _, err := in.businessLayer.Namespace.GetNamespace(req.Destination.Namespace); err != nil {
+		return models.AuthorizationSimulation{}, err
+	}
+	destination, err := fetchWorkload(in.businessLayer, req.Destination.Namespace, req.Destination.Workload, "")
+	if err != nil {
+		return models.AuthorizationSimulation{}, err
+	}
+
+	source := authzSource{
+		namespace:        req.Source.Namespace,
+		serviceAccount:   req.Source.ServiceAccount,
+		requestPrincipal: req.Source.RequestPrincipal,
+		ip:               req.Source.IP,
+		// A source identified by its namespace is assumed to be in the mesh
+		hasSidecar: req.Source.Namespace != "",
+	}
+	if req.Source.Workload != "" {
+		if req.Source.Namespace == "" {
+			return models.AuthorizationSimulation{}, errors.NewBadRequest("source namespace is required with a source workload")
+		}
+		if _, err := in.businessLayer.Namespace.GetNamespace(req.Source.Namespace); err != nil {
+			return models.AuthorizationSimulation{}, err
+		}
+		sourceWorkload, err := fetchWorkload(in.businessLayer, req.Source.Namespace, req.Source.Workload, "")
+		if err != nil {
+			return models.AuthorizationSimulation{}, err
+		}
+		source.hasSidecar = sourceWorkload.IstioSidecar
+		if source.serviceAccount == "" {
+			for _, p := range sourceWorkload.Pods {
+				if p.ServiceAccountName != "" {
+					source.serviceAccount = p.ServiceAccountName
+					break
+				}
+			}
+		}
+	}
+	if source.namespace != "" && source.serviceAccount == "" {
+		source.serviceAccount = "default"
+	}
+
+	authzConfig, err := in.GetAuthorizationConfig(req.Destination.Namespace)
+	if err != nil {
+		return models.AuthorizationSimulation{}, err
+	}
+	input := authzConfig.base
+	input.source = source
+	input.destLabels = destination.Labels
+	input.request = req.Request
+	return simulateAuthorization(&input), nil
+}
+
+// AuthorizationConfig holds the AuthorizationPolicies and PeerAuthentications applying to the workloads of a namespace
+type AuthorizationConfig struct {
+	base authzInput
+}
+
+// GetAuthorizationConfig fetches the security configuration applying to the workloads of a namespace,
+// including the mesh-wide configuration of the root namespace
+func (in *AuthorizationService) GetAuthorizationConfig(namespace string) (*AuthorizationConfig, error) {
+	conf := config.Get()
+	input := authzInput{
+		destNamespace: namespace,
+		autoMtls:      in.businessLayer.TLS.hasAutoMTLSEnabled(),
+		rootNamespace: conf.IstioNamespace,
+		trustDomain:   strings.TrimPrefix(conf.ExternalServices.Istio.IstioIdentityDomain, "svc."),
+	}
+	var err error
+	if input.policies, err = in.getIstioObjects(namespace, kubernetes.AuthorizationPolicies); err != nil {
+		return nil, err
+	}
+	if input.peerAuthns, err = in.getIstioObjects(namespace, kubernetes.PeerAuthentications); err != nil {
+		return nil, err
+	}
+	if namespace != conf.IstioNamespace {
+		rootPolicies, err := in.getIstioObjects(conf.IstioNamespace, kubernetes.AuthorizationPolicies)
+		if err != nil && !errors.IsForbidden(err) {
+			return nil, err
+		}
+		input.rootPoliciesForbidden = err != nil
+		input.policies = append(rootPolicies, input.policies...)
+		if input.meshPeerAuthns, err = in.getIstioObjects(conf.IstioNamespace, kubernetes.PeerAuthentications); err != nil && !errors.IsForbidden(err) {
+			return nil, err
+		}
+		input.rootPeerAuthnsForbidden = err != nil
+	} else {
+		input.meshPeerAuthns = input.peerAuthns
+	}
+	return &AuthorizationConfig{base: input}, nil
+}
+
+// SimulatePeer evaluates a connection from a peer to a workload with the given labels. The peer is identified by
+// its observed principal, e.g. "spiffe://cluster.local/ns/bookinfo/sa/default", empty for plain text connections.
+// Request attributes are unknown: rules restricting them don't match.
+func (c *AuthorizationConfig) SimulatePeer(principal string, destLabels map[string]string) models.AuthorizationSimulation {
+	input := c.base
+	input.destLabels = destLabels
+	input.source = authzSource{}
+	if trustDomain, namespace, serviceAccount, ok := parsePrincipal(principal); ok {
+		input.trustDomain = trustDomain
+		input.source = authzSource{namespace: namespace, serviceAccount: serviceAccount, hasSidecar: true, observedMtls: true}
+	}
+	return simulateAuthorization(&input)
+}
+
+// parsePrincipal splits a SPIFFE identity "spiffe://<trust domain>/ns/<namespace>/sa/<service account>"
+func parsePrincipal(principal string) (trustDomain, namespace, serviceAccount string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(principal, "spiffe://"), "/")
+	if len(parts) != 5 || parts[1] != "ns" || parts[3] != "sa" {
+		return "", "", "", false
+	}
+	return parts[0], parts[2], parts[4], true
+}
+
+func (in *AuthorizationService) getIstioObjects(namespace, resourceType string) ([]kubernetes.IstioObject, error) {
+	if IsResourceCached(in.k8s, namespace, resourceType) {
+		return kialiCache.GetIstioObjects(namespace, resourceType, "")
+	}
+	return in.k8s.GetIstioObjects(namespace, resourceType, "")
+}
+
+func simulateAuthorization(input *authzInput) models.AuthorizationSimulation {
+	result := models.AuthorizationSimulation{
+		Policies: []models.AuthorizationPolicyEvaluation{},
+		Warnings: []string{},
+	}
+	if input.rootPeerAuthnsForbidden {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("The PeerAuthentications of the root namespace %s cannot be read: the mesh-wide mTLS mode is ignored", input.rootNamespace))
+	}
+	if input.rootPoliciesForbidden {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("The AuthorizationPolicies of the root namespace %s cannot be read: the mesh-wide policies are ignored", input.rootNamespace))
+	}
+	result.MtlsMode = effectiveMtlsMode(input)
+	result.Mtls = input.source.observedMtls || (input.source.hasSidecar && result.MtlsMode != MtlsModeDisable && (input.autoMtls || result.MtlsMode == MtlsModeStrict))
+	if result.Mtls {
+		result.SourcePrincipal = fmt.Sprintf("%s/ns/%s/sa/%s", input.trustDomain, input.source.namespace, input.source.serviceAccount)
+	}
+	if result.MtlsMode == MtlsModeStrict && !result.Mtls {
+		result.Decision = models.AuthorizationDeny
+		result.Reason = "The destination requires mutual TLS (PeerAuthentication STRICT mode) and the source sends plain text"
+		return result
+	}
+
+	var denies, allows, customs, indeterminates []int
+	hasAllowPolicies := false
+	for _, ap := range input.policies {
+		if !policyAppliesTo(ap, input) {
+			continue
+		}
+		evaluation := evaluatePolicy(ap, input, result.SourcePrincipal)
+		result.Policies = append(result.Policies, evaluation)
+		i := len(result.Policies) - 1
+		if evaluation.Indeterminate {
+			indeterminates = append(indeterminates, i)
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s policy %s/%s has conditions that cannot be simulated", evaluation.Action, evaluation.Namespace, evaluation.Name))
+		}
+		switch evaluation.Action {
+		case "DENY":
+			if evaluation.Matched {
+				denies = append(denies, i)
+			}
+		case "CUSTOM":
+			if evaluation.Matched {
+				customs = append(customs, i)
+			}
+		case "ALLOW":
+			hasAllowPolicies = true
+			if evaluation.Matched {
+				allows = append(allows, i)
+			}
+		}
+	}
+
+	markDeciding := func(indexes []int) {
+		for _, i := range indexes {
+			result.Policies[i].Deciding = true
+		}
+	}
+	// Policies that may match make the decision indeterminate, unless a policy that matches for sure takes precedence
+	indeterminate := func(actions ...string) bool {
+		for _, i := range indeterminates {
+			for _, action := range actions {
+				if result.Policies[i].Action == action {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	switch {
+	case len(denies) > 0:
+		markDeciding(denies)
+		result.Decision = models.AuthorizationDeny
+		result.Reason = "The request matches a DENY policy"
+	case indeterminate("DENY"):
+		result.Decision = models.AuthorizationIndeterminate
+		result.Reason = "The request may match a DENY policy with conditions that cannot be simulated"
+	case hasAllowPolicies && len(allows) == 0 && indeterminate("ALLOW"):
+		result.Decision = models.AuthorizationIndeterminate
+		result.Reason = "The request matches no ALLOW policy for sure, and may match one with conditions that cannot be simulated"
+	case hasAllowPolicies && len(allows) == 0:
+		for i, p := range result.Policies {
+			if p.Action == "ALLOW" {
+				result.Policies[i].Deciding = true
+			}
+		}
+		result.Decision = models.AuthorizationDeny
+		result.Reason = "ALLOW policies apply to the destination, and the request matches none of them"
+	case len(customs) > 0:
+		markDeciding(customs)
+		result.Decision = models.AuthorizationCustom
+		result.Reason = "The request matches a CUSTOM policy: the decision is delegated to the external authorizer"
+	case indeterminate("CUSTOM"):
+		result.Decision = models.AuthorizationIndeterminate
+		result.Reason = "The request may match a CUSTOM policy with conditions that cannot be simulated"
+	case len(allows) > 0:
+		markDeciding(allows)
+		result.Decision = models.AuthorizationAllow
+		result.Reason = "The request matches an ALLOW policy"
+	default:
+		result.Decision = models.AuthorizationAllow
+		result.Reason = "No ALLOW policy applies to the destination"
+	}
+	// A mesh-wide policy could deny what the readable policies allow
+	if result.Decision == models.AuthorizationAllow && input.rootPoliciesForbidden {
+		result.Decision = models.AuthorizationIndeterminate
+		result.Reason = "The readable policies allow the request, but the mesh-wide policies of the root namespace cannot be read"
+	}
+	return result
+}
+
+// effectiveMtlsMode resolves the PeerAuthentication mode of the destination: workload policy, then namespace policy,
+// then mesh policy. Unset modes are inherited, and default to PERMISSIVE.
+func effectiveMtlsMode(input *authzInput) string {
+	var workloadPA, namespacePA, meshPA kubernetes.IstioObject
+	for _, pa := range input.peerAuthns {
+		if common.HasSelector(pa) {
+			if workloadPA == nil && selectorMatches(pa, input.destLabels) {
+				workloadPA = pa
+			}
+		} else if namespacePA == nil {
+			namespacePA = pa
+		}
+	}
+	for _, pa := range input.meshPeerAuthns {
+		if !common.HasSelector(pa) {
+			meshPA = pa
+			break
+		}
+	}
+
+	if workloadPA != nil && input.request.Port != 0 {
+		if portLevel, ok := workloadPA.GetSpec()["portLevelMtls"].(map[string]interface{}); ok {
+			if mode := peerAuthnMode(portLevel[strconv.Itoa(input.request.Port)]); mode != mtlsModeUnset {
+				return mode
+			}
+		}
+	}
+	for _, pa := range []kubernetes.IstioObject{workloadPA, namespacePA, meshPA} {
+		if pa == nil {
+			continue
+		}
+		if mode := peerAuthnMode(pa.GetSpec()["mtls"]); mode != mtlsModeUnset {
+			return mode
+		}
+	}
+	return MtlsModePermissive
+}
+
+func peerAuthnMode(mtls interface{}) string {
+	if m, ok := mtls.(map[string]interface{}); ok {
+		if mode, ok := m["mode"].(string); ok && mode != "" {
+			return mode
+		}
+	}
+	return mtlsModeUnset
+}
+
+func selectorMatches(o kubernetes.IstioObject, workloadLabels map[string]string) bool {
+	return labels.SelectorFromSet(common.GetSelectorLabels(o)).Matches(labels.Set(workloadLabels))
+}
+
+// policyAppliesTo tells whether an AuthorizationPolicy applies to the destination workload: policies of the
+// root namespace apply mesh-wide, policies without selector apply to their whole namespace.
+func policyAppliesTo(ap kubernetes.IstioObject, input *authzInput) bool {
+	ns := ap.GetObjectMeta().Namespace
+	if ns != input.destNamespace && ns != input.rootNamespace {
+		return false
+	}
+	return !common.HasSelector(ap) || selectorMatches(ap, input.destLabels)
+}
+
+func evaluatePolicy(ap kubernetes.IstioObject, input *authzInput, principal string) models.AuthorizationPolicyEvaluation {
+	spec := ap.GetSpec()
+	evaluation := models.AuthorizationPolicyEvaluation{
+		Name:      ap.GetObjectMeta().Name,
+		Namespace: ap.GetObjectMeta().Namespace,
+		Action:    "ALLOW",
+		Details:   []string{},
+	}
+	if action, ok := spec["action"].(string); ok && action != "" {
+		evaluation.Action = action
+	}
+	rules, _ := spec["rules"].([]interface{})
+	if len(rules) == 0 {
+		// An ALLOW policy without rules denies everything, other policies without rules never match
+		evaluation.Details = append(evaluation.Details, "The policy has no rules, it matches no request")
+		return evaluation
+	}
+	attrs := requestAttributes(input, principal)
+	indeterminate := false
+	for i, r := range rules {
+		rule, _ := r.(map[string]interface{})
+		reason, unsupported := ruleMismatch(rule, attrs)
+		if reason != "" {
+			evaluation.Details = append(evaluation.Details, fmt.Sprintf("Rule %d: %s", i, reason))
+			continue
+		}
+		if len(unsupported) > 0 {
+			evaluation.Details = append(evaluation.Details, fmt.Sprintf("Rule %d: conditions %v cannot be simulated, the rule may match", i, unsupported))
+			indeterminate = true
+			continue
+		}
+		index := i
+		evaluation.Matched = true
+		evaluation.MatchedRule = &index
+		break
+	}
+	evaluation.Indeterminate = indeterminate && !evaluation.Matched
+	return evaluation
+}
+
+// authzAttributes are the attributes of a request, as seen by the authorization filter
+type authzAttributes struct {
+	principal        string
+	namespace        string
+	requestPrincipal string
+	ip               string
+	request          models.AuthorizationRequestAttributes
+}
+
+func requestAttributes(input *authzInput, principal string) authzAttributes {
+	attrs := authzAttributes{
+		principal:        principal,
+		requestPrincipal: input.source.requestPrincipal,
+		ip:               input.source.ip,
+		request:          input.request,
+	}
+	// The source namespace is extracted from the peer certificate
+	if principal != "" {
+		attrs.namespace = input.source.namespace
+	}
+	return attrs
+}
+
+// ruleMismatch returns why a rule doesn't match the request, or an empty string when it matches.
+// A rule matches when any of its sources, any of its operations and all of its conditions match. The keys of the
+// conditions that can't be simulated are returned when nothing else rules the request out: the rule may match.
+func ruleMismatch(rule map[string]interface{}, attrs authzAttributes) (string, []string) {
+	if froms, ok := rule["from"].([]interface{}); ok && len(froms) > 0 {
+		reasons := []string{}
+		for _, f := range froms {
+			from, _ := f.(map[string]interface{})
+			source, _ := from["source"].(map[string]interface{})
+			reason := sourceMismatch(source, attrs)
+			if reason == "" {
+				reasons = nil
+				break
+			}
+			reasons = append(reasons, reason)
+		}
+		if reasons != nil {
+			return strings.Join(reasons, "; "), nil
+		}
+	}
+	if tos, ok := rule["to"].([]interface{}); ok && len(tos) > 0 {
+		reasons := []string{}
+		for _, t := range tos {
+			to, _ := t.(map[string]interface{})
+			operation, _ := to["operation"].(map[string]interface{})
+			reason := operationMismatch(operation, attrs.request)
+			if reason == "" {
+				reasons = nil
+				break
+			}
+			reasons = append(reasons, reason)
+		}
+		if reasons != nil {
+			return strings.Join(reasons, "; "), nil
+		}
+	}
+	var unsupported []string
+	if whens, ok := rule["when"].([]interface{}); ok {
+		for _, w := range whens {
+			condition, _ := w.(map[string]interface{})
+			reason, supported := conditionMismatch(condition, attrs)
+			if !supported {
+				key, _ := condition["key"].(string)
+				unsupported = append(unsupported, key)
+				continue
+			}
+			if reason != "" {
+				return reason, nil
+			}
+		}
+	}
+	return "", unsupported
+}
+
+func sourceMismatch(source map[string]interface{}, attrs authzAttributes) string {
+	checks := []struct {
+		field   string
+		value   string
+		matches func(pattern, value string) bool
+	}{
+		{"principals", attrs.principal, matchesWildcard},
+		{"namespaces", attrs.namespace, matchesWildcard},
+		{"requestPrincipals", attrs.requestPrincipal, matchesWildcard},
+		{"ipBlocks", attrs.ip, matchesIPBlock},
+		{"remoteIpBlocks", attrs.ip, matchesIPBlock},
+	}
+	for _, c := range checks {
+		if reason := fieldMismatch(source, c.field, c.value, c.matches); reason != "" {
+			return "source " + reason
+		}
+	}
+	return ""
+}
+
+func operationMismatch(operation map[string]interface{}, request models.AuthorizationRequestAttributes) string {
+	port := ""
+	if request.Port != 0 {
+		port = strconv.Itoa(request.Port)
+	}
+	checks := []struct {
+		field   string
+		value   string
+		matches func(pattern, value string) bool
+	}{
+		{"hosts", strings.ToLower(request.Host), func(p, v string) bool { return matchesWildcard(strings.ToLower(p), v) }},
+		{"ports", port, func(p, v string) bool { return p == v }},
+		{"methods", request.Method, matchesWildcard},
+		{"paths", request.Path, matchesWildcard},
+	}
+	for _, c := range checks {
+		if reason := fieldMismatch(operation, c.field, c.value, c.matches); reason != "" {
+			return "operation " + reason
+		}
+	}
+	return ""
+}
+
+// fieldMismatch checks a field and its "not" counterpart, e.g. principals and notPrincipals
+func fieldMismatch(spec map[string]interface{}, field, value string, matches func(pattern, value string) bool) string {
+	notField := "not" + strings.ToUpper(field[:1]) + field[1:]
+	if patterns := stringList(spec[field]); patterns != nil && !matchesAny(patterns, value, matches) {
+		return fmt.Sprintf("%s %q not in %v", field, value, patterns)
+	}
+	if patterns := stringList(spec[notField]); patterns != nil && matchesAny(patterns, value, matches) {
+		return fmt.Sprintf("%s %q in %s %v", field, value, notField, patterns)
+	}
+	return ""
+}
+
+// conditionMismatch returns why a condition doesn't match the request, or an empty string when it matches.
+// It returns false when the key of the condition can't be simulated.
+func conditionMismatch(condition map[string]interface{}, attrs authzAttributes) (string, bool) {
+	key, _ := condition["key"].(string)
+	var value string
+	var matches func(pattern, value string) bool = matchesWildcard
+	switch key {
+	case "source.principal":
+		value = attrs.principal
+	case "source.namespace":
+		value = attrs.namespace
+	case "request.auth.principal":
+		value = attrs.requestPrincipal
+	case "source.ip", "remote.ip":
+		value, matches = attrs.ip, matchesIPBlock
+	case "destination.port":
+		if attrs.request.Port != 0 {
+			value = strconv.Itoa(attrs.request.Port)
+		}
+	default:
+		if m := headerConditionKey.FindStringSubmatch(key); m != nil {
+			for h, v := range attrs.request.Headers {
+				if strings.EqualFold(h, m[1]) {
+					value = v
+				}
+			}
+		} else {
+			return "", false
+		}
+	}
+	if values := stringList(condition["values"]); values != nil && !matchesAny(values, value, matches) {
+		return fmt.Sprintf("condition %s %q not in %v", key, value, values), true
+	}
+	if notValues := stringList(condition["notValues"]); notValues != nil && matchesAny(notValues, value, matches) {
+		return fmt.Sprintf("condition %s %q in notValues %v", key, value, notValues), true
+	}
+	return "", true
+}
+
+func stringList(v interface{}) []string {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func matchesAny(patterns []string, value string, matches func(pattern, value string) bool) bool {
+	for _, p := range patterns {
+		if matches(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWildcard implements Istio string matching: exact, prefix ("abc*"), suffix ("*abc") or presence ("*")
+func matchesWildcard(pattern, value string) bool {
+	if value == "" {
+		return false
+	}
+	switch {
+	case pattern == "*":
+		return true
+	case strings.HasSuffix(pattern, "*"):
+		return strings.HasPrefix(value, strings.TrimSuffix(pattern, "*"))
+	case strings.HasPrefix(pattern, "*"):
+		return strings.HasSuffix(value, strings.TrimPrefix(pattern, "*"))
+	}
+	return pattern == value
+}
+
+func matchesIPBlock(block, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	if !strings.Contains(block, "/") {
+		return parsed.Equal(net.ParseIP(block))
+	}
+	_, cidr, err := net.ParseCIDR(block)
+	return err == nil && cidr.Contains(parsed)
+}
@@ -0,0 +1,260 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+func fakeAuthzObject(kind, namespace, name string, spec map[string]interface{}) kubernetes.IstioObject {
+	return &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kind},
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       spec,
+	}
+}
+
+func fakeAuthzInput() *authzInput {
+	return &authzInput{
+		source:        authzSource{namespace: "bookinfo", serviceAccount: "bookinfo-productpage", hasSidecar: true},
+		destNamespace: "bookinfo",
+		destLabels:    map[string]string{"app": "reviews", "version": "v1"},
+		request:       models.AuthorizationRequestAttributes{Method: "GET", Path: "/reviews/1", Port: 9080},
+		autoMtls:      true,
+		rootNamespace: "istio-system",
+		trustDomain:   "cluster.local",
+	}
+}
+
+func TestSimulateAuthorizationWithoutPolicies(t *testing.T) {
+	assert := assert.New(t)
+
+	result := simulateAuthorization(fakeAuthzInput())
+	assert.Equal(models.AuthorizationAllow, result.Decision)
+	assert.Equal(MtlsModePermissive, result.MtlsMode)
+	assert.True(result.Mtls)
+	assert.Equal("cluster.local/ns/bookinfo/sa/bookinfo-productpage", result.SourcePrincipal)
+	assert.Empty(result.Policies)
+}
+
+func TestSimulateAuthorizationAllowPolicies(t *testing.T) {
+	assert := assert.New(t)
+
+	input := fakeAuthzInput()
+	input.policies = []kubernetes.IstioObject{
+		fakeAuthzObject(kubernetes.AuthorizationPoliciesType, "bookinfo", "allow-productpage", map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "reviews"}},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{
+						"principals": []interface{}{"cluster.local/ns/bookinfo/sa/bookinfo-productpage"},
+					}}},
+					"to": []interface{}{map[string]interface{}{"operation": map[string]interface{}{
+						"methods": []interface{}{"GET"},
+						"paths":   []interface{}{"/reviews/*"},
+					}}},
+				},
+			},
+		}),
+		// Doesn't apply to the destination
+		fakeAuthzObject(kubernetes.AuthorizationPoliciesType, "bookinfo", "allow-ratings", map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "ratings"}},
+		}),
+	}
+
+	result := simulateAuthorization(input)
+	assert.Equal(models.AuthorizationAllow, result.Decision)
+	assert.Len(result.Policies, 1)
+	assert.True(result.Policies[0].Matched)
+	assert.True(result.Policies[0].Deciding)
+	assert.Equal(0, *result.Policies[0].MatchedRule)
+
+	input.request.Method = "POST"
+	result = simulateAuthorization(input)
+	assert.Equal(models.AuthorizationDeny, result.Decision)
+	assert.False(result.Policies[0].Matched)
+	assert.True(result.Policies[0].Deciding)
+	assert.Len(result.Policies[0].Details, 1)
+}
+
+func TestSimulateAuthorizationDenyPolicyWins(t *testing.T) {
+	assert := assert.New(t)
+
+	input := fakeAuthzInput()
+	input.request.Headers = map[string]string{"X-Canary": "true"}
+	input.policies = []kubernetes.IstioObject{
+		fakeAuthzObject(kubernetes.AuthorizationPoliciesType, "istio-system", "deny-canary", map[string]interface{}{
+			"action": "DENY",
+			"rules": []interface{}{
+				map[string]interface{}{
+					"when": []interface{}{map[string]interface{}{"key": "request.headers[x-canary]", "values": []interface{}{"true"}}},
+				},
+			},
+		}),
+		fakeAuthzObject(kubernetes.AuthorizationPoliciesType, "bookinfo", "allow-all", map[string]interface{}{
+			"rules": []interface{}{map[string]interface{}{}},
+		}),
+		// Other namespaces don't apply
+		fakeAuthzObject(kubernetes.AuthorizationPoliciesType, "default", "deny-all", map[string]interface{}{
+			"action": "DENY",
+			"rules":  []interface{}{map[string]interface{}{}},
+		}),
+	}
+
+	result := simulateAuthorization(input)
+	assert.Equal(models.AuthorizationDeny, result.Decision)
+	assert.Len(result.Policies, 2)
+	assert.True(result.Policies[0].Deciding)
+	assert.False(result.Policies[1].Deciding)
+
+	input.request.Headers = nil
+	result = simulateAuthorization(input)
+	assert.Equal(models.AuthorizationAllow, result.Decision)
+	assert.True(result.Policies[1].Deciding)
+}
+
+func TestSimulateAuthorizationCustomPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	input := fakeAuthzInput()
+	input.policies = []kubernetes.IstioObject{
+		fakeAuthzObject(kubernetes.AuthorizationPoliciesType, "bookinfo", "ext-authz", map[string]interface{}{
+			"action": "CUSTOM",
+			"rules": []interface{}{map[string]interface{}{
+				"to": []interface{}{map[string]interface{}{"operation": map[string]interface{}{"notPaths": []interface{}{"/health"}}}},
+			}},
+		}),
+	}
+
+	result := simulateAuthorization(input)
+	assert.Equal(models.AuthorizationCustom, result.Decision)
+
+	input.request.Path = "/health"
+	result = simulateAuthorization(input)
+	assert.Equal(models.AuthorizationAllow, result.Decision)
+}
+
+func TestSimulateAuthorizationIndeterminate(t *testing.T) {
+	assert := assert.New(t)
+
+	input := fakeAuthzInput()
+	input.policies = []kubernetes.IstioObject{
+		fakeAuthzObject(kubernetes.AuthorizationPoliciesType, "bookinfo", "deny-jwt-group", map[string]interface{}{
+			"action": "DENY",
+			"rules": []interface{}{map[string]interface{}{
+				"when": []interface{}{map[string]interface{}{"key": "request.auth.claims[groups]", "values": []interface{}{"guests"}}},
+			}},
+		}),
+	}
+
+	result := simulateAuthorization(input)
+	assert.Equal(models.AuthorizationIndeterminate, result.Decision)
+	assert.True(result.Policies[0].Indeterminate)
+	assert.False(result.Policies[0].Matched)
+	assert.Len(result.Warnings, 1)
+
+	// A supported condition ruling the request out makes the rule a mismatch for sure
+	input.policies[0].GetSpec()["rules"].([]interface{})[0].(map[string]interface{})["when"] = []interface{}{
+		map[string]interface{}{"key": "request.auth.claims[groups]", "values": []interface{}{"guests"}},
+		map[string]interface{}{"key": "destination.port", "values": []interface{}{"8080"}},
+	}
+	input.request.Port = 9080
+	result = simulateAuthorization(input)
+	assert.Equal(models.AuthorizationAllow, result.Decision)
+	assert.False(result.Policies[0].Indeterminate)
+	assert.Empty(result.Warnings)
+}
+
+func TestSimulateAuthorizationMtls(t *testing.T) {
+	assert := assert.New(t)
+
+	input := fakeAuthzInput()
+	input.source = authzSource{ip: "10.0.0.12"}
+	input.meshPeerAuthns = []kubernetes.IstioObject{
+		fakeAuthzObject(kubernetes.PeerAuthenticationsType, "istio-system", "default", map[string]interface{}{
+			"mtls": map[string]interface{}{"mode": "STRICT"},
+		}),
+	}
+
+	result := simulateAuthorization(input)
+	assert.Equal(models.AuthorizationDeny, result.Decision)
+	assert.Equal(MtlsModeStrict, result.MtlsMode)
+	assert.False(result.Mtls)
+
+	// Port level mTLS of the workload policy overrides the mesh policy
+	input.peerAuthns = []kubernetes.IstioObject{
+		fakeAuthzObject(kubernetes.PeerAuthenticationsType, "bookinfo", "reviews", map[string]interface{}{
+			"selector":      map[string]interface{}{"matchLabels": map[string]interface{}{"app": "reviews"}},
+			"portLevelMtls": map[string]interface{}{"9080": map[string]interface{}{"mode": "PERMISSIVE"}},
+		}),
+	}
+	input.policies = []kubernetes.IstioObject{
+		fakeAuthzObject(kubernetes.AuthorizationPoliciesType, "bookinfo", "allow-internal", map[string]interface{}{
+			"rules": []interface{}{map[string]interface{}{
+				"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{"ipBlocks": []interface{}{"10.0.0.0/16"}}}},
+			}},
+		}),
+	}
+	result = simulateAuthorization(input)
+	assert.Equal(MtlsModePermissive, result.MtlsMode)
+	assert.Equal(models.AuthorizationAllow, result.Decision)
+	assert.Empty(result.SourcePrincipal)
+}
+
+func TestSimulateAuthorizationForbiddenRootNamespace(t *testing.T) {
+	assert := assert.New(t)
+
+	input := fakeAuthzInput()
+	input.rootPoliciesForbidden = true
+	input.rootPeerAuthnsForbidden = true
+	result := simulateAuthorization(input)
+	assert.Equal(models.AuthorizationIndeterminate, result.Decision)
+	assert.Len(result.Warnings, 2)
+
+	input.policies = []kubernetes.IstioObject{
+		fakeAuthzObject(kubernetes.AuthorizationPoliciesType, "bookinfo", "deny-all", map[string]interface{}{
+			"action": "DENY",
+			"rules":  []interface{}{map[string]interface{}{}},
+		}),
+	}
+	result = simulateAuthorization(input)
+	assert.Equal(models.AuthorizationDeny, result.Decision)
+}
+
+func TestMatchesWildcard(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(matchesWildcard("*", "GET"))
+	assert.False(matchesWildcard("*", ""))
+	assert.True(matchesWildcard("/api/*", "/api/v1"))
+	assert.True(matchesWildcard("*.example.com", "www.example.com"))
+	assert.False(matchesWildcard("/api", "/api/v1"))
+	assert.True(matchesIPBlock("10.1.2.3", "10.1.2.3"))
+	assert.False(matchesIPBlock("10.0.0.0/16", "10.1.2.3"))
+}
+
+func TestSimulatePeer(t *testing.T) {
+	assert := assert.New(t)
+
+	input := fakeAuthzInput()
+	input.policies = []kubernetes.IstioObject{
+		fakeAuthzObject(kubernetes.AuthorizationPoliciesType, "bookinfo", "allow-bookinfo", map[string]interface{}{
+			"rules": []interface{}{map[string]interface{}{
+				"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{"namespaces": []interface{}{"bookinfo"}}}},
+			}},
+		}),
+	}
+	authzConfig := AuthorizationConfig{base: *input}
+
+	result := authzConfig.SimulatePeer("spiffe://example.org/ns/bookinfo/sa/productpage", input.destLabels)
+	assert.Equal(models.AuthorizationAllow, result.Decision)
+	assert.Equal("example.org/ns/bookinfo/sa/productpage", result.SourcePrincipal)
+
+	result = authzConfig.SimulatePeer("", input.destLabels)
+	assert.Equal(models.AuthorizationDeny, result.Decision)
+	assert.False(result.Mtls)
+}
@@ -0,0 +1,89 @@
+package business
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// CacheService reports and manages the content of the Kiali cache
+type CacheService struct {
+	businessLayer *Layer
+}
+
+// GetStatus reports the cached namespaces accessible by the user
+func (in *CacheService) GetStatus() (models.CacheStatus, error) {
+	if kialiCache == nil {
+		return models.CacheStatus{Namespaces: []models.CacheNamespaceStatus{}}, nil
+	}
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return models.CacheStatus{}, err
+	}
+	accessible := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		accessible[ns.Name] = true
+	}
+
+	status := kialiCache.GetStatus()
+	filtered := make([]models.CacheNamespaceStatus, 0, len(status.Namespaces))
+	for _, nsStatus := range status.Namespaces {
+		if accessible[nsStatus.Namespace] {
+			filtered = append(filtered, nsStatus)
+		}
+	}
+	status.Namespaces = filtered
+	return status, nil
+}
+
+// CanManage tells if the user may refresh or evict the cached namespaces. The cache is shared by all the users: only
+// those allowed to patch the Kiali deployment may manage it.
+func (in *CacheService) CanManage() (bool, error) {
+	ssars, err := in.businessLayer.k8s.GetSelfSubjectAccessReview(config.Get().Deployment.Namespace, "apps", "deployments", []string{"patch"})
+	if err != nil {
+		return false, err
+	}
+	return len(ssars) == 1 && ssars[0].Status.Allowed, nil
+}
+
+// checkManagedNamespace checks that the namespaces are cached one by one: the cluster-wide informers hold all namespaces
+// and are not refreshed nor evicted.
+func checkManagedNamespace() error {
+	if kialiCache == nil {
+		return errors.NewServiceUnavailable("Kiali cache is disabled")
+	}
+	if kialiCache.IsClusterScoped() {
+		return errors.NewBadRequest("the namespaces of the cluster-wide Kiali cache cannot be refreshed nor evicted")
+	}
+	return nil
+}
+
+// RefreshNamespace drops the cached objects of a namespace and waits for the cache to load them again.
+func (in *CacheService) RefreshNamespace(namespace string) error {
+	if err := checkManagedNamespace(); err != nil {
+		return err
+	}
+	evicted := kialiCache.EvictNamespace(namespace)
+	if !kialiCache.CheckNamespace(namespace) {
+		if !evicted {
+			return kubernetes.NewNotFound(namespace, "Kiali", "cached namespace")
+		}
+		return errors.NewServiceUnavailable(fmt.Sprintf("Kiali cache for namespace %s failed to sync", namespace))
+	}
+	return nil
+}
+
+// EvictNamespace drops the cached objects of a namespace; they are loaded again on the next read of the namespace.
+func (in *CacheService) EvictNamespace(namespace string) error {
+	if err := checkManagedNamespace(); err != nil {
+		return err
+	}
+	if !kialiCache.EvictNamespace(namespace) {
+		return kubernetes.NewNotFound(namespace, "Kiali", "cached namespace")
+	}
+	return nil
+}
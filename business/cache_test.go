@@ -0,0 +1,36 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	auth_v1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/cache"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+func TestCacheManagement(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.Deployment.Namespace = "kiali"
+	config.Set(conf)
+	defer func(previous cache.KialiCache) { kialiCache = previous }(kialiCache)
+	kialiCache = &clusterScopedCacheMock{access: map[string]bool{}}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetSelfSubjectAccessReview", "kiali", "apps", "deployments", []string{"patch"}).Return(
+		[]*auth_v1.SelfSubjectAccessReview{{Status: auth_v1.SubjectAccessReviewStatus{Allowed: false}}}, nil)
+	layer := NewWithBackends(k8s, nil, nil)
+
+	canManage, err := layer.Cache.CanManage()
+	assert.NoError(err)
+	assert.False(canManage)
+
+	// The cluster-wide informers are neither refreshed nor evicted
+	assert.True(errors.IsBadRequest(layer.Cache.RefreshNamespace("bookinfo")))
+	assert.True(errors.IsBadRequest(layer.Cache.EvictNamespace("bookinfo")))
+}
@@ -0,0 +1,239 @@
+package business
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// Maximum number of proxies whose config dump is fetched at the same time
+const maxConcurrentProxyDumps = 5
+
+// CertificatesService inspects the certificates actually loaded by the proxies, unlike the TLSService which
+// infers mTLS from the configuration
+type CertificatesService struct {
+	k8s           kubernetes.ClientInterface
+	businessLayer *Layer
+}
+
+// proxyPod is a pod whose proxy certificates are inspected
+type proxyPod struct {
+	namespace string
+	workload  string
+	pod       string
+}
+
+// GetWorkloadCertificates returns the certificates loaded by the proxies of the pods of a workload
+func (in *CertificatesService) GetWorkloadCertificates(namespace, workload string, expiringWithin time.Duration) (*models.CertificatesReport, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	w, err := fetchWorkload(in.businessLayer, namespace, workload, "")
+	if err != nil {
+		return nil, err
+	}
+	pods := []proxyPod{}
+	for _, p := range w.Pods {
+		if p.HasIstioSidecar() && p.Status == "Running" {
+			pods = append(pods, proxyPod{namespace: namespace, workload: workload, pod: p.Name})
+		}
+	}
+	return in.inspect(pods, expiringWithin), nil
+}
+
+// GetNamespaceCertificates returns the certificates loaded by the proxies of the pods of a namespace
+func (in *CertificatesService) GetNamespaceCertificates(namespace string, expiringWithin time.Duration) (*models.CertificatesReport, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	pods, err := in.namespaceProxyPods(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return in.inspect(pods, expiringWithin), nil
+}
+
+// GetMeshCertificates returns the certificates loaded by the proxies of the pods of all the accessible namespaces
+func (in *CertificatesService) GetMeshCertificates(expiringWithin time.Duration) (*models.CertificatesReport, error) {
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	pods := []proxyPod{}
+	// A namespace whose workloads can't be listed doesn't prevent reporting the others
+	warnings := []string{}
+	for _, ns := range namespaces {
+		nsPods, err := in.namespaceProxyPods(ns.Name)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Cannot list the proxies of namespace %s: %v", ns.Name, err))
+			continue
+		}
+		pods = append(pods, nsPods...)
+	}
+	report := in.inspect(pods, expiringWithin)
+	report.Warnings = warnings
+	return report, nil
+}
+
+func (in *CertificatesService) namespaceProxyPods(namespace string) ([]proxyPod, error) {
+	workloads, err := fetchWorkloads(in.businessLayer, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	pods := []proxyPod{}
+	for _, w := range workloads {
+		for _, p := range w.Pods {
+			if p.HasIstioSidecar() && p.Status == "Running" {
+				pods = append(pods, proxyPod{namespace: namespace, workload: w.Name, pod: p.Name})
+			}
+		}
+	}
+	return pods, nil
+}
+
+func (in *CertificatesService) inspect(pods []proxyPod, expiringWithin time.Duration) *models.CertificatesReport {
+	report := &models.CertificatesReport{
+		ExpectedTrustDomain: strings.TrimPrefix(config.Get().ExternalServices.Istio.IstioIdentityDomain, "svc."),
+		ExpiringWithin:      expiringWithin.String(),
+		Certificates:        []models.ProxyCertificate{},
+		Errors:              []models.ProxyCertificatesError{},
+		Warnings:            []string{},
+	}
+
+	type result struct {
+		certificates []models.ProxyCertificate
+		err          *models.ProxyCertificatesError
+	}
+	results := make([]result, len(pods))
+	wg := sync.WaitGroup{}
+	semaphore := make(chan struct{}, maxConcurrentProxyDumps)
+	for i, p := range pods {
+		wg.Add(1)
+		go func(i int, p proxyPod) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			certificates, err := in.podCertificates(p)
+			if err != nil {
+				results[i].err = &models.ProxyCertificatesError{Namespace: p.namespace, Pod: p.pod, Message: err.Error()}
+				return
+			}
+			results[i].certificates = certificates
+		}(i, p)
+	}
+	wg.Wait()
+
+	now := time.Now()
+	for _, r := range results {
+		if r.err != nil {
+			report.Errors = append(report.Errors, *r.err)
+			continue
+		}
+		for _, c := range r.certificates {
+			c.DaysUntilExpiry = c.ValidUntil.Sub(now).Hours() / 24
+			c.ExpiringSoon = c.ValidUntil.Before(now.Add(expiringWithin))
+			c.UnexpectedTrustDomain = c.Type == models.CertificateTypeWorkload && c.TrustDomain != report.ExpectedTrustDomain
+			if c.ExpiringSoon {
+				report.ExpiringSoon++
+			}
+			if c.UnexpectedTrustDomain {
+				report.UnexpectedTrustDomain++
+			}
+			report.Certificates = append(report.Certificates, c)
+		}
+	}
+	return report
+}
+
+func (in *CertificatesService) podCertificates(p proxyPod) ([]models.ProxyCertificate, error) {
+	dump, err := in.k8s.GetConfigDump(p.namespace, p.pod)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := dump.GetSecrets()
+	if err != nil {
+		return nil, err
+	}
+	certificates, err := parseSecretCertificates(secrets)
+	if err != nil {
+		return nil, err
+	}
+	for i := range certificates {
+		certificates[i].Namespace = p.namespace
+		certificates[i].Workload = p.workload
+		certificates[i].Pod = p.pod
+	}
+	return certificates, nil
+}
+
+// parseSecretCertificates extracts the leaf certificate of each secret: the workload certificate of TLS certificate
+// secrets, the root certificate of validation context secrets
+func parseSecretCertificates(secrets *kubernetes.SecretDump) ([]models.ProxyCertificate, error) {
+	certificates := []models.ProxyCertificate{}
+	for _, s := range append(secrets.DynamicActiveSecrets, secrets.StaticSecrets...) {
+		var data *kubernetes.EnvoyDataSource
+		certType := models.CertificateTypeWorkload
+		if s.Secret.TLSCertificate != nil {
+			data = s.Secret.TLSCertificate.CertificateChain
+		} else if s.Secret.ValidationContext != nil {
+			data = s.Secret.ValidationContext.TrustedCA
+			certType = models.CertificateTypeRoot
+		}
+		if data == nil || data.InlineBytes == "" {
+			// Secrets that are not loaded yet
+			continue
+		}
+		cert, err := parseCertificate(data.InlineBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in secret %s: %v", s.Name, err)
+		}
+		certificate := models.ProxyCertificate{
+			SecretName:   s.Name,
+			Type:         certType,
+			Issuer:       cert.Issuer.String(),
+			SerialNumber: cert.SerialNumber.String(),
+			ValidFrom:    cert.NotBefore,
+			ValidUntil:   cert.NotAfter,
+		}
+		if identity := spiffeIdentity(cert.URIs); identity != nil {
+			certificate.Identity = identity.String()
+			certificate.TrustDomain = identity.Host
+		}
+		certificates = append(certificates, certificate)
+	}
+	sort.Slice(certificates, func(i, j int) bool { return certificates[i].SecretName < certificates[j].SecretName })
+	return certificates, nil
+}
+
+// parseCertificate decodes the first certificate of a base64 encoded PEM chain
+func parseCertificate(inlineBytes string) (*x509.Certificate, error) {
+	chain, err := base64.StdEncoding.DecodeString(inlineBytes)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(chain)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func spiffeIdentity(uris []*url.URL) *url.URL {
+	for _, u := range uris {
+		if u.Scheme == "spiffe" {
+			return u
+		}
+	}
+	return nil
+}
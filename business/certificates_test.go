@@ -0,0 +1,132 @@
+package business
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func fakeCertificate(t *testing.T, identity string, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Issuer:       pkix.Name{Organization: []string{"cluster.local"}},
+		Subject:      pkix.Name{Organization: []string{"cluster.local"}},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	if identity != "" {
+		u, _ := url.Parse(identity)
+		template.URIs = []*url.URL{u}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func fakeSecretsConfigDump(workloadCert, rootCert string) *kubernetes.ConfigDump {
+	return &kubernetes.ConfigDump{Configs: []interface{}{
+		map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.admin.v3.SecretsConfigDump",
+			"dynamic_active_secrets": []interface{}{
+				map[string]interface{}{
+					"name": "default",
+					"secret": map[string]interface{}{
+						"name":            "default",
+						"tls_certificate": map[string]interface{}{"certificate_chain": map[string]interface{}{"inline_bytes": workloadCert}},
+					},
+				},
+				map[string]interface{}{
+					"name": "ROOTCA",
+					"secret": map[string]interface{}{
+						"name":               "ROOTCA",
+						"validation_context": map[string]interface{}{"trusted_ca": map[string]interface{}{"inline_bytes": rootCert}},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestParseSecretCertificates(t *testing.T) {
+	assert := assert.New(t)
+
+	notAfter := time.Now().Add(12 * time.Hour).Truncate(time.Second).UTC()
+	dump := fakeSecretsConfigDump(fakeCertificate(t, "spiffe://cluster.local/ns/bookinfo/sa/default", notAfter), fakeCertificate(t, "", notAfter.Add(24*365*time.Hour)))
+	secrets, err := dump.GetSecrets()
+	assert.NoError(err)
+
+	certificates, err := parseSecretCertificates(secrets)
+	assert.NoError(err)
+	assert.Len(certificates, 2)
+	assert.Equal("ROOTCA", certificates[0].SecretName)
+	assert.Equal(models.CertificateTypeRoot, certificates[0].Type)
+	assert.Empty(certificates[0].Identity)
+	assert.Equal("default", certificates[1].SecretName)
+	assert.Equal(models.CertificateTypeWorkload, certificates[1].Type)
+	assert.Equal("spiffe://cluster.local/ns/bookinfo/sa/default", certificates[1].Identity)
+	assert.Equal("cluster.local", certificates[1].TrustDomain)
+	assert.Equal("O=cluster.local", certificates[1].Issuer)
+	assert.Equal("42", certificates[1].SerialNumber)
+	assert.Equal(notAfter, certificates[1].ValidUntil)
+
+	// Secrets not loaded yet are skipped
+	certificates, err = parseSecretCertificates(&kubernetes.SecretDump{DynamicActiveSecrets: []kubernetes.EnvoySecretWrapper{{Name: "default"}}})
+	assert.NoError(err)
+	assert.Empty(certificates)
+}
+
+func TestInspectCertificates(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	now := time.Now()
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetConfigDump", "bookinfo", "reviews-v1-1").Return(fakeSecretsConfigDump(
+		fakeCertificate(t, "spiffe://cluster.local/ns/bookinfo/sa/reviews", now.Add(2*time.Hour)),
+		fakeCertificate(t, "", now.Add(24*365*time.Hour)),
+	), nil)
+	k8s.On("GetConfigDump", "bookinfo", "ratings-v1-1").Return(fakeSecretsConfigDump(
+		fakeCertificate(t, "spiffe://other.domain/ns/bookinfo/sa/ratings", now.Add(20*time.Hour)),
+		fakeCertificate(t, "", now.Add(24*365*time.Hour)),
+	), nil)
+	k8s.On("GetConfigDump", "bookinfo", "details-v1-1").Return((*kubernetes.ConfigDump)(nil), kubernetes.NewNotFound("details-v1-1", "", "pods"))
+
+	service := CertificatesService{k8s: k8s}
+	report := service.inspect([]proxyPod{
+		{namespace: "bookinfo", workload: "reviews-v1", pod: "reviews-v1-1"},
+		{namespace: "bookinfo", workload: "ratings-v1", pod: "ratings-v1-1"},
+		{namespace: "bookinfo", workload: "details-v1", pod: "details-v1-1"},
+	}, 6*time.Hour)
+
+	assert.Equal("cluster.local", report.ExpectedTrustDomain)
+	assert.Equal("6h0m0s", report.ExpiringWithin)
+	assert.Len(report.Certificates, 4)
+	assert.Equal(1, report.ExpiringSoon)
+	assert.Equal(1, report.UnexpectedTrustDomain)
+	assert.Len(report.Errors, 1)
+	assert.Equal("details-v1-1", report.Errors[0].Pod)
+
+	reviews := report.Certificates[1]
+	assert.Equal("reviews-v1", reviews.Workload)
+	assert.True(reviews.ExpiringSoon)
+	assert.False(reviews.UnexpectedTrustDomain)
+	assert.InDelta(2.0/24, reviews.DaysUntilExpiry, 0.01)
+	assert.True(report.Certificates[3].UnexpectedTrustDomain)
+}
@@ -19,12 +19,14 @@ const defaultNamespaceLabel = "namespace"
 
 // DashboardsService deals with fetching dashboards from config
 type DashboardsService struct {
-	promClient      prometheus.ClientInterface
-	dashboards      map[string]dashboards.MonitoringDashboard
-	promConfig      config.PrometheusConfig
-	globalNamespace string
-	namespaceLabel  string
-	CustomEnabled   bool
+	promClient prometheus.ClientInterface
+	dashboards map[string]dashboards.MonitoringDashboard
+	// Runtime dashboards only visible in their namespace
+	namespacedDashboards map[string]map[string]dashboards.MonitoringDashboard
+	promConfig           config.PrometheusConfig
+	globalNamespace      string
+	namespaceLabel       string
+	CustomEnabled        bool
 }
 
 // NewDashboardsService initializes this business service
@@ -39,13 +41,50 @@ func NewDashboardsService() *DashboardsService {
 	if nsLabel == "" {
 		nsLabel = "kubernetes_namespace"
 	}
-	return &DashboardsService{
-		CustomEnabled:   customEnabled,
-		promConfig:      prom,
-		globalNamespace: cfg.Deployment.Namespace,
-		namespaceLabel:  nsLabel,
-		dashboards:      cfg.CustomDashboards.OrganizeByName(),
+	service := &DashboardsService{
+		CustomEnabled:        customEnabled,
+		promConfig:           prom,
+		globalNamespace:      cfg.Deployment.Namespace,
+		namespaceLabel:       nsLabel,
+		dashboards:           cfg.CustomDashboards.OrganizeByName(),
+		namespacedDashboards: map[string]map[string]dashboards.MonitoringDashboard{},
+	}
+	if customEnabled {
+		runtimeDashboardsOnce.Do(initRuntimeDashboards)
+		if runtimeDashboards != nil {
+			global, namespaced := runtimeDashboards.get()
+			// Runtime dashboards override the dashboards of the config with the same name
+			for name, d := range global {
+				service.dashboards[name] = d
+			}
+			service.namespacedDashboards = namespaced
+		}
+	}
+	return service
+}
+
+// dashboardsFor returns the dashboards visible in a namespace: the global ones and its own ones, which take precedence
+func (in *DashboardsService) dashboardsFor(namespace string) map[string]dashboards.MonitoringDashboard {
+	namespaced, ok := in.namespacedDashboards[namespace]
+	if !ok {
+		return in.dashboards
+	}
+	all := make(map[string]dashboards.MonitoringDashboard, len(in.dashboards)+len(namespaced))
+	for name, d := range in.dashboards {
+		all[name] = d
+	}
+	for name, d := range namespaced {
+		all[name] = d
 	}
+	return all
+}
+
+// GetValidationErrors returns the errors of the dashboards discovered at runtime in a namespace
+func (in *DashboardsService) GetValidationErrors(namespace string) []models.DashboardValidationError {
+	if !in.CustomEnabled || runtimeDashboards == nil {
+		return []models.DashboardValidationError{}
+	}
+	return runtimeDashboards.validationErrors(namespace)
 }
 
 func (in *DashboardsService) prom() (prometheus.ClientInterface, error) {
@@ -60,8 +99,8 @@ func (in *DashboardsService) prom() (prometheus.ClientInterface, error) {
 	return in.promClient, nil
 }
 
-func (in *DashboardsService) loadRawDashboardResource(template string) (*dashboards.MonitoringDashboard, error) {
-	dashboard, ok := in.dashboards[template]
+func (in *DashboardsService) loadRawDashboardResource(namespace, template string) (*dashboards.MonitoringDashboard, error) {
+	dashboard, ok := in.dashboardsFor(namespace)[template]
 	if !ok {
 		return nil, fmt.Errorf("Dashboard [%v] does not exist or is disabled", template)
 	}
@@ -69,22 +108,22 @@ func (in *DashboardsService) loadRawDashboardResource(template string) (*dashboa
 	return &dashboard, nil
 }
 
-func (in *DashboardsService) loadAndResolveDashboardResource(template string, loaded map[string]bool) (*dashboards.MonitoringDashboard, error) {
+func (in *DashboardsService) loadAndResolveDashboardResource(namespace, template string, loaded map[string]bool) (*dashboards.MonitoringDashboard, error) {
 	// Circular dependency check
 	if _, ok := loaded[template]; ok {
 		return nil, fmt.Errorf("cannot load dashboard %s due to circular dependency detected. Already loaded dependencies: %v", template, loaded)
 	}
 	loaded[template] = true
-	dashboard, err := in.loadRawDashboardResource(template)
+	dashboard, err := in.loadRawDashboardResource(namespace, template)
 	if err != nil {
 		return nil, err
 	}
-	err = in.resolveReferences(dashboard, loaded)
+	err = in.resolveReferences(namespace, dashboard, loaded)
 	return dashboard, err
 }
 
 // resolveReferences resolves the composition mechanism that allows to reference a dashboard from another one
-func (in *DashboardsService) resolveReferences(dashboard *dashboards.MonitoringDashboard, loaded map[string]bool) error {
+func (in *DashboardsService) resolveReferences(namespace string, dashboard *dashboards.MonitoringDashboard, loaded map[string]bool) error {
 	resolved := []dashboards.MonitoringDashboardItem{}
 	for _, item := range dashboard.Items {
 		reference := strings.TrimSpace(item.Include)
@@ -92,7 +131,7 @@ func (in *DashboardsService) resolveReferences(dashboard *dashboards.MonitoringD
 			// reference can point to a whole dashboard (ex: microprofile-1.0) or a chart within a dashboard (ex: microprofile-1.0$Thread count)
 			parts := strings.Split(reference, "$")
 			dashboardRefName := parts[0]
-			composedDashboard, err := in.loadAndResolveDashboardResource(dashboardRefName, loaded)
+			composedDashboard, err := in.loadAndResolveDashboardResource(namespace, dashboardRefName, loaded)
 			if err != nil {
 				return err
 			}
@@ -122,7 +161,7 @@ func (in *DashboardsService) GetDashboard(authInfo *api.AuthInfo, params models.
 	if err != nil {
 		return nil, err
 	}
-	dashboard, err := in.loadAndResolveDashboardResource(template, map[string]bool{})
+	dashboard, err := in.loadAndResolveDashboardResource(params.Namespace, template, map[string]bool{})
 	if err != nil {
 		return nil, err
 	}
@@ -165,6 +204,10 @@ func (in *DashboardsService) GetDashboard(authInfo *api.AuthInfo, params models.
 			grouping := strings.Join(byLabels, ",")
 
 			filledCharts[idx] = models.ConvertChart(chart)
+			if chart.DataType == dashboards.Query {
+				in.fillQueryChart(promClient, &filledCharts[idx], chart, params, filters, grouping, conversionParams)
+				return
+			}
 			metrics := chart.GetMetrics()
 			for _, ref := range metrics {
 				var converted []models.Metric
@@ -220,23 +263,23 @@ func (in *DashboardsService) GetDashboard(authInfo *api.AuthInfo, params models.
 
 // SearchExplicitDashboards will check annotations of all supplied pods to extract a unique list of dashboards
 //	Accepted annotations are "kiali.io/runtimes" and "kiali.io/dashboards"
-func (in *DashboardsService) SearchExplicitDashboards(pods []models.Pod) []models.Runtime {
+func (in *DashboardsService) SearchExplicitDashboards(namespace string, pods []models.Pod) []models.Runtime {
 	uniqueRefsList := extractUniqueDashboards(pods)
 	if len(uniqueRefsList) > 0 {
 		log.Tracef("getting dashboards from refs list: %v", uniqueRefsList)
-		return in.buildRuntimesList(uniqueRefsList)
+		return in.buildRuntimesList(namespace, uniqueRefsList)
 	}
 	return []models.Runtime{}
 }
 
-func (in *DashboardsService) buildRuntimesList(templatesNames []string) []models.Runtime {
+func (in *DashboardsService) buildRuntimesList(namespace string, templatesNames []string) []models.Runtime {
 	dashboards := make([]*dashboards.MonitoringDashboard, len(templatesNames))
 	wg := sync.WaitGroup{}
 	wg.Add(len(templatesNames))
 	for idx, template := range templatesNames {
 		go func(i int, tpl string) {
 			defer wg.Done()
-			dashboard, err := in.loadRawDashboardResource(tpl)
+			dashboard, err := in.loadRawDashboardResource(namespace, tpl)
 			if err != nil {
 				log.Errorf("cannot get dashboard [%s]: %v", tpl, err)
 			} else {
@@ -284,7 +327,7 @@ func (in *DashboardsService) discoverDashboards(namespace string, labelsFilters
 	}()
 
 	wg.Wait()
-	return runDiscoveryMatcher(metrics, in.dashboards)
+	return runDiscoveryMatcher(metrics, in.dashboardsFor(namespace))
 }
 
 func runDiscoveryMatcher(metrics []string, allDashboards map[string]dashboards.MonitoringDashboard) []models.Runtime {
@@ -346,12 +389,52 @@ func addDashboardToRuntimes(dashboard *dashboards.MonitoringDashboard, runtimes
 	return runtimes
 }
 
-func (in *DashboardsService) buildLabels(namespace string, labelsFilters map[string]string) string {
-	namespaceLabel := in.namespaceLabel
-	if namespaceLabel == "" {
-		namespaceLabel = defaultNamespaceLabel
+// fillQueryChart fills a chart made of PromQL templates, expanded with the dashboard query parameters
+func (in *DashboardsService) fillQueryChart(promClient prometheus.ClientInterface, filled *models.Chart, chart dashboards.MonitoringDashboardChart, params models.DashboardQuery, filters, grouping string, conversionParams models.ConversionParams) {
+	cfg := config.Get()
+	queries, err := chart.ExpandQueries(dashboards.QueryVariables{
+		Namespace:    params.Namespace,
+		App:          params.LabelsFilters[cfg.IstioLabels.AppLabelName],
+		Workload:     params.Workload,
+		Version:      params.LabelsFilters[cfg.IstioLabels.VersionLabelName],
+		RateInterval: params.RateInterval,
+		Filters:      strings.TrimSuffix(strings.TrimPrefix(filters, "{"), "}"),
+		GroupBy:      grouping,
+	})
+	if err != nil {
+		filled.Error = err.Error()
+		return
 	}
-	labels := fmt.Sprintf(`{%s="%s"`, namespaceLabel, namespace)
+	for i, metric := range chart.Metrics {
+		if metric.Hidden {
+			continue
+		}
+		query := queries[i]
+		if chart.NamespaceScoped {
+			if query, err = injectNamespaceMatcher(query, in.dashboardNamespaceLabel(), []string{params.Namespace}); err != nil {
+				filled.Error = err.Error()
+				return
+			}
+		}
+		result := promClient.FetchQueryRange(query, &params.RangeQuery)
+		converted, err := models.ConvertMetric(metric.DisplayName, result, conversionParams)
+		if err != nil {
+			filled.Error = err.Error()
+		} else {
+			filled.Metrics = append(filled.Metrics, converted...)
+		}
+	}
+}
+
+func (in *DashboardsService) dashboardNamespaceLabel() string {
+	if in.namespaceLabel == "" {
+		return defaultNamespaceLabel
+	}
+	return in.namespaceLabel
+}
+
+func (in *DashboardsService) buildLabels(namespace string, labelsFilters map[string]string) string {
+	labels := fmt.Sprintf(`{%s="%s"`, in.dashboardNamespaceLabel(), namespace)
 	for k, v := range labelsFilters {
 		labels += fmt.Sprintf(`,%s="%s"`, prometheus.SanitizeLabelName(k), v)
 	}
@@ -490,7 +573,7 @@ func (in *DashboardsService) GetCustomDashboardRefs(namespace, app, version stri
 	for _, p := range pods {
 		podsCast = append(podsCast, *p)
 	}
-	runtimes := in.SearchExplicitDashboards(podsCast)
+	runtimes := in.SearchExplicitDashboards(namespace, podsCast)
 
 	if len(runtimes) == 0 {
 		cfg := config.Get()
@@ -0,0 +1,340 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/config/dashboards"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// dashboardsRegistry holds the dashboards discovered at runtime from MonitoringDashboard resources and labelled
+// ConfigMaps. It is built once the informers are synced, then rebuilt on every change of the watched resources.
+type dashboardsRegistry struct {
+	lock       sync.RWMutex
+	informers  []cache.SharedIndexInformer
+	stopCh     chan struct{}
+	synced     bool
+	global     map[string]dashboards.MonitoringDashboard
+	namespaced map[string]map[string]dashboards.MonitoringDashboard
+	errors     []models.DashboardValidationError
+}
+
+// Global registry of runtime dashboards, nil when they are disabled
+var runtimeDashboards *dashboardsRegistry
+var runtimeDashboardsOnce sync.Once
+
+func initRuntimeDashboards() {
+	cfg := config.Get()
+	if !cfg.ExternalServices.CustomDashboards.Enabled || !cfg.ExternalServices.CustomDashboards.Runtime.Enabled {
+		return
+	}
+	registry, err := newDashboardsRegistry()
+	if err != nil {
+		log.Errorf("Error initializing runtime dashboards. Details: %s", err)
+		return
+	}
+	runtimeDashboards = registry
+}
+
+func newDashboardsRegistry() (*dashboardsRegistry, error) {
+	// Like the Kiali cache, the registry watches with the ServiceAccount token
+	clientConfig, err := kubernetes.ConfigClient()
+	if err != nil {
+		return nil, err
+	}
+	cfg := config.Get()
+	token := ""
+	if cfg.InCluster {
+		if token, err = kubernetes.GetKialiToken(); err != nil {
+			return nil, err
+		}
+	}
+	client, err := kubernetes.NewClientFromConfig(&rest.Config{
+		Host:            clientConfig.Host,
+		TLSClientConfig: clientConfig.TLSClientConfig,
+		QPS:             clientConfig.QPS,
+		BearerToken:     token,
+		Burst:           clientConfig.Burst,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeCfg := cfg.ExternalServices.CustomDashboards.Runtime
+	namespaces := runtimeDashboardsNamespaces(cfg)
+	withCRD := client.IsMonitoringApi()
+	if !withCRD {
+		log.Infof("MonitoringDashboard CRD not found: runtime dashboards are only read from ConfigMaps labelled [%s]", runtimeCfg.ConfigMapLabel)
+	}
+
+	registry := &dashboardsRegistry{
+		stopCh:     make(chan struct{}),
+		global:     map[string]dashboards.MonitoringDashboard{},
+		namespaced: map[string]map[string]dashboards.MonitoringDashboard{},
+		errors:     []models.DashboardValidationError{},
+	}
+	refreshDuration := time.Duration(cfg.KubernetesConfig.CacheDuration) * time.Second
+	for _, ns := range namespaces {
+		if withCRD {
+			registry.informers = append(registry.informers, cache.NewSharedIndexInformer(
+				cache.NewListWatchFromClient(client.GetMonitoringApi(), kubernetes.MonitoringDashboards, ns, fields.Everything()),
+				&kubernetes.GenericIstioObject{},
+				refreshDuration,
+				cache.Indexers{},
+			))
+		}
+		sharedInformers := informers.NewSharedInformerFactoryWithOptions(client.GetK8sApi(), refreshDuration,
+			informers.WithNamespace(ns),
+			informers.WithTweakListOptions(func(opts *meta_v1.ListOptions) {
+				opts.LabelSelector = runtimeCfg.ConfigMapLabel
+			}))
+		registry.informers = append(registry.informers, sharedInformers.Core().V1().ConfigMaps().Informer())
+	}
+
+	// The initial list is loaded at once when the informers are synced, then each change reloads the dashboards
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { registry.reloadIfSynced() },
+		UpdateFunc: func(interface{}, interface{}) { registry.reloadIfSynced() },
+		DeleteFunc: func(interface{}) { registry.reloadIfSynced() },
+	}
+	hasSynced := make([]cache.InformerSynced, 0, len(registry.informers))
+	for _, informer := range registry.informers {
+		informer.AddEventHandler(handler)
+		hasSynced = append(hasSynced, informer.HasSynced)
+		go informer.Run(registry.stopCh)
+	}
+	go func() {
+		if !cache.WaitForCacheSync(registry.stopCh, hasSynced...) {
+			return
+		}
+		registry.lock.Lock()
+		registry.synced = true
+		registry.lock.Unlock()
+		registry.reload()
+	}()
+	log.Infof("Watching runtime dashboards [namespaces: %v, configMapLabel: %s]", namespaces, runtimeCfg.ConfigMapLabel)
+	return registry, nil
+}
+
+// runtimeDashboardsNamespaces returns the namespaces to watch: the configured ones or, by default, the Kiali and Istio
+// namespaces and the accessible namespaces
+func runtimeDashboardsNamespaces(cfg *config.Config) []string {
+	if namespaces := cfg.ExternalServices.CustomDashboards.Runtime.Namespaces; len(namespaces) > 0 {
+		return namespaces
+	}
+	namespaces := []string{}
+	seen := make(map[string]bool)
+	for _, ns := range append([]string{cfg.IstioNamespace, cfg.Deployment.Namespace}, cfg.Deployment.AccessibleNamespaces...) {
+		if ns == "**" {
+			// All the namespaces are accessible
+			return []string{meta_v1.NamespaceAll}
+		}
+		if ns != "" && !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// stop stops the informers of the registry
+func (r *dashboardsRegistry) stop() {
+	close(r.stopCh)
+}
+
+func (r *dashboardsRegistry) reloadIfSynced() {
+	r.lock.RLock()
+	synced := r.synced
+	r.lock.RUnlock()
+	if synced {
+		r.reload()
+	}
+}
+
+// reload rebuilds the dashboards from the content of the informers
+func (r *dashboardsRegistry) reload() {
+	resources := []kubernetes.IstioObject{}
+	configMaps := []*core_v1.ConfigMap{}
+	for _, informer := range r.informers {
+		for _, obj := range informer.GetStore().List() {
+			switch o := obj.(type) {
+			case *kubernetes.GenericIstioObject:
+				resources = append(resources, o)
+			case *core_v1.ConfigMap:
+				configMaps = append(configMaps, o)
+			}
+		}
+	}
+	global, namespaced, errs := buildRuntimeDashboards(resources, configMaps, config.Get().CustomDashboards.OrganizeByName())
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.global = global
+	r.namespaced = namespaced
+	r.errors = errs
+	log.Debugf("Runtime dashboards reloaded [global=%d, namespaces=%d, errors=%d]", len(global), len(namespaced), len(errs))
+}
+
+// get returns the global dashboards and the dashboards by namespace. The maps are replaced, never modified, on reload.
+func (r *dashboardsRegistry) get() (map[string]dashboards.MonitoringDashboard, map[string]map[string]dashboards.MonitoringDashboard) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.global, r.namespaced
+}
+
+func (r *dashboardsRegistry) validationErrors(namespace string) []models.DashboardValidationError {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	errs := []models.DashboardValidationError{}
+	for _, e := range r.errors {
+		if e.Namespace == namespace {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// isGlobalDashboardsNamespace tells whether the dashboards of a namespace are visible in all namespaces
+func isGlobalDashboardsNamespace(namespace string) bool {
+	cfg := config.Get()
+	return namespace == cfg.Deployment.Namespace || namespace == cfg.IstioNamespace
+}
+
+// runtimeDashboard is a dashboard with the resource it comes from
+type runtimeDashboard struct {
+	dashboards.MonitoringDashboard
+	kind      string
+	namespace string
+	name      string
+}
+
+// buildRuntimeDashboards validates the dashboards of the resources and organizes them by scope. Dashboards of the
+// Kiali and Istio namespaces are global, the others are only visible in their namespace. A runtime dashboard
+// overrides a dashboard of the config with the same name. Invalid dashboards are left out and reported.
+func buildRuntimeDashboards(resources []kubernetes.IstioObject, configMaps []*core_v1.ConfigMap, configDashboards map[string]dashboards.MonitoringDashboard) (map[string]dashboards.MonitoringDashboard, map[string]map[string]dashboards.MonitoringDashboard, []models.DashboardValidationError) {
+	errs := []models.DashboardValidationError{}
+	reportError := func(kind, namespace, name, dashboard, message string) {
+		errs = append(errs, models.DashboardValidationError{Kind: kind, Namespace: namespace, Name: name, Dashboard: dashboard, Message: message})
+	}
+
+	candidates := []runtimeDashboard{}
+	for _, o := range resources {
+		meta := o.GetObjectMeta()
+		d := dashboards.MonitoringDashboard{}
+		spec, err := yaml.Marshal(o.GetSpec())
+		if err == nil {
+			err = yaml.Unmarshal(spec, &d)
+		}
+		if err != nil {
+			reportError(kubernetes.MonitoringDashboardType, meta.Namespace, meta.Name, "", err.Error())
+			continue
+		}
+		// The resource name is the dashboard name
+		d.Name = meta.Name
+		candidates = append(candidates, runtimeDashboard{MonitoringDashboard: d, kind: kubernetes.MonitoringDashboardType, namespace: meta.Namespace, name: meta.Name})
+	}
+	for _, cm := range configMaps {
+		keys := make([]string, 0, len(cm.Data))
+		for key := range cm.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			list, err := dashboards.UnmarshalDashboards(cm.Data[key])
+			if err != nil {
+				reportError(kubernetes.ConfigMapType, cm.Namespace, cm.Name, "", fmt.Sprintf("%s: %v", key, err))
+				continue
+			}
+			for _, d := range list {
+				candidates = append(candidates, runtimeDashboard{MonitoringDashboard: d, kind: kubernetes.ConfigMapType, namespace: cm.Namespace, name: cm.Name})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].namespace != candidates[j].namespace {
+			return candidates[i].namespace < candidates[j].namespace
+		}
+		if candidates[i].kind != candidates[j].kind {
+			// MonitoringDashboard resources take precedence over ConfigMaps
+			return candidates[i].kind > candidates[j].kind
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	scopes := make(map[string]map[string]runtimeDashboard)
+	valid := []runtimeDashboard{}
+	for _, c := range candidates {
+		if err := c.Validate(); err != nil {
+			reportError(c.kind, c.namespace, c.name, c.Name, err.Error())
+			continue
+		}
+		scope := c.namespace
+		if isGlobalDashboardsNamespace(c.namespace) {
+			scope = ""
+		}
+		if _, ok := scopes[scope]; !ok {
+			scopes[scope] = make(map[string]runtimeDashboard)
+		}
+		if existing, found := scopes[scope][c.Name]; found {
+			reportError(c.kind, c.namespace, c.name, c.Name, fmt.Sprintf("Dashboard %s is already defined by %s %s/%s", c.Name, existing.kind, existing.namespace, existing.name))
+			continue
+		}
+		scopes[scope][c.Name] = c
+		valid = append(valid, c)
+	}
+
+	// Includes must reference a dashboard visible in the scope
+	global := make(map[string]dashboards.MonitoringDashboard)
+	namespaced := make(map[string]map[string]dashboards.MonitoringDashboard)
+	for _, c := range valid {
+		scope := c.namespace
+		if isGlobalDashboardsNamespace(c.namespace) {
+			scope = ""
+		}
+		missing := []string{}
+		for _, item := range c.Items {
+			include := strings.Split(strings.TrimSpace(item.Include), "$")[0]
+			if include == "" {
+				continue
+			}
+			_, inConfig := configDashboards[include]
+			_, inGlobal := scopes[""][include]
+			_, inNamespace := scopes[scope][include]
+			if !inConfig && !inGlobal && !inNamespace {
+				missing = append(missing, include)
+			}
+		}
+		if len(missing) > 0 {
+			reportError(c.kind, c.namespace, c.name, c.Name, fmt.Sprintf("Included dashboards not found: %s", strings.Join(missing, ", ")))
+			continue
+		}
+		if scope == "" {
+			global[c.Name] = c.MonitoringDashboard
+		} else {
+			// Anyone allowed to write in the namespace can define its dashboards
+			for i := range c.Items {
+				c.Items[i].Chart.NamespaceScoped = true
+			}
+			if _, ok := namespaced[scope]; !ok {
+				namespaced[scope] = make(map[string]dashboards.MonitoringDashboard)
+			}
+			namespaced[scope][c.Name] = c.MonitoringDashboard
+		}
+	}
+	return global, namespaced, errs
+}
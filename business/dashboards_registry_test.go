@@ -0,0 +1,144 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/config/dashboards"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+func fakeDashboardResource(namespace, name string, spec map[string]interface{}) kubernetes.IstioObject {
+	return &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.MonitoringDashboardType, APIVersion: kubernetes.ApiMonitoringVersion},
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       spec,
+	}
+}
+
+func fakeDashboardsConfigMap(namespace, name string, data map[string]string) *core_v1.ConfigMap {
+	return &core_v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"kiali.io/dashboards": "true"}},
+		Data:       data,
+	}
+}
+
+func TestBuildRuntimeDashboards(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	resources := []kubernetes.IstioObject{
+		fakeDashboardResource("istio-system", "vertx", map[string]interface{}{
+			"title":      "Vert.x",
+			"discoverOn": "vertx_http_server_connections",
+			"items": []interface{}{
+				map[string]interface{}{"chart": map[string]interface{}{"name": "Connections", "metricName": "vertx_http_server_connections", "dataType": "raw"}},
+				map[string]interface{}{"include": "go$Goroutines"},
+			},
+		}),
+		fakeDashboardResource("bookinfo", "broken", map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"chart": map[string]interface{}{"name": "Foo", "metricName": "foo", "dataType": "gauge"}},
+			},
+		}),
+	}
+	configMaps := []*core_v1.ConfigMap{
+		fakeDashboardsConfigMap("bookinfo", "dashboards", map[string]string{
+			"reviews.yaml": `
+- name: reviews
+  title: Reviews
+  items:
+  - chart:
+      name: Ratings
+      metricName: ratings_total
+      dataType: rate
+  - include: vertx
+- name: missing
+  items:
+  - include: unknown
+`,
+			"invalid.yaml": "name: [",
+		}),
+		// Same name as the resource, which takes precedence
+		fakeDashboardsConfigMap("istio-system", "vertx", map[string]string{
+			"vertx.yaml": "name: vertx\ndiscoverOn: vertx_http_server_connections\n",
+		}),
+	}
+
+	builtIn := dashboards.GetBuiltInMonitoringDashboards()
+	global, namespaced, errs := buildRuntimeDashboards(resources, configMaps, builtIn.OrganizeByName())
+
+	assert.Len(global, 1)
+	assert.Equal("Vert.x", global["vertx"].Title)
+	assert.Len(global["vertx"].Items, 2)
+	assert.False(global["vertx"].Items[0].Chart.NamespaceScoped)
+	assert.Len(namespaced, 1)
+	assert.Len(namespaced["bookinfo"], 1)
+	assert.Equal("Reviews", namespaced["bookinfo"]["reviews"].Title)
+	for _, item := range namespaced["bookinfo"]["reviews"].Items {
+		assert.True(item.Chart.NamespaceScoped)
+	}
+
+	assert.Equal([]models.DashboardValidationError{
+		{Kind: kubernetes.ConfigMapType, Namespace: "bookinfo", Name: "dashboards", Message: "invalid.yaml: failed to parse monitoring dashboard yaml data. error=yaml: line 1: did not find expected node content"},
+		{Kind: kubernetes.MonitoringDashboardType, Namespace: "bookinfo", Name: "broken", Dashboard: "broken", Message: `items[0]: invalid dataType "gauge", expected raw, rate, histogram or query`},
+		{Kind: kubernetes.ConfigMapType, Namespace: "istio-system", Name: "vertx", Dashboard: "vertx", Message: "Dashboard vertx is already defined by MonitoringDashboard istio-system/vertx"},
+		{Kind: kubernetes.ConfigMapType, Namespace: "bookinfo", Name: "dashboards", Dashboard: "missing", Message: "Included dashboards not found: unknown"},
+	}, errs)
+}
+
+func TestNamespacedRuntimeDashboards(t *testing.T) {
+	assert := assert.New(t)
+
+	setupService([]dashboards.MonitoringDashboard{*fakeDashboard("1")})
+	runtimeDashboards = &dashboardsRegistry{
+		global: map[string]dashboards.MonitoringDashboard{"dashboard2": *fakeDashboard("2")},
+		namespaced: map[string]map[string]dashboards.MonitoringDashboard{
+			"bookinfo": {"dashboard3": *fakeDashboard("3")},
+		},
+		errors: []models.DashboardValidationError{
+			{Kind: kubernetes.ConfigMapType, Namespace: "bookinfo", Name: "dashboards", Message: "invalid"},
+		},
+	}
+	defer func() { runtimeDashboards = nil }()
+	service := NewDashboardsService()
+
+	_, err := service.loadRawDashboardResource("bookinfo", "dashboard3")
+	assert.Nil(err)
+	_, err = service.loadRawDashboardResource("other", "dashboard3")
+	assert.NotNil(err)
+	for _, ns := range []string{"bookinfo", "other"} {
+		for _, name := range []string{"dashboard1", "dashboard2"} {
+			_, err = service.loadRawDashboardResource(ns, name)
+			assert.Nil(err)
+		}
+	}
+
+	metrics := []string{"my_metric_1_1", "my_metric_3_1"}
+	assert.Len(runDiscoveryMatcher(metrics, service.dashboardsFor("bookinfo")), 2)
+	assert.Len(runDiscoveryMatcher(metrics, service.dashboardsFor("other")), 1)
+
+	assert.Len(service.GetValidationErrors("bookinfo"), 1)
+	assert.Empty(service.GetValidationErrors("other"))
+}
+
+func TestRuntimeDashboardsNamespaces(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := config.NewConfig()
+	cfg.IstioNamespace = "istio-system"
+	cfg.Deployment.Namespace = "istio-system"
+	cfg.Deployment.AccessibleNamespaces = []string{"bookinfo", "travels"}
+	assert.Equal([]string{"istio-system", "bookinfo", "travels"}, runtimeDashboardsNamespaces(cfg))
+
+	cfg.Deployment.AccessibleNamespaces = []string{"**"}
+	assert.Equal([]string{meta_v1.NamespaceAll}, runtimeDashboardsNamespaces(cfg))
+
+	cfg.ExternalServices.CustomDashboards.Runtime.Namespaces = []string{"dashboards"}
+	assert.Equal([]string{"dashboards"}, runtimeDashboardsNamespaces(cfg))
+}
@@ -63,6 +63,73 @@ func TestGetDashboard(t *testing.T) {
 	assertHisto(assert, dashboard.Charts[1].Metrics, "0.99", 120)
 }
 
+func TestGetQueryDashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	chart := dashboards.MonitoringDashboardChart{
+		Name:      "Heap utilization",
+		Unit:      "%",
+		UnitScale: 100,
+		DataType:  dashboards.Query,
+		Metrics: []dashboards.MonitoringDashboardMetric{
+			{Ref: "used", Hidden: true, Query: `sum(jvm_memory_bytes_used{$filters,area="heap"}) by ($groupBy)`},
+			{DisplayName: "Heap", Query: `$used / sum(jvm_memory_bytes_max{$filters,area="heap"}) by ($groupBy)`},
+			{DisplayName: "Up", Query: `up{app="$app",pod=~"$workload-.*"}[$rateInterval]`},
+		},
+		GroupLabels: []string{"pod"},
+	}
+	service, prom := setupService([]dashboards.MonitoringDashboard{{Name: "jvm", Title: "JVM", Items: []dashboards.MonitoringDashboardItem{{Chart: chart}}}})
+
+	query := models.DashboardQuery{
+		Namespace:     "my-namespace",
+		Workload:      "my-workload",
+		LabelsFilters: map[string]string{"app": "my-app"},
+	}
+	query.FillDefaults()
+	prom.MockQuery(`(sum(jvm_memory_bytes_used{kubernetes_namespace="my-namespace",app="my-app",area="heap"}) by (pod)) / sum(jvm_memory_bytes_max{kubernetes_namespace="my-namespace",app="my-app",area="heap"}) by (pod)`, &query.RangeQuery, 0.5)
+	prom.MockQuery(`up{app="my-app",pod=~"my-workload-.*"}[1m]`, &query.RangeQuery, 1)
+
+	dashboard, err := service.GetDashboard(&api.AuthInfo{Token: ""}, query, "jvm")
+
+	assert.Nil(err)
+	assert.Len(dashboard.Charts, 1)
+	assert.Empty(dashboard.Charts[0].Error)
+	assert.Len(dashboard.Charts[0].Metrics, 2)
+	assert.Equal("Heap", dashboard.Charts[0].Metrics[0].Name)
+	assert.Equal(float64(50), dashboard.Charts[0].Metrics[0].Datapoints[0].Value)
+	assert.Equal("Up", dashboard.Charts[0].Metrics[1].Name)
+	prom.AssertNumberOfCalls(t, "FetchQueryRange", 2)
+}
+
+func TestGetNamespaceScopedQueryDashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	chart := dashboards.MonitoringDashboardChart{
+		Name:     "Pods",
+		DataType: dashboards.Query,
+		Metrics: []dashboards.MonitoringDashboardMetric{
+			{DisplayName: "Pods", Query: `count(kube_pod_info) / count(up{app="$app"})`},
+		},
+		NamespaceScoped: true,
+	}
+	service, prom := setupService([]dashboards.MonitoringDashboard{{Name: "pods", Title: "Pods", Items: []dashboards.MonitoringDashboardItem{{Chart: chart}}}})
+
+	query := models.DashboardQuery{
+		Namespace:     "my-namespace",
+		LabelsFilters: map[string]string{"app": "my-app"},
+	}
+	query.FillDefaults()
+	// Every series selector is restricted to the namespace of the dashboard
+	prom.MockQuery(`count(kube_pod_info{kubernetes_namespace=~"my-namespace"}) / count(up{app="my-app",kubernetes_namespace=~"my-namespace"})`, &query.RangeQuery, 1)
+
+	dashboard, err := service.GetDashboard(&api.AuthInfo{Token: ""}, query, "pods")
+
+	assert.Nil(err)
+	assert.Empty(dashboard.Charts[0].Error)
+	assert.Len(dashboard.Charts[0].Metrics, 1)
+	prom.AssertNumberOfCalls(t, "FetchQueryRange", 1)
+}
+
 func TestGetDashboardFromKialiNamespace(t *testing.T) {
 	assert := assert.New(t)
 
@@ -95,7 +162,7 @@ func TestGetComposedDashboard(t *testing.T) {
 	// Setup mocks
 	service, _ := setupService([]dashboards.MonitoringDashboard{*fakeDashboard("1"), *composed})
 
-	d, err := service.loadAndResolveDashboardResource("dashboard2", map[string]bool{})
+	d, err := service.loadAndResolveDashboardResource("my-namespace", "dashboard2", map[string]bool{})
 	assert.Nil(err)
 	assert.Equal("Dashboard 2", d.Title)
 	assert.Len(d.Items, 4)
@@ -114,7 +181,7 @@ func TestGetComposedDashboardSingleChart(t *testing.T) {
 	// Setup mocks
 	service, _ := setupService([]dashboards.MonitoringDashboard{*fakeDashboard("1"), *composed})
 
-	d, err := service.loadAndResolveDashboardResource("dashboard2", map[string]bool{})
+	d, err := service.loadAndResolveDashboardResource("my-namespace", "dashboard2", map[string]bool{})
 	assert.Nil(err)
 	assert.Equal("Dashboard 2", d.Title)
 	assert.Len(d.Items, 3)
@@ -132,7 +199,7 @@ func TestCircularDependency(t *testing.T) {
 	// Setup mocks
 	service, _ := setupService([]dashboards.MonitoringDashboard{*fakeDashboard("2"), *composed})
 
-	_, err := service.loadAndResolveDashboardResource("dashboard2", map[string]bool{})
+	_, err := service.loadAndResolveDashboardResource("my-namespace", "dashboard2", map[string]bool{})
 	assert.Contains(err.Error(), "circular dependency detected")
 }
 
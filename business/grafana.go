@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/kiali/kiali/config"
@@ -196,6 +197,26 @@ func getDashboardPath(name string, conn grafanaConnectionInfo, dashboardSupplier
 	return fullPath + conn.externalURLParams, nil
 }
 
+// ImportGrafanaDashboard converts the JSON model of a Grafana dashboard into a custom dashboard, reporting the panels
+// that could not be converted. The name defaults to the uid of the Grafana dashboard.
+func ImportGrafanaDashboard(name string, grafanaJSON []byte) (*models.GrafanaDashboardImport, error) {
+	converted, err := dashboards.ConvertGrafanaDashboard(name, grafanaJSON)
+	if err != nil {
+		return nil, k8serrors.NewBadRequest(err.Error())
+	}
+	out, err := converted.YAML()
+	if err != nil {
+		return nil, err
+	}
+	return &models.GrafanaDashboardImport{
+		Name:         converted.Dashboard.Name,
+		YAML:         out,
+		Charts:       len(converted.Dashboard.Items),
+		Untranslated: converted.Untranslated,
+		Warnings:     converted.Warnings,
+	}, nil
+}
+
 func findDashboard(url, searchPattern string, auth *config.Auth) ([]byte, int, error) {
 	urlParts := strings.Split(url, "?")
 	query := strings.TrimSuffix(urlParts[0], "/") + "/api/search?query=" + searchPattern
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/kiali/kiali/config"
@@ -156,3 +157,21 @@ func buildDashboardSupplier(jSon interface{}, code int, expectURL string, t *tes
 		return bytes, code, err
 	}
 }
+
+func TestImportGrafanaDashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	imported, err := ImportGrafanaDashboard("", []byte(`{"uid": "up", "title": "Up", "panels": [
+		{"id": 1, "type": "graph", "title": "Up", "targets": [{"expr": "sum(up)"}]},
+		{"id": 2, "type": "heatmap", "title": "Heat"}
+	]}`))
+	assert.Nil(err)
+	assert.Equal("up", imported.Name)
+	assert.Equal(1, imported.Charts)
+	assert.Contains(imported.YAML, "metricName: up")
+	assert.Len(imported.Untranslated, 1)
+	assert.Equal("Heat", imported.Untranslated[0].Title)
+
+	_, err = ImportGrafanaDashboard("", []byte(`{"title": "No uid"}`))
+	assert.True(errors.IsBadRequest(err))
+}
@@ -156,7 +156,7 @@ func (in *HealthService) GetNamespaceServiceHealth(namespace, rateInterval strin
 	}
 
 	// Check if namespace is cached
-	if IsNamespaceCached(namespace) {
+	if IsNamespaceCached(in.k8s, namespace, kubernetes.ServiceType) {
 		services, err = kialiCache.GetServices(namespace, nil)
 	} else {
 		services, err = in.k8s.GetServices(namespace, nil)
@@ -0,0 +1,147 @@
+package business
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// Health statuses, mirroring the ones computed by the Kiali UI
+const (
+	HealthStatusHealthy  = "Healthy"
+	HealthStatusDegraded = "Degraded"
+	HealthStatusFailure  = "Failure"
+	HealthStatusNA       = "NA"
+)
+
+var healthStatusPriority = map[string]int{
+	HealthStatusNA:       0,
+	HealthStatusHealthy:  1,
+	HealthStatusDegraded: 2,
+	HealthStatusFailure:  3,
+}
+
+// WorstHealthStatus returns the most severe of the given statuses
+func WorstHealthStatus(statuses ...string) string {
+	worst := HealthStatusNA
+	for _, s := range statuses {
+		if healthStatusPriority[s] > healthStatusPriority[worst] {
+			worst = s
+		}
+	}
+	return worst
+}
+
+// EvaluateWorkloadHealth computes the health status of a workload from its replicas and request error rates,
+// using the tolerances of the health configuration
+func EvaluateWorkloadHealth(namespace, workload string, health *models.WorkloadHealth) string {
+	return WorstHealthStatus(
+		evaluateWorkloadStatus(health.WorkloadStatus),
+		EvaluateRequestHealth(namespace, "workload", workload, health.Requests),
+	)
+}
+
+// EvaluateAppHealth computes the health status of an app from its workloads replicas and request error rates,
+// using the tolerances of the health configuration
+func EvaluateAppHealth(namespace, app string, health *models.AppHealth) string {
+	status := EvaluateRequestHealth(namespace, "app", app, health.Requests)
+	for _, ws := range health.WorkloadStatuses {
+		status = WorstHealthStatus(status, evaluateWorkloadStatus(ws))
+	}
+	return status
+}
+
+func evaluateWorkloadStatus(ws *models.WorkloadStatus) string {
+	if ws == nil {
+		return HealthStatusNA
+	}
+	if ws.DesiredReplicas == 0 && ws.AvailableReplicas == 0 {
+		// Scaled down on purpose
+		return HealthStatusNA
+	}
+	if ws.AvailableReplicas == 0 {
+		return HealthStatusFailure
+	}
+	if ws.AvailableReplicas < ws.DesiredReplicas || ws.CurrentReplicas != ws.DesiredReplicas {
+		return HealthStatusDegraded
+	}
+	if ws.SyncedProxies >= 0 && ws.SyncedProxies < ws.AvailableReplicas {
+		return HealthStatusDegraded
+	}
+	return HealthStatusHealthy
+}
+
+// EvaluateRequestHealth computes the health status from request error rates, using the tolerances
+// of the first health config rate matching the entity
+func EvaluateRequestHealth(namespace, kind, name string, requests models.RequestHealth) string {
+	rate := findHealthRate(namespace, kind, name)
+	if rate == nil {
+		return HealthStatusNA
+	}
+	status := HealthStatusNA
+	for direction, byProtocol := range map[string]map[string]map[string]float64{"inbound": requests.Inbound, "outbound": requests.Outbound} {
+		for protocol, codes := range byProtocol {
+			total := 0.0
+			for _, v := range codes {
+				total += v
+			}
+			if total == 0 {
+				continue
+			}
+			status = WorstHealthStatus(status, HealthStatusHealthy)
+			for _, tolerance := range rate.Tolerance {
+				if !healthRegexMatch(tolerance.Direction, direction) || !healthRegexMatch(tolerance.Protocol, protocol) {
+					continue
+				}
+				codeRegex, err := regexp.Compile(strings.ReplaceAll(tolerance.Code, "X", `\d`))
+				if err != nil {
+					log.Warningf("Invalid code in health config tolerance: %s", tolerance.Code)
+					continue
+				}
+				errors := 0.0
+				for code, v := range codes {
+					if codeRegex.MatchString(code) {
+						errors += v
+					}
+				}
+				if errors == 0 {
+					continue
+				}
+				ratio := float32(100 * errors / total)
+				if ratio >= tolerance.Failure {
+					status = WorstHealthStatus(status, HealthStatusFailure)
+				} else if ratio >= tolerance.Degraded {
+					status = WorstHealthStatus(status, HealthStatusDegraded)
+				}
+			}
+		}
+	}
+	return status
+}
+
+func findHealthRate(namespace, kind, name string) *config.Rate {
+	rates := config.Get().HealthConfig.Rate
+	for i := range rates {
+		r := &rates[i]
+		if healthRegexMatch(r.Namespace, namespace) && healthRegexMatch(r.Kind, kind) && healthRegexMatch(r.Name, name) {
+			return r
+		}
+	}
+	return nil
+}
+
+// healthRegexMatch matches a value against a health config expression. Empty expressions match everything.
+func healthRegexMatch(expr, value string) bool {
+	if expr == "" {
+		return true
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		log.Warningf("Invalid expression in health config: %s", expr)
+		return false
+	}
+	return re.MatchString(value)
+}
@@ -0,0 +1,49 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestEvaluateRequestHealth(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	noTraffic := models.RequestHealth{Inbound: map[string]map[string]float64{}, Outbound: map[string]map[string]float64{}}
+	assert.Equal(HealthStatusNA, EvaluateRequestHealth("bookinfo", "workload", "reviews-v1", noTraffic))
+
+	healthy := models.RequestHealth{Inbound: map[string]map[string]float64{"http": {"200": 10, "404": 0.5}}}
+	assert.Equal(HealthStatusHealthy, EvaluateRequestHealth("bookinfo", "workload", "reviews-v1", healthy))
+
+	degraded := models.RequestHealth{Inbound: map[string]map[string]float64{"http": {"200": 8.5, "404": 1.5}}}
+	assert.Equal(HealthStatusDegraded, EvaluateRequestHealth("bookinfo", "workload", "reviews-v1", degraded))
+
+	failure := models.RequestHealth{Outbound: map[string]map[string]float64{"http": {"200": 8, "503": 2}}}
+	assert.Equal(HealthStatusFailure, EvaluateRequestHealth("bookinfo", "workload", "reviews-v1", failure))
+
+	grpcFailure := models.RequestHealth{Inbound: map[string]map[string]float64{"grpc": {"0": 8, "14": 2}}}
+	assert.Equal(HealthStatusFailure, EvaluateRequestHealth("bookinfo", "workload", "reviews-v1", grpcFailure))
+}
+
+func TestEvaluateWorkloadHealth(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	health := &models.WorkloadHealth{
+		WorkloadStatus: &models.WorkloadStatus{Name: "reviews-v1", DesiredReplicas: 2, CurrentReplicas: 2, AvailableReplicas: 2, SyncedProxies: 2},
+	}
+	assert.Equal(HealthStatusHealthy, EvaluateWorkloadHealth("bookinfo", "reviews-v1", health))
+
+	health.WorkloadStatus.SyncedProxies = 1
+	assert.Equal(HealthStatusDegraded, EvaluateWorkloadHealth("bookinfo", "reviews-v1", health))
+
+	health.WorkloadStatus.AvailableReplicas = 0
+	assert.Equal(HealthStatusFailure, EvaluateWorkloadHealth("bookinfo", "reviews-v1", health))
+
+	health.WorkloadStatus = &models.WorkloadStatus{Name: "reviews-v1"}
+	assert.Equal(HealthStatusNA, EvaluateWorkloadHealth("bookinfo", "reviews-v1", health))
+}
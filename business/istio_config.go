@@ -82,6 +82,15 @@ var newSecurityConfigTypes = []string{
 	kubernetes.RequestAuthentications,
 }
 
+// getIstioObjects reads the Istio objects from the Kiali cache when the namespace and the type are cached for the user,
+// from the API otherwise.
+func (in *IstioConfigService) getIstioObjects(namespace, resourceType, labelSelector string) ([]kubernetes.IstioObject, error) {
+	if IsResourceCached(in.k8s, namespace, resourceType) {
+		return kialiCache.GetIstioObjects(namespace, resourceType, labelSelector)
+	}
+	return in.k8s.GetIstioObjects(namespace, resourceType, labelSelector)
+}
+
 // GetIstioConfigList returns a list of Istio routing objects, Mixer Rules, (etc.)
 // per a given Namespace.
 func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (models.IstioConfigList, error) {
@@ -125,12 +134,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		if criteria.Include(kubernetes.Gateways) {
 			var gg []kubernetes.IstioObject
 			var ggErr error
-			// Check if namespace is cached
-			if IsResourceCached(criteria.Namespace, kubernetes.Gateways) {
-				gg, ggErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.Gateways, criteria.LabelSelector)
-			} else {
-				gg, ggErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.Gateways, criteria.LabelSelector)
-			}
+			gg, ggErr = in.getIstioObjects(criteria.Namespace, kubernetes.Gateways, criteria.LabelSelector)
 			if ggErr == nil {
 				if isWorkloadSelector {
 					gg = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, gg)
@@ -147,12 +151,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		if criteria.Include(kubernetes.VirtualServices) {
 			var vs []kubernetes.IstioObject
 			var vsErr error
-			// Check if namespace is cached
-			if IsResourceCached(criteria.Namespace, kubernetes.VirtualServices) {
-				vs, vsErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.VirtualServices, criteria.LabelSelector)
-			} else {
-				vs, vsErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.VirtualServices, criteria.LabelSelector)
-			}
+			vs, vsErr = in.getIstioObjects(criteria.Namespace, kubernetes.VirtualServices, criteria.LabelSelector)
 			if vsErr == nil {
 				(&istioConfigList.VirtualServices).Parse(vs)
 			} else {
@@ -166,12 +165,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		if criteria.Include(kubernetes.DestinationRules) {
 			var dr []kubernetes.IstioObject
 			var drErr error
-			// Check if namespace is cached
-			if IsResourceCached(criteria.Namespace, kubernetes.DestinationRules) {
-				dr, drErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.DestinationRules, criteria.LabelSelector)
-			} else {
-				dr, drErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.DestinationRules, criteria.LabelSelector)
-			}
+			dr, drErr = in.getIstioObjects(criteria.Namespace, kubernetes.DestinationRules, criteria.LabelSelector)
 			if drErr == nil {
 				(&istioConfigList.DestinationRules).Parse(dr)
 			} else {
@@ -185,12 +179,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		if criteria.Include(kubernetes.ServiceEntries) {
 			var se []kubernetes.IstioObject
 			var seErr error
-			// Check if namespace is cached
-			if IsResourceCached(criteria.Namespace, kubernetes.ServiceEntries) {
-				se, seErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.ServiceEntries, criteria.LabelSelector)
-			} else {
-				se, seErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.ServiceEntries, criteria.LabelSelector)
-			}
+			se, seErr = in.getIstioObjects(criteria.Namespace, kubernetes.ServiceEntries, criteria.LabelSelector)
 			if seErr == nil {
 				(&istioConfigList.ServiceEntries).Parse(se)
 			} else {
@@ -204,11 +193,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		if criteria.Include(kubernetes.AuthorizationPolicies) {
 			var ap []kubernetes.IstioObject
 			var apErr error
-			if IsResourceCached(criteria.Namespace, kubernetes.AuthorizationPolicies) {
-				ap, apErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.AuthorizationPolicies, criteria.LabelSelector)
-			} else {
-				ap, apErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.AuthorizationPolicies, criteria.LabelSelector)
-			}
+			ap, apErr = in.getIstioObjects(criteria.Namespace, kubernetes.AuthorizationPolicies, criteria.LabelSelector)
 			if apErr == nil {
 				if isWorkloadSelector {
 					ap = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, ap)
@@ -225,11 +210,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		if criteria.Include(kubernetes.PeerAuthentications) {
 			var pa []kubernetes.IstioObject
 			var paErr error
-			if IsResourceCached(criteria.Namespace, kubernetes.PeerAuthentications) {
-				pa, paErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.PeerAuthentications, criteria.LabelSelector)
-			} else {
-				pa, paErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.PeerAuthentications, criteria.LabelSelector)
-			}
+			pa, paErr = in.getIstioObjects(criteria.Namespace, kubernetes.PeerAuthentications, criteria.LabelSelector)
 			if paErr == nil {
 				if isWorkloadSelector {
 					pa = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, pa)
@@ -246,11 +227,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		if criteria.Include(kubernetes.Sidecars) {
 			var sc []kubernetes.IstioObject
 			var scErr error
-			if IsResourceCached(criteria.Namespace, kubernetes.Sidecars) {
-				sc, scErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.Sidecars, criteria.LabelSelector)
-			} else {
-				sc, scErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.Sidecars, criteria.LabelSelector)
-			}
+			sc, scErr = in.getIstioObjects(criteria.Namespace, kubernetes.Sidecars, criteria.LabelSelector)
 			if scErr == nil {
 				if isWorkloadSelector {
 					sc = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, sc)
@@ -267,11 +244,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		if criteria.Include(kubernetes.WorkloadEntries) {
 			var we []kubernetes.IstioObject
 			var weErr error
-			if IsResourceCached(criteria.Namespace, kubernetes.WorkloadEntries) {
-				we, weErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.WorkloadEntries, criteria.LabelSelector)
-			} else {
-				we, weErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.WorkloadEntries, criteria.LabelSelector)
-			}
+			we, weErr = in.getIstioObjects(criteria.Namespace, kubernetes.WorkloadEntries, criteria.LabelSelector)
 			if weErr == nil {
 				(&istioConfigList.WorkloadEntries).Parse(we)
 			} else {
@@ -285,11 +258,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		if criteria.Include(kubernetes.WorkloadGroups) {
 			var wg []kubernetes.IstioObject
 			var wgErr error
-			if IsResourceCached(criteria.Namespace, kubernetes.WorkloadGroups) {
-				wg, wgErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.WorkloadGroups, criteria.LabelSelector)
-			} else {
-				wg, wgErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.WorkloadGroups, criteria.LabelSelector)
-			}
+			wg, wgErr = in.getIstioObjects(criteria.Namespace, kubernetes.WorkloadGroups, criteria.LabelSelector)
 			if wgErr == nil {
 				(&istioConfigList.WorkloadGroups).Parse(wg)
 			} else {
@@ -303,11 +272,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		if criteria.Include(kubernetes.RequestAuthentications) {
 			var ra []kubernetes.IstioObject
 			var raErr error
-			if IsResourceCached(criteria.Namespace, kubernetes.RequestAuthentications) {
-				ra, raErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.RequestAuthentications, criteria.LabelSelector)
-			} else {
-				ra, raErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.RequestAuthentications, criteria.LabelSelector)
-			}
+			ra, raErr = in.getIstioObjects(criteria.Namespace, kubernetes.RequestAuthentications, criteria.LabelSelector)
 			if raErr == nil {
 				if isWorkloadSelector {
 					ra = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, ra)
@@ -324,11 +289,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		if criteria.Include(kubernetes.EnvoyFilters) {
 			var ef []kubernetes.IstioObject
 			var efErr error
-			if IsResourceCached(criteria.Namespace, kubernetes.EnvoyFilters) {
-				ef, efErr = kialiCache.GetIstioObjects(criteria.Namespace, kubernetes.EnvoyFilters, criteria.LabelSelector)
-			} else {
-				ef, efErr = in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.EnvoyFilters, criteria.LabelSelector)
-			}
+			ef, efErr = in.getIstioObjects(criteria.Namespace, kubernetes.EnvoyFilters, criteria.LabelSelector)
 			if efErr == nil {
 				if isWorkloadSelector {
 					ef = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, ef)
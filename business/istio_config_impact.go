@@ -0,0 +1,368 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// impactTargets are the hosts and workloads an Istio object acts on
+type impactTargets struct {
+	aspect string
+	// FQDNs of the hosts, possibly wildcard ones
+	hosts map[string]bool
+	// Names of the workloads of the namespace
+	workloads map[string]bool
+	warnings  []string
+}
+
+// GetChangeImpact analyzes, before it is applied, the impact of deleting or patching an Istio object: the services
+// whose routing and the workloads whose security or sidecar scope would change, with their current traffic rates,
+// and the validation checks that would appear on the other objects of the namespace.
+func (in *IstioConfigUsageService) GetChangeImpact(namespace, objectType, object string, change models.IstioConfigChange, rateInterval string, queryTime time.Time) (*models.IstioConfigImpact, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	current, err := in.k8s.GetIstioObject(namespace, objectType, object)
+	if err != nil {
+		return nil, err
+	}
+	var draft kubernetes.IstioObject
+	switch change.Action {
+	case models.IstioConfigChangeDelete:
+	case models.IstioConfigChangePatch:
+		if draft, err = applyMergePatch(current, change.Patch); err != nil {
+			return nil, errors.NewBadRequest("Invalid patch: " + err.Error())
+		}
+	default:
+		return nil, errors.NewBadRequest(fmt.Sprintf("Invalid action %q: expected %s or %s", change.Action, models.IstioConfigChangeDelete, models.IstioConfigChangePatch))
+	}
+
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	usage := &istioConfigUsage{
+		namespace:           namespace,
+		namespaces:          make([]string, 0, len(namespaces)),
+		meshVirtualServices: []kubernetes.IstioObject{},
+		workloadLabels:      make(map[string]map[string]map[string]string),
+	}
+	for _, ns := range namespaces {
+		usage.namespaces = append(usage.namespaces, ns.Name)
+	}
+	if objectType == kubernetes.Gateways {
+		for _, ns := range usage.namespaces {
+			vss, err := in.getIstioObjects(ns, kubernetes.VirtualServices)
+			if err != nil {
+				return nil, err
+			}
+			usage.meshVirtualServices = append(usage.meshVirtualServices, vss...)
+		}
+	}
+	workloads, err := fetchWorkloads(in.businessLayer, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	usage.workloadLabels[namespace] = make(map[string]map[string]string, len(workloads))
+	for _, w := range workloads {
+		usage.workloadLabels[namespace][w.Name] = w.Labels
+	}
+
+	// The object may act on other targets once patched
+	targets := usage.impactTargets(current)
+	if draft != nil {
+		patched := usage.impactTargets(draft)
+		for h := range patched.hosts {
+			targets.hosts[h] = true
+		}
+		for w := range patched.workloads {
+			targets.workloads[w] = true
+		}
+		known := make(map[string]bool, len(targets.warnings))
+		for _, w := range targets.warnings {
+			known[w] = true
+		}
+		for _, w := range patched.warnings {
+			if !known[w] {
+				targets.warnings = append(targets.warnings, w)
+			}
+		}
+	}
+
+	// Wildcard hosts are expanded to the services of the accessible namespaces
+	rateNamespaces := []string{namespace}
+	hosts := make([]string, 0, len(targets.hosts))
+	for h := range targets.hosts {
+		hosts = append(hosts, h)
+		if strings.HasPrefix(h, "*") {
+			rateNamespaces = usage.namespaces
+		}
+	}
+	sort.Strings(hosts)
+	rates, err := in.prom.GetDestinationServiceRates(rateNamespaces, hosts, rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	outboundRates := model.Vector{}
+	if targets.aspect == models.ImpactSidecar {
+		if outboundRates, err = in.prom.GetNamespaceOutboundRates(namespace, rateInterval, queryTime); err != nil {
+			return nil, err
+		}
+	}
+
+	before, err := in.businessLayer.Validations.GetValidations(namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	after, err := in.businessLayer.Validations.GetValidationsWithChange(namespace, current, draft)
+	if err != nil {
+		return nil, err
+	}
+
+	impact := buildChangeImpact(namespace, usage.namespaces, targets, rates, outboundRates)
+	impact.ObjectType = objectType
+	impact.Name = object
+	impact.Change = change
+	impact.RateInterval = rateInterval
+	impact.NewValidations = newValidations(before, after, models.IstioValidationKey{ObjectType: models.ObjectTypeSingular[objectType], Namespace: namespace, Name: object})
+	return impact, nil
+}
+
+// impactTargets resolves what an Istio object acts on: the hosts it routes, or the workloads it selects
+func (usage *istioConfigUsage) impactTargets(o kubernetes.IstioObject) *impactTargets {
+	targets := &impactTargets{hosts: map[string]bool{}, workloads: map[string]bool{}, warnings: []string{}}
+	addHosts := func(hosts []string, namespace string) {
+		for _, h := range hosts {
+			targets.hosts[usage.fqdn(h, namespace)] = true
+		}
+	}
+	selectWorkloads := func(selector map[string]string) {
+		if len(selector) == 0 && usage.namespace == config.Get().IstioNamespace {
+			targets.warnings = append(targets.warnings, fmt.Sprintf("%s %s applies to the whole mesh: only the workloads of the namespace %s are listed", o.GetTypeMeta().Kind, o.GetObjectMeta().Name, usage.namespace))
+		}
+		s := labels.SelectorFromSet(selector)
+		for w, wLabels := range usage.workloadLabels[usage.namespace] {
+			if s.Matches(labels.Set(wLabels)) {
+				targets.workloads[w] = true
+			}
+		}
+	}
+
+	switch o.GetTypeMeta().Kind {
+	case kubernetes.VirtualServiceType:
+		targets.aspect = models.ImpactRouting
+		addHosts(append(specHosts(o), virtualServiceDestinations(o)...), usage.namespace)
+	case kubernetes.DestinationRuleType:
+		targets.aspect = models.ImpactRouting
+		if host, ok := o.GetSpec()["host"].(string); ok {
+			addHosts([]string{host}, usage.namespace)
+		}
+	case kubernetes.ServiceEntryType:
+		targets.aspect = models.ImpactRouting
+		addHosts(specHosts(o), usage.namespace)
+	case kubernetes.GatewayType:
+		// The routes of the VirtualServices bound to the gateway
+		targets.aspect = models.ImpactRouting
+		for _, vs := range usage.meshVirtualServices {
+			for _, gw := range virtualServiceGateways(vs) {
+				h := kubernetes.ParseGatewayAsHost(gw, vs.GetObjectMeta().Namespace, "")
+				if h.Namespace == usage.namespace && h.Service == o.GetObjectMeta().Name {
+					addHosts(virtualServiceDestinations(vs), vs.GetObjectMeta().Namespace)
+					break
+				}
+			}
+		}
+	case kubernetes.AuthorizationPoliciesType, kubernetes.PeerAuthenticationsType, kubernetes.RequestAuthenticationsType:
+		targets.aspect = models.ImpactSecurity
+		selectWorkloads(common.GetSelectorLabels(o))
+	case kubernetes.SidecarType, kubernetes.EnvoyFilterType:
+		targets.aspect = models.ImpactSidecar
+		selectWorkloads(common.GetWorkloadSelectorLabels(o))
+	default:
+		targets.warnings = append(targets.warnings, fmt.Sprintf("The impact of %s changes is not analyzed", o.GetTypeMeta().Kind))
+	}
+	return targets
+}
+
+func buildChangeImpact(namespace string, namespaces []string, targets *impactTargets, rates, outboundRates model.Vector) *models.IstioConfigImpact {
+	impact := &models.IstioConfigImpact{
+		Namespace: namespace,
+		Services:  []models.ImpactedService{},
+		Workloads: []models.ImpactedWorkload{},
+		Warnings:  targets.warnings,
+	}
+
+	// The traffic of the services of inaccessible namespaces is not reported. External hosts have no namespace:
+	// their traffic is reported when they are targeted.
+	accessible := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		accessible[ns] = true
+	}
+	targetedHost := func(host string) bool {
+		for h := range targets.hosts {
+			if h == host || h == "*" || (strings.HasPrefix(h, "*") && kubernetes.HostWithinWildcardHost(host, h)) {
+				return true
+			}
+		}
+		return false
+	}
+	accessibleRates := model.Vector{}
+	for _, s := range rates {
+		serviceNamespace := string(s.Metric["destination_service_namespace"])
+		external := serviceNamespace == "" || serviceNamespace == "unknown"
+		if accessible[serviceNamespace] || (external && targetedHost(string(s.Metric["destination_service"]))) {
+			accessibleRates = append(accessibleRates, s)
+		}
+	}
+	rates = accessibleRates
+
+	hostRates := reportedRates(rates, func(m model.Metric) string { return string(m["destination_service"]) })
+	hosts := make(map[string]bool)
+	for h := range targets.hosts {
+		if !strings.HasPrefix(h, "*") {
+			hosts[h] = true
+			continue
+		}
+		// Wildcard hosts are expanded to the accessible hosts that received traffic
+		for called := range hostRates {
+			if h == "*" || kubernetes.HostWithinWildcardHost(called, h) {
+				hosts[called] = true
+			}
+		}
+	}
+	for _, h := range sortedKeys(hosts) {
+		service := models.ImpactedService{Host: h, Aspect: targets.aspect, Rate: hostRates[h]}
+		if parsed := kubernetes.ParseHost(h, "", ""); parsed.CompleteInput {
+			service.Namespace = parsed.Namespace
+			service.Name = parsed.Service
+		}
+		impact.Services = append(impact.Services, service)
+	}
+
+	workloadRates := reportedRates(rates, func(m model.Metric) string {
+		return string(m["destination_workload_namespace"]) + "/" + string(m["destination_workload"])
+	})
+	if targets.aspect == models.ImpactSidecar {
+		workloadRates = reportedRates(outboundRates, func(m model.Metric) string {
+			return namespace + "/" + string(m["source_workload"])
+		})
+	}
+	for _, w := range sortedKeys(targets.workloads) {
+		impact.Workloads = append(impact.Workloads, models.ImpactedWorkload{
+			Namespace: namespace,
+			Name:      w,
+			Aspect:    targets.aspect,
+			Rate:      workloadRates[namespace+"/"+w],
+		})
+	}
+	return impact
+}
+
+// reportedRates sums the rates by key. Both ends of a meshed connection report it: the highest sum of a reporter is kept.
+func reportedRates(rates model.Vector, key func(model.Metric) string) map[string]float64 {
+	byReporter := make(map[string]map[string]float64)
+	for _, s := range rates {
+		k := key(s.Metric)
+		if _, ok := byReporter[k]; !ok {
+			byReporter[k] = make(map[string]float64)
+		}
+		byReporter[k][string(s.Metric["reporter"])] += float64(s.Value)
+	}
+	result := make(map[string]float64, len(byReporter))
+	for k, reporters := range byReporter {
+		for _, rate := range reporters {
+			if rate > result[k] {
+				result[k] = rate
+			}
+		}
+	}
+	return result
+}
+
+// newValidations returns the checks that appear on other objects than the changed one
+func newValidations(before, after models.IstioValidations, changed models.IstioValidationKey) models.IstioValidations {
+	result := models.IstioValidations{}
+	for key, validation := range after {
+		if key == changed {
+			continue
+		}
+		existing := make(map[string]bool)
+		if previous, ok := before[key]; ok {
+			for _, c := range previous.Checks {
+				existing[c.Path+" "+c.Message] = true
+			}
+		}
+		checks := []*models.IstioCheck{}
+		for _, c := range validation.Checks {
+			if !existing[c.Path+" "+c.Message] {
+				checks = append(checks, c)
+			}
+		}
+		if len(checks) > 0 {
+			result[key] = &models.IstioValidation{
+				Name:       validation.Name,
+				ObjectType: validation.ObjectType,
+				Valid:      validation.Valid,
+				Checks:     checks,
+				References: validation.References,
+			}
+		}
+	}
+	return result
+}
+
+// applyMergePatch returns a copy of an Istio object with a JSON merge patch (RFC 7386) applied
+func applyMergePatch(o kubernetes.IstioObject, patch string) (kubernetes.IstioObject, error) {
+	original, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	var document, p interface{}
+	if err := json.Unmarshal(original, &document); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(patch), &p); err != nil {
+		return nil, err
+	}
+	patched, err := json.Marshal(mergePatch(document, p))
+	if err != nil {
+		return nil, err
+	}
+	result := &kubernetes.GenericIstioObject{}
+	if err := json.Unmarshal(patched, result); err != nil {
+		return nil, err
+	}
+	result.SetTypeMeta(o.GetTypeMeta())
+	return result, nil
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
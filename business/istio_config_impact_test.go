@@ -0,0 +1,193 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.VirtualServiceType, APIVersion: kubernetes.ApiNetworkingVersion},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec: map[string]interface{}{
+			"hosts":    []interface{}{"reviews"},
+			"gateways": []interface{}{"bookinfo-gateway"},
+		},
+	}
+	patched, err := applyMergePatch(vs, `{"spec": {"hosts": ["reviews", "ratings"], "gateways": null}}`)
+	assert.NoError(err)
+	assert.Equal(vs.GetTypeMeta(), patched.GetTypeMeta())
+	assert.Equal("reviews", patched.GetObjectMeta().Name)
+	assert.Equal(map[string]interface{}{"hosts": []interface{}{"reviews", "ratings"}}, patched.GetSpec())
+	// The original object is left untouched
+	assert.Len(vs.Spec, 2)
+
+	_, err = applyMergePatch(vs, `{"spec":`)
+	assert.Error(err)
+}
+
+func TestBuildChangeImpact(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	rates := model.Vector{
+		&model.Sample{Metric: model.Metric{
+			"reporter":                       "source",
+			"destination_service":            "reviews.bookinfo.svc.cluster.local",
+			"destination_service_namespace":  "bookinfo",
+			"destination_workload":           "reviews-v1",
+			"destination_workload_namespace": "bookinfo",
+		}, Value: 4},
+		&model.Sample{Metric: model.Metric{
+			"reporter":                       "destination",
+			"destination_service":            "reviews.bookinfo.svc.cluster.local",
+			"destination_service_namespace":  "bookinfo",
+			"destination_workload":           "reviews-v1",
+			"destination_workload_namespace": "bookinfo",
+		}, Value: 5},
+		&model.Sample{Metric: model.Metric{
+			"reporter":                       "source",
+			"destination_service":            "api.example.com",
+			"destination_service_namespace":  "unknown",
+			"destination_workload":           "unknown",
+			"destination_workload_namespace": "unknown",
+		}, Value: 2},
+		// Services of inaccessible namespaces are not reported
+		&model.Sample{Metric: model.Metric{
+			"reporter":                       "source",
+			"destination_service":            "vault.secrets.svc.cluster.local",
+			"destination_service_namespace":  "secrets",
+			"destination_workload":           "vault",
+			"destination_workload_namespace": "secrets",
+		}, Value: 7},
+	}
+	usage := &istioConfigUsage{
+		namespace:  "bookinfo",
+		namespaces: []string{"bookinfo"},
+		workloadLabels: map[string]map[string]map[string]string{
+			"bookinfo": {
+				"reviews-v1":     {"app": "reviews", "version": "v1"},
+				"productpage-v1": {"app": "productpage", "version": "v1"},
+			},
+		},
+	}
+
+	// Patching the hosts of a VirtualService impacts the services before and after the change
+	vs := &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.VirtualServiceType},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec:       map[string]interface{}{"hosts": []interface{}{"reviews"}},
+	}
+	targets := usage.impactTargets(vs)
+	for h := range usage.impactTargets(&kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.VirtualServiceType},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec:       map[string]interface{}{"hosts": []interface{}{"*.example.com"}},
+	}).hosts {
+		targets.hosts[h] = true
+	}
+	impact := buildChangeImpact("bookinfo", usage.namespaces, targets, rates, model.Vector{})
+	assert.Equal([]models.ImpactedService{
+		{Host: "api.example.com", Aspect: models.ImpactRouting, Rate: 2},
+		{Host: "reviews.bookinfo.svc.cluster.local", Namespace: "bookinfo", Name: "reviews", Aspect: models.ImpactRouting, Rate: 5},
+	}, impact.Services)
+	assert.Empty(impact.Workloads)
+
+	// The "*" host expands to the accessible hosts only
+	targets = usage.impactTargets(&kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.VirtualServiceType},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "all", Namespace: "bookinfo"},
+		Spec:       map[string]interface{}{"hosts": []interface{}{"*"}},
+	})
+	impact = buildChangeImpact("bookinfo", usage.namespaces, targets, rates, model.Vector{})
+	assert.Equal([]models.ImpactedService{
+		{Host: "api.example.com", Aspect: models.ImpactRouting, Rate: 2},
+		{Host: "reviews.bookinfo.svc.cluster.local", Namespace: "bookinfo", Name: "reviews", Aspect: models.ImpactRouting, Rate: 5},
+	}, impact.Services)
+
+	// A policy impacts the workloads it selects
+	ap := &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.AuthorizationPoliciesType},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec:       map[string]interface{}{"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "reviews"}}},
+	}
+	impact = buildChangeImpact("bookinfo", usage.namespaces, usage.impactTargets(ap), rates, model.Vector{})
+	assert.Empty(impact.Services)
+	assert.Equal([]models.ImpactedWorkload{
+		{Namespace: "bookinfo", Name: "reviews-v1", Aspect: models.ImpactSecurity, Rate: 5},
+	}, impact.Workloads)
+
+	// A Sidecar without selector impacts the outbound traffic of all the workloads
+	sidecar := &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.SidecarType},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "default", Namespace: "bookinfo"},
+		Spec:       map[string]interface{}{},
+	}
+	outbound := model.Vector{
+		&model.Sample{Metric: model.Metric{"reporter": "source", "source_workload": "productpage-v1"}, Value: 3},
+	}
+	impact = buildChangeImpact("bookinfo", usage.namespaces, usage.impactTargets(sidecar), rates, outbound)
+	assert.Equal([]models.ImpactedWorkload{
+		{Namespace: "bookinfo", Name: "productpage-v1", Aspect: models.ImpactSidecar, Rate: 3},
+		{Namespace: "bookinfo", Name: "reviews-v1", Aspect: models.ImpactSidecar, Rate: 0},
+	}, impact.Workloads)
+}
+
+func TestNewValidations(t *testing.T) {
+	assert := assert.New(t)
+
+	changed := models.IstioValidationKey{ObjectType: "destinationrule", Namespace: "bookinfo", Name: "reviews"}
+	vsKey := models.IstioValidationKey{ObjectType: "virtualservice", Namespace: "bookinfo", Name: "reviews"}
+	existing := &models.IstioCheck{Message: "existing", Path: "spec/hosts[0]"}
+	subsetNotFound := &models.IstioCheck{Message: "Subset not found", Path: "spec/http[0]/route[0]/destination"}
+
+	before := models.IstioValidations{
+		changed: {Name: "reviews", ObjectType: "destinationrule", Valid: true, Checks: []*models.IstioCheck{}},
+		vsKey:   {Name: "reviews", ObjectType: "virtualservice", Valid: true, Checks: []*models.IstioCheck{existing}},
+	}
+	after := models.IstioValidations{
+		changed: {Name: "reviews", ObjectType: "destinationrule", Valid: false, Checks: []*models.IstioCheck{existing}},
+		vsKey:   {Name: "reviews", ObjectType: "virtualservice", Valid: false, Checks: []*models.IstioCheck{existing, subsetNotFound}},
+	}
+
+	validations := newValidations(before, after, changed)
+	assert.Len(validations, 1)
+	assert.Equal([]*models.IstioCheck{subsetNotFound}, validations[vsKey].Checks)
+	assert.False(validations[vsKey].Valid)
+}
+
+func TestApplyDraftsDeleted(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	ap := &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.AuthorizationPoliciesType},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "allow-nothing", Namespace: "bookinfo"},
+	}
+	dr := &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.DestinationRuleType},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+	}
+	gw := &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.GatewayType},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo-gateway", Namespace: "bookinfo"},
+	}
+	details := kubernetes.IstioDetails{DestinationRules: []kubernetes.IstioObject{dr}}
+	mtlsDetails := kubernetes.MTLSDetails{DestinationRules: []kubernetes.IstioObject{dr}}
+	rbacDetails := kubernetes.RBACDetails{AuthorizationPolicies: []kubernetes.IstioObject{ap}}
+
+	gateways := applyDrafts(nil, []kubernetes.IstioObject{ap, dr, gw}, &details, &mtlsDetails, &rbacDetails, [][]kubernetes.IstioObject{{gw}})
+	assert.Empty(details.DestinationRules)
+	assert.Empty(mtlsDetails.DestinationRules)
+	assert.Empty(rbacDetails.AuthorizationPolicies)
+	assert.Equal([][]kubernetes.IstioObject{{}}, gateways)
+}
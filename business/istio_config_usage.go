@@ -0,0 +1,362 @@
+package business
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// IstioConfigUsageService finds the Istio config that has no effect, unlike the IstioValidationsService which finds
+// the config that is broken
+type IstioConfigUsageService struct {
+	k8s           kubernetes.ClientInterface
+	prom          prometheus.ClientInterface
+	businessLayer *Layer
+}
+
+// istioConfigUsage is the config of a namespace, with what it may apply to
+type istioConfigUsage struct {
+	namespace             string
+	namespaces            []string
+	virtualServices       []kubernetes.IstioObject
+	destinationRules      []kubernetes.IstioObject
+	serviceEntries        []kubernetes.IstioObject
+	gateways              []kubernetes.IstioObject
+	authorizationPolicies []kubernetes.IstioObject
+	// VirtualServices of all the accessible namespaces, which may bind the gateways
+	meshVirtualServices []kubernetes.IstioObject
+	// Labels of the workloads, by namespace and name
+	workloadLabels map[string]map[string]map[string]string
+}
+
+// GetUnusedIstioConfig cross-references the Istio config of a namespace with the traffic observed over the rate
+// interval and the registry. Reported as unused are:
+// - VirtualServices whose hosts and route destinations received no traffic
+// - DestinationRule subsets whose workloads received no traffic for the rule host
+// - ServiceEntries whose hosts received no traffic
+// - Gateways bound by no VirtualService of the accessible namespaces
+// - AuthorizationPolicies matching no workload
+func (in *IstioConfigUsageService) GetUnusedIstioConfig(namespace, rateInterval string, queryTime time.Time) (*models.UnusedIstioConfigReport, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	usage := &istioConfigUsage{
+		namespace:           namespace,
+		namespaces:          make([]string, 0, len(namespaces)),
+		meshVirtualServices: []kubernetes.IstioObject{},
+		workloadLabels:      make(map[string]map[string]map[string]string),
+	}
+	for _, ns := range namespaces {
+		usage.namespaces = append(usage.namespaces, ns.Name)
+	}
+
+	for resourceType, objects := range map[string]*[]kubernetes.IstioObject{
+		kubernetes.VirtualServices:       &usage.virtualServices,
+		kubernetes.DestinationRules:      &usage.destinationRules,
+		kubernetes.ServiceEntries:        &usage.serviceEntries,
+		kubernetes.Gateways:              &usage.gateways,
+		kubernetes.AuthorizationPolicies: &usage.authorizationPolicies,
+	} {
+		if *objects, err = in.getIstioObjects(namespace, resourceType); err != nil {
+			return nil, err
+		}
+	}
+	if len(usage.gateways) > 0 {
+		for _, ns := range usage.namespaces {
+			vss, err := in.getIstioObjects(ns, kubernetes.VirtualServices)
+			if err != nil {
+				return nil, err
+			}
+			usage.meshVirtualServices = append(usage.meshVirtualServices, vss...)
+		}
+	}
+
+	// The workloads of the namespace, and of the accessible namespaces of the DestinationRule hosts
+	accessible := make(map[string]bool, len(usage.namespaces))
+	for _, ns := range usage.namespaces {
+		accessible[ns] = true
+	}
+	workloadNamespaces := map[string]bool{namespace: true}
+	for _, dr := range usage.destinationRules {
+		if host, ok := dr.GetSpec()["host"].(string); ok {
+			if h := kubernetes.GetHost(host, namespace, config.Get().ExternalServices.Istio.IstioIdentityDomain, usage.namespaces); h.CompleteInput && accessible[h.Namespace] {
+				workloadNamespaces[h.Namespace] = true
+			}
+		}
+	}
+	for ns := range workloadNamespaces {
+		workloads, err := fetchWorkloads(in.businessLayer, ns, "")
+		if err != nil {
+			return nil, err
+		}
+		usage.workloadLabels[ns] = make(map[string]map[string]string, len(workloads))
+		for _, w := range workloads {
+			usage.workloadLabels[ns][w.Name] = w.Labels
+		}
+	}
+
+	rates, err := in.prom.GetDestinationServiceRates([]string{namespace}, usage.referencedHosts(), rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UnusedIstioConfigReport{
+		Namespace:    namespace,
+		RateInterval: rateInterval,
+		Unused:       findUnusedIstioConfig(usage, rates),
+	}, nil
+}
+
+func (in *IstioConfigUsageService) getIstioObjects(namespace, resourceType string) ([]kubernetes.IstioObject, error) {
+	if IsResourceCached(in.k8s, namespace, resourceType) {
+		return kialiCache.GetIstioObjects(namespace, resourceType, "")
+	}
+	return in.k8s.GetIstioObjects(namespace, resourceType, "")
+}
+
+func findUnusedIstioConfig(usage *istioConfigUsage, rates model.Vector) []models.UnusedIstioConfig {
+	// The workloads that received traffic, by destination service
+	called := make(map[string]map[string]bool)
+	for _, s := range rates {
+		service := string(s.Metric["destination_service"])
+		if _, ok := called[service]; !ok {
+			called[service] = make(map[string]bool)
+		}
+		called[service][string(s.Metric["destination_workload_namespace"])+"/"+string(s.Metric["destination_workload"])] = true
+	}
+
+	unused := []models.UnusedIstioConfig{}
+	add := func(resourceType string, o kubernetes.IstioObject, subset, reason string) {
+		unused = append(unused, models.UnusedIstioConfig{
+			ObjectType: models.ObjectTypeSingular[resourceType],
+			Name:       o.GetObjectMeta().Name,
+			Subset:     subset,
+			Reason:     reason,
+		})
+	}
+
+	for _, vs := range usage.virtualServices {
+		used := false
+		for _, host := range append(specHosts(vs), virtualServiceDestinations(vs)...) {
+			used = used || hostCalled(usage.fqdn(host, usage.namespace), called)
+		}
+		if !used {
+			add(kubernetes.VirtualServices, vs, "", "Neither its hosts nor its route destinations received traffic")
+		}
+	}
+
+	for _, dr := range usage.destinationRules {
+		host, _ := dr.GetSpec()["host"].(string)
+		h := kubernetes.GetHost(host, usage.namespace, config.Get().ExternalServices.Istio.IstioIdentityDomain, usage.namespaces)
+		workloadLabels, found := usage.workloadLabels[h.Namespace]
+		if strings.HasPrefix(host, "*") || !h.CompleteInput || !found {
+			// The subsets of wildcard hosts, external hosts and hosts of inaccessible namespaces can't be resolved
+			continue
+		}
+		subsets, _ := dr.GetSpec()["subsets"].([]interface{})
+		for _, s := range subsets {
+			subset, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := subset["name"].(string)
+			subsetLabels := make(map[string]string)
+			if sl, ok := subset["labels"].(map[string]interface{}); ok {
+				for k, v := range sl {
+					if value, ok := v.(string); ok {
+						subsetLabels[k] = value
+					}
+				}
+			}
+			selector := labels.SelectorFromSet(subsetLabels)
+			matched, used := false, false
+			for w, wLabels := range workloadLabels {
+				if selector.Matches(labels.Set(wLabels)) {
+					matched = true
+					used = used || called[h.String()][h.Namespace+"/"+w]
+				}
+			}
+			if !matched {
+				add(kubernetes.DestinationRules, dr, name, "No workload matches the subset labels")
+			} else if !used {
+				add(kubernetes.DestinationRules, dr, name, "The workloads of the subset received no traffic for the host")
+			}
+		}
+	}
+
+	for _, se := range usage.serviceEntries {
+		used := false
+		for _, host := range specHosts(se) {
+			used = used || hostCalled(usage.fqdn(host, usage.namespace), called)
+		}
+		if !used {
+			add(kubernetes.ServiceEntries, se, "", "None of its hosts received traffic")
+		}
+	}
+
+	bound := make(map[string]bool)
+	for _, vs := range usage.meshVirtualServices {
+		for _, gw := range virtualServiceGateways(vs) {
+			h := kubernetes.ParseGatewayAsHost(gw, vs.GetObjectMeta().Namespace, "")
+			bound[h.Namespace+"/"+h.Service] = true
+		}
+	}
+	for _, gw := range usage.gateways {
+		if !bound[usage.namespace+"/"+gw.GetObjectMeta().Name] {
+			add(kubernetes.Gateways, gw, "", "No VirtualService is bound to it")
+		}
+	}
+
+	for _, ap := range usage.authorizationPolicies {
+		if common.HasSelector(ap) {
+			selector := labels.SelectorFromSet(common.GetSelectorLabels(ap))
+			matched := false
+			for _, wLabels := range usage.workloadLabels[usage.namespace] {
+				matched = matched || selector.Matches(labels.Set(wLabels))
+			}
+			if !matched {
+				add(kubernetes.AuthorizationPolicies, ap, "", "Its selector matches no workload")
+			}
+		} else if usage.namespace != config.Get().IstioNamespace && len(usage.workloadLabels[usage.namespace]) == 0 {
+			// Policies of the root namespace apply to the whole mesh
+			add(kubernetes.AuthorizationPolicies, ap, "", "The namespace has no workload")
+		}
+	}
+
+	sort.SliceStable(unused, func(i, j int) bool {
+		if unused[i].ObjectType != unused[j].ObjectType {
+			return unused[i].ObjectType < unused[j].ObjectType
+		}
+		return unused[i].Name < unused[j].Name
+	})
+	return unused
+}
+
+// referencedHosts returns the FQDNs of the hosts referenced by the routing config of the namespace, which may
+// belong to other namespaces
+func (usage *istioConfigUsage) referencedHosts() []string {
+	referenced := make(map[string]bool)
+	for _, vs := range usage.virtualServices {
+		for _, host := range append(specHosts(vs), virtualServiceDestinations(vs)...) {
+			referenced[usage.fqdn(host, usage.namespace)] = true
+		}
+	}
+	for _, dr := range usage.destinationRules {
+		if host, ok := dr.GetSpec()["host"].(string); ok {
+			referenced[usage.fqdn(host, usage.namespace)] = true
+		}
+	}
+	for _, se := range usage.serviceEntries {
+		for _, host := range specHosts(se) {
+			referenced[usage.fqdn(host, usage.namespace)] = true
+		}
+	}
+	hosts := make([]string, 0, len(referenced))
+	for h := range referenced {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// fqdn resolves a host relative to the namespace of its object, as reported in the telemetry
+func (usage *istioConfigUsage) fqdn(host, namespace string) string {
+	if strings.HasPrefix(host, "*") {
+		return host
+	}
+	return kubernetes.GetHost(host, namespace, config.Get().ExternalServices.Istio.IstioIdentityDomain, usage.namespaces).String()
+}
+
+// hostCalled tells whether a host, possibly a wildcard one, received traffic
+func hostCalled(host string, called map[string]map[string]bool) bool {
+	if host == "*" {
+		return len(called) > 0
+	}
+	if strings.HasPrefix(host, "*") {
+		for h := range called {
+			if kubernetes.HostWithinWildcardHost(h, host) {
+				return true
+			}
+		}
+		return false
+	}
+	_, found := called[host]
+	return found
+}
+
+func specHosts(o kubernetes.IstioObject) []string {
+	hosts := []string{}
+	if specHosts, ok := o.GetSpec()["hosts"].([]interface{}); ok {
+		for _, h := range specHosts {
+			if host, ok := h.(string); ok {
+				hosts = append(hosts, host)
+			}
+		}
+	}
+	return hosts
+}
+
+// virtualServiceDestinations returns the hosts of the route destinations of a VirtualService
+func virtualServiceDestinations(vs kubernetes.IstioObject) []string {
+	hosts := []string{}
+	for _, protocol := range []string{"http", "tcp", "tls"} {
+		routes, _ := vs.GetSpec()[protocol].([]interface{})
+		for _, r := range routes {
+			route, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			destinations, _ := route["route"].([]interface{})
+			for _, d := range destinations {
+				if destination, ok := d.(map[string]interface{})["destination"].(map[string]interface{}); ok {
+					if host, ok := destination["host"].(string); ok {
+						hosts = append(hosts, host)
+					}
+				}
+			}
+		}
+	}
+	return hosts
+}
+
+// virtualServiceGateways returns the gateways a VirtualService is bound to, at the top level or in its http matches
+func virtualServiceGateways(vs kubernetes.IstioObject) []string {
+	gateways := []string{}
+	appendGateways := func(gws interface{}) {
+		if list, ok := gws.([]interface{}); ok {
+			for _, g := range list {
+				if gw, ok := g.(string); ok && gw != "mesh" {
+					gateways = append(gateways, gw)
+				}
+			}
+		}
+	}
+	appendGateways(vs.GetSpec()["gateways"])
+	routes, _ := vs.GetSpec()["http"].([]interface{})
+	for _, r := range routes {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		matches, _ := route["match"].([]interface{})
+		for _, m := range matches {
+			if match, ok := m.(map[string]interface{}); ok {
+				appendGateways(match["gateways"])
+			}
+		}
+	}
+	return gateways
+}
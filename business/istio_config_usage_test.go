@@ -0,0 +1,141 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+func fakeIstioObject(namespace, name string, spec map[string]interface{}) kubernetes.IstioObject {
+	return &kubernetes.GenericIstioObject{ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: namespace}, Spec: spec}
+}
+
+func TestFindUnusedIstioConfig(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	rates := model.Vector{
+		&model.Sample{Metric: model.Metric{
+			"destination_service":            "reviews.bookinfo.svc.cluster.local",
+			"destination_workload":           "reviews-v1",
+			"destination_workload_namespace": "bookinfo",
+		}, Value: 1},
+		&model.Sample{Metric: model.Metric{
+			"destination_service":            "productpage.bookinfo.svc.cluster.local",
+			"destination_workload":           "productpage-v1",
+			"destination_workload_namespace": "bookinfo",
+		}, Value: 1},
+		&model.Sample{Metric: model.Metric{
+			"destination_service":            "api.example.com",
+			"destination_workload":           "unknown",
+			"destination_workload_namespace": "unknown",
+		}, Value: 1},
+	}
+
+	usage := &istioConfigUsage{
+		namespace:  "bookinfo",
+		namespaces: []string{"bookinfo", "istio-system"},
+		virtualServices: []kubernetes.IstioObject{
+			fakeIstioObject("bookinfo", "reviews", map[string]interface{}{
+				"hosts": []interface{}{"reviews"},
+			}),
+			fakeIstioObject("bookinfo", "bookinfo", map[string]interface{}{
+				"hosts":    []interface{}{"bookinfo.example.com"},
+				"gateways": []interface{}{"bookinfo-gateway"},
+				"http": []interface{}{map[string]interface{}{
+					"route": []interface{}{map[string]interface{}{"destination": map[string]interface{}{"host": "productpage"}}},
+				}},
+			}),
+			fakeIstioObject("bookinfo", "ratings", map[string]interface{}{
+				"hosts": []interface{}{"ratings.bookinfo"},
+			}),
+		},
+		destinationRules: []kubernetes.IstioObject{
+			fakeIstioObject("bookinfo", "reviews", map[string]interface{}{
+				"host": "reviews.bookinfo.svc.cluster.local",
+				"subsets": []interface{}{
+					map[string]interface{}{"name": "v1", "labels": map[string]interface{}{"version": "v1"}},
+					map[string]interface{}{"name": "v2", "labels": map[string]interface{}{"version": "v2"}},
+					map[string]interface{}{"name": "v3", "labels": map[string]interface{}{"version": "v3"}},
+				},
+			}),
+			fakeIstioObject("bookinfo", "all", map[string]interface{}{
+				"host":    "*.bookinfo.svc.cluster.local",
+				"subsets": []interface{}{map[string]interface{}{"name": "v9", "labels": map[string]interface{}{"version": "v9"}}},
+			}),
+		},
+		serviceEntries: []kubernetes.IstioObject{
+			fakeIstioObject("bookinfo", "example", map[string]interface{}{"hosts": []interface{}{"*.example.com"}}),
+			fakeIstioObject("bookinfo", "google", map[string]interface{}{"hosts": []interface{}{"www.google.com"}}),
+		},
+		gateways: []kubernetes.IstioObject{
+			fakeIstioObject("bookinfo", "bookinfo-gateway", map[string]interface{}{}),
+			fakeIstioObject("bookinfo", "shared-gateway", map[string]interface{}{}),
+			fakeIstioObject("bookinfo", "old-gateway", map[string]interface{}{}),
+		},
+		authorizationPolicies: []kubernetes.IstioObject{
+			fakeIstioObject("bookinfo", "reviews", map[string]interface{}{
+				"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "reviews"}},
+			}),
+			fakeIstioObject("bookinfo", "details", map[string]interface{}{
+				"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "details"}},
+			}),
+			fakeIstioObject("bookinfo", "namespace-wide", map[string]interface{}{}),
+		},
+		meshVirtualServices: []kubernetes.IstioObject{
+			fakeIstioObject("bookinfo", "bookinfo", map[string]interface{}{
+				"gateways": []interface{}{"bookinfo-gateway", "mesh"},
+			}),
+			fakeIstioObject("istio-system", "shared", map[string]interface{}{
+				"http": []interface{}{map[string]interface{}{
+					"match": []interface{}{map[string]interface{}{"gateways": []interface{}{"bookinfo/shared-gateway"}}},
+				}},
+			}),
+		},
+		workloadLabels: map[string]map[string]map[string]string{
+			"bookinfo": {
+				"reviews-v1":     {"app": "reviews", "version": "v1"},
+				"reviews-v2":     {"app": "reviews", "version": "v2"},
+				"productpage-v1": {"app": "productpage", "version": "v1"},
+			},
+		},
+	}
+
+	unused := findUnusedIstioConfig(usage, rates)
+	assert.Equal([]models.UnusedIstioConfig{
+		{ObjectType: "authorizationpolicy", Name: "details", Reason: "Its selector matches no workload"},
+		{ObjectType: "destinationrule", Name: "reviews", Subset: "v2", Reason: "The workloads of the subset received no traffic for the host"},
+		{ObjectType: "destinationrule", Name: "reviews", Subset: "v3", Reason: "No workload matches the subset labels"},
+		{ObjectType: "gateway", Name: "old-gateway", Reason: "No VirtualService is bound to it"},
+		{ObjectType: "serviceentry", Name: "google", Reason: "None of its hosts received traffic"},
+		{ObjectType: "virtualservice", Name: "ratings", Reason: "Neither its hosts nor its route destinations received traffic"},
+	}, unused)
+}
+
+func TestFindUnusedIstioConfigEmptyNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	usage := &istioConfigUsage{
+		namespace:  "legacy",
+		namespaces: []string{"legacy"},
+		authorizationPolicies: []kubernetes.IstioObject{
+			fakeIstioObject("legacy", "allow-nothing", map[string]interface{}{}),
+		},
+		workloadLabels: map[string]map[string]map[string]string{"legacy": {}},
+	}
+	assert.Equal([]models.UnusedIstioConfig{
+		{ObjectType: "authorizationpolicy", Name: "allow-nothing", Reason: "The namespace has no workload"},
+	}, findUnusedIstioConfig(usage, model.Vector{}))
+
+	// Policies of the root namespace apply to the whole mesh
+	usage.namespace = "istio-system"
+	usage.workloadLabels = map[string]map[string]map[string]string{"istio-system": {}}
+	assert.Empty(findUnusedIstioConfig(usage, model.Vector{}))
+}
@@ -2,6 +2,7 @@ package business
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	apps_v1 "k8s.io/api/apps/v1"
@@ -27,6 +28,37 @@ type ObjectChecker interface {
 // GetValidations returns an IstioValidations object with all the checks found when running
 // all the enabled checkers. If service is "" then the whole namespace is validated.
 func (in *IstioValidationsService) GetValidations(namespace, service string) (models.IstioValidations, error) {
+	return in.getValidations(namespace, service, nil, nil)
+}
+
+// ValidateDrafts runs the checkers as if the given objects were applied in the namespace, replacing existing
+// objects with the same type and name, and returns the validations of these objects only.
+func (in *IstioValidationsService) ValidateDrafts(namespace string, drafts []kubernetes.IstioObject) (models.IstioValidations, error) {
+	validations, err := in.getValidations(namespace, "", drafts, nil)
+	if err != nil {
+		return nil, err
+	}
+	draftValidations := models.IstioValidations{}
+	for _, d := range drafts {
+		for key, v := range validations.FilterByKey(strings.ToLower(d.GetTypeMeta().Kind), d.GetObjectMeta().Name) {
+			if key.Namespace == namespace {
+				draftValidations[key] = v
+			}
+		}
+	}
+	return draftValidations, nil
+}
+
+// GetValidationsWithChange returns the validations of the namespace as if an object were replaced by a draft, or
+// deleted when the draft is nil
+func (in *IstioValidationsService) GetValidationsWithChange(namespace string, object, draft kubernetes.IstioObject) (models.IstioValidations, error) {
+	if draft == nil {
+		return in.getValidations(namespace, "", nil, []kubernetes.IstioObject{object})
+	}
+	return in.getValidations(namespace, "", []kubernetes.IstioObject{draft}, nil)
+}
+
+func (in *IstioValidationsService) getValidations(namespace, service string, drafts, deleted []kubernetes.IstioObject) (models.IstioValidations, error) {
 	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
 	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
 	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
@@ -87,6 +119,10 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 		}
 	}
 
+	if len(drafts) > 0 || len(deleted) > 0 {
+		gatewaysPerNamespace = applyDrafts(drafts, deleted, &istioDetails, &mtlsDetails, &rbacDetails, gatewaysPerNamespace)
+	}
+
 	objectCheckers := in.getAllObjectCheckers(namespace, istioDetails, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces, registryStatus)
 
 	if service != "" {
@@ -102,6 +138,76 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 	return validations, nil
 }
 
+// applyDrafts removes the deleted objects from the fetched namespace details, then replaces or adds the draft objects
+func applyDrafts(drafts, deleted []kubernetes.IstioObject, istioDetails *kubernetes.IstioDetails, mtlsDetails *kubernetes.MTLSDetails, rbacDetails *kubernetes.RBACDetails, gatewaysPerNamespace [][]kubernetes.IstioObject) [][]kubernetes.IstioObject {
+	remove := func(objects []kubernetes.IstioObject, draft kubernetes.IstioObject) []kubernetes.IstioObject {
+		result := []kubernetes.IstioObject{}
+		for _, o := range objects {
+			if o.GetObjectMeta().Name != draft.GetObjectMeta().Name || o.GetObjectMeta().Namespace != draft.GetObjectMeta().Namespace {
+				result = append(result, o)
+			}
+		}
+		return result
+	}
+	// Each kind of object, with the lists it belongs to
+	lists := func(o kubernetes.IstioObject) []*[]kubernetes.IstioObject {
+		switch o.GetTypeMeta().Kind {
+		case kubernetes.VirtualServiceType:
+			return []*[]kubernetes.IstioObject{&istioDetails.VirtualServices}
+		case kubernetes.DestinationRuleType:
+			return []*[]kubernetes.IstioObject{&istioDetails.DestinationRules, &mtlsDetails.DestinationRules}
+		case kubernetes.ServiceEntryType:
+			return []*[]kubernetes.IstioObject{&istioDetails.ServiceEntries}
+		case kubernetes.SidecarType:
+			return []*[]kubernetes.IstioObject{&istioDetails.Sidecars}
+		case kubernetes.RequestAuthenticationsType:
+			return []*[]kubernetes.IstioObject{&istioDetails.RequestAuthentications}
+		case kubernetes.AuthorizationPoliciesType:
+			return []*[]kubernetes.IstioObject{&rbacDetails.AuthorizationPolicies}
+		case kubernetes.PeerAuthenticationsType:
+			if o.GetObjectMeta().Namespace == config.Get().IstioNamespace {
+				return []*[]kubernetes.IstioObject{&mtlsDetails.PeerAuthentications, &mtlsDetails.MeshPeerAuthentications}
+			}
+			return []*[]kubernetes.IstioObject{&mtlsDetails.PeerAuthentications}
+		}
+		return []*[]kubernetes.IstioObject{}
+	}
+	draftGateways := []kubernetes.IstioObject{}
+	for _, d := range deleted {
+		for _, l := range lists(d) {
+			*l = remove(*l, d)
+		}
+	}
+	for _, d := range drafts {
+		if d.GetTypeMeta().Kind == kubernetes.GatewayType {
+			draftGateways = append(draftGateways, d)
+		}
+		for _, l := range lists(d) {
+			*l = append(remove(*l, d), d)
+		}
+	}
+	removedGateways := append([]kubernetes.IstioObject{}, draftGateways...)
+	for _, d := range deleted {
+		if d.GetTypeMeta().Kind == kubernetes.GatewayType {
+			removedGateways = append(removedGateways, d)
+		}
+	}
+	if len(removedGateways) == 0 {
+		return gatewaysPerNamespace
+	}
+	result := make([][]kubernetes.IstioObject, 0, len(gatewaysPerNamespace)+1)
+	for _, gws := range gatewaysPerNamespace {
+		for _, d := range removedGateways {
+			gws = remove(gws, d)
+		}
+		result = append(result, gws)
+	}
+	if len(draftGateways) == 0 {
+		return result
+	}
+	return append(result, draftGateways)
+}
+
 func (in *IstioValidationsService) getServiceCheckers(namespace string, services []core_v1.Service, deployments []apps_v1.Deployment, pods []core_v1.Pod) []ObjectChecker {
 	return []ObjectChecker{
 		checkers.ServiceChecker{Services: services, Deployments: deployments, Pods: pods},
@@ -241,7 +347,7 @@ func (in *IstioValidationsService) fetchGatewaysPerNamespace(gatewaysPerNamespac
 		for i, ns := range nss {
 			var getCacheGateways func(string) ([]kubernetes.IstioObject, error)
 			// businessLayer.Namespace.GetNamespaces() is invoked before, so, namespace used are under the user's view
-			if IsResourceCached(ns.Name, kubernetes.Gateways) {
+			if IsResourceCached(in.k8s, ns.Name, kubernetes.Gateways) {
 				getCacheGateways = func(namespace string) ([]kubernetes.IstioObject, error) {
 					return kialiCache.GetIstioObjects(namespace, kubernetes.Gateways, "")
 				}
@@ -297,7 +403,7 @@ func (in *IstioValidationsService) fetchServices(rValue *[]core_v1.Service, name
 		var err error
 		// Check if namespace is cached
 		// Namespace access is checked in the upper caller
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(in.k8s, namespace, kubernetes.ServiceType) {
 			services, err = kialiCache.GetServices(namespace, nil)
 		} else {
 			services, err = in.k8s.GetServices(namespace, nil)
@@ -321,7 +427,7 @@ func (in *IstioValidationsService) fetchDeployments(rValue *[]apps_v1.Deployment
 
 		// Check if namespace is cached
 		// Namespace access is checked in the upper GetValidations
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(in.k8s, namespace, kubernetes.DeploymentType) {
 			deployments, err = kialiCache.GetDeployments(namespace)
 		} else {
 			deployments, err = in.k8s.GetDeployments(namespace)
@@ -344,7 +450,7 @@ func (in *IstioValidationsService) fetchPods(rValue *[]core_v1.Pod, namespace st
 		var pods []core_v1.Pod
 		// Check if namespace is cached
 		// Namespace access is checked in the upper call
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(in.k8s, namespace, kubernetes.PodType) {
 			pods, err = kialiCache.GetPods(namespace, "")
 		} else {
 			pods, err = in.k8s.GetPods(namespace, "")
@@ -408,7 +514,7 @@ func (in *IstioValidationsService) fetchDetails(rValue *kubernetes.IstioDetails,
 		errChan2 := make(chan error, 5)
 		istioDetails := kubernetes.IstioDetails{}
 
-		if IsResourceCached(namespace, kubernetes.VirtualServices) {
+		if IsResourceCached(in.k8s, namespace, kubernetes.VirtualServices) {
 			istioDetails.VirtualServices, err = kialiCache.GetIstioObjects(namespace, kubernetes.VirtualServices, "")
 		} else {
 			wg2.Add(1)
@@ -417,7 +523,7 @@ func (in *IstioValidationsService) fetchDetails(rValue *kubernetes.IstioDetails,
 			}
 			go fetchIstioObjects(&istioDetails.VirtualServices, namespace, getVirtualServices, &wg2, errChan2)
 		}
-		if IsResourceCached(namespace, kubernetes.DestinationRules) {
+		if IsResourceCached(in.k8s, namespace, kubernetes.DestinationRules) {
 			istioDetails.DestinationRules, err = kialiCache.GetIstioObjects(namespace, kubernetes.DestinationRules, "")
 		} else {
 			wg2.Add(1)
@@ -426,7 +532,7 @@ func (in *IstioValidationsService) fetchDetails(rValue *kubernetes.IstioDetails,
 			}
 			go fetchIstioObjects(&istioDetails.DestinationRules, namespace, getDestinationRules, &wg2, errChan2)
 		}
-		if IsResourceCached(namespace, kubernetes.ServiceEntries) {
+		if IsResourceCached(in.k8s, namespace, kubernetes.ServiceEntries) {
 			istioDetails.ServiceEntries, err = kialiCache.GetIstioObjects(namespace, kubernetes.ServiceEntries, "")
 		} else {
 			wg2.Add(1)
@@ -435,7 +541,7 @@ func (in *IstioValidationsService) fetchDetails(rValue *kubernetes.IstioDetails,
 			}
 			go fetchIstioObjects(&istioDetails.ServiceEntries, namespace, getServiceEntries, &wg2, errChan2)
 		}
-		if IsResourceCached(namespace, kubernetes.Gateways) {
+		if IsResourceCached(in.k8s, namespace, kubernetes.Gateways) {
 			istioDetails.Gateways, err = kialiCache.GetIstioObjects(namespace, kubernetes.Gateways, "")
 		} else {
 			wg2.Add(1)
@@ -444,7 +550,7 @@ func (in *IstioValidationsService) fetchDetails(rValue *kubernetes.IstioDetails,
 			}
 			go fetchIstioObjects(&istioDetails.Gateways, namespace, getGateways, &wg2, errChan2)
 		}
-		if IsResourceCached(namespace, kubernetes.Sidecars) {
+		if IsResourceCached(in.k8s, namespace, kubernetes.Sidecars) {
 			istioDetails.Sidecars, err = kialiCache.GetIstioObjects(namespace, kubernetes.Sidecars, "")
 		} else {
 			wg2.Add(1)
@@ -453,7 +559,7 @@ func (in *IstioValidationsService) fetchDetails(rValue *kubernetes.IstioDetails,
 			}
 			go fetchIstioObjects(&istioDetails.Sidecars, namespace, getSidecars, &wg2, errChan2)
 		}
-		if IsResourceCached(namespace, kubernetes.RequestAuthentications) {
+		if IsResourceCached(in.k8s, namespace, kubernetes.RequestAuthentications) {
 			istioDetails.RequestAuthentications, err = kialiCache.GetIstioObjects(namespace, kubernetes.RequestAuthentications, "")
 		} else {
 			wg2.Add(1)
@@ -493,7 +599,7 @@ func (in *IstioValidationsService) fetchNonLocalmTLSConfigs(mtlsDetails *kuberne
 
 		var meshpeerauths []kubernetes.IstioObject
 		var iErr error
-		if IsResourceCached(config.Get().IstioNamespace, kubernetes.PeerAuthentications) {
+		if IsResourceCached(in.k8s, config.Get().IstioNamespace, kubernetes.PeerAuthentications) {
 			if meshpeerauths, iErr = kialiCache.GetIstioObjects(config.Get().IstioNamespace, kubernetes.PeerAuthentications, ""); iErr == nil {
 				details.MeshPeerAuthentications = meshpeerauths
 			} else {
@@ -511,7 +617,7 @@ func (in *IstioValidationsService) fetchNonLocalmTLSConfigs(mtlsDetails *kuberne
 
 		var peerAuthns []kubernetes.IstioObject
 		var err error
-		if IsResourceCached(namespace, kubernetes.PeerAuthentications) {
+		if IsResourceCached(in.k8s, namespace, kubernetes.PeerAuthentications) {
 			peerAuthns, err = kialiCache.GetIstioObjects(namespace, kubernetes.PeerAuthentications, "")
 		} else {
 			peerAuthns, err = in.k8s.GetIstioObjects(namespace, kubernetes.PeerAuthentications, "")
@@ -529,7 +635,7 @@ func (in *IstioValidationsService) fetchNonLocalmTLSConfigs(mtlsDetails *kuberne
 
 		var istioConfig *core_v1.ConfigMap
 		var err error
-		if IsNamespaceCached(cfg.IstioNamespace) {
+		if IsNamespaceCached(in.k8s, cfg.IstioNamespace, kubernetes.ConfigMapType) {
 			istioConfig, err = kialiCache.GetConfigMap(cfg.IstioNamespace, cfg.ExternalServices.Istio.ConfigMapName)
 		} else {
 			istioConfig, err = in.k8s.GetConfigMap(cfg.IstioNamespace, cfg.ExternalServices.Istio.ConfigMapName)
@@ -578,7 +684,7 @@ func (in *IstioValidationsService) fetchAuthorizationDetails(rValue *kubernetes.
 		go func(errChan chan error) {
 			defer wg.Done()
 			var err error
-			if IsResourceCached(namespace, kubernetes.AuthorizationPolicies) {
+			if IsResourceCached(in.k8s, namespace, kubernetes.AuthorizationPolicies) {
 				authDetails.AuthorizationPolicies, err = kialiCache.GetIstioObjects(namespace, kubernetes.AuthorizationPolicies, "")
 			} else {
 				authDetails.AuthorizationPolicies, err = in.k8s.GetIstioObjects(namespace, kubernetes.AuthorizationPolicies, "")
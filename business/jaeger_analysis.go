@@ -0,0 +1,400 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	// A span is reported slower / faster when its duration changed by more than this ratio...
+	spanComparisonRatio = 0.2
+	// ... and by more than this absolute value (microseconds)
+	spanComparisonMinDelta = 1000
+)
+
+// GetTraceAnalysis computes the critical path and the per-service self time of a trace.
+// It returns nil when the trace is not found.
+func (in *JaegerService) GetTraceAnalysis(traceID string) (*models.TraceAnalysis, error) {
+	trace, err := in.GetJaegerTraceDetail(traceID)
+	if err != nil || trace == nil {
+		return nil, err
+	}
+	analysis := analyzeTrace(&trace.Data)
+	return &analysis, nil
+}
+
+// CompareTraces compares a trace with a baseline trace. It returns nil when any of the traces is not found.
+func (in *JaegerService) CompareTraces(traceID, baselineID string) (*models.TraceComparison, error) {
+	trace, err := in.GetJaegerTraceDetail(traceID)
+	if err != nil || trace == nil {
+		return nil, err
+	}
+	baseline, err := in.GetJaegerTraceDetail(baselineID)
+	if err != nil || baseline == nil {
+		return nil, err
+	}
+	comparison := compareTraces(&trace.Data, []jaegerModels.Trace{baseline.Data})
+	return &comparison, nil
+}
+
+// CompareTraceToMedian compares a trace with the median of similar traces of the app, that is traces found
+// in the query interval and starting with the same root operation. It returns nil when the trace is not found.
+func (in *JaegerService) CompareTraceToMedian(ns, app, traceID string, query models.TracingQuery) (*models.TraceComparison, error) {
+	trace, err := in.GetJaegerTraceDetail(traceID)
+	if err != nil || trace == nil {
+		return nil, err
+	}
+	root := findRootSpan(&trace.Data)
+	if root == nil {
+		return nil, fmt.Errorf("trace %s has no root span", traceID)
+	}
+	r, err := in.GetAppTraces(ns, app, query)
+	if err != nil {
+		return nil, err
+	}
+	similar := []jaegerModels.Trace{}
+	for i := range r.Data {
+		t := &r.Data[i]
+		if t.TraceID == trace.Data.TraceID {
+			continue
+		}
+		if tRoot := findRootSpan(t); tRoot != nil && tRoot.OperationName == root.OperationName {
+			similar = append(similar, *t)
+		}
+	}
+	if len(similar) == 0 {
+		return nil, fmt.Errorf("no similar traces found for operation %s", root.OperationName)
+	}
+	comparison := compareTraces(&trace.Data, similar)
+	return &comparison, nil
+}
+
+type spanTree struct {
+	spans    map[jaegerModels.SpanID]*jaegerModels.Span
+	children map[jaegerModels.SpanID][]*jaegerModels.Span
+	services map[jaegerModels.SpanID]string
+}
+
+func newSpanTree(trace *jaegerModels.Trace) spanTree {
+	tree := spanTree{
+		spans:    make(map[jaegerModels.SpanID]*jaegerModels.Span, len(trace.Spans)),
+		children: make(map[jaegerModels.SpanID][]*jaegerModels.Span),
+		services: make(map[jaegerModels.SpanID]string, len(trace.Spans)),
+	}
+	for i := range trace.Spans {
+		span := &trace.Spans[i]
+		tree.spans[span.SpanID] = span
+		tree.services[span.SpanID] = spanServiceName(trace, span)
+	}
+	for _, span := range tree.spans {
+		if parent := parentSpanID(span); parent != "" {
+			if _, ok := tree.spans[parent]; ok {
+				tree.children[parent] = append(tree.children[parent], span)
+			}
+		}
+	}
+	return tree
+}
+
+func spanServiceName(trace *jaegerModels.Trace, span *jaegerModels.Span) string {
+	if span.Process != nil {
+		return span.Process.ServiceName
+	}
+	if process, ok := trace.Processes[span.ProcessID]; ok {
+		return process.ServiceName
+	}
+	return ""
+}
+
+func parentSpanID(span *jaegerModels.Span) jaegerModels.SpanID {
+	for _, ref := range span.References {
+		if ref.RefType == jaegerModels.ChildOf {
+			return ref.SpanID
+		}
+	}
+	return span.ParentSpanID
+}
+
+// findRootSpan returns the longest span that has no parent in the trace
+func findRootSpan(trace *jaegerModels.Trace) *jaegerModels.Span {
+	ids := make(map[jaegerModels.SpanID]bool, len(trace.Spans))
+	for _, span := range trace.Spans {
+		ids[span.SpanID] = true
+	}
+	var root *jaegerModels.Span
+	for i := range trace.Spans {
+		span := &trace.Spans[i]
+		if parent := parentSpanID(span); parent != "" && ids[parent] {
+			continue
+		}
+		if root == nil || span.Duration > root.Duration {
+			root = span
+		}
+	}
+	return root
+}
+
+func traceBounds(trace *jaegerModels.Trace) (start, end uint64) {
+	for i, span := range trace.Spans {
+		if i == 0 || span.StartTime < start {
+			start = span.StartTime
+		}
+		if spanEnd := span.StartTime + span.Duration; spanEnd > end {
+			end = spanEnd
+		}
+	}
+	return start, end
+}
+
+func analyzeTrace(trace *jaegerModels.Trace) models.TraceAnalysis {
+	tree := newSpanTree(trace)
+	start, end := traceBounds(trace)
+	analysis := models.TraceAnalysis{
+		TraceID:      string(trace.TraceID),
+		Duration:     end - start,
+		CriticalPath: []models.CriticalPathStep{},
+		Services:     []models.ServiceSelfTime{},
+	}
+	if root := findRootSpan(trace); root != nil {
+		analysis.CriticalPath = tree.criticalPath(root, root.StartTime, root.StartTime+root.Duration, nil)
+		sort.Slice(analysis.CriticalPath, func(i, j int) bool {
+			return analysis.CriticalPath[i].Start < analysis.CriticalPath[j].Start
+		})
+		for i := range analysis.CriticalPath {
+			analysis.CriticalPath[i].Start -= start
+		}
+	}
+
+	byService := make(map[string]*models.ServiceSelfTime)
+	getService := func(name string) *models.ServiceSelfTime {
+		s, ok := byService[name]
+		if !ok {
+			s = &models.ServiceSelfTime{Service: name}
+			byService[name] = s
+		}
+		return s
+	}
+	for id, span := range tree.spans {
+		s := getService(tree.services[id])
+		s.Spans++
+		s.SelfTime += span.Duration - childrenCoverage(span, tree.children[id])
+	}
+	for _, step := range analysis.CriticalPath {
+		getService(step.Service).CriticalPathTime += step.Duration
+	}
+	for _, s := range byService {
+		if analysis.Duration > 0 {
+			s.Percent = 100 * float64(s.SelfTime) / float64(analysis.Duration)
+		}
+		analysis.Services = append(analysis.Services, *s)
+	}
+	sort.Slice(analysis.Services, func(i, j int) bool {
+		if analysis.Services[i].SelfTime == analysis.Services[j].SelfTime {
+			return analysis.Services[i].Service < analysis.Services[j].Service
+		}
+		return analysis.Services[i].SelfTime > analysis.Services[j].SelfTime
+	})
+	return analysis
+}
+
+// criticalPath walks backward from the end of the span: the child finishing last before the cursor is the one
+// blocking the span, so it is followed recursively, then the cursor moves to that child's start.
+// Gaps between blocking children are the span's own contribution to the critical path.
+func (t spanTree) criticalPath(span *jaegerModels.Span, from, to uint64, steps []models.CriticalPathStep) []models.CriticalPathStep {
+	addStep := func(start, end uint64) {
+		if end > start {
+			steps = append(steps, models.CriticalPathStep{
+				SpanID:    string(span.SpanID),
+				Service:   t.services[span.SpanID],
+				Operation: span.OperationName,
+				Start:     start,
+				Duration:  end - start,
+			})
+		}
+	}
+	children := append([]*jaegerModels.Span{}, t.children[span.SpanID]...)
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].StartTime+children[i].Duration > children[j].StartTime+children[j].Duration
+	})
+	cursor := to
+	for _, child := range children {
+		childStart := child.StartTime
+		childEnd := child.StartTime + child.Duration
+		if childStart >= cursor || childEnd <= from {
+			continue
+		}
+		if childEnd > cursor {
+			childEnd = cursor
+		}
+		if childStart < from {
+			childStart = from
+		}
+		addStep(childEnd, cursor)
+		steps = t.criticalPath(child, childStart, childEnd, steps)
+		cursor = childStart
+	}
+	addStep(from, cursor)
+	return steps
+}
+
+// childrenCoverage returns the time of a span covered by at least one of its children
+func childrenCoverage(span *jaegerModels.Span, children []*jaegerModels.Span) uint64 {
+	type interval struct{ start, end uint64 }
+	spanEnd := span.StartTime + span.Duration
+	intervals := []interval{}
+	for _, child := range children {
+		start, end := child.StartTime, child.StartTime+child.Duration
+		if start < span.StartTime {
+			start = span.StartTime
+		}
+		if end > spanEnd {
+			end = spanEnd
+		}
+		if end > start {
+			intervals = append(intervals, interval{start: start, end: end})
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start < intervals[j].start })
+	var covered, cursor uint64
+	for _, i := range intervals {
+		if i.start > cursor {
+			cursor = i.start
+		}
+		if i.end > cursor {
+			covered += i.end - cursor
+			cursor = i.end
+		}
+	}
+	return covered
+}
+
+type spanKey struct {
+	service   string
+	operation string
+}
+
+type spanStats struct {
+	count    int
+	duration uint64
+}
+
+func traceSpanStats(trace *jaegerModels.Trace) map[spanKey]spanStats {
+	stats := make(map[spanKey]spanStats)
+	for i := range trace.Spans {
+		span := &trace.Spans[i]
+		key := spanKey{service: spanServiceName(trace, span), operation: span.OperationName}
+		s := stats[key]
+		s.count++
+		s.duration += span.Duration
+		stats[key] = s
+	}
+	return stats
+}
+
+// compareTraces compares spans of a trace, grouped by service and operation, with the median of the baselines
+func compareTraces(trace *jaegerModels.Trace, baselines []jaegerModels.Trace) models.TraceComparison {
+	start, end := traceBounds(trace)
+	comparison := models.TraceComparison{
+		TraceID:  string(trace.TraceID),
+		Baseline: []string{},
+		Duration: end - start,
+		Spans:    []models.SpanComparison{},
+	}
+
+	durations := []uint64{}
+	baselineStats := make([]map[spanKey]spanStats, len(baselines))
+	keys := make(map[spanKey]bool)
+	for i := range baselines {
+		comparison.Baseline = append(comparison.Baseline, string(baselines[i].TraceID))
+		bStart, bEnd := traceBounds(&baselines[i])
+		durations = append(durations, bEnd-bStart)
+		baselineStats[i] = traceSpanStats(&baselines[i])
+		for k := range baselineStats[i] {
+			keys[k] = true
+		}
+	}
+	comparison.BaselineDuration = medianUint(durations)
+
+	current := traceSpanStats(trace)
+	for k := range current {
+		keys[k] = true
+	}
+	for k := range keys {
+		counts := make([]uint64, len(baselineStats))
+		durations := make([]uint64, len(baselineStats))
+		for i, stats := range baselineStats {
+			counts[i] = uint64(stats[k].count)
+			durations[i] = stats[k].duration
+		}
+		c := models.SpanComparison{
+			Service:          k.service,
+			Operation:        k.operation,
+			Count:            current[k].count,
+			BaselineCount:    int(medianUint(counts)),
+			Duration:         current[k].duration,
+			BaselineDuration: medianUint(durations),
+		}
+		if c.Count == 0 && c.BaselineCount == 0 {
+			// Span is occasional in baselines, and not in this trace: not relevant
+			continue
+		}
+		c.Delta = int64(c.Duration) - int64(c.BaselineDuration)
+		if c.BaselineDuration > 0 {
+			c.DeltaPercent = 100 * float64(c.Delta) / float64(c.BaselineDuration)
+		}
+		c.Status = spanComparisonStatus(c)
+		comparison.Spans = append(comparison.Spans, c)
+	}
+	sort.Slice(comparison.Spans, func(i, j int) bool {
+		a, b := comparison.Spans[i], comparison.Spans[j]
+		if absInt64(a.Delta) != absInt64(b.Delta) {
+			return absInt64(a.Delta) > absInt64(b.Delta)
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Operation < b.Operation
+	})
+	return comparison
+}
+
+func spanComparisonStatus(c models.SpanComparison) string {
+	if c.Count == 0 {
+		return models.SpanMissing
+	}
+	if c.BaselineCount == 0 {
+		return models.SpanAdded
+	}
+	if absInt64(c.Delta) > spanComparisonMinDelta && math.Abs(float64(c.Delta)) > spanComparisonRatio*float64(c.BaselineDuration) {
+		if c.Delta > 0 {
+			return models.SpanSlower
+		}
+		return models.SpanFaster
+	}
+	return models.SpanUnchanged
+}
+
+func medianUint(values []uint64) uint64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]uint64{}, values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func absInt64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
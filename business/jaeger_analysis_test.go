@@ -0,0 +1,114 @@
+package business
+
+import (
+	"testing"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/models"
+)
+
+func fakeSpan(id, parent, process, operation string, start, duration uint64) jaegerModels.Span {
+	span := jaegerModels.Span{
+		SpanID:        jaegerModels.SpanID(id),
+		ProcessID:     jaegerModels.ProcessID(process),
+		OperationName: operation,
+		StartTime:     start,
+		Duration:      duration,
+	}
+	if parent != "" {
+		span.References = []jaegerModels.Reference{{RefType: jaegerModels.ChildOf, SpanID: jaegerModels.SpanID(parent)}}
+	}
+	return span
+}
+
+func fakeAnalysisTrace(id string, cDuration uint64) jaegerModels.Trace {
+	return jaegerModels.Trace{
+		TraceID: jaegerModels.TraceID(id),
+		Spans: []jaegerModels.Span{
+			fakeSpan("a", "", "p1", "GET /", 1000, 100000),
+			fakeSpan("b", "a", "p2", "reviews", 11000, 40000),
+			fakeSpan("c", "a", "p3", "ratings", 41000, cDuration),
+			fakeSpan("d", "c", "p4", "db", 51000, 20000),
+		},
+		Processes: map[jaegerModels.ProcessID]jaegerModels.Process{
+			"p1": {ServiceName: "productpage"},
+			"p2": {ServiceName: "reviews"},
+			"p3": {ServiceName: "ratings"},
+			"p4": {ServiceName: "mongodb"},
+		},
+	}
+}
+
+func TestAnalyzeTrace(t *testing.T) {
+	assert := assert.New(t)
+
+	trace := fakeAnalysisTrace("t1", 50000)
+	analysis := analyzeTrace(&trace)
+
+	assert.Equal("t1", analysis.TraceID)
+	assert.Equal(uint64(100000), analysis.Duration)
+	assert.Equal([]models.CriticalPathStep{
+		{SpanID: "a", Service: "productpage", Operation: "GET /", Start: 0, Duration: 10000},
+		{SpanID: "b", Service: "reviews", Operation: "reviews", Start: 10000, Duration: 30000},
+		{SpanID: "c", Service: "ratings", Operation: "ratings", Start: 40000, Duration: 10000},
+		{SpanID: "d", Service: "mongodb", Operation: "db", Start: 50000, Duration: 20000},
+		{SpanID: "c", Service: "ratings", Operation: "ratings", Start: 70000, Duration: 20000},
+		{SpanID: "a", Service: "productpage", Operation: "GET /", Start: 90000, Duration: 10000},
+	}, analysis.CriticalPath)
+
+	assert.Len(analysis.Services, 4)
+	assert.Equal("reviews", analysis.Services[0].Service)
+	assert.Equal(uint64(40000), analysis.Services[0].SelfTime)
+	assert.Equal(uint64(30000), analysis.Services[0].CriticalPathTime)
+	assert.Equal(40.0, analysis.Services[0].Percent)
+	assert.Equal("ratings", analysis.Services[1].Service)
+	assert.Equal(uint64(30000), analysis.Services[1].SelfTime)
+	assert.Equal(uint64(30000), analysis.Services[1].CriticalPathTime)
+	assert.Equal("mongodb", analysis.Services[2].Service)
+	assert.Equal(uint64(20000), analysis.Services[2].SelfTime)
+	assert.Equal("productpage", analysis.Services[3].Service)
+	assert.Equal(uint64(20000), analysis.Services[3].SelfTime)
+}
+
+func TestCompareTraces(t *testing.T) {
+	assert := assert.New(t)
+
+	trace := fakeAnalysisTrace("slow", 80000)
+	// Remove the db span
+	trace.Spans = trace.Spans[:3]
+	baselines := []jaegerModels.Trace{
+		fakeAnalysisTrace("b1", 50000),
+		fakeAnalysisTrace("b2", 52000),
+		fakeAnalysisTrace("b3", 40000),
+	}
+	comparison := compareTraces(&trace, baselines)
+
+	assert.Equal("slow", comparison.TraceID)
+	assert.Equal([]string{"b1", "b2", "b3"}, comparison.Baseline)
+	assert.Equal(uint64(100000), comparison.BaselineDuration)
+	assert.Len(comparison.Spans, 4)
+
+	assert.Equal("ratings", comparison.Spans[0].Service)
+	assert.Equal(uint64(50000), comparison.Spans[0].BaselineDuration)
+	assert.Equal(int64(30000), comparison.Spans[0].Delta)
+	assert.Equal(60.0, comparison.Spans[0].DeltaPercent)
+	assert.Equal(models.SpanSlower, comparison.Spans[0].Status)
+
+	assert.Equal("mongodb", comparison.Spans[1].Service)
+	assert.Equal(0, comparison.Spans[1].Count)
+	assert.Equal(1, comparison.Spans[1].BaselineCount)
+	assert.Equal(models.SpanMissing, comparison.Spans[1].Status)
+
+	assert.Equal(models.SpanUnchanged, comparison.Spans[2].Status)
+	assert.Equal(models.SpanUnchanged, comparison.Spans[3].Status)
+}
+
+func TestMedianUint(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(uint64(0), medianUint([]uint64{}))
+	assert.Equal(uint64(3), medianUint([]uint64{5, 1, 3}))
+	assert.Equal(uint64(4), medianUint([]uint64{5, 1, 3, 8}))
+}
@@ -0,0 +1,145 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/jaeger"
+	"github.com/kiali/kiali/models"
+)
+
+// Upper bounds of the heatmap duration buckets, in milliseconds. A last, unbounded bucket is added.
+var heatmapDurationBuckets = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+var statsPercentiles = []float64{50, 90, 95, 99}
+
+// GetAppTracesStats computes per-operation statistics and a latency heatmap from the spans of an app.
+// Traces are always fetched with the sliced interval mode, in order to spread them over the requested time range
+// while staying within the Jaeger query limits.
+func (in *JaegerService) GetAppTracesStats(ns, app string, query models.TracingQuery, timeBuckets int) (*models.TracingStats, error) {
+	if timeBuckets <= 0 {
+		return nil, fmt.Errorf("invalid number of time buckets: %d", timeBuckets)
+	}
+	if !query.Start.Before(query.End) {
+		return nil, fmt.Errorf("invalid time range: start %s is not before end %s", query.Start.Format(time.RFC3339), query.End.Format(time.RFC3339))
+	}
+	r, err := in.getAppTracesSlicedInterval(ns, app, query)
+	if r == nil {
+		return nil, err
+	}
+	if err != nil && len(r.Data) == 0 {
+		return nil, err
+	}
+	spans := tracesToSpans(app, r, nil)
+	stats := computeTracingStats(spans, query.Start, query.End, timeBuckets)
+	stats.Traces = len(r.Data)
+	return &stats, nil
+}
+
+func computeTracingStats(spans []jaeger.JaegerSpan, start, end time.Time, timeBuckets int) models.TracingStats {
+	stats := models.TracingStats{
+		Spans:      len(spans),
+		Operations: []models.OperationStats{},
+		Heatmap:    newSpansHeatmap(start, end, timeBuckets),
+	}
+
+	durations := make(map[string][]uint64)
+	errors := make(map[string]int)
+	startMicros := uint64(start.UnixNano() / int64(time.Microsecond))
+	bucketMicros := uint64(end.Sub(start).Microseconds()) / uint64(timeBuckets)
+	for i := range spans {
+		span := &spans[i].Span
+		durations[span.OperationName] = append(durations[span.OperationName], span.Duration)
+		if isErrorSpan(span) {
+			errors[span.OperationName]++
+		}
+		if span.StartTime < startMicros || bucketMicros == 0 {
+			continue
+		}
+		t := int((span.StartTime - startMicros) / bucketMicros)
+		if t >= timeBuckets {
+			continue
+		}
+		stats.Heatmap.Counts[t][durationBucket(span.Duration)]++
+	}
+
+	for op, values := range durations {
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		var sum uint64
+		for _, v := range values {
+			sum += v
+		}
+		opStats := models.OperationStats{
+			Operation:   op,
+			Count:       len(values),
+			Errors:      errors[op],
+			ErrorRatio:  float64(errors[op]) / float64(len(values)),
+			Min:         values[0],
+			Max:         values[len(values)-1],
+			Avg:         float64(sum) / float64(len(values)),
+			Percentiles: make(map[string]uint64, len(statsPercentiles)),
+		}
+		for _, p := range statsPercentiles {
+			opStats.Percentiles[fmt.Sprintf("p%v", p)] = percentile(values, p)
+		}
+		stats.Operations = append(stats.Operations, opStats)
+	}
+	sort.Slice(stats.Operations, func(i, j int) bool {
+		if stats.Operations[i].Count == stats.Operations[j].Count {
+			return stats.Operations[i].Operation < stats.Operations[j].Operation
+		}
+		return stats.Operations[i].Count > stats.Operations[j].Count
+	})
+	return stats
+}
+
+func newSpansHeatmap(start, end time.Time, timeBuckets int) models.SpansHeatmap {
+	heatmap := models.SpansHeatmap{
+		TimeBuckets:     make([]int64, timeBuckets),
+		DurationBuckets: append(append([]float64{}, heatmapDurationBuckets...), -1),
+		Counts:          make([][]int, timeBuckets),
+	}
+	step := end.Sub(start) / time.Duration(timeBuckets)
+	for i := 0; i < timeBuckets; i++ {
+		heatmap.TimeBuckets[i] = start.Add(step*time.Duration(i)).UnixNano() / int64(time.Millisecond)
+		heatmap.Counts[i] = make([]int, len(heatmap.DurationBuckets))
+	}
+	return heatmap
+}
+
+func durationBucket(micros uint64) int {
+	ms := float64(micros) / 1000
+	for i, bound := range heatmapDurationBuckets {
+		if ms <= bound {
+			return i
+		}
+	}
+	return len(heatmapDurationBuckets)
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []uint64, p float64) uint64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func isErrorSpan(span *jaegerModels.Span) bool {
+	for _, tag := range span.Tags {
+		if tag.Key == "error" {
+			switch v := tag.Value.(type) {
+			case bool:
+				return v
+			case string:
+				return v == "true"
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/jaeger"
+	"github.com/kiali/kiali/models"
+)
+
+func TestComputeTracingStats(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Unix(1000, 0)
+	end := start.Add(10 * time.Second)
+	startMicros := uint64(start.UnixNano() / int64(time.Microsecond))
+	errorTag := []jaegerModels.KeyValue{{Key: "error", Value: true}}
+	spans := []jaeger.JaegerSpan{
+		{Span: jaegerModels.Span{OperationName: "op1", StartTime: startMicros, Duration: 800}},
+		{Span: jaegerModels.Span{OperationName: "op1", StartTime: startMicros + 1500000, Duration: 3000}},
+		{Span: jaegerModels.Span{OperationName: "op1", StartTime: startMicros + 9000000, Duration: 20000000, Tags: errorTag}},
+		{Span: jaegerModels.Span{OperationName: "op2", StartTime: startMicros + 9500000, Duration: 40000}},
+		// Out of range
+		{Span: jaegerModels.Span{OperationName: "op2", StartTime: startMicros + 12000000, Duration: 40000}},
+	}
+
+	stats := computeTracingStats(spans, start, end, 5)
+
+	assert.Equal(5, stats.Spans)
+	assert.Len(stats.Operations, 2)
+	op1 := stats.Operations[0]
+	assert.Equal("op1", op1.Operation)
+	assert.Equal(3, op1.Count)
+	assert.Equal(1, op1.Errors)
+	assert.Equal(uint64(800), op1.Min)
+	assert.Equal(uint64(20000000), op1.Max)
+	assert.Equal(uint64(3000), op1.Percentiles["p50"])
+	assert.Equal(uint64(20000000), op1.Percentiles["p99"])
+	assert.Equal("op2", stats.Operations[1].Operation)
+	assert.Equal(0, stats.Operations[1].Errors)
+
+	assert.Equal([]int64{1000000, 1002000, 1004000, 1006000, 1008000}, stats.Heatmap.TimeBuckets)
+	assert.Len(stats.Heatmap.DurationBuckets, 14)
+	assert.Equal(1, stats.Heatmap.Counts[0][0])
+	assert.Equal(1, stats.Heatmap.Counts[0][2])
+	assert.Equal(1, stats.Heatmap.Counts[4][5])
+	assert.Equal(1, stats.Heatmap.Counts[4][13])
+}
+
+func TestGetAppTracesStatsRejectsInvalidRange(t *testing.T) {
+	assert := assert.New(t)
+
+	in := JaegerService{}
+	end := time.Unix(1000, 0)
+	_, err := in.GetAppTracesStats("bookinfo", "reviews", models.TracingQuery{Start: end.Add(time.Second), End: end}, 5)
+	assert.Error(err)
+	_, err = in.GetAppTracesStats("bookinfo", "reviews", models.TracingQuery{Start: end, End: end}, 5)
+	assert.Error(err)
+}
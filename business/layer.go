@@ -3,6 +3,7 @@ package business
 import (
 	"sync"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/kiali/kiali/config"
@@ -15,23 +16,29 @@ import (
 
 // Layer is a container for fast access to inner services
 type Layer struct {
-	App            AppService
-	Health         HealthService
-	IstioConfig    IstioConfigService
-	IstioStatus    IstioStatusService
-	Iter8          Iter8Service
-	Jaeger         JaegerService
-	k8s            kubernetes.ClientInterface
-	Mesh           MeshService
-	Namespace      NamespaceService
-	OpenshiftOAuth OpenshiftOAuthService
-	ProxyStatus    ProxyStatusService
-	RegistryStatus RegistryStatusService
-	Svc            SvcService
-	TLS            TLSService
-	TokenReview    TokenReviewService
-	Validations    IstioValidationsService
-	Workload       WorkloadService
+	App              AppService
+	Authorization    AuthorizationService
+	Cache            CacheService
+	Certificates     CertificatesService
+	Health           HealthService
+	IstioConfig      IstioConfigService
+	IstioConfigUsage IstioConfigUsageService
+	IstioStatus      IstioStatusService
+	Iter8            Iter8Service
+	Jaeger           JaegerService
+	k8s              kubernetes.ClientInterface
+	Mesh             MeshService
+	Namespace        NamespaceService
+	OpenshiftOAuth   OpenshiftOAuthService
+	ProxyStatus      ProxyStatusService
+	RegistryStatus   RegistryStatusService
+	SidecarScoping   SidecarScopingService
+	Svc              SvcService
+	TLS              TLSService
+	TokenReview      TokenReviewService
+	Traffic          TrafficTemplatesService
+	Validations      IstioValidationsService
+	Workload         WorkloadService
 }
 
 // Global clientfactory and prometheus clients.
@@ -56,19 +63,61 @@ func initKialiCache() {
 	}
 }
 
-func IsNamespaceCached(namespace string) bool {
-	ok := kialiCache != nil && kialiCache.CheckNamespace(namespace)
-	return ok
+// Group and resource of the Kubernetes types held by the cache, used to review the access of the users
+var cachedKubernetesResources = map[string]schema.GroupResource{
+	kubernetes.ConfigMapType:             {Group: "", Resource: "configmaps"},
+	kubernetes.CronJobType:               {Group: "batch", Resource: "cronjobs"},
+	kubernetes.DaemonSetType:             {Group: "apps", Resource: "daemonsets"},
+	kubernetes.DeploymentType:            {Group: "apps", Resource: "deployments"},
+	kubernetes.EndpointsType:             {Group: "", Resource: "endpoints"},
+	kubernetes.JobType:                   {Group: "batch", Resource: "jobs"},
+	kubernetes.PodType:                   {Group: "", Resource: "pods"},
+	kubernetes.ReplicationControllerType: {Group: "", Resource: "replicationcontrollers"},
+	kubernetes.ReplicaSetType:            {Group: "apps", Resource: "replicasets"},
+	kubernetes.ServiceType:               {Group: "", Resource: "services"},
+	kubernetes.StatefulSetType:           {Group: "apps", Resource: "statefulsets"},
+}
+
+// IsNamespaceCached tells if the objects of the type (a Kubernetes kind or an Istio resource) in the namespace can be
+// read from the cache by the user of the client.
+func IsNamespaceCached(k8s kubernetes.ClientInterface, namespace string, resourceType string) bool {
+	ok := kialiCache != nil && kialiCache.CheckNamespace(namespace) && kialiCache.CheckKubernetesType(resourceType)
+	return ok && canReadCache(k8s, namespace, resourceType)
 }
 
-func IsResourceCached(namespace string, resource string) bool {
-	ok := IsNamespaceCached(namespace)
+func IsResourceCached(k8s kubernetes.ClientInterface, namespace string, resource string) bool {
+	ok := IsNamespaceCached(k8s, namespace, resource)
 	if ok && resource != "" {
 		ok = kialiCache.CheckIstioResource(resource)
 	}
 	return ok
 }
 
+// canReadCache tells if the user of the client may list the objects of the type in the namespace.
+// In cluster-wide mode, the cache holds the objects of every namespace, read with the Kiali ServiceAccount: a user not
+// allowed to list them reads from the API instead, with their own rights.
+func canReadCache(k8s kubernetes.ClientInterface, namespace string, resourceType string) bool {
+	if !kialiCache.IsClusterScoped() {
+		return true
+	}
+	token := k8s.GetToken()
+	if allowed, found := kialiCache.GetAccess(token, namespace, resourceType); found {
+		return allowed
+	}
+	resource, ok := cachedKubernetesResources[resourceType]
+	if !ok {
+		resource = schema.GroupResource{Group: kubernetes.ResourceTypesToAPI[resourceType], Resource: resourceType}
+	}
+	ssars, err := k8s.GetSelfSubjectAccessReview(namespace, resource.Group, resource.Resource, []string{"list"})
+	if err != nil {
+		log.Errorf("Error reviewing the access to [%s] in namespace [%s]: %v", resourceType, namespace, err)
+		return false
+	}
+	allowed := len(ssars) == 1 && ssars[0].Status.Allowed
+	kialiCache.SetAccess(token, namespace, resourceType, allowed)
+	return allowed
+}
+
 // Get the business.Layer
 func Get(authInfo *api.AuthInfo) (*Layer, error) {
 	// Kiali Cache will be initialized once at first use of Business layer
@@ -117,8 +166,12 @@ func SetWithBackends(cf kubernetes.ClientFactory, prom prometheus.ClientInterfac
 func NewWithBackends(k8s kubernetes.ClientInterface, prom prometheus.ClientInterface, jaegerClient JaegerLoader) *Layer {
 	temporaryLayer := &Layer{}
 	temporaryLayer.App = AppService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.Authorization = AuthorizationService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
+	temporaryLayer.Cache = CacheService{businessLayer: temporaryLayer}
+	temporaryLayer.Certificates = CertificatesService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Health = HealthService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.IstioConfig = IstioConfigService{k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.IstioConfigUsage = IstioConfigUsageService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
 	temporaryLayer.IstioStatus = IstioStatusService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Iter8 = Iter8Service{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Jaeger = JaegerService{loader: jaegerClient, businessLayer: temporaryLayer}
@@ -128,8 +181,10 @@ func NewWithBackends(k8s kubernetes.ClientInterface, prom prometheus.ClientInter
 	temporaryLayer.OpenshiftOAuth = OpenshiftOAuthService{k8s: k8s}
 	temporaryLayer.ProxyStatus = ProxyStatusService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.RegistryStatus = RegistryStatusService{k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.SidecarScoping = SidecarScopingService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
 	temporaryLayer.Svc = SvcService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
-	temporaryLayer.TLS = TLSService{k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.TLS = TLSService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
+	temporaryLayer.Traffic = TrafficTemplatesService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.TokenReview = NewTokenReview(k8s)
 	temporaryLayer.Validations = IstioValidationsService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Workload = WorkloadService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
@@ -141,4 +196,7 @@ func Stop() {
 	if kialiCache != nil {
 		kialiCache.Stop()
 	}
+	if runtimeDashboards != nil {
+		runtimeDashboards.stop()
+	}
 }
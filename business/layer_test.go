@@ -0,0 +1,60 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	auth_v1 "k8s.io/api/authorization/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/cache"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+// clusterScopedCacheMock caches every namespace cluster-wide, with the access reviews of the users
+type clusterScopedCacheMock struct {
+	cache.KialiCache
+	access map[string]bool
+}
+
+func (c *clusterScopedCacheMock) CheckNamespace(namespace string) bool { return true }
+
+func (c *clusterScopedCacheMock) CheckIstioResource(resourceType string) bool { return true }
+
+func (c *clusterScopedCacheMock) CheckKubernetesType(resourceType string) bool { return true }
+
+func (c *clusterScopedCacheMock) IsClusterScoped() bool { return true }
+
+func (c *clusterScopedCacheMock) SetAccess(token, namespace, resourceType string, allowed bool) {
+	c.access[token+"/"+namespace+"/"+resourceType] = allowed
+}
+
+func (c *clusterScopedCacheMock) GetAccess(token, namespace, resourceType string) (bool, bool) {
+	allowed, found := c.access[token+"/"+namespace+"/"+resourceType]
+	return allowed, found
+}
+
+func TestClusterScopedCacheAccess(t *testing.T) {
+	assert := assert.New(t)
+	defer func(previous cache.KialiCache) { kialiCache = previous }(kialiCache)
+	kialiCache = &clusterScopedCacheMock{access: map[string]bool{}}
+
+	review := func(allowed bool) []*auth_v1.SelfSubjectAccessReview {
+		return []*auth_v1.SelfSubjectAccessReview{{Status: auth_v1.SubjectAccessReviewStatus{Allowed: allowed}}}
+	}
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetToken").Return("user")
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "apps", "deployments", []string{"list"}).Return(review(true), nil)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "", "pods", []string{"list"}).Return(review(false), nil)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "security.istio.io", "authorizationpolicies", []string{"list"}).Return(review(true), nil)
+
+	assert.True(IsNamespaceCached(k8s, "bookinfo", kubernetes.DeploymentType))
+	// A user not allowed to list the objects reads them from the API
+	assert.False(IsNamespaceCached(k8s, "bookinfo", kubernetes.PodType))
+	assert.True(IsResourceCached(k8s, "bookinfo", kubernetes.AuthorizationPolicies))
+
+	// The reviews are cached per user, namespace and type
+	assert.True(IsNamespaceCached(k8s, "bookinfo", kubernetes.DeploymentType))
+	assert.False(IsNamespaceCached(k8s, "bookinfo", kubernetes.PodType))
+	k8s.AssertNumberOfCalls(t, "GetSelfSubjectAccessReview", 3)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus"
 )
@@ -72,9 +73,21 @@ func (in *MetricsService) fetchAllMetrics(q models.IstioMetricsQuery, lb *Metric
 		*histo = h
 	}
 
+	fetchExemplars := func(p8sFamilyName string, exemplars *[]prometheus.ExemplarQueryResult) {
+		defer wg.Done()
+		e, err := in.prom.FetchExemplars(p8sFamilyName+"_bucket"+labels, q.Start, q.End)
+		if err != nil {
+			// Exemplars are optional: don't fail the whole request
+			log.Warningf("Cannot fetch exemplars for %s: %v", p8sFamilyName, err)
+			return
+		}
+		*exemplars = e
+	}
+
 	type resultHolder struct {
 		metric     prometheus.Metric
 		histo      prometheus.Histogram
+		exemplars  []prometheus.ExemplarQueryResult
 		definition istioMetric
 	}
 	maxResults := len(istioMetrics)
@@ -100,6 +113,10 @@ func (in *MetricsService) fetchAllMetrics(q models.IstioMetricsQuery, lb *Metric
 			results = append(results, &result)
 			if istioMetric.isHisto {
 				go fetchHisto(istioMetric.istioName, &result.histo)
+				if q.Exemplars && istioMetric.withExemplars {
+					wg.Add(1)
+					go fetchExemplars(istioMetric.istioName, &result.exemplars)
+				}
 			} else {
 				labelsToUse := istioMetric.labelsToUse(labels, labelsError)
 				go fetchRate(istioMetric.istioName, &result.metric, labelsToUse)
@@ -126,6 +143,7 @@ func (in *MetricsService) fetchAllMetrics(q models.IstioMetricsQuery, lb *Metric
 				if err != nil {
 					return nil, err
 				}
+				models.AttachExemplars(converted, result.exemplars, conversionParams.Scale)
 			} else {
 				converted, err = models.ConvertMetric(result.definition.kialiName, result.metric, conversionParams)
 				if err != nil {
@@ -5,6 +5,7 @@ type istioMetric struct {
 	istioName      string
 	isHisto        bool
 	useErrorLabels bool
+	withExemplars  bool
 }
 
 var istioMetrics = []istioMetric{
@@ -20,9 +21,10 @@ var istioMetrics = []istioMetric{
 		useErrorLabels: true,
 	},
 	{
-		kialiName: "request_duration_millis",
-		istioName: "istio_request_duration_milliseconds",
-		isHisto:   true,
+		kialiName:     "request_duration_millis",
+		istioName:     "istio_request_duration_milliseconds",
+		isHisto:       true,
+		withExemplars: true,
 	},
 	{
 		kialiName: "request_throughput",
@@ -0,0 +1,304 @@
+package business
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// defaultQueryNamespaceLabel is the label holding the namespace of the Istio telemetry, in the scoped queries
+const defaultQueryNamespaceLabel = "destination_workload_namespace"
+
+// QueryNamespaceLabels lists the labels that can scope a PromQL query to namespaces. Only labels known to hold
+// namespace names are accepted, otherwise series of other namespaces could match.
+func QueryNamespaceLabels() []string {
+	return []string{
+		defaultQueryNamespaceLabel,
+		"source_workload_namespace",
+		"destination_service_namespace",
+		"namespace",
+		config.Get().ExternalServices.CustomDashboards.NamespaceLabel,
+	}
+}
+
+// QueryScopedMetrics runs a PromQL query on the given range, after restricting every series selector of the query to
+// the given namespaces through the namespace label. The namespaces must have been checked for access by the caller.
+func (in *MetricsService) QueryScopedMetrics(query, namespaceLabel string, namespaces []string, q *prometheus.RangeQuery) ([]models.Metric, error) {
+	if namespaceLabel == "" {
+		namespaceLabel = defaultQueryNamespaceLabel
+	}
+	allowed := false
+	for _, label := range QueryNamespaceLabels() {
+		if label == namespaceLabel {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("label %q cannot scope a query, expected one of %s", namespaceLabel, strings.Join(QueryNamespaceLabels(), ", ")))
+	}
+	if len(namespaces) == 0 {
+		return []models.Metric{}, nil
+	}
+	scoped, err := injectNamespaceMatcher(query, namespaceLabel, namespaces)
+	if err != nil {
+		return nil, k8serrors.NewBadRequest(err.Error())
+	}
+	result := in.prom.FetchQueryRange(scoped, q)
+	if result.Err != nil {
+		return nil, k8serrors.NewServiceUnavailable(result.Err.Error())
+	}
+	return models.ConvertMetric("query", result, models.ConversionParams{Scale: 1.0})
+}
+
+var promQLKeywords = map[string]bool{
+	"and": true, "or": true, "unless": true, "atan2": true, "bool": true, "offset": true,
+	"by": true, "without": true, "on": true, "ignoring": true, "group_left": true, "group_right": true,
+	"sum": true, "avg": true, "count": true, "min": true, "max": true, "group": true, "stddev": true, "stdvar": true,
+	"topk": true, "bottomk": true, "count_values": true, "quantile": true, "inf": true, "nan": true,
+}
+
+var promQLLabelListKeywords = map[string]bool{
+	"by": true, "without": true, "on": true, "ignoring": true, "group_left": true, "group_right": true,
+}
+
+// Keywords followed by an operand, when they are not in the place of an operand themselves
+var promQLBinaryKeywords = map[string]bool{
+	"and": true, "or": true, "unless": true, "atan2": true, "bool": true,
+}
+
+// Label list keywords of the binary operations, followed by an operand
+var promQLMatchingKeywords = map[string]bool{
+	"on": true, "ignoring": true, "group_left": true, "group_right": true,
+}
+
+var identifierStartRE = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*`)
+
+// injectNamespaceMatcher adds a label matcher restricting the namespace label to the given namespaces, to every series
+// selector of the query: metric names, with or without braces, and bare brace selectors. Function names, keywords,
+// label lists (by, on...), strings, comments and durations are left untouched. Like Prometheus, a keyword in the place
+// of an operand is a metric name: it is turned into a __name__ matcher.
+func injectNamespaceMatcher(query, label string, namespaces []string) (string, error) {
+	quoted := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		quoted[i] = regexp.QuoteMeta(ns)
+	}
+	matcher := fmt.Sprintf("%s=~%s", label, strconv.Quote(strings.Join(quoted, "|")))
+
+	var out strings.Builder
+	// For each open parenthesis, the keyword of the list of labels it holds, if any
+	var parens []string
+	labelListKeyword := ""
+	// Whether the next token is an operand, rather than an operator
+	operand := true
+	// The modifiers allowed in the place of an operand, after a binary operator or its labels
+	modifiers := map[string]bool{}
+	i := 0
+	for i < len(query) {
+		c := query[i]
+		// A list of labels only follows a keyword such as "by", whitespaces and comments apart
+		afterLabelListKeyword := labelListKeyword
+		allowedModifiers := modifiers
+		if !isSpace(c) && c != '#' {
+			labelListKeyword = ""
+			modifiers = map[string]bool{}
+		}
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			end, err := skipString(query, i)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(query[i:end])
+			i = end
+			operand = false
+		case c == '#':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			out.WriteString(query[i : i+end])
+			i += end
+		case c == '(':
+			parens = append(parens, afterLabelListKeyword)
+			out.WriteByte(c)
+			i++
+			operand = true
+		case c == ')':
+			if len(parens) == 0 {
+				return "", fmt.Errorf("unbalanced parenthesis at position %d", i)
+			}
+			// The labels of a binary operation are followed by its right operand
+			operand = promQLMatchingKeywords[parens[len(parens)-1]]
+			if keyword := parens[len(parens)-1]; keyword == "on" || keyword == "ignoring" {
+				modifiers = map[string]bool{"group_left": true, "group_right": true}
+			}
+			parens = parens[:len(parens)-1]
+			out.WriteByte(c)
+			i++
+		case c == '[':
+			end := strings.IndexByte(query[i:], ']')
+			if end < 0 {
+				return "", fmt.Errorf("unclosed bracket at position %d", i)
+			}
+			out.WriteString(query[i : i+end+1])
+			i += end + 1
+			operand = false
+		case c == '{':
+			end, err := injectInBraces(&out, query, i, matcher)
+			if err != nil {
+				return "", err
+			}
+			i = end
+			operand = false
+		case c >= '0' && c <= '9' || c == '.':
+			// Numbers and durations, e.g. 0.5, 1e-3, 5m
+			j := i + 1
+			for j < len(query) && (isAlnum(query[j]) || query[j] == '.' || (query[j] == '-' || query[j] == '+') && (query[j-1] == 'e' || query[j-1] == 'E')) {
+				j++
+			}
+			out.WriteString(query[i:j])
+			i = j
+			operand = false
+		case identifierStartRE.MatchString(query[i:]):
+			ident := identifierStartRE.FindString(query[i:])
+			i += len(ident)
+			if len(parens) > 0 && parens[len(parens)-1] != "" {
+				// A label of a by/on... list
+				out.WriteString(ident)
+				continue
+			}
+			lower := strings.ToLower(ident)
+			next := i
+			for next < len(query) && isSpace(query[next]) {
+				next++
+			}
+			followedBy := func(b byte) bool { return next < len(query) && query[next] == b }
+			if !operand && promQLKeywords[lower] || allowedModifiers[lower] {
+				// An operator or a modifier
+				out.WriteString(ident)
+				operand = promQLBinaryKeywords[lower] || promQLMatchingKeywords[lower] && !followedBy('(')
+				if promQLLabelListKeywords[lower] {
+					labelListKeyword = lower
+				}
+				if promQLBinaryKeywords[lower] {
+					modifiers = map[string]bool{"on": true, "ignoring": true}
+				}
+				continue
+			}
+			if followedBy('(') {
+				// A function or an aggregation
+				out.WriteString(ident)
+				continue
+			}
+			if lower == "inf" || lower == "nan" {
+				out.WriteString(ident)
+				operand = false
+				continue
+			}
+			if promQLKeywords[lower] && !promQLLabelListKeywords[lower] {
+				// An aggregation with its labels first, e.g. sum by (app) (...)
+				if modifier := identifierStartRE.FindString(query[next:]); promQLLabelListKeywords[strings.ToLower(modifier)] {
+					out.WriteString(ident)
+					operand = false
+					continue
+				}
+			}
+			operand = false
+			// A metric name
+			if promQLKeywords[lower] {
+				var braces strings.Builder
+				if followedBy('{') {
+					end, err := injectInBraces(&braces, query, next, matcher)
+					if err != nil {
+						return "", err
+					}
+					i = end
+				} else {
+					braces.WriteString("{" + matcher + "}")
+				}
+				out.WriteString(fmt.Sprintf("{__name__=%s,%s", strconv.Quote(ident), strings.TrimPrefix(braces.String(), "{")))
+				continue
+			}
+			out.WriteString(ident)
+			if followedBy('{') {
+				out.WriteString(query[i:next])
+				end, err := injectInBraces(&out, query, next, matcher)
+				if err != nil {
+					return "", err
+				}
+				i = end
+			} else {
+				out.WriteString("{" + matcher + "}")
+			}
+		default:
+			if strings.IndexByte("+-*/%^=!<>,@", c) >= 0 {
+				operand = true
+			}
+			if strings.IndexByte("+-*/%^=!<>", c) >= 0 {
+				modifiers = map[string]bool{"on": true, "ignoring": true, "bool": strings.IndexByte("=!<>", c) >= 0}
+			}
+			out.WriteByte(c)
+			i++
+		}
+	}
+	if len(parens) > 0 {
+		return "", fmt.Errorf("unbalanced parenthesis")
+	}
+	return out.String(), nil
+}
+
+// injectInBraces copies the label matchers of the braces starting at position start, adding the matcher at the end.
+// It returns the position following the closing brace.
+func injectInBraces(out *strings.Builder, query string, start int, matcher string) (int, error) {
+	i := start + 1
+	for i < len(query) && query[i] != '}' {
+		if query[i] == '"' || query[i] == '\'' || query[i] == '`' {
+			end, err := skipString(query, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		} else {
+			i++
+		}
+	}
+	if i == len(query) {
+		return 0, fmt.Errorf("unclosed brace at position %d", start)
+	}
+	matchers := strings.TrimRight(query[start+1:i], " \t\r\n")
+	out.WriteString("{" + matchers)
+	if strings.TrimSpace(matchers) != "" && !strings.HasSuffix(matchers, ",") {
+		out.WriteString(",")
+	}
+	out.WriteString(matcher + "}")
+	return i + 1, nil
+}
+
+// skipString returns the position following the string literal starting at position start
+func skipString(query string, start int) (int, error) {
+	quote := query[start]
+	for i := start + 1; i < len(query); i++ {
+		if query[i] == '\\' && quote != '`' {
+			i++
+		} else if query[i] == quote {
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unclosed string at position %d", start)
+}
+
+func isAlnum(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
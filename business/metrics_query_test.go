@@ -0,0 +1,66 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/prometheus"
+	pmock "github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestInjectNamespaceMatcher(t *testing.T) {
+	assert := assert.New(t)
+	namespaces := []string{"bookinfo", "travel.agency"}
+	matcher := `ns=~"bookinfo|travel\\.agency"`
+
+	for query, expected := range map[string]string{
+		`up`:                     `up{` + matcher + `}`,
+		`up{job="x", }`:          `up{job="x",` + matcher + `}`,
+		`{__name__=~"istio_.*"}`: `{__name__=~"istio_.*",` + matcher + `}`,
+		`sum by (ns, pod) (rate(foo{a="}"}[5m]))`:                               `sum by (ns, pod) (rate(foo{a="}",` + matcher + `}[5m]))`,
+		`sum(rate(a[5m] offset 1h)) by (app) / on (app) group_left(version) b`:  `sum(rate(a{` + matcher + `}[5m] offset 1h)) by (app) / on (app) group_left(version) b{` + matcher + `}`,
+		`a * on(x) group_left rate(b[1m:10s])`:                                  `a{` + matcher + `} * on(x) group_left rate(b{` + matcher + `}[1m:10s])`,
+		`label_replace(x, "dst", "$1", "src", "(.*)") > bool 1e-3`:              `label_replace(x{` + matcher + `}, "dst", "$1", "src", "(.*)") > bool 1e-3`,
+		`topk(5, count_values("v", build_info)) # by (pod)`:                     `topk(5, count_values("v", build_info{` + matcher + `})) # by (pod)`,
+		`histogram_quantile(0.99, sum(rate(d_bucket[1m])) without (pod)) + Inf`: `histogram_quantile(0.99, sum(rate(d_bucket{` + matcher + `}[1m])) without (pod)) + Inf`,
+		`sum`:                                   `{__name__="sum",` + matcher + `}`,
+		`rate(count[5m]) / on(a) group_left by`: `rate({__name__="count",` + matcher + `}[5m]) / on(a) group_left {__name__="by",` + matcher + `}`,
+		`a and and`:                             `a{` + matcher + `} and {__name__="and",` + matcher + `}`,
+		`bool{job="x"} > bool offset`:           `{__name__="bool",job="x",` + matcher + `} > bool {__name__="offset",` + matcher + `}`,
+	} {
+		scoped, err := injectNamespaceMatcher(query, "ns", namespaces)
+		assert.NoError(err, query)
+		assert.Equal(expected, scoped, query)
+	}
+
+	for _, query := range []string{`sum(up`, `up)`, `up{a="b"`, `up{a="b}`, `rate(up[5m)`} {
+		_, err := injectNamespaceMatcher(query, "ns", namespaces)
+		assert.Error(err, query)
+	}
+}
+
+func TestQueryScopedMetrics(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	prom := new(pmock.PromClientMock)
+	service := NewMetricsService(prom)
+	q := prometheus.RangeQuery{}
+	q.FillDefaults()
+	prom.MockQuery(`sum(up{namespace=~"bookinfo"})`, &q, 1)
+
+	metrics, err := service.QueryScopedMetrics("sum(up)", "namespace", []string{"bookinfo"}, &q)
+	assert.NoError(err)
+	assert.Len(metrics, 1)
+	assert.Equal(float64(1), metrics[0].Datapoints[0].Value)
+
+	metrics, err = service.QueryScopedMetrics("sum(up)", "", []string{}, &q)
+	assert.NoError(err)
+	assert.Empty(metrics)
+
+	_, err = service.QueryScopedMetrics("sum(up)", "app", []string{"bookinfo"}, &q)
+	assert.EqualError(err, `label "app" cannot scope a query, expected one of destination_workload_namespace, source_workload_namespace, destination_service_namespace, namespace, kubernetes_namespace`)
+	prom.AssertNumberOfCalls(t, "FetchQueryRange", 1)
+}
@@ -51,7 +51,7 @@ func (in *SvcService) GetServiceList(namespace string, linkIstioResources bool)
 		var err2 error
 		// Check if namespace is cached
 		// Namespace access is checked in the upper call
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(in.k8s, namespace, kubernetes.ServiceType) {
 			svcs, err2 = kialiCache.GetServices(namespace, nil)
 		} else {
 			svcs, err2 = in.k8s.GetServices(namespace, nil)
@@ -67,7 +67,7 @@ func (in *SvcService) GetServiceList(namespace string, linkIstioResources bool)
 		var err2 error
 		// Check if namespace is cached
 		// Namespace access is checked in the upper call
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(in.k8s, namespace, kubernetes.PodType) {
 			pods, err2 = kialiCache.GetPods(namespace, "")
 		} else {
 			pods, err2 = in.k8s.GetPods(namespace, "")
@@ -83,7 +83,7 @@ func (in *SvcService) GetServiceList(namespace string, linkIstioResources bool)
 		var err2 error
 		// Check if namespace is cached
 		// Namespace access is checked in the upper call
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(in.k8s, namespace, kubernetes.DeploymentType) {
 			deployments, err2 = kialiCache.GetDeployments(namespace)
 		} else {
 			deployments, err2 = in.k8s.GetDeployments(namespace)
@@ -107,7 +107,7 @@ func (in *SvcService) GetServiceList(namespace string, linkIstioResources bool)
 			go func(namespace, resourceType string, dest *[]kubernetes.IstioObject, errChan chan error) {
 				defer wg.Done()
 				var err2 error
-				if IsNamespaceCached(namespace) {
+				if IsNamespaceCached(in.k8s, namespace, resourceType) {
 					*dest, err2 = kialiCache.GetIstioObjects(namespace, resourceType, "")
 				} else {
 					*dest, err2 = in.k8s.GetIstioObjects(namespace, resourceType, "")
@@ -227,7 +227,7 @@ func (in *SvcService) GetService(namespace, service, interval string, queryTime
 			var err2 error
 			// Check if namespace is cached
 			// Namespace access is checked in the upper caller
-			if IsNamespaceCached(namespace) {
+			if IsNamespaceCached(in.k8s, namespace, kubernetes.PodType) {
 				pods, err2 = kialiCache.GetPods(namespace, labelsSelector)
 			} else {
 				pods, err2 = in.k8s.GetPods(namespace, labelsSelector)
@@ -271,7 +271,7 @@ func (in *SvcService) GetService(namespace, service, interval string, queryTime
 		var err2 error
 		// Check if namespace is cached
 		// Namespace access is checked in the upper caller
-		if IsResourceCached(namespace, kubernetes.VirtualServices) {
+		if IsResourceCached(in.k8s, namespace, kubernetes.VirtualServices) {
 			vs, err2 = kialiCache.GetIstioObjects(namespace, kubernetes.VirtualServices, "")
 		} else {
 			vs, err2 = in.k8s.GetIstioObjects(namespace, kubernetes.VirtualServices, "")
@@ -286,7 +286,7 @@ func (in *SvcService) GetService(namespace, service, interval string, queryTime
 	go func() {
 		defer wg.Done()
 		var err2 error
-		if IsResourceCached(namespace, kubernetes.DestinationRules) {
+		if IsResourceCached(in.k8s, namespace, kubernetes.DestinationRules) {
 			dr, err2 = kialiCache.GetIstioObjects(namespace, kubernetes.DestinationRules, "")
 		} else {
 			dr, err2 = in.k8s.GetIstioObjects(namespace, kubernetes.DestinationRules, "")
@@ -367,7 +367,7 @@ func (in *SvcService) GetServiceDefinition(namespace, service string) (*models.S
 }
 
 func (in *SvcService) getService(namespace, service string) (svc *core_v1.Service, err error) {
-	if IsNamespaceCached(namespace) {
+	if IsNamespaceCached(in.k8s, namespace, kubernetes.ServiceType) {
 		// Cache uses Kiali ServiceAccount, check if user can access to the namespace
 		if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err == nil {
 			svc, err = kialiCache.GetService(namespace, service)
@@ -396,7 +396,7 @@ func (in *SvcService) getServiceDefinition(namespace, service string) (svc *core
 	go func() {
 		defer wg.Done()
 		var err2 error
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(in.k8s, namespace, kubernetes.EndpointsType) {
 			// Cache uses Kiali ServiceAccount, check if user can access to the namespace
 			if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err == nil {
 				eps, err = kialiCache.GetEndpoints(namespace, service)
@@ -433,7 +433,7 @@ func (in *SvcService) GetServiceDefinitionList(namespace string) (*models.Servic
 
 	var svcs []core_v1.Service
 	// Check if namespace is cached
-	if IsNamespaceCached(namespace) {
+	if IsNamespaceCached(in.k8s, namespace, kubernetes.ServiceType) {
 		svcs, err = kialiCache.GetServices(namespace, nil)
 	} else {
 		svcs, err = in.k8s.GetServices(namespace, nil)
@@ -0,0 +1,260 @@
+package business
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const sidecarScopingWizard = "sidecar_scoping"
+
+// SidecarScopingService recommends Sidecar objects limiting the configuration pushed to the proxies to the hosts
+// the workloads actually call
+type SidecarScopingService struct {
+	k8s           kubernetes.ClientInterface
+	prom          prometheus.ClientInterface
+	businessLayer *Layer
+}
+
+// GetSidecarRecommendations builds, for each workload of the namespace with a sidecar, a Sidecar whose egress
+// permits the hosts called over the rate interval, plus the control plane namespace. The reduction of Envoy clusters
+// is estimated from the config dump of a proxy of each workload.
+func (in *SidecarScopingService) GetSidecarRecommendations(namespace, rateInterval string, queryTime time.Time) (*models.SidecarRecommendations, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	rates, err := in.prom.GetNamespaceOutboundRates(namespace, rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	workloads, err := fetchWorkloads(in.businessLayer, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	var sidecars []kubernetes.IstioObject
+	if IsResourceCached(in.k8s, namespace, kubernetes.Sidecars) {
+		sidecars, err = kialiCache.GetIstioObjects(namespace, kubernetes.Sidecars, "")
+	} else {
+		sidecars, err = in.k8s.GetIstioObjects(namespace, kubernetes.Sidecars, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations, err := buildSidecarRecommendations(namespace, rateInterval, rates, workloads, sidecars)
+	if err != nil {
+		return nil, err
+	}
+
+	// Estimate the reduction of clusters on a proxy of each workload
+	pods := make(map[string]string)
+	for _, w := range workloads {
+		for _, p := range w.Pods {
+			if p.HasIstioSidecar() && p.Status == "Running" {
+				pods[w.Name] = p.Name
+				break
+			}
+		}
+	}
+	dumpErrors := make([]error, len(recommendations.Recommendations))
+	wg := sync.WaitGroup{}
+	semaphore := make(chan struct{}, maxConcurrentProxyDumps)
+	for i := range recommendations.Recommendations {
+		pod, ok := pods[recommendations.Recommendations[i].Workload]
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, pod string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			dump, err := in.k8s.GetConfigDump(namespace, pod)
+			if err != nil {
+				dumpErrors[i] = err
+				return
+			}
+			clusters, err := dump.GetClusters()
+			if err != nil {
+				dumpErrors[i] = err
+				return
+			}
+			r := &recommendations.Recommendations[i]
+			current, estimated := estimateClusters(clusters, r.Hosts)
+			r.CurrentClusters = &current
+			r.EstimatedClusters = &estimated
+		}(i, pod)
+	}
+	wg.Wait()
+	for i, err := range dumpErrors {
+		if err != nil {
+			recommendations.Warnings = append(recommendations.Warnings, fmt.Sprintf("Cannot estimate the clusters of workload %s from the config dump of pod %s: %v", recommendations.Recommendations[i].Workload, pods[recommendations.Recommendations[i].Workload], err))
+		}
+	}
+
+	return recommendations, nil
+}
+
+func buildSidecarRecommendations(namespace, rateInterval string, rates model.Vector, workloads models.Workloads, sidecars []kubernetes.IstioObject) (*models.SidecarRecommendations, error) {
+	recommendations := &models.SidecarRecommendations{
+		Namespace:       namespace,
+		RateInterval:    rateInterval,
+		Recommendations: []models.SidecarRecommendation{},
+		Warnings:        []string{},
+	}
+
+	hostsPerWorkload := make(map[string]map[string]bool)
+	passthrough := make(map[string]bool)
+	for _, s := range rates {
+		m := s.Metric
+		workload := string(m["source_workload"])
+		service := string(m["destination_service"])
+		serviceNamespace := string(m["destination_service_namespace"])
+		if _, ok := hostsPerWorkload[workload]; !ok {
+			hostsPerWorkload[workload] = make(map[string]bool)
+		}
+		switch {
+		case service == "" || service == "unknown" || service == "PassthroughCluster" || service == "BlackHoleCluster":
+			passthrough[workload] = true
+		case serviceNamespace == "" || serviceNamespace == "unknown":
+			// ServiceEntry, its namespace is unknown
+			hostsPerWorkload[workload]["*/"+service] = true
+		default:
+			hostsPerWorkload[workload][serviceNamespace+"/"+service] = true
+		}
+	}
+
+	conf := config.Get()
+	controlPlane := conf.IstioNamespace + "/*"
+	documents := []string{}
+	for _, w := range workloads {
+		if !w.IstioSidecar {
+			continue
+		}
+		// The app and version labels select the workload, other labels may change between deployments
+		selectorLabels := map[string]interface{}{}
+		for _, l := range []string{conf.IstioLabels.AppLabelName, conf.IstioLabels.VersionLabelName} {
+			if v, ok := w.Labels[l]; ok {
+				selectorLabels[l] = v
+			}
+		}
+		if _, ok := selectorLabels[conf.IstioLabels.AppLabelName]; !ok {
+			recommendations.Warnings = append(recommendations.Warnings, fmt.Sprintf("Workload %s has no %s label to select it: no Sidecar recommended", w.Name, conf.IstioLabels.AppLabelName))
+			continue
+		}
+		hostSet := map[string]bool{controlPlane: true}
+		for h := range hostsPerWorkload[w.Name] {
+			hostSet[h] = true
+		}
+		hosts := sortedKeys(hostSet)
+		// The control plane namespace includes its own hosts
+		hosts = compactHosts(hosts, controlPlane)
+
+		if _, ok := hostsPerWorkload[w.Name]; !ok {
+			recommendations.Warnings = append(recommendations.Warnings, fmt.Sprintf("No outbound traffic observed for workload %s: its Sidecar only permits the control plane", w.Name))
+		}
+		if passthrough[w.Name] {
+			recommendations.Warnings = append(recommendations.Warnings, fmt.Sprintf("Workload %s sent traffic to hosts unknown to the mesh: it still depends on the outbound traffic policy", w.Name))
+		}
+
+		egressHosts := make([]interface{}, 0, len(hosts))
+		for _, h := range hosts {
+			egressHosts = append(egressHosts, h)
+		}
+		sidecar := &kubernetes.GenericIstioObject{
+			TypeMeta: meta_v1.TypeMeta{Kind: kubernetes.SidecarType, APIVersion: kubernetes.ApiNetworkingVersion},
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      w.Name,
+				Namespace: namespace,
+				Labels:    map[string]string{wizardLabel: sidecarScopingWizard},
+			},
+			Spec: map[string]interface{}{
+				"workloadSelector": map[string]interface{}{"labels": selectorLabels},
+				"egress":           []interface{}{map[string]interface{}{"hosts": egressHosts}},
+			},
+		}
+		document, err := toYAML(sidecar)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+		recommendations.Recommendations = append(recommendations.Recommendations, models.SidecarRecommendation{
+			Workload:       w.Name,
+			Hosts:          hosts,
+			Sidecar:        sidecar,
+			CurrentSidecar: currentSidecar(w.Labels, sidecars),
+		})
+	}
+	recommendations.YAML = strings.Join(documents, "---\n")
+	return recommendations, nil
+}
+
+// compactHosts removes the hosts included in a namespace wildcard
+func compactHosts(hosts []string, wildcard string) []string {
+	prefix := strings.TrimSuffix(wildcard, "*")
+	compacted := []string{}
+	for _, h := range hosts {
+		if h == wildcard || !strings.HasPrefix(h, prefix) {
+			compacted = append(compacted, h)
+		}
+	}
+	return compacted
+}
+
+// currentSidecar returns the Sidecar of the namespace scoping a workload: the one selecting it, else the default one
+func currentSidecar(workloadLabels map[string]string, sidecars []kubernetes.IstioObject) string {
+	defaultSidecar := ""
+	for _, s := range sidecars {
+		if !common.HasWorkloadSelector(s) {
+			defaultSidecar = s.GetObjectMeta().Name
+			continue
+		}
+		if labels.SelectorFromSet(common.GetWorkloadSelectorLabels(s)).Matches(labels.Set(workloadLabels)) {
+			return s.GetObjectMeta().Name
+		}
+	}
+	return defaultSidecar
+}
+
+// estimateClusters counts the clusters of a proxy, and those left when its egress is limited to the hosts.
+// Outbound cluster names are in the "outbound|<port>|<subset>|<host>" format.
+func estimateClusters(clusters *kubernetes.ClusterDump, hosts []string) (current, estimated int) {
+	for _, c := range append(clusters.DynamicClusters, clusters.StaticClusters...) {
+		current++
+		parts := strings.Split(c.Cluster.Name, "|")
+		if parts[0] != "outbound" || len(parts) != 4 || egressAllows(hosts, parts[3]) {
+			estimated++
+		}
+	}
+	return current, estimated
+}
+
+// egressAllows tells whether a host is permitted by Sidecar egress hosts
+func egressAllows(egressHosts []string, host string) bool {
+	hostNamespace := ""
+	if parsed := kubernetes.ParseHost(host, "", ""); parsed.CompleteInput {
+		hostNamespace = parsed.Namespace
+	}
+	for _, eh := range egressHosts {
+		parts := strings.SplitN(eh, "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if (parts[0] == "*" || parts[0] == hostNamespace) && (parts[1] == "*" || parts[1] == host) {
+			return true
+		}
+	}
+	return false
+}
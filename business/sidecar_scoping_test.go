@@ -0,0 +1,120 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+func TestBuildSidecarRecommendations(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	rates := model.Vector{
+		&model.Sample{Metric: model.Metric{
+			"source_workload":               "productpage-v1",
+			"destination_service":           "reviews.bookinfo.svc.cluster.local",
+			"destination_service_namespace": "bookinfo",
+		}, Value: 1},
+		&model.Sample{Metric: model.Metric{
+			"source_workload":               "productpage-v1",
+			"destination_service":           "istiod.istio-system.svc.cluster.local",
+			"destination_service_namespace": "istio-system",
+		}, Value: 1},
+		&model.Sample{Metric: model.Metric{
+			"source_workload":               "reviews-v1",
+			"destination_service":           "api.example.com",
+			"destination_service_namespace": "unknown",
+		}, Value: 1},
+		&model.Sample{Metric: model.Metric{
+			"source_workload":               "reviews-v1",
+			"destination_service":           "PassthroughCluster",
+			"destination_service_namespace": "unknown",
+		}, Value: 1},
+	}
+	workloads := models.Workloads{
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "productpage-v1", IstioSidecar: true, Labels: map[string]string{"app": "productpage"}}},
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "reviews-v1", IstioSidecar: true, Labels: map[string]string{"app": "reviews", "version": "v1", "pod-template-hash": "5c5b9c7d"}}},
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "unlabelled", IstioSidecar: true, Labels: map[string]string{"run": "unlabelled"}}},
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "ratings-v1", IstioSidecar: true, Labels: map[string]string{"app": "ratings"}}},
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "legacy", IstioSidecar: false, Labels: map[string]string{"app": "legacy"}}},
+	}
+	sidecars := []kubernetes.IstioObject{
+		&kubernetes.GenericIstioObject{ObjectMeta: meta_v1.ObjectMeta{Name: "default"}, Spec: map[string]interface{}{}},
+		&kubernetes.GenericIstioObject{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "reviews"},
+			Spec: map[string]interface{}{
+				"workloadSelector": map[string]interface{}{"labels": map[string]interface{}{"app": "reviews"}},
+			},
+		},
+	}
+
+	recommendations, err := buildSidecarRecommendations("bookinfo", "1h", rates, workloads, sidecars)
+	assert.NoError(err)
+	assert.Len(recommendations.Recommendations, 3)
+	assert.Equal([]string{
+		"Workload reviews-v1 sent traffic to hosts unknown to the mesh: it still depends on the outbound traffic policy",
+		"Workload unlabelled has no app label to select it: no Sidecar recommended",
+		"No outbound traffic observed for workload ratings-v1: its Sidecar only permits the control plane",
+	}, recommendations.Warnings)
+
+	productpage := recommendations.Recommendations[0]
+	assert.Equal("productpage-v1", productpage.Workload)
+	assert.Equal([]string{"bookinfo/reviews.bookinfo.svc.cluster.local", "istio-system/*"}, productpage.Hosts)
+	assert.Equal("default", productpage.CurrentSidecar)
+	assert.Equal(sidecarScopingWizard, productpage.Sidecar.Labels[wizardLabel])
+
+	reviews := recommendations.Recommendations[1]
+	assert.Equal([]string{"*/api.example.com", "istio-system/*"}, reviews.Hosts)
+	assert.Equal("reviews", reviews.CurrentSidecar)
+	assert.Equal(map[string]interface{}{"labels": map[string]interface{}{"app": "reviews", "version": "v1"}}, reviews.Sidecar.Spec["workloadSelector"])
+
+	ratings := recommendations.Recommendations[2]
+	assert.Equal([]string{"istio-system/*"}, ratings.Hosts)
+
+	assert.Contains(recommendations.YAML, "kind: Sidecar")
+	assert.Contains(recommendations.YAML, "- '*/api.example.com'")
+}
+
+func TestEstimateClusters(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	clusters := &kubernetes.ClusterDump{
+		DynamicClusters: []kubernetes.EnvoyClusterWrapper{
+			{Cluster: kubernetes.EnvoyCluster{Name: "outbound|9080||reviews.bookinfo.svc.cluster.local"}},
+			{Cluster: kubernetes.EnvoyCluster{Name: "outbound|9080|v1|reviews.bookinfo.svc.cluster.local"}},
+			{Cluster: kubernetes.EnvoyCluster{Name: "outbound|9080||ratings.bookinfo.svc.cluster.local"}},
+			{Cluster: kubernetes.EnvoyCluster{Name: "outbound|15012||istiod.istio-system.svc.cluster.local"}},
+			{Cluster: kubernetes.EnvoyCluster{Name: "outbound|443||api.example.com"}},
+			{Cluster: kubernetes.EnvoyCluster{Name: "outbound|8080||foo.other.svc.cluster.local"}},
+			{Cluster: kubernetes.EnvoyCluster{Name: "inbound|9080||"}},
+		},
+		StaticClusters: []kubernetes.EnvoyClusterWrapper{
+			{Cluster: kubernetes.EnvoyCluster{Name: "BlackHoleCluster"}},
+		},
+	}
+
+	current, estimated := estimateClusters(clusters, []string{"bookinfo/reviews.bookinfo.svc.cluster.local", "*/api.example.com", "istio-system/*"})
+	assert.Equal(8, current)
+	// both reviews subsets, istiod, api.example.com, the inbound and the static clusters
+	assert.Equal(6, estimated)
+}
+
+func TestEgressAllows(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	assert.True(egressAllows([]string{"*/*"}, "reviews.bookinfo.svc.cluster.local"))
+	assert.True(egressAllows([]string{"bookinfo/*"}, "reviews.bookinfo.svc.cluster.local"))
+	assert.True(egressAllows([]string{"*/reviews.bookinfo.svc.cluster.local"}, "reviews.bookinfo.svc.cluster.local"))
+	assert.False(egressAllows([]string{"other/*"}, "reviews.bookinfo.svc.cluster.local"))
+	assert.False(egressAllows([]string{"bookinfo/*"}, "api.example.com"))
+	assert.False(egressAllows([]string{"invalid"}, "api.example.com"))
+}
@@ -8,11 +8,13 @@ import (
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/util/mtls"
 )
 
 type TLSService struct {
 	k8s             kubernetes.ClientInterface
+	prom            prometheus.ClientInterface
 	businessLayer   *Layer
 	enabledAutoMtls *bool
 }
@@ -51,7 +53,7 @@ func (in *TLSService) getMeshPeerAuthentications() ([]kubernetes.IstioObject, er
 	var mps []kubernetes.IstioObject
 	var err error
 	controlPlaneNs := config.Get().IstioNamespace
-	if IsResourceCached(controlPlaneNs, kubernetes.PeerAuthentications) {
+	if IsResourceCached(in.k8s, controlPlaneNs, kubernetes.PeerAuthentications) {
 		mps, err = kialiCache.GetIstioObjects(controlPlaneNs, kubernetes.PeerAuthentications, "")
 	} else {
 		mps, err = in.k8s.GetIstioObjects(controlPlaneNs, kubernetes.PeerAuthentications, "")
@@ -73,7 +75,7 @@ func (in *TLSService) getAllDestinationRules(namespaces []string) ([]kubernetes.
 			var err error
 			// Check if namespace is cached
 			// Namespace access is checked in the upper call
-			if IsResourceCached(ns, kubernetes.DestinationRules) {
+			if IsResourceCached(in.k8s, ns, kubernetes.DestinationRules) {
 				drs, err = kialiCache.GetIstioObjects(ns, kubernetes.DestinationRules, "")
 			} else {
 				drs, err = in.k8s.GetIstioObjects(ns, kubernetes.DestinationRules, "")
@@ -138,7 +140,7 @@ func (in TLSService) getPeerAuthentications(namespace string) ([]kubernetes.Isti
 	if namespace == config.Get().IstioNamespace {
 		return []kubernetes.IstioObject{}, nil
 	}
-	if IsResourceCached(namespace, kubernetes.PeerAuthentications) {
+	if IsResourceCached(in.k8s, namespace, kubernetes.PeerAuthentications) {
 		return kialiCache.GetIstioObjects(namespace, kubernetes.PeerAuthentications, "")
 	} else {
 		return in.k8s.GetIstioObjects(namespace, kubernetes.PeerAuthentications, "")
@@ -167,7 +169,7 @@ func (in *TLSService) hasAutoMTLSEnabled() bool {
 	cfg := config.Get()
 	var istioConfig *core_v1.ConfigMap
 	var err error
-	if IsNamespaceCached(cfg.IstioNamespace) {
+	if IsNamespaceCached(in.k8s, cfg.IstioNamespace, kubernetes.ConfigMapType) {
 		istioConfig, err = kialiCache.GetConfigMap(cfg.IstioNamespace, cfg.ExternalServices.Istio.ConfigMapName)
 	} else {
 		istioConfig, err = in.k8s.GetConfigMap(cfg.IstioNamespace, cfg.ExternalServices.Istio.ConfigMapName)
@@ -0,0 +1,277 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	mtlsMigrationWizard = "mtls_migration"
+	// The PERMISSIVE PeerAuthentications of the workloads are prefixed so that they don't collide with the policies of the users
+	mtlsMigrationPrefix = "kiali-mtls-migration-"
+	// Name Istio gives by convention to the namespace-wide PeerAuthentication
+	namespacePeerAuthnName = "default"
+)
+
+// GetMtlsMigration reports, for each workload of the namespace, its PeerAuthentication mode and the mTLS and plain
+// text traffic it received over the rate interval, with the clients still sending plain text. It tells whether
+// switching the namespace to STRICT would break any of them, and generates the PeerAuthentications of the next
+// step: STRICT for the namespace, PERMISSIVE for the workloads still receiving plain text.
+func (in *TLSService) GetMtlsMigration(namespace, rateInterval string, queryTime time.Time) (*models.MtlsMigrationReport, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	authzConfig, err := in.businessLayer.Authorization.GetAuthorizationConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	workloads, err := fetchWorkloads(in.businessLayer, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	rates, err := in.prom.GetNamespaceInboundSecurityRates(namespace, rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Whether the plain text clients have a sidecar, for the accessible namespaces
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	accessible := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		accessible[ns.Name] = true
+	}
+	clientNamespaces := make(map[string]bool)
+	for _, s := range rates {
+		if string(s.Metric["connection_security_policy"]) == "none" && accessible[string(s.Metric["source_workload_namespace"])] {
+			clientNamespaces[string(s.Metric["source_workload_namespace"])] = true
+		}
+	}
+	clientSidecars := make(map[string]bool)
+	for ns := range clientNamespaces {
+		clients := workloads
+		if ns != namespace {
+			if clients, err = fetchWorkloads(in.businessLayer, ns, ""); err != nil {
+				return nil, err
+			}
+		}
+		for _, w := range clients {
+			clientSidecars[ns+"/"+w.Name] = w.IstioSidecar
+		}
+	}
+
+	return buildMtlsMigration(namespace, rateInterval, authzConfig, workloads, rates, clientSidecars)
+}
+
+func buildMtlsMigration(namespace, rateInterval string, authzConfig *AuthorizationConfig, workloads models.Workloads, rates model.Vector, clientSidecars map[string]bool) (*models.MtlsMigrationReport, error) {
+	report := &models.MtlsMigrationReport{
+		Namespace:           namespace,
+		RateInterval:        rateInterval,
+		AutoMtls:            authzConfig.AutoMtls(),
+		Workloads:           []models.WorkloadMtlsMigration{},
+		ReadyForStrict:      true,
+		PeerAuthentications: []*kubernetes.GenericIstioObject{},
+		Warnings:            []string{},
+	}
+	report.NamespaceMode, _ = authzConfig.PeerAuthenticationMode(nil)
+
+	mtlsRates := make(map[string]float64)
+	plaintextClients := make(map[string]map[string]float64)
+	for _, s := range rates {
+		workload := string(s.Metric["destination_workload"])
+		switch string(s.Metric["connection_security_policy"]) {
+		case "mutual_tls":
+			mtlsRates[workload] += float64(s.Value)
+		case "none":
+			if _, ok := plaintextClients[workload]; !ok {
+				plaintextClients[workload] = make(map[string]float64)
+			}
+			plaintextClients[workload][fmt.Sprintf("%s/%s", s.Metric["source_workload_namespace"], s.Metric["source_workload"])] += float64(s.Value)
+		}
+	}
+
+	sorted := make(models.Workloads, len(workloads))
+	copy(sorted, workloads)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	permissive := []*models.Workload{}
+	for _, w := range sorted {
+		migration := models.WorkloadMtlsMigration{
+			Workload:         w.Name,
+			HasSidecar:       w.IstioSidecar,
+			MtlsRate:         mtlsRates[w.Name],
+			PlaintextClients: []models.MtlsMigrationClient{},
+		}
+		migration.Mode, migration.PeerAuthentication = authzConfig.PeerAuthenticationMode(w.Labels)
+
+		clients := make([]string, 0, len(plaintextClients[w.Name]))
+		for c := range plaintextClients[w.Name] {
+			clients = append(clients, c)
+		}
+		sort.Strings(clients)
+		for _, c := range clients {
+			rate := plaintextClients[w.Name][c]
+			parts := strings.SplitN(c, "/", 2)
+			migration.PlaintextRate += rate
+			migration.PlaintextClients = append(migration.PlaintextClients, models.MtlsMigrationClient{
+				Namespace: parts[0],
+				Workload:  parts[1],
+				Rate:      rate,
+				Reason:    plaintextReason(parts[0], parts[1], clientSidecars, report.AutoMtls),
+			})
+		}
+
+		switch {
+		case !w.IstioSidecar:
+			report.Warnings = append(report.Warnings, fmt.Sprintf("Workload %s has no sidecar: it can't receive mTLS traffic, add it to the mesh first", w.Name))
+		case migration.PeerAuthentication != "":
+			if migration.Mode != MtlsModeStrict && len(migration.PlaintextClients) == 0 {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("Workload %s only receives mTLS traffic: its PeerAuthentication %s can be switched to STRICT", w.Name, migration.PeerAuthentication))
+			}
+		case len(migration.PlaintextClients) > 0:
+			migration.BreaksOnStrict = true
+			report.ReadyForStrict = false
+			permissive = append(permissive, w)
+		}
+		report.MtlsRate += migration.MtlsRate
+		report.PlaintextRate += migration.PlaintextRate
+		report.Workloads = append(report.Workloads, migration)
+	}
+
+	// Next step: the namespace goes STRICT, except the workloads still receiving plain text
+	if report.NamespaceMode != MtlsModeStrict {
+		// The app and version labels select the workload, other labels may change between deployments
+		conf := config.Get()
+		selectors := make([]map[string]string, 0, len(permissive))
+		for _, w := range permissive {
+			selector := map[string]string{}
+			for _, l := range []string{conf.IstioLabels.AppLabelName, conf.IstioLabels.VersionLabelName} {
+				if v, ok := w.Labels[l]; ok {
+					selector[l] = v
+				}
+			}
+			if _, ok := selector[conf.IstioLabels.AppLabelName]; !ok {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("Workload %s has no %s label to keep it PERMISSIVE: the namespace can't be switched to STRICT while it receives plain text", w.Name, conf.IstioLabels.AppLabelName))
+				permissive = nil
+				break
+			}
+			selectors = append(selectors, selector)
+		}
+		if permissive != nil {
+			// The existing namespace-wide PeerAuthentication is updated, a namespace can only have one
+			name := authzConfig.NamespacePeerAuthentication()
+			if name == "" {
+				name = namespacePeerAuthnName
+			}
+			report.PeerAuthentications = append(report.PeerAuthentications, newPeerAuthentication(name, namespace, nil, MtlsModeStrict))
+			for i, w := range permissive {
+				report.PeerAuthentications = append(report.PeerAuthentications, newPeerAuthentication(mtlsMigrationPrefix+w.Name, namespace, selectors[i], MtlsModePermissive))
+			}
+		}
+	}
+
+	documents := make([]string, 0, len(report.PeerAuthentications))
+	for _, pa := range report.PeerAuthentications {
+		document, err := toYAML(pa)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	report.YAML = strings.Join(documents, "---\n")
+	return report, nil
+}
+
+// plaintextReason explains why a client sends plain text, hence why STRICT mode would reject it
+func plaintextReason(namespace, workload string, clientSidecars map[string]bool, autoMtls bool) string {
+	hasSidecar, found := clientSidecars[namespace+"/"+workload]
+	switch {
+	case workload == "" || workload == "unknown":
+		return "Client outside the mesh"
+	case !found:
+		return "Client in a namespace that is not accessible"
+	case !hasSidecar:
+		return "Client without sidecar"
+	case autoMtls:
+		return "Client sidecar sends plain text: a DestinationRule may disable TLS"
+	}
+	return "Client sidecar sends plain text: auto mTLS is disabled, a DestinationRule must set ISTIO_MUTUAL"
+}
+
+func newPeerAuthentication(name, namespace string, selectorLabels map[string]string, mode string) *kubernetes.GenericIstioObject {
+	spec := map[string]interface{}{"mtls": map[string]interface{}{"mode": mode}}
+	if len(selectorLabels) > 0 {
+		matchLabels := make(map[string]interface{}, len(selectorLabels))
+		for k, v := range selectorLabels {
+			matchLabels[k] = v
+		}
+		spec["selector"] = map[string]interface{}{"matchLabels": matchLabels}
+	}
+	return &kubernetes.GenericIstioObject{
+		TypeMeta: meta_v1.TypeMeta{Kind: kubernetes.PeerAuthenticationsType, APIVersion: kubernetes.ApiSecurityVersion},
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{wizardLabel: mtlsMigrationWizard},
+		},
+		Spec: spec,
+	}
+}
+
+// PeerAuthenticationMode returns the mTLS mode applying to a workload with the given labels, and the name of the
+// workload-level PeerAuthentication setting it, if any: such workloads don't follow the namespace mode.
+func (c *AuthorizationConfig) PeerAuthenticationMode(destLabels map[string]string) (mode, workloadPeerAuthn string) {
+	input := c.base
+	input.destLabels = destLabels
+	if workloadPA, _, _ := applyingPeerAuthentications(&input); workloadPA != nil && peerAuthnMode(workloadPA.GetSpec()["mtls"]) != mtlsModeUnset {
+		workloadPeerAuthn = workloadPA.GetObjectMeta().Name
+	}
+	return effectiveMtlsMode(&input), workloadPeerAuthn
+}
+
+// NamespacePeerAuthentication returns the name of the namespace-wide PeerAuthentication, the one without selector,
+// or an empty string when the namespace has none
+func (c *AuthorizationConfig) NamespacePeerAuthentication() string {
+	input := c.base
+	if _, namespacePA, _ := applyingPeerAuthentications(&input); namespacePA != nil {
+		return namespacePA.GetObjectMeta().Name
+	}
+	return ""
+}
+
+// AutoMtls tells whether the clients with sidecars send mTLS to the workloads with sidecars, whatever the mode
+func (c *AuthorizationConfig) AutoMtls() bool {
+	return c.base.autoMtls
+}
+
+// applyingPeerAuthentications returns the PeerAuthentications applying to the destination workload, by level
+func applyingPeerAuthentications(input *authzInput) (workloadPA, namespacePA, meshPA kubernetes.IstioObject) {
+	for _, pa := range input.peerAuthns {
+		if common.HasSelector(pa) {
+			if workloadPA == nil && selectorMatches(pa, input.destLabels) {
+				workloadPA = pa
+			}
+		} else if namespacePA == nil {
+			namespacePA = pa
+		}
+	}
+	for _, pa := range input.meshPeerAuthns {
+		if !common.HasSelector(pa) {
+			meshPA = pa
+			break
+		}
+	}
+	return workloadPA, namespacePA, meshPA
+}
@@ -0,0 +1,134 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+func TestBuildMtlsMigration(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	authzConfig := &AuthorizationConfig{base: authzInput{
+		autoMtls: true,
+		peerAuthns: []kubernetes.IstioObject{
+			&kubernetes.GenericIstioObject{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "details", Namespace: "bookinfo"},
+				Spec: map[string]interface{}{
+					"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "details"}},
+					"mtls":     map[string]interface{}{"mode": "PERMISSIVE"},
+				},
+			},
+		},
+	}}
+	workloads := models.Workloads{
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "reviews-v1", IstioSidecar: true, Labels: map[string]string{"app": "reviews", "version": "v1", "pod-template-hash": "7d8b9c"}}},
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "details-v1", IstioSidecar: true, Labels: map[string]string{"app": "details"}}},
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "productpage-v1", IstioSidecar: true, Labels: map[string]string{"app": "productpage"}}},
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "legacy", IstioSidecar: false, Labels: map[string]string{"app": "legacy"}}},
+	}
+	rates := model.Vector{
+		&model.Sample{Metric: model.Metric{
+			"destination_workload":       "reviews-v1",
+			"source_workload_namespace":  "bookinfo",
+			"source_workload":            "productpage-v1",
+			"connection_security_policy": "mutual_tls",
+		}, Value: 10},
+		&model.Sample{Metric: model.Metric{
+			"destination_workload":       "reviews-v1",
+			"source_workload_namespace":  "bookinfo",
+			"source_workload":            "legacy",
+			"connection_security_policy": "none",
+		}, Value: 2},
+		&model.Sample{Metric: model.Metric{
+			"destination_workload":       "reviews-v1",
+			"source_workload_namespace":  "unknown",
+			"source_workload":            "unknown",
+			"connection_security_policy": "none",
+		}, Value: 1},
+		&model.Sample{Metric: model.Metric{
+			"destination_workload":       "details-v1",
+			"source_workload_namespace":  "bookinfo",
+			"source_workload":            "productpage-v1",
+			"connection_security_policy": "mutual_tls",
+		}, Value: 5},
+	}
+	clientSidecars := map[string]bool{"bookinfo/productpage-v1": true, "bookinfo/legacy": false}
+
+	report, err := buildMtlsMigration("bookinfo", "1h", authzConfig, workloads, rates, clientSidecars)
+	assert.NoError(err)
+	assert.Equal(MtlsModePermissive, report.NamespaceMode)
+	assert.True(report.AutoMtls)
+	assert.False(report.ReadyForStrict)
+	assert.Equal(15.0, report.MtlsRate)
+	assert.Equal(3.0, report.PlaintextRate)
+	assert.Equal([]string{
+		"Workload details-v1 only receives mTLS traffic: its PeerAuthentication details can be switched to STRICT",
+		"Workload legacy has no sidecar: it can't receive mTLS traffic, add it to the mesh first",
+	}, report.Warnings)
+
+	assert.Len(report.Workloads, 4)
+	details := report.Workloads[0]
+	assert.Equal("details-v1", details.Workload)
+	assert.Equal("details", details.PeerAuthentication)
+	assert.False(details.BreaksOnStrict)
+
+	reviews := report.Workloads[3]
+	assert.Equal("reviews-v1", reviews.Workload)
+	assert.Equal(MtlsModePermissive, reviews.Mode)
+	assert.True(reviews.BreaksOnStrict)
+	assert.Equal([]models.MtlsMigrationClient{
+		{Namespace: "bookinfo", Workload: "legacy", Rate: 2, Reason: "Client without sidecar"},
+		{Namespace: "unknown", Workload: "unknown", Rate: 1, Reason: "Client outside the mesh"},
+	}, reviews.PlaintextClients)
+
+	// The namespace goes STRICT, reviews-v1 stays PERMISSIVE
+	assert.Len(report.PeerAuthentications, 2)
+	assert.Equal(namespacePeerAuthnName, report.PeerAuthentications[0].Name)
+	assert.Equal(map[string]interface{}{"mtls": map[string]interface{}{"mode": MtlsModeStrict}}, report.PeerAuthentications[0].Spec)
+	assert.Equal("kiali-mtls-migration-reviews-v1", report.PeerAuthentications[1].Name)
+	assert.Equal(map[string]interface{}{
+		"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "reviews", "version": "v1"}},
+		"mtls":     map[string]interface{}{"mode": MtlsModePermissive},
+	}, report.PeerAuthentications[1].Spec)
+	assert.Contains(report.YAML, "kind: PeerAuthentication")
+
+	// The existing namespace-wide PeerAuthentication is updated rather than duplicated
+	authzConfig.base.peerAuthns = append(authzConfig.base.peerAuthns, &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "namespace-mtls", Namespace: "bookinfo"},
+		Spec:       map[string]interface{}{"mtls": map[string]interface{}{"mode": "PERMISSIVE"}},
+	})
+	report, err = buildMtlsMigration("bookinfo", "1h", authzConfig, workloads, rates, clientSidecars)
+	assert.NoError(err)
+	assert.Equal("namespace-mtls", report.PeerAuthentications[0].Name)
+}
+
+func TestBuildMtlsMigrationStrict(t *testing.T) {
+	assert := assert.New(t)
+
+	authzConfig := &AuthorizationConfig{base: authzInput{
+		peerAuthns: []kubernetes.IstioObject{
+			&kubernetes.GenericIstioObject{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "default", Namespace: "bookinfo"},
+				Spec:       map[string]interface{}{"mtls": map[string]interface{}{"mode": "STRICT"}},
+			},
+		},
+	}}
+	workloads := models.Workloads{
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "reviews-v1", IstioSidecar: true, Labels: map[string]string{"app": "reviews"}}},
+	}
+
+	report, err := buildMtlsMigration("bookinfo", "1h", authzConfig, workloads, model.Vector{}, map[string]bool{})
+	assert.NoError(err)
+	assert.Equal(MtlsModeStrict, report.NamespaceMode)
+	assert.True(report.ReadyForStrict)
+	assert.Empty(report.PeerAuthentications)
+	assert.Empty(report.YAML)
+}
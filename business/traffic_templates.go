@@ -0,0 +1,453 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// Label set on generated objects, shared with the UI wizards
+const wizardLabel = "kiali_wizard"
+
+// Wizard label values of generated objects
+const (
+	WizardWeightedRouting = "traffic_shifting"
+	WizardHeaderRouting   = "request_routing"
+	WizardTCPRouting      = "tcp_traffic_shifting"
+	WizardFaultInjection  = "fault_injection"
+	WizardRequestTimeouts = "request_timeouts"
+)
+
+// TrafficTemplatesService generates VirtualServices, DestinationRules and Gateways from high-level traffic intents
+type TrafficTemplatesService struct {
+	k8s           kubernetes.ClientInterface
+	businessLayer *Layer
+}
+
+// GenerateTrafficConfig builds the Istio objects implementing the intent for the given service, and validates
+// them against the existing configuration of the namespace. Nothing is applied to the cluster.
+func (in *TrafficTemplatesService) GenerateTrafficConfig(namespace, service string, intent models.TrafficIntent) (*models.TrafficConfig, error) {
+	svc, err := in.businessLayer.Svc.getService(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("service %s has no selector, its workloads can't be resolved", service))
+	}
+	workloads, err := fetchWorkloads(in.businessLayer, namespace, labels.Set(svc.Spec.Selector).String())
+	if err != nil {
+		return nil, err
+	}
+	trafficConfig, err := buildTrafficConfig(namespace, service, workloads, intent)
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+	drafts := []kubernetes.IstioObject{trafficConfig.VirtualService, trafficConfig.DestinationRule}
+	if trafficConfig.Gateway != nil {
+		drafts = append(drafts, trafficConfig.Gateway)
+	}
+	trafficConfig.Validations, err = in.businessLayer.Validations.ValidateDrafts(namespace, drafts)
+	if err != nil {
+		return nil, err
+	}
+	return trafficConfig, nil
+}
+
+// ApplyTrafficConfig creates the generated objects, or updates them when they already exist. When an object can't
+// be applied, the objects already applied are restored, so that the namespace isn't left with a partial config.
+func (in *TrafficTemplatesService) ApplyTrafficConfig(namespace string, trafficConfig *models.TrafficConfig) error {
+	objects := []struct {
+		resourceType string
+		object       *kubernetes.GenericIstioObject
+	}{
+		{kubernetes.Gateways, trafficConfig.Gateway},
+		{kubernetes.DestinationRules, trafficConfig.DestinationRule},
+		{kubernetes.VirtualServices, trafficConfig.VirtualService},
+	}
+	undos := []func() error{}
+	for _, o := range objects {
+		if o.object == nil {
+			continue
+		}
+		undo, err := createOrReplaceIstioObject(in.businessLayer, in.k8s, namespace, o.resourceType, o.object)
+		if err != nil {
+			for i := len(undos) - 1; i >= 0; i-- {
+				if undoErr := undos[i](); undoErr != nil {
+					log.Errorf("Cannot roll back the traffic config applied in namespace [%s]: %v", namespace, undoErr)
+				}
+			}
+			return err
+		}
+		undos = append(undos, undo)
+	}
+	trafficConfig.Applied = true
+	return nil
+}
+
+// createOrReplaceIstioObject creates an Istio object, or replaces the spec of the existing one. It returns the
+// function undoing the change.
+func createOrReplaceIstioObject(layer *Layer, k8s kubernetes.ClientInterface, namespace, resourceType string, object *kubernetes.GenericIstioObject) (func() error, error) {
+	api := kubernetes.ResourceTypesToAPI[resourceType]
+	existing, err := k8s.GetIstioObject(namespace, resourceType, object.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err != nil {
+		body, err := json.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = layer.IstioConfig.CreateIstioConfigDetail(api, namespace, resourceType, body); err != nil {
+			return nil, err
+		}
+		return func() error {
+			return layer.IstioConfig.DeleteIstioConfigDetail(api, namespace, resourceType, object.Name)
+		}, nil
+	}
+	patch, err := replacePatch(existing, object)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = layer.IstioConfig.UpdateIstioConfigDetail(api, namespace, resourceType, object.Name, patch); err != nil {
+		return nil, err
+	}
+	return func() error {
+		patch, err := restorePatch(object, existing)
+		if err != nil {
+			return err
+		}
+		_, err = layer.IstioConfig.UpdateIstioConfigDetail(api, namespace, resourceType, object.Name, patch)
+		return err
+	}, nil
+}
+
+// replacePatch builds a merge patch replacing the spec of an existing object, removing the fields it doesn't define
+func replacePatch(existing kubernetes.IstioObject, object *kubernetes.GenericIstioObject) (string, error) {
+	spec := make(map[string]interface{}, len(object.Spec))
+	for k := range existing.GetSpec() {
+		spec[k] = nil
+	}
+	for k, v := range object.Spec {
+		spec[k] = v
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": object.Labels,
+		},
+		"spec": spec,
+	})
+	return string(patch), err
+}
+
+// restorePatch builds a merge patch restoring the spec and the labels of an object replaced by replacePatch
+func restorePatch(applied *kubernetes.GenericIstioObject, previous kubernetes.IstioObject) (string, error) {
+	spec := make(map[string]interface{}, len(applied.Spec))
+	for k := range applied.Spec {
+		spec[k] = nil
+	}
+	for k, v := range previous.GetSpec() {
+		spec[k] = v
+	}
+	previousLabels := previous.GetObjectMeta().Labels
+	labels := make(map[string]interface{}, len(applied.Labels))
+	for k := range applied.Labels {
+		labels[k] = nil
+	}
+	for k, v := range previousLabels {
+		labels[k] = v
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": labels,
+		},
+		"spec": spec,
+	})
+	return string(patch), err
+}
+
+func buildTrafficConfig(namespace, service string, workloads models.Workloads, intent models.TrafficIntent) (*models.TrafficConfig, error) {
+	routings := 0
+	for _, defined := range []bool{len(intent.WeightedRoutes) > 0, len(intent.HeaderRoutes) > 0, len(intent.TCPRoutes) > 0} {
+		if defined {
+			routings++
+		}
+	}
+	if routings > 1 {
+		return nil, fmt.Errorf("only one of weightedRoutes, headerRoutes and tcpRoutes can be defined")
+	}
+	if routings == 0 && intent.Fault == nil && intent.Timeout == "" && intent.Retries == nil && intent.CircuitBreaker == nil && intent.Gateway == nil {
+		return nil, fmt.Errorf("the intent is empty")
+	}
+	if len(intent.TCPRoutes) > 0 && (intent.Fault != nil || intent.Timeout != "" || intent.Retries != nil) {
+		return nil, fmt.Errorf("fault injection, timeouts and retries only apply to HTTP routing")
+	}
+	if intent.Fault != nil && intent.Fault.DelayPercent > 0 {
+		if _, err := time.ParseDuration(intent.Fault.FixedDelay); err != nil {
+			return nil, fmt.Errorf("a fault delay requires a valid fixedDelay, e.g. 5s, got [%s]", intent.Fault.FixedDelay)
+		}
+	}
+	if intent.Fault != nil && intent.Fault.AbortPercent > 0 {
+		if intent.Fault.HTTPStatus < 200 || intent.Fault.HTTPStatus > 599 {
+			return nil, fmt.Errorf("a fault abort requires an httpStatus between 200 and 599, got [%d]", intent.Fault.HTTPStatus)
+		}
+	}
+
+	conf := config.Get()
+	host := fmt.Sprintf("%s.%s.%s", service, namespace, conf.ExternalServices.Istio.IstioIdentityDomain)
+	subsets, err := workloadSubsets(workloads, conf.IstioLabels.VersionLabelName)
+	if err != nil {
+		return nil, err
+	}
+
+	wizard := WizardWeightedRouting
+	switch {
+	case len(intent.HeaderRoutes) > 0:
+		wizard = WizardHeaderRouting
+	case len(intent.TCPRoutes) > 0:
+		wizard = WizardTCPRouting
+	case len(intent.WeightedRoutes) == 0 && intent.Fault != nil:
+		wizard = WizardFaultInjection
+	case len(intent.WeightedRoutes) == 0 && (intent.Timeout != "" || intent.Retries != nil):
+		wizard = WizardRequestTimeouts
+	}
+	objectMeta := func(name string) meta_v1.ObjectMeta {
+		return meta_v1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{wizardLabel: wizard}}
+	}
+
+	// DestinationRule: one subset per workload
+	drSubsets := []interface{}{}
+	for _, w := range workloads {
+		if s, ok := subsets[w.Name]; ok {
+			drSubsets = append(drSubsets, map[string]interface{}{
+				"name":   s,
+				"labels": map[string]interface{}{conf.IstioLabels.VersionLabelName: s},
+			})
+		}
+	}
+	drSpec := map[string]interface{}{
+		"host":    host,
+		"subsets": drSubsets,
+	}
+	if intent.CircuitBreaker != nil {
+		drSpec["trafficPolicy"] = circuitBreakerPolicy(intent.CircuitBreaker)
+	}
+	trafficConfig := &models.TrafficConfig{
+		DestinationRule: &kubernetes.GenericIstioObject{
+			TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.DestinationRuleType, APIVersion: kubernetes.ApiNetworkingVersion},
+			ObjectMeta: objectMeta(service),
+			Spec:       drSpec,
+		},
+	}
+
+	// VirtualService
+	vsSpec := map[string]interface{}{
+		"hosts": []interface{}{service},
+	}
+	destination := func(workload string) (map[string]interface{}, error) {
+		subset, ok := subsets[workload]
+		if !ok {
+			return nil, fmt.Errorf("workload %s doesn't belong to service %s", workload, service)
+		}
+		return map[string]interface{}{"host": host, "subset": subset}, nil
+	}
+	if len(intent.TCPRoutes) > 0 {
+		route, err := weightedRoute(intent.TCPRoutes, destination)
+		if err != nil {
+			return nil, err
+		}
+		vsSpec["tcp"] = []interface{}{map[string]interface{}{"route": route}}
+	} else {
+		httpRoutes := []map[string]interface{}{}
+		for _, hr := range intent.HeaderRoutes {
+			d, err := destination(hr.Workload)
+			if err != nil {
+				return nil, err
+			}
+			matchType := hr.MatchType
+			if matchType == "" {
+				matchType = "exact"
+			}
+			if matchType != "exact" && matchType != "prefix" && matchType != "regex" {
+				return nil, fmt.Errorf("unknown header match type: %s", matchType)
+			}
+			httpRoutes = append(httpRoutes, map[string]interface{}{
+				"match": []interface{}{map[string]interface{}{
+					"headers": map[string]interface{}{hr.Header: map[string]interface{}{matchType: hr.Value}},
+				}},
+				"route": []interface{}{map[string]interface{}{"destination": d}},
+			})
+		}
+		if len(intent.WeightedRoutes) > 0 {
+			route, err := weightedRoute(intent.WeightedRoutes, destination)
+			if err != nil {
+				return nil, err
+			}
+			httpRoutes = append(httpRoutes, map[string]interface{}{"route": route})
+		} else {
+			// Default route to the whole service
+			httpRoutes = append(httpRoutes, map[string]interface{}{
+				"route": []interface{}{map[string]interface{}{"destination": map[string]interface{}{"host": host}}},
+			})
+		}
+		http := make([]interface{}, len(httpRoutes))
+		for i, r := range httpRoutes {
+			if intent.Fault != nil {
+				r["fault"] = faultPolicy(intent.Fault)
+			}
+			if intent.Timeout != "" {
+				r["timeout"] = intent.Timeout
+			}
+			if intent.Retries != nil {
+				r["retries"] = retriesPolicy(intent.Retries)
+			}
+			http[i] = r
+		}
+		vsSpec["http"] = http
+	}
+
+	// Gateway
+	if intent.Gateway != nil {
+		if len(intent.Gateway.Hosts) == 0 {
+			return nil, fmt.Errorf("gateway hosts are required")
+		}
+		port, protocol := intent.Gateway.Port, "HTTP"
+		if port == 0 {
+			port = 80
+		}
+		if len(intent.TCPRoutes) > 0 {
+			protocol = "TCP"
+		}
+		gwName := service + "-gateway"
+		hosts := make([]interface{}, len(intent.Gateway.Hosts))
+		for i, h := range intent.Gateway.Hosts {
+			hosts[i] = h
+		}
+		trafficConfig.Gateway = &kubernetes.GenericIstioObject{
+			TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.GatewayType, APIVersion: kubernetes.ApiNetworkingVersion},
+			ObjectMeta: objectMeta(gwName),
+			Spec: map[string]interface{}{
+				"selector": map[string]interface{}{"istio": "ingressgateway"},
+				"servers": []interface{}{map[string]interface{}{
+					"port":  map[string]interface{}{"number": port, "name": fmt.Sprintf("%s-%d", protocol, port), "protocol": protocol},
+					"hosts": hosts,
+				}},
+			},
+		}
+		vsSpec["hosts"] = append(hosts, service)
+		vsSpec["gateways"] = []interface{}{gwName, "mesh"}
+	}
+	trafficConfig.VirtualService = &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.VirtualServiceType, APIVersion: kubernetes.ApiNetworkingVersion},
+		ObjectMeta: objectMeta(service),
+		Spec:       vsSpec,
+	}
+	return trafficConfig, nil
+}
+
+// workloadSubsets returns the subset name of every workload, which is its version label
+func workloadSubsets(workloads models.Workloads, versionLabel string) (map[string]string, error) {
+	subsets := make(map[string]string, len(workloads))
+	for _, w := range workloads {
+		version, ok := w.Labels[versionLabel]
+		if !ok {
+			return nil, fmt.Errorf("workload %s has no %s label", w.Name, versionLabel)
+		}
+		subsets[w.Name] = version
+	}
+	return subsets, nil
+}
+
+func weightedRoute(weights []models.WorkloadWeight, destination func(string) (map[string]interface{}, error)) ([]interface{}, error) {
+	route := []interface{}{}
+	total := 0
+	for _, ww := range weights {
+		if ww.Weight < 0 {
+			return nil, fmt.Errorf("weight of workload %s is negative", ww.Workload)
+		}
+		d, err := destination(ww.Workload)
+		if err != nil {
+			return nil, err
+		}
+		total += ww.Weight
+		route = append(route, map[string]interface{}{"destination": d, "weight": ww.Weight})
+	}
+	if total != 100 {
+		return nil, fmt.Errorf("weights must sum to 100, got %d", total)
+	}
+	return route, nil
+}
+
+func faultPolicy(fault *models.FaultIntent) map[string]interface{} {
+	policy := map[string]interface{}{}
+	if fault.DelayPercent > 0 {
+		policy["delay"] = map[string]interface{}{
+			"percentage": map[string]interface{}{"value": fault.DelayPercent},
+			"fixedDelay": fault.FixedDelay,
+		}
+	}
+	if fault.AbortPercent > 0 {
+		policy["abort"] = map[string]interface{}{
+			"percentage": map[string]interface{}{"value": fault.AbortPercent},
+			"httpStatus": fault.HTTPStatus,
+		}
+	}
+	return policy
+}
+
+func retriesPolicy(retries *models.RetryIntent) map[string]interface{} {
+	policy := map[string]interface{}{"attempts": retries.Attempts}
+	if retries.PerTryTimeout != "" {
+		policy["perTryTimeout"] = retries.PerTryTimeout
+	}
+	if retries.RetryOn != "" {
+		policy["retryOn"] = retries.RetryOn
+	}
+	return policy
+}
+
+func circuitBreakerPolicy(cb *models.CircuitBreakerIntent) map[string]interface{} {
+	policy := map[string]interface{}{}
+	connectionPool := map[string]interface{}{}
+	if cb.MaxConnections > 0 {
+		connectionPool["tcp"] = map[string]interface{}{"maxConnections": cb.MaxConnections}
+	}
+	httpPool := map[string]interface{}{}
+	if cb.HTTP1MaxPendingRequests > 0 {
+		httpPool["http1MaxPendingRequests"] = cb.HTTP1MaxPendingRequests
+	}
+	if cb.MaxRequestsPerConnection > 0 {
+		httpPool["maxRequestsPerConnection"] = cb.MaxRequestsPerConnection
+	}
+	if len(httpPool) > 0 {
+		connectionPool["http"] = httpPool
+	}
+	if len(connectionPool) > 0 {
+		policy["connectionPool"] = connectionPool
+	}
+	outlierDetection := map[string]interface{}{}
+	if cb.ConsecutiveErrors > 0 {
+		outlierDetection["consecutive5xxErrors"] = cb.ConsecutiveErrors
+	}
+	if cb.Interval != "" {
+		outlierDetection["interval"] = cb.Interval
+	}
+	if cb.BaseEjectionTime != "" {
+		outlierDetection["baseEjectionTime"] = cb.BaseEjectionTime
+	}
+	if cb.MaxEjectionPercent > 0 {
+		outlierDetection["maxEjectionPercent"] = cb.MaxEjectionPercent
+	}
+	if len(outlierDetection) > 0 {
+		policy["outlierDetection"] = outlierDetection
+	}
+	return policy
+}
@@ -0,0 +1,164 @@
+package business
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+func fakeReviewsWorkloads() models.Workloads {
+	return models.Workloads{
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "reviews-v1", Labels: map[string]string{"app": "reviews", "version": "v1"}}},
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "reviews-v2", Labels: map[string]string{"app": "reviews", "version": "v2"}}},
+	}
+}
+
+func toJSON(t *testing.T, o interface{}) string {
+	b, err := json.Marshal(o)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestBuildWeightedTrafficConfig(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	intent := models.TrafficIntent{
+		WeightedRoutes: []models.WorkloadWeight{{Workload: "reviews-v1", Weight: 80}, {Workload: "reviews-v2", Weight: 20}},
+		Timeout:        "2s",
+		CircuitBreaker: &models.CircuitBreakerIntent{MaxConnections: 10, ConsecutiveErrors: 5},
+	}
+	trafficConfig, err := buildTrafficConfig("bookinfo", "reviews", fakeReviewsWorkloads(), intent)
+	assert.NoError(err)
+	assert.Nil(trafficConfig.Gateway)
+	assert.Equal("traffic_shifting", trafficConfig.VirtualService.Labels["kiali_wizard"])
+
+	assert.JSONEq(`{
+		"host": "reviews.bookinfo.svc.cluster.local",
+		"subsets": [{"name": "v1", "labels": {"version": "v1"}}, {"name": "v2", "labels": {"version": "v2"}}],
+		"trafficPolicy": {"connectionPool": {"tcp": {"maxConnections": 10}}, "outlierDetection": {"consecutive5xxErrors": 5}}
+	}`, toJSON(t, trafficConfig.DestinationRule.Spec))
+	assert.JSONEq(`{
+		"hosts": ["reviews"],
+		"http": [{
+			"route": [
+				{"destination": {"host": "reviews.bookinfo.svc.cluster.local", "subset": "v1"}, "weight": 80},
+				{"destination": {"host": "reviews.bookinfo.svc.cluster.local", "subset": "v2"}, "weight": 20}
+			],
+			"timeout": "2s"
+		}]
+	}`, toJSON(t, trafficConfig.VirtualService.Spec))
+}
+
+func TestBuildHeaderTrafficConfigWithGateway(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	intent := models.TrafficIntent{
+		HeaderRoutes: []models.HeaderRoute{{Header: "end-user", Value: "jason", Workload: "reviews-v2"}},
+		Fault:        &models.FaultIntent{AbortPercent: 10, HTTPStatus: 503},
+		Gateway:      &models.GatewayIntent{Hosts: []string{"reviews.example.com"}},
+	}
+	trafficConfig, err := buildTrafficConfig("bookinfo", "reviews", fakeReviewsWorkloads(), intent)
+	assert.NoError(err)
+	assert.Equal("request_routing", trafficConfig.VirtualService.Labels["kiali_wizard"])
+	assert.JSONEq(`{
+		"hosts": ["reviews.example.com", "reviews"],
+		"gateways": ["reviews-gateway", "mesh"],
+		"http": [{
+			"match": [{"headers": {"end-user": {"exact": "jason"}}}],
+			"route": [{"destination": {"host": "reviews.bookinfo.svc.cluster.local", "subset": "v2"}}],
+			"fault": {"abort": {"percentage": {"value": 10}, "httpStatus": 503}}
+		}, {
+			"route": [{"destination": {"host": "reviews.bookinfo.svc.cluster.local"}}],
+			"fault": {"abort": {"percentage": {"value": 10}, "httpStatus": 503}}
+		}]
+	}`, toJSON(t, trafficConfig.VirtualService.Spec))
+	assert.JSONEq(`{
+		"selector": {"istio": "ingressgateway"},
+		"servers": [{"port": {"number": 80, "name": "HTTP-80", "protocol": "HTTP"}, "hosts": ["reviews.example.com"]}]
+	}`, toJSON(t, trafficConfig.Gateway.Spec))
+}
+
+func TestBuildTrafficConfigErrors(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	_, err := buildTrafficConfig("bookinfo", "reviews", fakeReviewsWorkloads(), models.TrafficIntent{})
+	assert.Error(err)
+
+	_, err = buildTrafficConfig("bookinfo", "reviews", fakeReviewsWorkloads(), models.TrafficIntent{
+		WeightedRoutes: []models.WorkloadWeight{{Workload: "reviews-v1", Weight: 80}},
+	})
+	assert.EqualError(err, "weights must sum to 100, got 80")
+
+	_, err = buildTrafficConfig("bookinfo", "reviews", fakeReviewsWorkloads(), models.TrafficIntent{
+		TCPRoutes: []models.WorkloadWeight{{Workload: "ratings-v1", Weight: 100}},
+	})
+	assert.EqualError(err, "workload ratings-v1 doesn't belong to service reviews")
+
+	_, err = buildTrafficConfig("bookinfo", "reviews", fakeReviewsWorkloads(), models.TrafficIntent{
+		TCPRoutes: []models.WorkloadWeight{{Workload: "reviews-v1", Weight: 100}},
+		Timeout:   "1s",
+	})
+	assert.Error(err)
+
+	_, err = buildTrafficConfig("bookinfo", "reviews", fakeReviewsWorkloads(), models.TrafficIntent{
+		WeightedRoutes: []models.WorkloadWeight{{Workload: "reviews-v1", Weight: 100}},
+		Fault:          &models.FaultIntent{DelayPercent: 10},
+	})
+	assert.EqualError(err, "a fault delay requires a valid fixedDelay, e.g. 5s, got []")
+
+	_, err = buildTrafficConfig("bookinfo", "reviews", fakeReviewsWorkloads(), models.TrafficIntent{
+		Fault: &models.FaultIntent{AbortPercent: 10},
+	})
+	assert.EqualError(err, "a fault abort requires an httpStatus between 200 and 599, got [0]")
+}
+
+func TestRestorePatch(t *testing.T) {
+	assert := assert.New(t)
+
+	previous := &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Labels: map[string]string{"team": "a"}},
+		Spec:       map[string]interface{}{"hosts": []interface{}{"reviews"}, "exportTo": []interface{}{"."}},
+	}
+	applied := &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Labels: map[string]string{"kiali_wizard": "traffic_template"}},
+		Spec:       map[string]interface{}{"hosts": []interface{}{"reviews"}, "http": []interface{}{}},
+	}
+	patch, err := restorePatch(applied, previous)
+	assert.NoError(err)
+	assert.JSONEq(`{"metadata":{"labels":{"kiali_wizard":null,"team":"a"}},"spec":{"hosts":["reviews"],"exportTo":["."],"http":null}}`, patch)
+}
+
+func TestApplyDrafts(t *testing.T) {
+	assert := assert.New(t)
+
+	existing := &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.VirtualServiceType},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+	}
+	other := &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.VirtualServiceType},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "ratings", Namespace: "bookinfo"},
+	}
+	draft := existing.DeepCopyIstioObject()
+	draft.SetSpec(map[string]interface{}{"hosts": []interface{}{"reviews"}})
+	gw := &kubernetes.GenericIstioObject{
+		TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.GatewayType},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-gateway", Namespace: "bookinfo"},
+	}
+
+	details := kubernetes.IstioDetails{VirtualServices: []kubernetes.IstioObject{existing, other}}
+	gateways := applyDrafts([]kubernetes.IstioObject{draft, gw}, nil, &details, &kubernetes.MTLSDetails{}, &kubernetes.RBACDetails{}, [][]kubernetes.IstioObject{{gw.DeepCopyIstioObject()}})
+	assert.Len(details.VirtualServices, 2)
+	assert.Equal("ratings", details.VirtualServices[0].GetObjectMeta().Name)
+	assert.Equal(draft, details.VirtualServices[1])
+	assert.Equal([][]kubernetes.IstioObject{{}, {gw}}, gateways)
+}
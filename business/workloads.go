@@ -119,7 +119,7 @@ func (in *WorkloadService) GetWorkloadList(namespace string, linkIstioResources
 			go func(namespace, resourceType string, dest *[]kubernetes.IstioObject, errChan chan error) {
 				defer wg.Done()
 				var err2 error
-				if IsNamespaceCached(namespace) {
+				if IsNamespaceCached(in.k8s, namespace, resourceType) {
 					*dest, err2 = kialiCache.GetIstioObjects(namespace, resourceType, "")
 				} else {
 					*dest, err2 = in.k8s.GetIstioObjects(namespace, resourceType, "")
@@ -190,7 +190,7 @@ func (in *WorkloadService) GetWorkload(namespace string, workloadName string, wo
 		var services []core_v1.Service
 		var err error
 		// Check if namespace is cached
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(in.k8s, namespace, kubernetes.ServiceType) {
 			// Cache uses Kiali ServiceAccount, check if user can access to the namespace
 			if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err == nil {
 				services, err = kialiCache.GetServices(namespace, workload.Labels)
@@ -230,7 +230,7 @@ func (in *WorkloadService) GetPods(namespace string, labelSelector string) (mode
 	var err error
 	var ps []core_v1.Pod
 	// Check if namespace is cached
-	if IsNamespaceCached(namespace) {
+	if IsNamespaceCached(in.k8s, namespace, kubernetes.PodType) {
 		// Cache uses Kiali ServiceAccount, check if user can access to the namespace
 		if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err == nil {
 			ps, err = kialiCache.GetPods(namespace, labelSelector)
@@ -463,7 +463,7 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 		var err error
 		// Check if namespace is cached
 		// Namespace access is checked in the upper caller
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(layer.k8s, namespace, kubernetes.PodType) {
 			pods, err = kialiCache.GetPods(namespace, labelSelector)
 		} else {
 			pods, err = layer.k8s.GetPods(namespace, labelSelector)
@@ -479,7 +479,7 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 		var err error
 		// Check if namespace is cached
 		// Namespace access is checked in the upper caller
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(layer.k8s, namespace, kubernetes.DeploymentType) {
 			dep, err = kialiCache.GetDeployments(namespace)
 		} else {
 			dep, err = layer.k8s.GetDeployments(namespace)
@@ -495,7 +495,7 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 		var err error
 		// Check if namespace is cached
 		// Namespace access is checked in the upper caller
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(layer.k8s, namespace, kubernetes.ReplicaSetType) {
 			repset, err = kialiCache.GetReplicaSets(namespace)
 		} else {
 			repset, err = layer.k8s.GetReplicaSets(namespace)
@@ -510,7 +510,11 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 		defer wg.Done()
 		var err error
 		if isWorkloadIncluded(kubernetes.ReplicationControllerType) {
-			repcon, err = layer.k8s.GetReplicationControllers(namespace)
+			if IsNamespaceCached(layer.k8s, namespace, kubernetes.ReplicationControllerType) {
+				repcon, err = kialiCache.GetReplicationControllers(namespace)
+			} else {
+				repcon, err = layer.k8s.GetReplicationControllers(namespace)
+			}
 			if err != nil {
 				log.Errorf("Error fetching GetReplicationControllers per namespace %s: %s", namespace, err)
 				errChan <- err
@@ -534,7 +538,7 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 		defer wg.Done()
 		var err error
 		if isWorkloadIncluded(kubernetes.StatefulSetType) {
-			if IsNamespaceCached(namespace) {
+			if IsNamespaceCached(layer.k8s, namespace, kubernetes.StatefulSetType) {
 				fulset, err = kialiCache.GetStatefulSets(namespace)
 			} else {
 				fulset, err = layer.k8s.GetStatefulSets(namespace)
@@ -550,7 +554,11 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 		defer wg.Done()
 		var err error
 		if isWorkloadIncluded(kubernetes.CronJobType) {
-			conjbs, err = layer.k8s.GetCronJobs(namespace)
+			if IsNamespaceCached(layer.k8s, namespace, kubernetes.CronJobType) {
+				conjbs, err = kialiCache.GetCronJobs(namespace)
+			} else {
+				conjbs, err = layer.k8s.GetCronJobs(namespace)
+			}
 			if err != nil {
 				log.Errorf("Error fetching CronJobs per namespace %s: %s", namespace, err)
 				errChan <- err
@@ -562,7 +570,11 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 		defer wg.Done()
 		var err error
 		if isWorkloadIncluded(kubernetes.JobType) {
-			jbs, err = layer.k8s.GetJobs(namespace)
+			if IsNamespaceCached(layer.k8s, namespace, kubernetes.JobType) {
+				jbs, err = kialiCache.GetJobs(namespace)
+			} else {
+				jbs, err = layer.k8s.GetJobs(namespace)
+			}
 			if err != nil {
 				log.Errorf("Error fetching Jobs per namespace %s: %s", namespace, err)
 				errChan <- err
@@ -574,7 +586,7 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 		defer wg.Done()
 		var err error
 		if isWorkloadIncluded(kubernetes.DaemonSetType) {
-			if IsNamespaceCached(namespace) {
+			if IsNamespaceCached(layer.k8s, namespace, kubernetes.DaemonSetType) {
 				daeset, err = kialiCache.GetDaemonSets(namespace)
 			} else {
 				daeset, err = layer.k8s.GetDaemonSets(namespace)
@@ -1030,7 +1042,7 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 		var err error
 		// Check if namespace is cached
 		// Namespace access is checked in the upper call
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(layer.k8s, namespace, kubernetes.PodType) {
 			pods, err = kialiCache.GetPods(namespace, "")
 		} else {
 			pods, err = layer.k8s.GetPods(namespace, "")
@@ -1050,7 +1062,7 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 		}
 		// Check if namespace is cached
 		// Namespace access is checked in the upper call
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(layer.k8s, namespace, kubernetes.DeploymentType) {
 			dep, err = kialiCache.GetDeployment(namespace, workloadName)
 		} else {
 			dep, err = layer.k8s.GetDeployment(namespace, workloadName)
@@ -1075,7 +1087,7 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 		var err error
 		// Check if namespace is cached
 		// Namespace access is checked in the upper call
-		if IsNamespaceCached(namespace) {
+		if IsNamespaceCached(layer.k8s, namespace, kubernetes.ReplicaSetType) {
 			repset, err = kialiCache.GetReplicaSets(namespace)
 		} else {
 			repset, err = layer.k8s.GetReplicaSets(namespace)
@@ -1094,7 +1106,11 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 		}
 		var err error
 		if isWorkloadIncluded(kubernetes.ReplicationControllerType) {
-			repcon, err = layer.k8s.GetReplicationControllers(namespace)
+			if IsNamespaceCached(layer.k8s, namespace, kubernetes.ReplicationControllerType) {
+				repcon, err = kialiCache.GetReplicationControllers(namespace)
+			} else {
+				repcon, err = layer.k8s.GetReplicationControllers(namespace)
+			}
 			if err != nil {
 				log.Errorf("Error fetching GetReplicationControllers per namespace %s: %s", namespace, err)
 				errChan <- err
@@ -1125,7 +1141,7 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 		}
 		var err error
 		if isWorkloadIncluded(kubernetes.StatefulSetType) {
-			if IsNamespaceCached(namespace) {
+			if IsNamespaceCached(layer.k8s, namespace, kubernetes.StatefulSetType) {
 				fulset, err = kialiCache.GetStatefulSet(namespace, workloadName)
 			} else {
 				fulset, err = layer.k8s.GetStatefulSet(namespace, workloadName)
@@ -1144,7 +1160,11 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 		}
 		var err error
 		if isWorkloadIncluded(kubernetes.CronJobType) {
-			conjbs, err = layer.k8s.GetCronJobs(namespace)
+			if IsNamespaceCached(layer.k8s, namespace, kubernetes.CronJobType) {
+				conjbs, err = kialiCache.GetCronJobs(namespace)
+			} else {
+				conjbs, err = layer.k8s.GetCronJobs(namespace)
+			}
 			if err != nil {
 				log.Errorf("Error fetching CronJobs per namespace %s: %s", namespace, err)
 				errChan <- err
@@ -1160,7 +1180,11 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 		}
 		var err error
 		if isWorkloadIncluded(kubernetes.JobType) {
-			jbs, err = layer.k8s.GetJobs(namespace)
+			if IsNamespaceCached(layer.k8s, namespace, kubernetes.JobType) {
+				jbs, err = kialiCache.GetJobs(namespace)
+			} else {
+				jbs, err = layer.k8s.GetJobs(namespace)
+			}
 			if err != nil {
 				log.Errorf("Error fetching Jobs per namespace %s: %s", namespace, err)
 				errChan <- err
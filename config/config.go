@@ -67,6 +67,13 @@ const (
 	DashboardsDiscoveryAuto    = "auto"
 )
 
+// The supported tracing backends
+const (
+	TracingProviderJaeger = "jaeger"
+	TracingProviderTempo  = "tempo"
+	TracingProviderZipkin = "zipkin"
+)
+
 // Global configuration for the application.
 var configuration Config
 var rwMutex sync.RWMutex
@@ -109,25 +116,59 @@ func (a *Auth) Obfuscate() {
 // PrometheusConfig describes configuration of the Prometheus component
 type PrometheusConfig struct {
 	Auth Auth `yaml:"auth,omitempty"`
+	// Additional Prometheus-compatible backends (i.e. per-cluster Prometheus, Thanos, Cortex) federated with URL
+	Backends []PrometheusBackendConfig `yaml:"backends,omitempty"`
 	// Cache duration per query expressed in seconds
 	CacheDuration int `yaml:"cache_duration,omitempty"`
 	// Enable cache for Prometheus queries
 	CacheEnabled bool `yaml:"cache_enabled,omitempty"`
 	// Global cache expiration expressed in seconds
-	CacheExpiration int    `yaml:"cache_expiration,omitempty"`
-	HealthCheckUrl  string `yaml:"health_check_url,omitempty"`
-	IsCore          bool   `yaml:"is_core,omitempty"`
-	URL             string `yaml:"url,omitempty"`
+	CacheExpiration int `yaml:"cache_expiration,omitempty"`
+	// Maximum number of results kept by the cache of the graph and metrics queries, which are held for CacheDuration.
+	// The cache is disabled when zero, which is the default.
+	CacheMaxEntries int `yaml:"cache_max_entries,omitempty"`
+	// Cluster holding the metrics of URL, when federated with backends of other clusters
+	Cluster        string `yaml:"cluster,omitempty"`
+	HealthCheckUrl string `yaml:"health_check_url,omitempty"`
+	IsCore         bool   `yaml:"is_core,omitempty"`
+	// Age of the oldest metrics held by URL, as a Prometheus duration (i.e. 15d), when federated with backends.
+	// Older metrics are read from the backends. Unlimited when empty.
+	Retention string `yaml:"retention,omitempty"`
+	URL       string `yaml:"url,omitempty"`
+}
+
+// PrometheusBackendConfig describes a Prometheus-compatible backend federated with the main Prometheus server.
+// The queries are routed by the cluster label of their selectors to the backends of that cluster, otherwise all
+// clusters are queried and their results merged. Within a cluster, the queries are routed by time range: the backend
+// with the shortest retention holding the data is used, so that a long-term storage only serves historical data.
+type PrometheusBackendConfig struct {
+	Auth Auth `yaml:"auth,omitempty"`
+	// Cluster holding the metrics of the backend; empty for the cluster of the main Prometheus server
+	Cluster string `yaml:"cluster,omitempty"`
+	Name    string `yaml:"name,omitempty"`
+	// Age of the oldest metrics held by the backend, as a Prometheus duration (i.e. 30d). Unlimited when empty.
+	Retention string `yaml:"retention,omitempty"`
+	URL       string `yaml:"url,omitempty"`
 }
 
 // CustomDashboardsConfig describes configuration specific to Custom Dashboards
 type CustomDashboardsConfig struct {
-	DiscoveryEnabled       string           `yaml:"discovery_enabled,omitempty"`
-	DiscoveryAutoThreshold int              `yaml:"discovery_auto_threshold,omitempty"`
-	Enabled                bool             `yaml:"enabled,omitempty"`
-	IsCore                 bool             `yaml:"is_core,omitempty"`
-	NamespaceLabel         string           `yaml:"namespace_label,omitempty"`
-	Prometheus             PrometheusConfig `yaml:"prometheus,omitempty"`
+	DiscoveryEnabled       string                  `yaml:"discovery_enabled,omitempty"`
+	DiscoveryAutoThreshold int                     `yaml:"discovery_auto_threshold,omitempty"`
+	Enabled                bool                    `yaml:"enabled,omitempty"`
+	IsCore                 bool                    `yaml:"is_core,omitempty"`
+	NamespaceLabel         string                  `yaml:"namespace_label,omitempty"`
+	Prometheus             PrometheusConfig        `yaml:"prometheus,omitempty"`
+	Runtime                RuntimeDashboardsConfig `yaml:"runtime,omitempty"`
+}
+
+// RuntimeDashboardsConfig describes where dashboards are discovered at runtime: MonitoringDashboard resources and
+// ConfigMaps labelled with ConfigMapLabel. Dashboards of the Kiali and Istio namespaces are visible in all namespaces, the
+// others only in their own namespace. No Namespaces means the Kiali and Istio namespaces and the accessible namespaces.
+type RuntimeDashboardsConfig struct {
+	ConfigMapLabel string   `yaml:"config_map_label,omitempty"`
+	Enabled        bool     `yaml:"enabled,omitempty"`
+	Namespaces     []string `yaml:"namespaces,omitempty"`
 }
 
 // GrafanaConfig describes configuration used for Grafana links
@@ -162,8 +203,9 @@ type TracingConfig struct {
 	InClusterURL         string   `yaml:"in_cluster_url"`
 	IsCore               bool     `yaml:"is_core,omitempty"`
 	NamespaceSelector    bool     `yaml:"namespace_selector"`
+	Provider             string   `yaml:"provider,omitempty"` // Tracing backend: jaeger (default), zipkin or tempo
 	URL                  string   `yaml:"url"`
-	UseGRPC              bool     `yaml:"use_grpc"`
+	UseGRPC              bool     `yaml:"use_grpc"` // Only applies to the jaeger provider
 	WhiteListIstioSystem []string `yaml:"whitelist_istio_system"`
 }
 
@@ -252,9 +294,13 @@ type KubernetesConfig struct {
 	CacheDuration int `yaml:"cache_duration,omitempty"`
 	// Enable cache for kubernetes and istio resources
 	CacheEnabled bool `yaml:"cache_enabled,omitempty"`
-	// Kiali can cache VirtualService,DestinationRule,Gateway and ServiceEntry Istio resources if they are present
-	// on this list of Istio types. Other Istio types are not yet supported.
+	// Kiali can cache the Istio resources if they are present on this list of Istio types.
 	CacheIstioTypes []string `yaml:"cache_istio_types,omitempty"`
+	// Cluster-wide mode: a single set of informers watches every namespace and every cached type, instead of a set per
+	// namespace matching CacheNamespaces. All the Istio types are cached, regardless of CacheIstioTypes.
+	// The cache reads with the Kiali ServiceAccount: it only serves a user allowed to list the resources of a namespace,
+	// checked with an access review cached for CacheTokenNamespaceDuration. Other users read from the API.
+	CacheClusterScoped bool `yaml:"cache_cluster_scoped,omitempty"`
 	// List of namespaces or regex defining namespaces to include in a cache
 	CacheNamespaces []string `yaml:"cache_namespaces,omitempty"`
 	// Cache duration expressed in seconds
@@ -271,9 +317,34 @@ type KubernetesConfig struct {
 
 // ApiConfig contains API specific configuration.
 type ApiConfig struct {
+	Graph      ApiGraphConfig `yaml:"graph,omitempty"`
 	Namespaces ApiNamespacesConfig
 }
 
+// ApiGraphConfig bounds the graph requests: their Prometheus queries and their size. The limits are unlimited when zero.
+type ApiGraphConfig struct {
+	// Maximum number of namespaces whose traffic is queried concurrently
+	MaxConcurrentNamespaces int `yaml:"max_concurrent_namespaces,omitempty"`
+	// Maximum number of graph requests of a user running at the same time. The requests without a user of their own (i.e. with
+	// anonymous access) are limited by client address.
+	MaxConcurrentRequestsPerUser int `yaml:"max_concurrent_requests_per_user,omitempty"`
+	// Maximum duration of a graph request, in seconds
+	MaxDuration int `yaml:"max_duration,omitempty"`
+	// Maximum number of edges of a graph, above which a summary graph of the namespaces is returned
+	MaxEdges int `yaml:"max_edges,omitempty"`
+	// Maximum number of namespaces of a graph request
+	MaxNamespaces int `yaml:"max_namespaces,omitempty"`
+	// Maximum number of nodes of a graph, above which a summary graph of the namespaces is returned
+	MaxNodes int `yaml:"max_nodes,omitempty"`
+	// Return the graph computed from the queries that succeeded, with warnings for the failed namespaces and appenders,
+	// unless the request sets the "partial" query parameter
+	PartialResults bool `yaml:"partial_results,omitempty"`
+	// Time budget of the queries of a graph request, in seconds. Unlimited when zero.
+	QueryBudget int `yaml:"query_budget,omitempty"`
+	// Timeout of each query of a graph request, in seconds. Unlimited when zero.
+	QueryTimeout int `yaml:"query_timeout,omitempty"`
+}
+
 // ApiNamespacesConfig provides a list of regex strings defining namespaces to blacklist.
 type ApiNamespacesConfig struct {
 	Exclude       []string
@@ -366,6 +437,42 @@ type HealthConfig struct {
 	Rate []Rate `yaml:"rate,omitempty" json:"rate,omitempty"`
 }
 
+// NotificationSink describes a destination for notifications
+type NotificationSink struct {
+	Name string `yaml:"name,omitempty"`
+	// Type is one of: webhook, slack, email
+	Type string `yaml:"type,omitempty"`
+	// URL of the webhook, for webhook and slack sinks
+	URL string `yaml:"url,omitempty"`
+	// SMTP settings, for email sinks
+	SMTPHost     string   `yaml:"smtp_host,omitempty"`
+	SMTPPort     int      `yaml:"smtp_port,omitempty"`
+	SMTPUsername string   `yaml:"smtp_username,omitempty"`
+	SMTPPassword string   `yaml:"smtp_password,omitempty"`
+	From         string   `yaml:"from,omitempty"`
+	To           []string `yaml:"to,omitempty"`
+}
+
+// NotificationsConfig describes configuration of the background health and validations notifier
+type NotificationsConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Evaluation interval expressed in seconds
+	EvaluationInterval int      `yaml:"evaluation_interval,omitempty"`
+	Namespaces         []string `yaml:"namespaces,omitempty"`
+	// Rate interval used for health evaluation, e.g: "2m"
+	RateInterval string `yaml:"rate_interval,omitempty"`
+	// Time during which identical notifications are not sent again, expressed in seconds
+	RepeatInterval int                `yaml:"repeat_interval,omitempty"`
+	Sinks          []NotificationSink `yaml:"sinks,omitempty"`
+}
+
+// RolloutsConfig describes configuration of the canary rollouts controller
+type RolloutsConfig struct {
+	// The controller shifts traffic with the Kiali service account, after checking that the requesting user is
+	// allowed to update the VirtualService. It is disabled unless explicitly enabled.
+	Enabled bool `yaml:"enabled,omitempty"`
+}
+
 // Config defines full YAML configuration.
 type Config struct {
 	AdditionalDisplayDetails []AdditionalDisplayItem             `yaml:"additional_display_details,omitempty"`
@@ -384,6 +491,8 @@ type Config struct {
 	KialiFeatureFlags        KialiFeatureFlags                   `yaml:"kiali_feature_flags,omitempty"`
 	KubernetesConfig         KubernetesConfig                    `yaml:"kubernetes_config,omitempty"`
 	LoginToken               LoginToken                          `yaml:"login_token,omitempty"`
+	Notifications            NotificationsConfig                 `yaml:"notifications,omitempty"`
+	Rollouts                 RolloutsConfig                      `yaml:"rollouts,omitempty"`
 	Server                   Server                              `yaml:",omitempty"`
 }
 
@@ -393,6 +502,11 @@ func NewConfig() (c *Config) {
 		InCluster:      true,
 		IstioNamespace: "istio-system",
 		API: ApiConfig{
+			Graph: ApiGraphConfig{
+				MaxConcurrentNamespaces: 5,
+				QueryBudget:             60,
+				QueryTimeout:            30,
+			},
 			Namespaces: ApiNamespacesConfig{
 				Exclude: []string{
 					"istio-operator",
@@ -442,6 +556,11 @@ func NewConfig() (c *Config) {
 				Enabled:                true,
 				IsCore:                 false,
 				NamespaceLabel:         "kubernetes_namespace",
+				Runtime: RuntimeDashboardsConfig{
+					ConfigMapLabel: "kiali.io/dashboards",
+					Enabled:        false,
+					Namespaces:     []string{},
+				},
 			},
 			Grafana: GrafanaConfig{
 				Auth: Auth{
@@ -499,6 +618,7 @@ func NewConfig() (c *Config) {
 				NamespaceSelector:    true,
 				InClusterURL:         "http://tracing.istio-system/jaeger",
 				IsCore:               false,
+				Provider:             TracingProviderJaeger,
 				URL:                  "",
 				UseGRPC:              false,
 				WhiteListIstioSystem: []string{"jaeger-query", "istio-ingressgateway"},
@@ -559,6 +679,17 @@ func NewConfig() (c *Config) {
 			ExpirationSeconds: 24 * 3600,
 			SigningKey:        "kiali",
 		},
+		Notifications: NotificationsConfig{
+			Enabled:            false,
+			EvaluationInterval: 60,
+			Namespaces:         []string{},
+			RateInterval:       "2m",
+			RepeatInterval:     3600,
+			Sinks:              []NotificationSink{},
+		},
+		Rollouts: RolloutsConfig{
+			Enabled: false,
+		},
 		Server: Server{
 			AuditLog:                   true,
 			GzipEnabled:                true,
@@ -639,10 +770,20 @@ func (conf Config) String() (str string) {
 	obf := conf
 	obf.ExternalServices.Grafana.Auth.Obfuscate()
 	obf.ExternalServices.Prometheus.Auth.Obfuscate()
+	obf.ExternalServices.Prometheus.Backends = make([]PrometheusBackendConfig, len(conf.ExternalServices.Prometheus.Backends))
+	for i, backend := range conf.ExternalServices.Prometheus.Backends {
+		backend.Auth.Obfuscate()
+		obf.ExternalServices.Prometheus.Backends[i] = backend
+	}
 	obf.ExternalServices.Tracing.Auth.Obfuscate()
 	obf.Identity.Obfuscate()
 	obf.LoginToken.Obfuscate()
 	obf.Auth.OpenId.ClientSecret = "xxx"
+	obf.Notifications.Sinks = make([]NotificationSink, len(conf.Notifications.Sinks))
+	for i, sink := range conf.Notifications.Sinks {
+		sink.SMTPPassword = "xxx"
+		obf.Notifications.Sinks[i] = sink
+	}
 	str, err := Marshal(&obf)
 	if err != nil {
 		str = fmt.Sprintf("Failed to marshal config to string. err=%v", err)
@@ -16,6 +16,8 @@ const (
 	Rate = "rate"
 	// Histogram constant for DataType
 	Histogram = "histogram"
+	// Query constant for DataType: the metrics are PromQL templates
+	Query = "query"
 )
 
 type MonitoringDashboardsList []MonitoringDashboard
@@ -49,18 +51,27 @@ type MonitoringDashboardChart struct {
 	Max              *int                             `yaml:"max"`
 	MetricName       string                           `yaml:"metricName"` // Deprecated; use Metrics instead
 	Metrics          []MonitoringDashboardMetric      `yaml:"metrics"`
-	DataType         string                           `yaml:"dataType"`   // DataType is either "raw", "rate" or "histogram"
+	DataType         string                           `yaml:"dataType"`   // DataType is either "raw", "rate", "histogram" or "query"
 	Aggregator       string                           `yaml:"aggregator"` // Aggregator can be set for raw data. Ex: "sum", "avg". See https://prometheus.io/docs/prometheus/latest/querying/operators/#aggregation-operators
 	Aggregations     []MonitoringDashboardAggregation `yaml:"aggregations"`
 	XAxis            *string                          `yaml:"xAxis"`            // "time" (default) or "series"
 	GroupLabels      []string                         `yaml:"groupLabels"`      // Prometheus label to be used for grouping; Similar to Aggregations, except this grouping will be always turned on
 	SortLabel        string                           `yaml:"sortLabel"`        // Prometheus label to be used for sorting
 	SortLabelParseAs string                           `yaml:"sortLabelParseAs"` // Set "int" if the SortLabel needs to be parsed and compared as an integer
+	// NamespaceScoped is set on the charts of the dashboards discovered at runtime in a namespace: their queries are
+	// restricted to the series of the namespace
+	NamespaceScoped bool `yaml:"-"`
 }
 
 type MonitoringDashboardMetric struct {
 	MetricName  string `yaml:"metricName"`
 	DisplayName string `yaml:"displayName"`
+	// Query is a PromQL template, for the charts of DataType "query". See QueryVariables for the placeholders.
+	Query string `yaml:"query,omitempty"`
+	// Ref names the metric for the queries of the chart, which include it as $ref
+	Ref string `yaml:"ref,omitempty"`
+	// Hidden metrics are only included in other queries, they are not displayed
+	Hidden bool `yaml:"hidden,omitempty"`
 }
 
 type MonitoringDashboardAggregation struct {
@@ -137,6 +148,65 @@ func (in *MonitoringDashboardsList) OrganizeByName() map[string]MonitoringDashbo
 	return out
 }
 
+// Validate checks that a dashboard can be rendered, all its errors are joined in the returned error
+func (in *MonitoringDashboard) Validate() error {
+	errs := []string{}
+	if strings.TrimSpace(in.Name) == "" {
+		errs = append(errs, "name is missing")
+	}
+	if strings.TrimSpace(in.DiscoverOn) == "" && len(in.Items) == 0 {
+		errs = append(errs, "neither items nor discoverOn are set")
+	}
+	for i, item := range in.Items {
+		if strings.TrimSpace(item.Include) != "" {
+			continue
+		}
+		chart := item.Chart
+		if strings.TrimSpace(chart.Name) == "" {
+			errs = append(errs, fmt.Sprintf("items[%d]: chart name is missing", i))
+		}
+		if chart.DataType != Raw && chart.DataType != Rate && chart.DataType != Histogram && chart.DataType != Query {
+			errs = append(errs, fmt.Sprintf("items[%d]: invalid dataType %q, expected %s, %s, %s or %s", i, chart.DataType, Raw, Rate, Histogram, Query))
+		}
+		if chart.DataType == Query {
+			if len(chart.Metrics) == 0 {
+				errs = append(errs, fmt.Sprintf("items[%d]: metrics are not set", i))
+			}
+			if err := chart.validateQueries(); err != nil {
+				errs = append(errs, fmt.Sprintf("items[%d]: %v", i, err))
+			}
+		} else {
+			if strings.TrimSpace(chart.MetricName) == "" && len(chart.Metrics) == 0 {
+				errs = append(errs, fmt.Sprintf("items[%d]: neither metricName nor metrics are set", i))
+			}
+			for j, m := range chart.Metrics {
+				if strings.TrimSpace(m.MetricName) == "" {
+					errs = append(errs, fmt.Sprintf("items[%d]: metrics[%d]: metricName is missing", i, j))
+				}
+			}
+		}
+		if chart.Spans < 0 || chart.Spans > 12 {
+			errs = append(errs, fmt.Sprintf("items[%d]: spans must be between 1 and 12", i))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// UnmarshalDashboards parses either a list of dashboards or a single dashboard, as found in ConfigMaps
+func UnmarshalDashboards(yamlString string) (MonitoringDashboardsList, error) {
+	if list, err := unmarshal(yamlString); err == nil {
+		return list, nil
+	}
+	dashboard := MonitoringDashboard{}
+	if err := yaml.Unmarshal([]byte(yamlString), &dashboard); err != nil {
+		return nil, fmt.Errorf("failed to parse monitoring dashboard yaml data. error=%v", err)
+	}
+	return MonitoringDashboardsList{dashboard}, nil
+}
+
 // Unmarshal parses the given YAML string and returns its MonitoringDashboardsList object representation.
 func unmarshal(yamlString string) (out MonitoringDashboardsList, err error) {
 	list := new(MonitoringDashboardsList)
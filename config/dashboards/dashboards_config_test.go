@@ -97,3 +97,46 @@ func TestDeepCopy(t *testing.T) {
 	assert.Equal(t, len(list[0].Items), len((*dup)[0].Items))
 	assert.Equal(t, list[0].Items[0].Chart.Name, (*dup)[0].Items[0].Chart.Name)
 }
+
+func TestValidate(t *testing.T) {
+	for _, d := range GetBuiltInMonitoringDashboards() {
+		assert.NoError(t, d.Validate(), d.Name)
+	}
+
+	d := MonitoringDashboard{
+		Items: []MonitoringDashboardItem{
+			{Include: "go"},
+			{Chart: MonitoringDashboardChart{Name: "Foo", DataType: "gauge", MetricName: "foo"}},
+			{Chart: MonitoringDashboardChart{Name: "Bar", DataType: Rate, Spans: 13}},
+		},
+	}
+	err := d.Validate()
+	assert.EqualError(t, err, `name is missing; items[1]: invalid dataType "gauge", expected raw, rate, histogram or query; items[2]: neither metricName nor metrics are set; items[2]: spans must be between 1 and 12`)
+
+	d = MonitoringDashboard{Name: "foo"}
+	assert.EqualError(t, d.Validate(), "neither items nor discoverOn are set")
+}
+
+func TestUnmarshalDashboards(t *testing.T) {
+	list, err := UnmarshalDashboards(`
+- name: foo
+  discoverOn: foo_total
+- name: bar
+  discoverOn: bar_total
+`)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "bar", list[1].Name)
+
+	list, err = UnmarshalDashboards(`
+name: foo
+title: Foo
+discoverOn: foo_total
+`)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "Foo", list[0].Title)
+
+	_, err = UnmarshalDashboards("name: [")
+	assert.Error(t, err)
+}
@@ -0,0 +1,539 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// UntranslatedPanel is a Grafana panel that could not be converted into a chart
+type UntranslatedPanel struct {
+	ID     int    `json:"id" yaml:"id"`
+	Title  string `json:"title" yaml:"title"`
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// GrafanaImport is the result of the conversion of a Grafana dashboard
+type GrafanaImport struct {
+	Dashboard    MonitoringDashboard
+	Untranslated []UntranslatedPanel
+	// Warnings are about what is lost in the translated panels
+	Warnings []string
+}
+
+type grafanaDashboard struct {
+	UID    string         `json:"uid"`
+	Title  string         `json:"title"`
+	Panels []grafanaPanel `json:"panels"`
+	// Dashboards of the schema before Grafana 5 have rows of panels
+	Rows []struct {
+		Panels []grafanaPanel `json:"panels"`
+	} `json:"rows"`
+}
+
+type grafanaPanel struct {
+	ID      int    `json:"id"`
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	GridPos struct {
+		W int `json:"w"`
+	} `json:"gridPos"`
+	// Width of the schema before Grafana 5, in 12 columns
+	Span        float64         `json:"span"`
+	Targets     []grafanaTarget `json:"targets"`
+	Panels      []grafanaPanel  `json:"panels"`
+	Bars        bool            `json:"bars"`
+	Stack       bool            `json:"stack"`
+	Format      string          `json:"format"`
+	FieldConfig struct {
+		Defaults struct {
+			Unit string   `json:"unit"`
+			Min  *float64 `json:"min"`
+			Max  *float64 `json:"max"`
+		} `json:"defaults"`
+	} `json:"fieldConfig"`
+	Yaxes []struct {
+		Format string `json:"format"`
+	} `json:"yaxes"`
+}
+
+type grafanaTarget struct {
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+	Hide         bool   `json:"hide"`
+}
+
+// Grafana panel types rendered as time series
+var grafanaChartPanels = map[string]bool{
+	"graph":      true,
+	"timeseries": true,
+	"stat":       true,
+	"singlestat": true,
+	"gauge":      true,
+	"bargauge":   true,
+}
+
+// Grafana units that have an equivalent, with the scale of the values to the Kiali unit
+var grafanaUnits = map[string]struct {
+	unit  string
+	scale float64
+}{
+	"s":        {unit: "seconds", scale: 1},
+	"ms":       {unit: "seconds", scale: 0.001},
+	"µs":       {unit: "seconds", scale: 0.000001},
+	"bytes":    {unit: "bytes", scale: 1},
+	"decbytes": {unit: "bytes", scale: 1},
+	"Bps":      {unit: "bytes/s", scale: 1},
+	"bps":      {unit: "bitrate", scale: 1},
+	"reqps":    {unit: "rps", scale: 1},
+	"rps":      {unit: "rps", scale: 1},
+	"ops":      {unit: "ops", scale: 1},
+}
+
+var legendLabelRegexp = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+// ConvertGrafanaDashboard converts the JSON model of a Grafana dashboard, or its export from the Grafana API, into a
+// dashboard. Only the panels with PromQL targets that map to a metric, with a rate, a sum or a histogram quantile,
+// are converted: the others are reported as untranslated. The name defaults to the uid of the Grafana dashboard.
+func ConvertGrafanaDashboard(name string, grafanaJSON []byte) (*GrafanaImport, error) {
+	var export struct {
+		Dashboard *grafanaDashboard `json:"dashboard"`
+	}
+	if err := json.Unmarshal(grafanaJSON, &export); err != nil {
+		return nil, fmt.Errorf("invalid Grafana dashboard JSON: %v", err)
+	}
+	gd := export.Dashboard
+	if gd == nil {
+		gd = &grafanaDashboard{}
+		if err := json.Unmarshal(grafanaJSON, gd); err != nil {
+			return nil, fmt.Errorf("invalid Grafana dashboard JSON: %v", err)
+		}
+	}
+	if name == "" {
+		name = gd.UID
+	}
+	if name == "" {
+		return nil, fmt.Errorf("the Grafana dashboard has no uid, a name is required")
+	}
+
+	result := &GrafanaImport{
+		Dashboard: MonitoringDashboard{
+			Name:    name,
+			Title:   gd.Title,
+			Runtime: gd.Title,
+			Items:   []MonitoringDashboardItem{},
+		},
+		Untranslated: []UntranslatedPanel{},
+		Warnings:     []string{},
+	}
+	panels := gd.Panels
+	for _, row := range gd.Rows {
+		panels = append(panels, row.Panels...)
+	}
+	for _, p := range flattenGrafanaPanels(panels) {
+		chart, warnings, err := convertGrafanaPanel(p)
+		if err != nil {
+			result.Untranslated = append(result.Untranslated, UntranslatedPanel{ID: p.ID, Title: p.Title, Reason: err.Error()})
+			continue
+		}
+		for _, w := range warnings {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Panel %q: %s", p.Title, w))
+		}
+		result.Dashboard.Items = append(result.Dashboard.Items, MonitoringDashboardItem{Chart: *chart})
+	}
+	// The dashboard is discovered on the workloads exposing the metric of its first chart, the series of a
+	// histogram are its buckets
+	if len(result.Dashboard.Items) > 0 {
+		first := result.Dashboard.Items[0].Chart
+		result.Dashboard.DiscoverOn = first.GetMetrics()[0].MetricName
+		if first.DataType == Histogram {
+			result.Dashboard.DiscoverOn += "_bucket"
+		}
+	}
+	if err := result.Dashboard.Validate(); err != nil {
+		return nil, fmt.Errorf("the converted dashboard is invalid: %v", err)
+	}
+	return result, nil
+}
+
+// YAML returns the dashboard as a list, the format of the custom dashboards of the config and of the ConfigMaps.
+// The unset fields are left out.
+func (in *GrafanaImport) YAML() (string, error) {
+	out, err := yaml.Marshal(MonitoringDashboardsList{in.Dashboard})
+	if err != nil {
+		return "", err
+	}
+	var list []yaml.MapSlice
+	if err := yaml.Unmarshal(out, &list); err != nil {
+		return "", err
+	}
+	pruned, _ := pruneUnset(list)
+	if out, err = yaml.Marshal(pruned); err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// pruneUnset removes the empty strings, false booleans, nulls and empty collections of a YAML document. Numbers are
+// kept, zeros of the pointer fields being meaningful.
+func pruneUnset(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+	case string:
+		return v, v != ""
+	case bool:
+		return v, v
+	case yaml.MapSlice:
+		pruned := yaml.MapSlice{}
+		for _, item := range v {
+			if value, ok := pruneUnset(item.Value); ok {
+				pruned = append(pruned, yaml.MapItem{Key: item.Key, Value: value})
+			}
+		}
+		return pruned, len(pruned) > 0
+	case []yaml.MapSlice:
+		pruned := []interface{}{}
+		for _, item := range v {
+			if value, ok := pruneUnset(item); ok {
+				pruned = append(pruned, value)
+			}
+		}
+		return pruned, len(pruned) > 0
+	case []interface{}:
+		pruned := []interface{}{}
+		for _, item := range v {
+			if value, ok := pruneUnset(item); ok {
+				pruned = append(pruned, value)
+			}
+		}
+		return pruned, len(pruned) > 0
+	}
+	return value, true
+}
+
+// flattenGrafanaPanels returns the panels of the collapsed rows with the others, without the rows
+func flattenGrafanaPanels(panels []grafanaPanel) []grafanaPanel {
+	flat := []grafanaPanel{}
+	for _, p := range panels {
+		if p.Type == "row" {
+			flat = append(flat, flattenGrafanaPanels(p.Panels)...)
+		} else {
+			flat = append(flat, p)
+		}
+	}
+	return flat
+}
+
+func convertGrafanaPanel(p grafanaPanel) (*MonitoringDashboardChart, []string, error) {
+	if !grafanaChartPanels[p.Type] {
+		return nil, nil, fmt.Errorf("panels of type %s are not supported", p.Type)
+	}
+	chart := &MonitoringDashboardChart{
+		Name:      p.Title,
+		Spans:     4,
+		UnitScale: 1,
+		Metrics:   []MonitoringDashboardMetric{},
+	}
+	warnings := []string{}
+
+	switch {
+	case p.GridPos.W > 0:
+		chart.Spans = (p.GridPos.W + 1) / 2
+	case p.Span > 0:
+		chart.Spans = int(p.Span)
+	}
+	if chart.Spans > 12 {
+		chart.Spans = 12
+	}
+	if p.Bars {
+		chartType := "bar"
+		chart.ChartType = &chartType
+	} else if p.Stack {
+		chartType := "area"
+		chart.ChartType = &chartType
+	}
+	if p.FieldConfig.Defaults.Min != nil {
+		min := int(*p.FieldConfig.Defaults.Min)
+		chart.Min = &min
+	}
+	if p.FieldConfig.Defaults.Max != nil {
+		max := int(*p.FieldConfig.Defaults.Max)
+		chart.Max = &max
+	}
+
+	unit := p.FieldConfig.Defaults.Unit
+	if unit == "" && len(p.Yaxes) > 0 {
+		unit = p.Yaxes[0].Format
+	}
+	if unit == "" {
+		unit = p.Format
+	}
+	if u, ok := grafanaUnits[unit]; ok {
+		chart.Unit = u.unit
+		chart.UnitScale = u.scale
+	} else if unit != "" && unit != "short" && unit != "none" {
+		warnings = append(warnings, fmt.Sprintf("unit %s has no equivalent, it is dropped", unit))
+	}
+
+	groupLabels := map[string]bool{}
+	aggregations := map[string]bool{}
+	targets := 0
+	for _, t := range p.Targets {
+		if t.Hide || strings.TrimSpace(t.Expr) == "" {
+			continue
+		}
+		targets++
+		q, err := parsePromQL(t.Expr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v: %s", err, t.Expr)
+		}
+		if chart.DataType != "" && chart.DataType != q.dataType {
+			return nil, nil, fmt.Errorf("its targets mix %s and %s queries", chart.DataType, q.dataType)
+		}
+		if chart.DataType == Raw && chart.Aggregator != q.aggregator {
+			return nil, nil, fmt.Errorf("its targets mix %q and %q aggregations", chart.Aggregator, q.aggregator)
+		}
+		chart.DataType = q.dataType
+		chart.Aggregator = q.aggregator
+		for _, l := range q.byLabels {
+			if !groupLabels[l] {
+				groupLabels[l] = true
+				chart.GroupLabels = append(chart.GroupLabels, l)
+			}
+		}
+		if q.matchers != "" {
+			// Matchers on dashboard variables are replaced by the filters of Kiali, the others select other series
+			if fixed := fixedMatchers(q.matchers); len(fixed) > 0 {
+				return nil, nil, fmt.Errorf("label matchers %s restrict the series and can't be kept", strings.Join(fixed, ","))
+			}
+			warnings = append(warnings, fmt.Sprintf("label matchers %s are dropped, Kiali filters on the namespace and the workload", q.matchers))
+		}
+
+		// The labels of the legend are made available as aggregations
+		for _, m := range legendLabelRegexp.FindAllStringSubmatch(t.LegendFormat, -1) {
+			if l := m[1]; !aggregations[l] && !groupLabels[l] {
+				aggregations[l] = true
+				chart.Aggregations = append(chart.Aggregations, MonitoringDashboardAggregation{Label: l, DisplayName: l})
+			}
+		}
+		displayName := strings.TrimSpace(legendLabelRegexp.ReplaceAllString(t.LegendFormat, ""))
+		if displayName == "" {
+			displayName = p.Title
+		}
+		chart.Metrics = append(chart.Metrics, MonitoringDashboardMetric{MetricName: q.metric, DisplayName: displayName})
+	}
+	if targets == 0 {
+		return nil, nil, fmt.Errorf("it has no PromQL target")
+	}
+	return chart, warnings, nil
+}
+
+var (
+	promMatcherRegexp  = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*"(.*)"\s*$`)
+	grafanaVarRegexp   = regexp.MustCompile(`^(\$[a-zA-Z_][a-zA-Z0-9_]*|\$\{[^}]+\}|\[\[[^\]]+\]\])$`)
+	promMatchAnyValues = map[string]bool{".*": true, ".+": true}
+)
+
+// fixedMatchers returns the label matchers of a selector that neither use a dashboard variable nor match any value
+func fixedMatchers(selector string) []string {
+	selector = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(selector), "{"), "}")
+	fixed := []string{}
+	for _, matcher := range splitTopLevel(selector, ',') {
+		if strings.TrimSpace(matcher) == "" {
+			continue
+		}
+		m := promMatcherRegexp.FindStringSubmatch(matcher)
+		if m == nil {
+			fixed = append(fixed, strings.TrimSpace(matcher))
+			continue
+		}
+		if grafanaVarRegexp.MatchString(m[3]) || (m[2] == "=~" && promMatchAnyValues[m[3]]) {
+			continue
+		}
+		fixed = append(fixed, strings.TrimSpace(matcher))
+	}
+	return fixed
+}
+
+type promQuery struct {
+	metric     string
+	dataType   string
+	aggregator string
+	byLabels   []string
+	// Label matchers of the selector, which can't be kept
+	matchers string
+}
+
+var (
+	promSelectorRegexp = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)\s*(\{.*\})?$`)
+	promRangeRegexp    = regexp.MustCompile(`^(.*)\[[^\]]+\]$`)
+	promByRegexp       = regexp.MustCompile(`^(by|without)\s*\(([^)]*)\)`)
+	promAggregators    = []string{"sum", "avg", "min", "max", "count", "stddev", "stdvar"}
+)
+
+// parsePromQL recognizes the PromQL expressions that map to a chart: a metric, possibly aggregated, the rate of a
+// counter, summed, and the quantile of a histogram
+func parsePromQL(expr string) (*promQuery, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "(") && matchingParen(expr, 0) == len(expr)-1 {
+		return parsePromQL(expr[1 : len(expr)-1])
+	}
+
+	if args, ok := promCall(expr, "histogram_quantile"); ok {
+		parts := splitTopLevel(args, ',')
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unsupported histogram_quantile arguments")
+		}
+		q, err := parsePromQL(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		if q.dataType != Rate || !strings.HasSuffix(q.metric, "_bucket") {
+			return nil, fmt.Errorf("histogram_quantile must apply to the rate of a _bucket metric")
+		}
+		q.dataType = Histogram
+		q.metric = strings.TrimSuffix(q.metric, "_bucket")
+		byLabels := []string{}
+		for _, l := range q.byLabels {
+			if l != "le" {
+				byLabels = append(byLabels, l)
+			}
+		}
+		q.byLabels = byLabels
+		return q, nil
+	}
+
+	for _, f := range []string{"rate", "irate"} {
+		if args, ok := promCall(expr, f); ok {
+			m := promRangeRegexp.FindStringSubmatch(strings.TrimSpace(args))
+			if m == nil {
+				return nil, fmt.Errorf("%s must apply to a range vector", f)
+			}
+			q, err := parsePromQL(m[1])
+			if err != nil {
+				return nil, err
+			}
+			if q.dataType != Raw || q.aggregator != "" {
+				return nil, fmt.Errorf("%s must apply to a metric", f)
+			}
+			q.dataType = Rate
+			return q, nil
+		}
+	}
+
+	for _, agg := range promAggregators {
+		if !strings.HasPrefix(expr, agg) {
+			continue
+		}
+		rest := strings.TrimSpace(expr[len(agg):])
+		var byLabels []string
+		if m := promByRegexp.FindStringSubmatch(rest); m != nil {
+			if m[1] == "without" {
+				return nil, fmt.Errorf("without clauses are not supported")
+			}
+			byLabels = splitLabels(m[2])
+			rest = strings.TrimSpace(rest[len(m[0]):])
+		}
+		if !strings.HasPrefix(rest, "(") {
+			continue
+		}
+		end := matchingParen(rest, 0)
+		if end < 0 {
+			return nil, fmt.Errorf("unbalanced parentheses")
+		}
+		trailing := strings.TrimSpace(rest[end+1:])
+		if m := promByRegexp.FindStringSubmatch(trailing); m != nil {
+			if m[1] == "without" {
+				return nil, fmt.Errorf("without clauses are not supported")
+			}
+			byLabels = splitLabels(m[2])
+			trailing = strings.TrimSpace(trailing[len(m[0]):])
+		}
+		if trailing != "" {
+			return nil, fmt.Errorf("unsupported PromQL expression")
+		}
+		q, err := parsePromQL(rest[1:end])
+		if err != nil {
+			return nil, err
+		}
+		if q.aggregator != "" || len(q.byLabels) > 0 {
+			return nil, fmt.Errorf("nested aggregations are not supported")
+		}
+		if q.dataType == Rate && agg != "sum" {
+			return nil, fmt.Errorf("only sums of rates are supported")
+		}
+		if q.dataType == Raw {
+			q.aggregator = agg
+		}
+		q.byLabels = byLabels
+		return q, nil
+	}
+
+	if m := promSelectorRegexp.FindStringSubmatch(expr); m != nil {
+		return &promQuery{metric: m[1], dataType: Raw, matchers: m[2]}, nil
+	}
+	return nil, fmt.Errorf("unsupported PromQL expression")
+}
+
+// promCall returns the arguments of a call of the function f spanning the whole expression
+func promCall(expr, f string) (string, bool) {
+	if !strings.HasPrefix(expr, f) {
+		return "", false
+	}
+	rest := strings.TrimSpace(expr[len(f):])
+	if !strings.HasPrefix(rest, "(") || matchingParen(rest, 0) != len(rest)-1 {
+		return "", false
+	}
+	return rest[1 : len(rest)-1], true
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open, or -1
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits s on the separators that are not within parentheses or braces
+func splitTopLevel(s string, sep byte) []string {
+	parts := []string{}
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func splitLabels(s string) []string {
+	labels := []string{}
+	for _, l := range strings.Split(s, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
@@ -0,0 +1,205 @@
+package dashboards
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const grafanaDashboardJSON = `{
+  "dashboard": {
+    "uid": "vertx-http",
+    "title": "Vert.x HTTP",
+    "panels": [
+      {
+        "id": 1,
+        "type": "graph",
+        "title": "Request rate",
+        "gridPos": {"w": 12},
+        "yaxes": [{"format": "reqps"}],
+        "targets": [
+          {"expr": "sum(rate(vertx_http_server_requests_total{namespace=\"$namespace\"}[5m])) by (method)", "legendFormat": "{{method}} {{code}}"}
+        ]
+      },
+      {
+        "id": 2,
+        "type": "row",
+        "title": "Latency",
+        "panels": [
+          {
+            "id": 3,
+            "type": "timeseries",
+            "title": "Response time",
+            "fieldConfig": {"defaults": {"unit": "ms", "min": 0}},
+            "targets": [
+              {"expr": "histogram_quantile(0.95, sum by (le, route) (rate(vertx_http_server_response_time_bucket[1m])))"}
+            ]
+          }
+        ]
+      },
+      {
+        "id": 4,
+        "type": "stat",
+        "title": "Connections",
+        "bars": true,
+        "targets": [
+          {"expr": "max(vertx_http_server_connections)", "legendFormat": "Open"},
+          {"expr": "max(vertx_http_server_connections_max)", "legendFormat": "Max"},
+          {"expr": "vertx_ignored", "hide": true}
+        ]
+      },
+      {"id": 5, "type": "text", "title": "Notes"},
+      {
+        "id": 6,
+        "type": "graph",
+        "title": "Error ratio",
+        "targets": [
+          {"expr": "sum(rate(errors_total[5m])) / sum(rate(requests_total[5m]))"}
+        ]
+      },
+      {"id": 7, "type": "graph", "title": "Empty", "targets": []},
+      {
+        "id": 8,
+        "type": "graph",
+        "title": "Server errors",
+        "targets": [
+          {"expr": "sum(rate(vertx_http_server_requests_total{namespace=~\"$namespace\",code=~\"5..\"}[5m]))"}
+        ]
+      }
+    ]
+  },
+  "meta": {"slug": "vertx-http"}
+}`
+
+func TestConvertGrafanaDashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	result, err := ConvertGrafanaDashboard("", []byte(grafanaDashboardJSON))
+	assert.NoError(err)
+
+	d := result.Dashboard
+	assert.NoError(d.Validate())
+	assert.Equal("vertx-http", d.Name)
+	assert.Equal("Vert.x HTTP", d.Title)
+	assert.Equal("vertx_http_server_requests_total", d.DiscoverOn)
+	assert.Len(d.Items, 3)
+
+	rate := d.Items[0].Chart
+	assert.Equal("Request rate", rate.Name)
+	assert.Equal(Rate, rate.DataType)
+	assert.Equal("rps", rate.Unit)
+	assert.Equal(6, rate.Spans)
+	assert.Equal([]string{"method"}, rate.GroupLabels)
+	assert.Equal([]MonitoringDashboardAggregation{{Label: "code", DisplayName: "code"}}, rate.Aggregations)
+	assert.Equal([]MonitoringDashboardMetric{{MetricName: "vertx_http_server_requests_total", DisplayName: "Request rate"}}, rate.Metrics)
+
+	histo := d.Items[1].Chart
+	assert.Equal(Histogram, histo.DataType)
+	assert.Equal("vertx_http_server_response_time", histo.Metrics[0].MetricName)
+	assert.Equal("seconds", histo.Unit)
+	assert.Equal(0.001, histo.UnitScale)
+	assert.Equal([]string{"route"}, histo.GroupLabels)
+	assert.Equal(0, *histo.Min)
+
+	raw := d.Items[2].Chart
+	assert.Equal(Raw, raw.DataType)
+	assert.Equal("max", raw.Aggregator)
+	assert.Equal("bar", *raw.ChartType)
+	assert.Equal([]MonitoringDashboardMetric{
+		{MetricName: "vertx_http_server_connections", DisplayName: "Open"},
+		{MetricName: "vertx_http_server_connections_max", DisplayName: "Max"},
+	}, raw.Metrics)
+
+	assert.Equal([]UntranslatedPanel{
+		{ID: 5, Title: "Notes", Reason: "panels of type text are not supported"},
+		{ID: 6, Title: "Error ratio", Reason: "unsupported PromQL expression: sum(rate(errors_total[5m])) / sum(rate(requests_total[5m]))"},
+		{ID: 7, Title: "Empty", Reason: "it has no PromQL target"},
+		{ID: 8, Title: "Server errors", Reason: `label matchers code=~"5.." restrict the series and can't be kept`},
+	}, result.Untranslated)
+	assert.Equal([]string{`Panel "Request rate": label matchers {namespace="$namespace"} are dropped, Kiali filters on the namespace and the workload`}, result.Warnings)
+}
+
+func TestConvertGrafanaDashboardErrors(t *testing.T) {
+	_, err := ConvertGrafanaDashboard("foo", []byte("{"))
+	assert.Error(t, err)
+
+	_, err = ConvertGrafanaDashboard("", []byte(`{"title": "No uid"}`))
+	assert.EqualError(t, err, "the Grafana dashboard has no uid, a name is required")
+
+	_, err = ConvertGrafanaDashboard("foo", []byte(`{"title": "Nothing to convert", "panels": [{"id": 1, "type": "text"}]}`))
+	assert.EqualError(t, err, "the converted dashboard is invalid: neither items nor discoverOn are set")
+
+	result, err := ConvertGrafanaDashboard("foo", []byte(`{"title": "Old schema", "rows": [{"panels": [{"id": 1, "type": "graph", "title": "Up", "span": 6, "targets": [{"expr": "up"}]}]}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", result.Dashboard.Name)
+	assert.Equal(t, 6, result.Dashboard.Items[0].Chart.Spans)
+}
+
+func TestConvertGrafanaHistogramDiscovery(t *testing.T) {
+	result, err := ConvertGrafanaDashboard("latency", []byte(`{"panels": [{"id": 1, "type": "graph", "title": "Latency", "targets": [
+		{"expr": "histogram_quantile(0.99, sum(rate(http_latency_seconds_bucket[1m])) by (le))"}
+	]}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "http_latency_seconds_bucket", result.Dashboard.DiscoverOn)
+}
+
+func TestFixedMatchers(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(fixedMatchers(`{namespace="$namespace", pod=~"${pod}", app=~"[[app]]", job=~".*"}`))
+	assert.Equal([]string{`code="500"`, `method!="GET"`}, fixedMatchers(`{code="500",namespace="$namespace",method!="GET"}`))
+}
+
+func TestParsePromQL(t *testing.T) {
+	assert := assert.New(t)
+
+	q, err := parsePromQL("sum by (pod) (irate(process_cpu_seconds_total[1m]))")
+	assert.NoError(err)
+	assert.Equal(&promQuery{metric: "process_cpu_seconds_total", dataType: Rate, byLabels: []string{"pod"}}, q)
+
+	q, err = parsePromQL("(avg(go_goroutines))")
+	assert.NoError(err)
+	assert.Equal(&promQuery{metric: "go_goroutines", dataType: Raw, aggregator: "avg"}, q)
+
+	q, err = parsePromQL("summary_total")
+	assert.NoError(err)
+	assert.Equal(&promQuery{metric: "summary_total", dataType: Raw}, q)
+
+	for _, expr := range []string{
+		"avg(rate(foo[5m]))",
+		"sum without (pod) (foo)",
+		"sum(sum(foo) by (pod))",
+		"histogram_quantile(0.9, rate(foo[5m]))",
+		"rate(foo)",
+		"foo * 100",
+	} {
+		_, err = parsePromQL(expr)
+		assert.Error(err, expr)
+	}
+}
+
+func TestGrafanaImportYAML(t *testing.T) {
+	result, err := ConvertGrafanaDashboard("up", []byte(`{"title": "Up", "panels": [{"id": 1, "type": "graph", "title": "Up", "targets": [{"expr": "sum(up)"}]}]}`))
+	assert.NoError(t, err)
+	out, err := result.YAML()
+	assert.NoError(t, err)
+	assert.Equal(t, `- name: up
+  title: Up
+  runtime: Up
+  discoverOn: up
+  items:
+  - chart:
+      name: Up
+      unitScale: 1
+      spans: 4
+      metrics:
+      - metricName: up
+        displayName: Up
+      dataType: raw
+      aggregator: sum
+`, out)
+
+	parsed, err := UnmarshalDashboards(out)
+	assert.NoError(t, err)
+	assert.Equal(t, result.Dashboard, parsed[0])
+}
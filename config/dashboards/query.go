@@ -0,0 +1,114 @@
+package dashboards
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// QueryVariables holds the values of the placeholders of the PromQL templates, used in the "query" charts as $name or ${name}.
+// Besides, the query of a metric can include the query of a previous metric of the chart by its ref.
+type QueryVariables struct {
+	// Namespace, App, Workload and Version identify the entity the dashboard is displayed for ($namespace, $app, $workload, $version).
+	// They are escaped to fit in a quoted label value.
+	Namespace string
+	App       string
+	Workload  string
+	Version   string
+	// RateInterval is the rate interval selected in the UI, e.g. "1m" ($rateInterval)
+	RateInterval string
+	// Filters are the label matchers set by Kiali, without braces, e.g. namespace="bookinfo",app="reviews" ($filters)
+	Filters string
+	// GroupBy holds the comma separated labels to group by, possibly empty ($groupBy)
+	GroupBy string
+}
+
+var (
+	placeholderRegexp = regexp.MustCompile(`\$(\{(\w+)\}|(\w+))`)
+	refRegexp         = regexp.MustCompile(`^\w+$`)
+)
+
+const placeholdersList = "$namespace, $app, $workload, $version, $rateInterval, $filters, $groupBy"
+
+var queryPlaceholders = map[string]bool{
+	"namespace":    true,
+	"app":          true,
+	"workload":     true,
+	"version":      true,
+	"rateInterval": true,
+	"filters":      true,
+	"groupBy":      true,
+}
+
+func (in QueryVariables) values() map[string]string {
+	return map[string]string{
+		"namespace":    escapeLabelValue(in.Namespace),
+		"app":          escapeLabelValue(in.App),
+		"workload":     escapeLabelValue(in.Workload),
+		"version":      escapeLabelValue(in.Version),
+		"rateInterval": in.RateInterval,
+		"filters":      in.Filters,
+		"groupBy":      in.GroupBy,
+	}
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
+
+// ExpandQueries expands the PromQL templates of a "query" chart with the given variables.
+// The returned queries are aligned with the chart metrics. A reference to a previous metric is replaced by its expanded
+// query, in parentheses, so that series can be combined, e.g. "$errors / $total".
+func (in *MonitoringDashboardChart) ExpandQueries(vars QueryVariables) ([]string, error) {
+	values := vars.values()
+	queries := make([]string, len(in.Metrics))
+	for i, metric := range in.Metrics {
+		var unknown []string
+		queries[i] = placeholderRegexp.ReplaceAllStringFunc(metric.Query, func(placeholder string) string {
+			groups := placeholderRegexp.FindStringSubmatch(placeholder)
+			name := groups[2] + groups[3]
+			if value, ok := values[name]; ok {
+				return value
+			}
+			unknown = append(unknown, placeholder)
+			return placeholder
+		})
+		if len(unknown) > 0 {
+			return nil, fmt.Errorf("metrics[%d]: unknown placeholders %s, expected one of %s or a ref of a previous metric", i, strings.Join(unknown, ", "), placeholdersList)
+		}
+		if metric.Ref != "" {
+			values[metric.Ref] = "(" + queries[i] + ")"
+		}
+	}
+	return queries, nil
+}
+
+func (in *MonitoringDashboardChart) validateQueries() error {
+	var errs []string
+	refs := make(map[string]bool)
+	for j, m := range in.Metrics {
+		if strings.TrimSpace(m.Query) == "" {
+			errs = append(errs, fmt.Sprintf("metrics[%d]: query is missing", j))
+		}
+		if m.Ref == "" {
+			continue
+		}
+		if queryPlaceholders[m.Ref] {
+			errs = append(errs, fmt.Sprintf("metrics[%d]: ref %q clashes with a placeholder", j, m.Ref))
+		} else if refs[m.Ref] {
+			errs = append(errs, fmt.Sprintf("metrics[%d]: ref %q is already defined", j, m.Ref))
+		} else if !refRegexp.MatchString(m.Ref) {
+			errs = append(errs, fmt.Sprintf("metrics[%d]: invalid ref %q, only letters, digits and underscores are allowed", j, m.Ref))
+		}
+		refs[m.Ref] = true
+	}
+	if len(errs) == 0 {
+		if _, err := in.ExpandQueries(QueryVariables{}); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
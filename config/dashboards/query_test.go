@@ -0,0 +1,61 @@
+package dashboards
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandQueries(t *testing.T) {
+	assert := assert.New(t)
+
+	chart := MonitoringDashboardChart{
+		Name:     "Error ratio",
+		DataType: Query,
+		Metrics: []MonitoringDashboardMetric{
+			{Ref: "errors", Hidden: true, Query: `sum(rate(http_requests_total{$filters,code=~"5.."}[$rateInterval])) by ($groupBy)`},
+			{Ref: "total", Hidden: true, Query: `sum(rate(http_requests_total{$filters}[${rateInterval}])) by ($groupBy)`},
+			{DisplayName: "Errors", Query: "$errors / $total"},
+			{DisplayName: "Workload", Query: `up{namespace="$namespace",app="$app",version="$version",pod=~"$workload-.*"}`},
+		},
+	}
+	assert.NoError(chart.validateQueries())
+
+	queries, err := chart.ExpandQueries(QueryVariables{
+		Namespace:    "bookinfo",
+		App:          "reviews",
+		Workload:     "reviews-v1",
+		Version:      `v"1`,
+		RateInterval: "5m",
+		Filters:      `namespace="bookinfo",app="reviews"`,
+		GroupBy:      "pod",
+	})
+	assert.NoError(err)
+	assert.Equal([]string{
+		`sum(rate(http_requests_total{namespace="bookinfo",app="reviews",code=~"5.."}[5m])) by (pod)`,
+		`sum(rate(http_requests_total{namespace="bookinfo",app="reviews"}[5m])) by (pod)`,
+		`(sum(rate(http_requests_total{namespace="bookinfo",app="reviews",code=~"5.."}[5m])) by (pod)) / (sum(rate(http_requests_total{namespace="bookinfo",app="reviews"}[5m])) by (pod))`,
+		`up{namespace="bookinfo",app="reviews",version="v\"1",pod=~"reviews-v1-.*"}`,
+	}, queries)
+}
+
+func TestValidateQueries(t *testing.T) {
+	chart := MonitoringDashboardChart{
+		Name:     "Heap",
+		DataType: Query,
+		Metrics: []MonitoringDashboardMetric{
+			{DisplayName: "Ratio", Query: "$used / $max"},
+			{Ref: "used", Query: "jvm_memory_bytes_used{$filters}"},
+			{Ref: "used", Query: "jvm_memory_bytes_max{$filters}"},
+			{Ref: "app"},
+			{Ref: "max-heap", Query: "foo"},
+		},
+	}
+	assert.EqualError(t, chart.validateQueries(), `metrics[2]: ref "used" is already defined; metrics[3]: query is missing; metrics[3]: ref "app" clashes with a placeholder; metrics[4]: invalid ref "max-heap", only letters, digits and underscores are allowed`)
+
+	chart.Metrics = chart.Metrics[:2]
+	assert.EqualError(t, chart.validateQueries(), "metrics[0]: unknown placeholders $used, $max, expected one of $namespace, $app, $workload, $version, $rateInterval, $filters, $groupBy or a ref of a previous metric")
+
+	dashboard := MonitoringDashboard{Name: "jvm", Items: []MonitoringDashboardItem{{Chart: MonitoringDashboardChart{Name: "Empty", DataType: Query}}}}
+	assert.EqualError(t, dashboard.Validate(), "items[0]: metrics are not set")
+}
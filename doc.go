@@ -34,7 +34,7 @@ type AggregateValueParam struct {
 	Name string `json:"aggregateValue"`
 }
 
-// swagger:parameters appMetrics appDetails graphApp graphAppVersion appDashboard appSpans appTraces errorTraces
+// swagger:parameters appMetrics appDetails graphApp graphAppVersion appDashboard appSpans appTraces appTracesStats errorTraces
 type AppParam struct {
 	// The app name (label value).
 	//
@@ -70,7 +70,7 @@ type ContainerParam struct {
 	Name string `json:"container"`
 }
 
-// swagger:parameters istioConfigList workloadList workloadDetails workloadUpdate serviceDetails serviceUpdate appSpans serviceSpans workloadSpans appTraces serviceTraces workloadTraces errorTraces workloadValidations appList serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype serviceList appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceValidations getIter8Experiments postIter8Experiments patchIter8Experiments deleteIter8Experiments podProxyDump podProxyResource
+// swagger:parameters istioConfigList workloadList workloadDetails workloadUpdate serviceDetails serviceUpdate appSpans serviceSpans workloadSpans appTraces appTracesStats serviceTraces workloadTraces errorTraces workloadValidations appList serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype serviceList appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceValidations getIter8Experiments postIter8Experiments patchIter8Experiments deleteIter8Experiments podProxyDump podProxyResource rollouts rolloutCreate rolloutDetails rolloutAction serviceTrafficTemplate authorizationPoliciesGenerate authorizationPoliciesApply namespaceCertificates workloadCertificates sidecarRecommendations namespaceUnusedIstioConfig istioConfigImpact namespaceMtlsMigration customDashboardsErrors cacheNamespaceRefresh cacheNamespaceEvict
 type NamespaceParam struct {
 	// The namespace name.
 	//
@@ -88,7 +88,7 @@ type NameParam struct {
 	Name string `json:"name"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigImpact
 type ObjectNameParam struct {
 	// The Istio object name.
 	//
@@ -97,7 +97,7 @@ type ObjectNameParam struct {
 	Name string `json:"object"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype istioConfigImpact
 type ObjectTypeParam struct {
 	// The Istio object type.
 	//
@@ -125,7 +125,7 @@ type ResourceParam struct {
 	Name string `json:"resource"`
 }
 
-// swagger:parameters serviceDetails serviceUpdate serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces
+// swagger:parameters serviceDetails serviceUpdate serviceTrafficTemplate serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces
 type ServiceParam struct {
 	// The service name.
 	//
@@ -153,7 +153,7 @@ type DurationLogParam struct {
 	Name string `json:"duration"`
 }
 
-// swagger:parameters traceDetails
+// swagger:parameters traceDetails traceAnalysis traceComparison
 type TraceIDParam struct {
 	// The trace ID.
 	//
@@ -162,6 +162,49 @@ type TraceIDParam struct {
 	Name string `json:"traceID"`
 }
 
+// swagger:parameters traceComparison
+type TraceComparisonParam struct {
+	// The baseline trace ID. When not set, the trace is compared with the median of similar traces of the app
+	// given by the 'namespace' and 'app' parameters, found between 'startMicros' and 'endMicros'.
+	//
+	// in: query
+	// required: false
+	Baseline string `json:"baseline"`
+	// The namespace of the app used to find similar traces.
+	//
+	// in: query
+	// required: false
+	Namespace string `json:"namespace"`
+	// The app used to find similar traces.
+	//
+	// in: query
+	// required: false
+	App string `json:"app"`
+}
+
+// swagger:parameters appTracesStats
+type TracesStatsTimeBucketsParam struct {
+	// The number of time buckets of the latency heatmap. Default: 20, at most 500.
+	//
+	// in: query
+	// required: false
+	Name string `json:"timeBuckets"`
+}
+
+// swagger:parameters grafanaDashboardImport
+type GrafanaDashboardImportParams struct {
+	// Name of the custom dashboard. Default is the uid of the Grafana dashboard.
+	//
+	// in: query
+	// required: false
+	Name string `json:"name"`
+	// The Grafana dashboard JSON model, or its export from the Grafana API.
+	//
+	// in: body
+	// required: true
+	Body map[string]interface{}
+}
+
 // swagger:parameters customDashboard
 type DashboardParam struct {
 	// The dashboard resource name.
@@ -171,7 +214,7 @@ type DashboardParam struct {
 	Name string `json:"dashboard"`
 }
 
-// swagger:parameters workloadDetails workloadUpdate workloadValidations workloadMetrics graphWorkload workloadDashboard workloadSpans workloadTraces
+// swagger:parameters workloadDetails workloadUpdate workloadValidations workloadMetrics graphWorkload workloadDashboard workloadSpans workloadTraces workloadCertificates
 type WorkloadParam struct {
 	// The workload name.
 	//
@@ -187,7 +230,7 @@ type WorkloadParam struct {
 
 // swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
 type AppendersParam struct {
-	// Comma-separated list of Appenders to run. Available appenders: [aggregateNode, deadNode, healthConfig, idleNode, istio, responseTime, securityPolicy, serviceEntry, sidecarsCheck, throughput].
+	// Comma-separated list of Appenders to run. Available appenders: [aggregateNode, authorizationPolicy (not run by default), deadNode, healthConfig, idleNode, istio, responseTime, securityPolicy, serviceEntry, sidecarsCheck, throughput].
 	//
 	// in: query
 	// required: false
@@ -254,6 +297,15 @@ type NamespacesParam struct {
 	Name string `json:"namespaces"`
 }
 
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+type PartialParam struct {
+	// Return the parts of the graph that could be generated, with warnings for the failed namespaces and appenders. Defaults to the partial_results setting of the server.
+	//
+	// in: query
+	// required: false
+	Name string `json:"partial"`
+}
+
 // swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
 type QueryTimeParam struct {
 	// Unix time (seconds) for query such that time range is [queryTime-duration..queryTime]. Default is now.
@@ -328,7 +380,7 @@ type DirectionParam struct {
 	Name string `json:"direction"`
 }
 
-// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics customDashboard appDashboard serviceDashboard workloadDashboard
+// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics customDashboard appDashboard serviceDashboard workloadDashboard metricsQuery
 type DurationParam struct {
 	// Duration of the query period, in seconds.
 	//
@@ -358,6 +410,16 @@ type LabelsFiltersParam struct {
 	Name string `json:"labelsFilters"`
 }
 
+// swagger:parameters customDashboard
+type DashboardWorkloadParam struct {
+	// In custom dashboards, the workload that replaces the $workload placeholder of the PromQL query charts.
+	//
+	// in: query
+	// required: false
+	//
+	Name string `json:"workload"`
+}
+
 // swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics customDashboard appDashboard serviceDashboard workloadDashboard
 type QuantilesParam struct {
 	// List of quantiles to fetch. Fetch no quantiles when empty. Ex: [0.5, 0.95, 0.99].
@@ -398,6 +460,16 @@ type RequestProtocolParam struct {
 	Name string `json:"requestProtocol"`
 }
 
+// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics appDashboard serviceDashboard workloadDashboard
+type ExemplarsParam struct {
+	// When true, exemplars of the request duration histogram are returned with each series, linking to traces.
+	//
+	// in: query
+	// required: false
+	// default: false
+	Name string `json:"exemplars"`
+}
+
 // swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics appDashboard serviceDashboard workloadDashboard
 type ReporterParam struct {
 	// Istio telemetry reporter: 'source' or 'destination'.
@@ -408,7 +480,7 @@ type ReporterParam struct {
 	Name string `json:"reporter"`
 }
 
-// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics customDashboard appDashboard serviceDashboard workloadDashboard
+// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics customDashboard appDashboard serviceDashboard workloadDashboard metricsQuery
 type StepParam struct {
 	// Step between [graph] datapoints, in seconds.
 	//
@@ -427,6 +499,36 @@ type VersionParam struct {
 	Name string `json:"version"`
 }
 
+// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics namespaceMetrics metricsQuery
+type MetricsFormatParam struct {
+	// Format of the response: 'json', 'csv' or 'openmetrics'. CSV and OpenMetrics text are meant for exports.
+	//
+	// in: query
+	// required: false
+	// default: json
+	Name string `json:"format"`
+}
+
+// swagger:parameters metricsQuery
+type MetricsQueryParams struct {
+	// The PromQL query. Every series selector is restricted to the namespaces given by the 'namespaces' parameter.
+	//
+	// in: query
+	// required: true
+	Query string `json:"query"`
+	// Comma separated list of namespaces. Defaults to all the namespaces accessible to the user.
+	//
+	// in: query
+	// required: false
+	Namespaces string `json:"namespaces"`
+	// Label holding the namespace of the series: 'destination_workload_namespace', 'source_workload_namespace', 'destination_service_namespace', 'namespace' or the custom dashboards namespace label.
+	//
+	// in: query
+	// required: false
+	// default: destination_workload_namespace
+	NamespaceLabel string `json:"namespaceLabel"`
+}
+
 /////////////////////
 // SWAGGER RESPONSES
 /////////////////////
@@ -612,6 +714,27 @@ type TraceDetailsResponse struct {
 	Body []jaegerModels.Trace
 }
 
+// Critical path and per-service self time of a trace
+// swagger:response traceAnalysisResponse
+type TraceAnalysisResponse struct {
+	// in:body
+	Body models.TraceAnalysis
+}
+
+// Comparison of a trace with a baseline
+// swagger:response traceComparisonResponse
+type TraceComparisonResponse struct {
+	// in:body
+	Body models.TraceComparison
+}
+
+// Span statistics and latency heatmap of an app
+// swagger:response tracesStatsResponse
+type TracesStatsResponse struct {
+	// in:body
+	Body models.TracingStats
+}
+
 // Number of traces in error
 // swagger:response errorTracesResponse
 type ErrorTracesResponse struct {
@@ -647,6 +770,13 @@ type DashboardResponse struct {
 	Body models.MonitoringDashboard
 }
 
+// Validation errors of the custom dashboards discovered at runtime
+// swagger:response dashboardValidationErrorsResponse
+type DashboardValidationErrorsResponse struct {
+	// in:body
+	Body []models.DashboardValidationError
+}
+
 // IstioConfig details of an specific Istio Object
 // swagger:response istioConfigDetailsResponse
 type IstioConfigDetailsResponse struct {
@@ -675,6 +805,13 @@ type GrafanaInfoResponse struct {
 	Body models.GrafanaInfo
 }
 
+// Grafana dashboard converted into a custom dashboard
+// swagger:response grafanaDashboardImportResponse
+type GrafanaDashboardImportResponse struct {
+	// in: body
+	Body models.GrafanaDashboardImport
+}
+
 // Return all the descriptor data related to Jaeger
 // swagger:response jaegerInfoResponse
 type JaegerInfoResponse struct {
@@ -771,6 +908,13 @@ type IstioStatusResponse struct {
 	Body business.IstioComponentStatus
 }
 
+// Return the state of the Kiali cache
+// swagger:response cacheStatusResponse
+type CacheStatusResponse struct {
+	// in: body
+	Body models.CacheStatus
+}
+
 // Posted parameters for a metrics stats query
 // swagger:parameters metricsStats
 type MetricsStatsQueryBody struct {
@@ -791,3 +935,216 @@ type ClustersResponse struct {
 	// in: body
 	Body []business.Cluster
 }
+
+// Recent notification events
+// swagger:response notificationEventsResponse
+type NotificationEventsResponse struct {
+	// in: body
+	Body []models.NotificationEvent
+}
+
+// List of notification silences
+// swagger:response notificationSilencesResponse
+type NotificationSilencesResponse struct {
+	// in: body
+	Body []models.NotificationSilence
+}
+
+// Created notification silence
+// swagger:response notificationSilenceResponse
+type NotificationSilenceResponse struct {
+	// in: body
+	Body models.NotificationSilence
+}
+
+// Notification silence to create
+// swagger:parameters notificationSilenceCreate
+type NotificationSilenceBody struct {
+	// in: body
+	Body models.NotificationSilence
+}
+
+// swagger:parameters notificationSilenceDelete
+type NotificationSilenceIDParam struct {
+	// The silence ID.
+	//
+	// in: path
+	// required: true
+	Name string `json:"id"`
+}
+
+// List of rollouts
+// swagger:response rolloutsResponse
+type RolloutsResponse struct {
+	// in: body
+	Body []models.Rollout
+}
+
+// A rollout with its state and history
+// swagger:response rolloutResponse
+type RolloutResponse struct {
+	// in: body
+	Body models.Rollout
+}
+
+// Rollout definition
+// swagger:parameters rolloutCreate
+type RolloutBody struct {
+	// in: body
+	Body models.RolloutSpec
+}
+
+// swagger:parameters rolloutDetails rolloutAction
+type RolloutNameParam struct {
+	// The rollout name.
+	//
+	// in: path
+	// required: true
+	Name string `json:"rollout"`
+}
+
+// swagger:parameters rolloutAction
+type RolloutActionParam struct {
+	// The action: pause, resume or abort.
+	//
+	// in: path
+	// required: true
+	Name string `json:"action"`
+}
+
+// Istio configuration generated from a traffic intent, with its validations
+// swagger:response trafficConfigResponse
+type TrafficConfigResponse struct {
+	// in: body
+	Body models.TrafficConfig
+}
+
+// Traffic intent of the service
+// swagger:parameters serviceTrafficTemplate
+type TrafficIntentBody struct {
+	// in: body
+	Body models.TrafficIntent
+}
+
+// swagger:parameters serviceTrafficTemplate
+type TrafficTemplateApplyParam struct {
+	// Apply the generated configuration when it has no validation errors. Default: false.
+	//
+	// in: query
+	// required: false
+	Name bool `json:"apply"`
+}
+
+// Decision of a simulated request, with the policies leading to it
+// swagger:response authorizationSimulationResponse
+type AuthorizationSimulationResponse struct {
+	// in: body
+	Body models.AuthorizationSimulation
+}
+
+// Source, destination and attributes of the simulated request
+// swagger:parameters authorizationSimulation
+type AuthorizationSimulationBody struct {
+	// in: body
+	Body models.AuthorizationSimulationRequest
+}
+
+// Least-privilege AuthorizationPolicies generated from the observed traffic
+// swagger:response generatedAuthorizationPoliciesResponse
+type GeneratedAuthorizationPoliciesResponse struct {
+	// in: body
+	Body models.GeneratedAuthorizationPolicies
+}
+
+// swagger:parameters authorizationPoliciesGenerate authorizationPoliciesApply sidecarRecommendations namespaceUnusedIstioConfig namespaceMtlsMigration
+type ObservedTrafficParams struct {
+	// Interval of the observed traffic.
+	//
+	// in: query
+	// required: false
+	// default: 24h
+	RateInterval string `json:"rateInterval"`
+	// Unix time (seconds) of the end of the interval. Default is now.
+	//
+	// in: query
+	// required: false
+	QueryTime string `json:"queryTime"`
+}
+
+// swagger:parameters authorizationPoliciesGenerate authorizationPoliciesApply
+type DenyAllParam struct {
+	// Also generate an allow-nothing policy, moving the namespace to default-deny.
+	//
+	// in: query
+	// required: false
+	Name bool `json:"denyAll"`
+}
+
+// Certificates loaded by the proxies, with those needing attention
+// swagger:response certificatesReportResponse
+type CertificatesReportResponse struct {
+	// in: body
+	Body models.CertificatesReport
+}
+
+// swagger:parameters meshCertificates namespaceCertificates workloadCertificates
+type CertificatesExpiringWithinParam struct {
+	// Report the certificates expiring within this duration, e.g. "6h".
+	//
+	// in: query
+	// required: false
+	// default: 6h
+	Name string `json:"expiringWithin"`
+}
+
+// Sidecar recommendations of the workloads of a namespace
+// swagger:response sidecarRecommendationsResponse
+type SidecarRecommendationsResponse struct {
+	// in: body
+	Body models.SidecarRecommendations
+}
+
+// Istio config of a namespace with no effect on the observed traffic
+// swagger:response unusedIstioConfigResponse
+type UnusedIstioConfigResponse struct {
+	// in: body
+	Body models.UnusedIstioConfigReport
+}
+
+// swagger:parameters istioConfigImpact
+type IstioConfigChangeParam struct {
+	// The proposed change: delete, or patch with a JSON merge patch.
+	//
+	// in: body
+	// required: true
+	Body models.IstioConfigChange
+}
+
+// swagger:parameters istioConfigImpact
+type IstioConfigImpactParams struct {
+	// Interval of the current traffic rates.
+	//
+	// in: query
+	// required: false
+	// default: 10m
+	RateInterval string `json:"rateInterval"`
+	// Unix time (seconds) of the end of the interval. Default is now.
+	//
+	// in: query
+	// required: false
+	QueryTime string `json:"queryTime"`
+}
+
+// Impact of a proposed Istio config change
+// swagger:response istioConfigImpactResponse
+type IstioConfigImpactResponse struct {
+	// in: body
+	Body models.IstioConfigImpact
+}
+
+// Migration report of a namespace to STRICT mTLS
+// swagger:response mtlsMigrationResponse
+type MtlsMigrationResponse struct {
+	// in: body
+	Body models.MtlsMigrationReport
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/graph"
@@ -36,13 +37,17 @@ func GraphNamespaces(business *business.Layer, o graph.Options) (code int, confi
 
 // graphNamespacesIstio provides a test hook that accepts mock clients
 func graphNamespacesIstio(business *business.Layer, prom *prometheus.Client, o graph.Options) (code int, config interface{}) {
+	ctx, cancel := graph.BudgetContext(o.Context)
+	defer cancel()
+	prom = prom.WithContext(ctx)
 
 	// Create a 'global' object to store the business. Global only to the request.
 	globalInfo := graph.NewAppenderGlobalInfo()
 	globalInfo.Business = business
+	globalInfo.PromClient = prom
 
 	trafficMap := istio.BuildNamespacesTrafficMap(o.TelemetryOptions, prom, globalInfo)
-	code, config = generateGraph(trafficMap, o)
+	code, config = generateGraph(trafficMap, globalInfo, o)
 
 	return code, config
 }
@@ -73,27 +78,42 @@ func GraphNode(business *business.Layer, o graph.Options) (code int, config inte
 
 // graphNodeIstio provides a test hook that accepts mock clients
 func graphNodeIstio(business *business.Layer, client *prometheus.Client, o graph.Options) (code int, config interface{}) {
+	ctx, cancel := graph.BudgetContext(o.Context)
+	defer cancel()
+	client = client.WithContext(ctx)
 
 	// Create a 'global' object to store the business. Global only to the request.
 	globalInfo := graph.NewAppenderGlobalInfo()
 	globalInfo.Business = business
+	globalInfo.PromClient = client
 
 	trafficMap := istio.BuildNodeTrafficMap(o.TelemetryOptions, client, globalInfo)
-	code, config = generateGraph(trafficMap, o)
+	code, config = generateGraph(trafficMap, globalInfo, o)
 
 	return code, config
 }
 
-func generateGraph(trafficMap graph.TrafficMap, o graph.Options) (int, interface{}) {
+func generateGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, o graph.Options) (int, interface{}) {
 	log.Tracef("Generating config for [%s] graph...", o.ConfigVendor)
 
 	promtimer := internalmetrics.GetGraphMarshalTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
 	defer promtimer.ObserveDuration()
 
+	if globalInfo.Summary {
+		// the namespaces are the nodes of the summary
+		if strings.Contains(o.BoxBy, graph.BoxByCluster) {
+			o.BoxBy = graph.BoxByCluster
+		} else {
+			o.BoxBy = ""
+		}
+	}
+
 	var vendorConfig interface{}
 	switch o.ConfigVendor {
 	case graph.VendorCytoscape:
-		vendorConfig = cytoscape.NewConfig(trafficMap, o.ConfigOptions)
+		cytoscapeConfig := cytoscape.NewConfig(trafficMap, o.ConfigOptions)
+		cytoscapeConfig.Warnings = globalInfo.Warnings
+		vendorConfig = cytoscapeConfig
 	default:
 		graph.Error(fmt.Sprintf("ConfigVendor [%s] not supported", o.ConfigVendor))
 	}
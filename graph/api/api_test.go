@@ -4,15 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	osproject_v1 "github.com/openshift/api/project/v1"
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -23,6 +27,7 @@ import (
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
 	"github.com/kiali/kiali/kubernetes/kubetest"
 	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/prometheustest"
@@ -168,6 +173,12 @@ func mockQuery(api *prometheustest.PromAPIMock, query string, ret *model.Vector)
 		query,
 		mock.AnythingOfType("time.Time"),
 	).Return(*ret, nil)
+	api.On(
+		"Query",
+		mock.AnythingOfType("*context.timerCtx"),
+		query,
+		mock.AnythingOfType("time.Time"),
+	).Return(*ret, nil)
 }
 
 // mockNamespaceGraph provides the same single-namespace mocks to be used for different graph types
@@ -2734,3 +2745,93 @@ func TestMultiClusterSourceGraph(t *testing.T) {
 	}
 	assert.Equal(t, 200, resp.StatusCode)
 }
+
+// failingNamespaceAPI fails the queries of a namespace, as an overloaded Prometheus would
+type failingNamespaceAPI struct {
+	*prometheustest.PromAPIMock
+	namespace string
+}
+
+func (in failingNamespaceAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, prom_v1.Warnings, error) {
+	if strings.Contains(query, `"`+in.namespace+`"`) {
+		return nil, nil, errors.New("query timed out")
+	}
+	return in.PromAPIMock.Query(ctx, query, ts)
+}
+
+func TestPartialGraph(t *testing.T) {
+	client, xapi, _, err := setupMocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	xapi.On("Query", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(model.Vector{})
+	client.Inject(failingNamespaceAPI{PromAPIMock: xapi, namespace: "tutorial"})
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/namespaces/graph", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			code, config := graphNamespacesIstio(nil, client, graph.NewOptions(r.WithContext(context)))
+			respond(w, code, config)
+		}))
+
+	ts := httptest.NewServer(mr)
+	defer ts.Close()
+
+	url := ts.URL + "/api/namespaces/graph?graphType=versionedApp&appenders=&partial=true&queryTime=1523364075&namespaces=bookinfo,tutorial"
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var actual cytoscape.Config
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+	assert.Equal(t, []graph.Warning{{Namespace: "tutorial", Message: "query timed out"}}, actual.Warnings)
+}
+
+func TestGraphSummaryAboveNodeLimit(t *testing.T) {
+	client, err := mockNamespaceGraph(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := config.Get()
+	conf.API.Graph.MaxNodes = 2
+	config.Set(conf)
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/namespaces/graph", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			code, config := graphNamespacesIstio(nil, client, graph.NewOptions(r.WithContext(context)))
+			respond(w, code, config)
+		}))
+
+	ts := httptest.NewServer(mr)
+	defer ts.Close()
+
+	// The appenders are skipped for a summary: their queries are not mocked
+	url := ts.URL + "/api/namespaces/graph?namespaces=bookinfo&graphType=versionedApp&boxBy=namespace&appenders=responseTime,securityPolicy&queryTime=1523364075"
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var actual cytoscape.Config
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+	assert.Len(t, actual.Warnings, 1)
+	namespaces := []string{}
+	for _, n := range actual.Elements.Nodes {
+		assert.Equal(t, graph.NodeTypeBox, n.Data.NodeType)
+		assert.Equal(t, graph.BoxByNamespace, n.Data.IsBox)
+		assert.Empty(t, n.Data.Parent)
+		namespaces = append(namespaces, n.Data.Namespace)
+	}
+	assert.Contains(t, namespaces, "bookinfo")
+	assert.Contains(t, namespaces, "istio-system")
+	for _, e := range actual.Elements.Edges {
+		assert.NotEqual(t, e.Data.Source, e.Data.Target)
+	}
+	assert.NotEmpty(t, actual.Elements.Edges)
+}
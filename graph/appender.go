@@ -2,6 +2,7 @@ package graph
 
 import (
 	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/prometheus"
 )
 
@@ -16,6 +17,16 @@ type AppenderGlobalInfo struct {
 	HomeCluster string
 	PromClient  *prometheus.Client
 	Vendor      AppenderVendorInfo // telemetry vendor's global info
+	Warnings    []Warning          // parts of the graph that could not be generated, in partial-result mode
+	Summary     bool               // the graph exceeds the size limits, it is reduced to the summary of its namespaces
+}
+
+// Warning reports a namespace or an appender skipped from a graph generated in partial-result mode,
+// because its telemetry could not be fetched.
+type Warning struct {
+	Namespace string `json:"namespace,omitempty"`
+	Appender  string `json:"appender,omitempty"`
+	Message   string `json:"message"`
 }
 
 // AppenderNamespaceInfo caches information relevant to a single namespace. It allows
@@ -35,6 +46,12 @@ func NewAppenderGlobalInfo() *AppenderGlobalInfo {
 	return &AppenderGlobalInfo{Vendor: NewAppenderVendorInfo()}
 }
 
+// AddWarning records a part of the graph that could not be generated. It must not be called concurrently.
+func (in *AppenderGlobalInfo) AddWarning(namespace, appender string, err error) {
+	log.Warningf("Graph generated without [namespace: %s] [appender: %s]: %v", namespace, appender, err)
+	in.Warnings = append(in.Warnings, Warning{Namespace: namespace, Appender: appender, Message: err.Error()})
+}
+
 func NewAppenderNamespaceInfo(namespace string) *AppenderNamespaceInfo {
 	return &AppenderNamespaceInfo{Namespace: namespace, Vendor: NewAppenderVendorInfo()}
 }
@@ -98,12 +98,15 @@ type EdgeData struct {
 	Target string `json:"target"` // child node ID
 
 	// App Fields (not required by Cytoscape)
-	DestPrincipal   string          `json:"destPrincipal,omitempty"`   // principal used for the edge destination
-	IsMTLS          string          `json:"isMTLS,omitempty"`          // set to the percentage of traffic using a mutual TLS connection
-	ResponseTime    string          `json:"responseTime,omitempty"`    // in millis
-	SourcePrincipal string          `json:"sourcePrincipal,omitempty"` // principal used for the edge source
-	Throughput      string          `json:"throughput,omitempty"`      // in bytes/sec (request or response, depends on client request)
-	Traffic         ProtocolTraffic `json:"traffic,omitempty"`         // traffic rates for the edge protocol
+	AuthzDecision    string          `json:"authzDecision,omitempty"`    // expected authorization decision: allowed, custom, denied, indeterminate, unrestricted or mixed
+	AuthzPolicies    []string        `json:"authzPolicies,omitempty"`    // AuthorizationPolicies deciding the authzDecision
+	DestPrincipal    string          `json:"destPrincipal,omitempty"`    // principal used for the edge destination
+	HasAuthzMismatch bool            `json:"hasAuthzMismatch,omitempty"` // observed traffic contradicts the authzDecision
+	IsMTLS           string          `json:"isMTLS,omitempty"`           // set to the percentage of traffic using a mutual TLS connection
+	ResponseTime     string          `json:"responseTime,omitempty"`     // in millis
+	SourcePrincipal  string          `json:"sourcePrincipal,omitempty"`  // principal used for the edge source
+	Throughput       string          `json:"throughput,omitempty"`       // in bytes/sec (request or response, depends on client request)
+	Traffic          ProtocolTraffic `json:"traffic,omitempty"`          // traffic rates for the edge protocol
 }
 
 type NodeWrapper struct {
@@ -120,10 +123,11 @@ type Elements struct {
 }
 
 type Config struct {
-	Timestamp int64    `json:"timestamp"`
-	Duration  int64    `json:"duration"`
-	GraphType string   `json:"graphType"`
-	Elements  Elements `json:"elements"`
+	Timestamp int64           `json:"timestamp"`
+	Duration  int64           `json:"duration"`
+	GraphType string          `json:"graphType"`
+	Elements  Elements        `json:"elements"`
+	Warnings  []graph.Warning `json:"warnings,omitempty"` // parts missing from a partial graph
 }
 
 func nodeHash(id string) string {
@@ -233,6 +237,11 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			nd.IsDead = val.(bool)
 		}
 
+		// node may be a box summarizing nodes
+		if val, ok := n.Metadata[graph.IsBox]; ok {
+			nd.IsBox = val.(string)
+		}
+
 		// node may be idle
 		if val, ok := n.Metadata[graph.IsIdle]; ok {
 			nd.IsIdle = val.(bool)
@@ -334,6 +343,15 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			if e.Metadata[graph.SourcePrincipal] != nil {
 				ed.SourcePrincipal = e.Metadata[graph.SourcePrincipal].(string)
 			}
+			if val, ok := e.Metadata[graph.AuthzDecision]; ok {
+				ed.AuthzDecision = val.(string)
+			}
+			if val, ok := e.Metadata[graph.AuthzPolicies]; ok {
+				ed.AuthzPolicies = val.([]string)
+			}
+			if val, ok := e.Metadata[graph.HasAuthzMismatch]; ok {
+				ed.HasAuthzMismatch = val.(bool)
+			}
 			addEdgeTelemetry(e, &ed)
 
 			ew := EdgeWrapper{
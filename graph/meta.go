@@ -15,8 +15,11 @@ func NewMetadata() Metadata {
 const (
 	Aggregate             MetadataKey = "aggregate" // the prom attribute used for aggregation
 	AggregateValue        MetadataKey = "aggregateValue"
+	AuthzDecision         MetadataKey = "authzDecision" // allowed, custom, denied, indeterminate, unrestricted or mixed
+	AuthzPolicies         MetadataKey = "authzPolicies" // AuthorizationPolicies deciding the authzDecision
 	DestPrincipal         MetadataKey = "destPrincipal"
 	DestServices          MetadataKey = "destServices"
+	HasAuthzMismatch      MetadataKey = "hasAuthzMismatch" // observed traffic contradicts the authzDecision
 	HasCB                 MetadataKey = "hasCB"
 	HasFaultInjection     MetadataKey = "hasFaultInjection"
 	HasHealthConfig       MetadataKey = "hasHealthConfig"
@@ -26,6 +29,7 @@ const (
 	HasRequestRouting     MetadataKey = "hasRequestRouting"
 	HasRequestTimeout     MetadataKey = "hasRequestTimeout"
 	HasVS                 MetadataKey = "hasVS"
+	IsBox                 MetadataKey = "isBox" // set on the box nodes of a summary graph: namespace
 	IsDead                MetadataKey = "isDead"
 	IsEgressCluster       MetadataKey = "isEgressCluster" // PassthroughCluster or BlackHoleCluster
 	IsIdle                MetadataKey = "isIdle"
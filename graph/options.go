@@ -3,6 +3,7 @@ package graph
 // Options.go holds the option settings for a single graph request.
 
 import (
+	"context"
 	"fmt"
 	net_http "net/http"
 	"net/url"
@@ -80,8 +81,10 @@ type RequestedAppenders struct {
 type TelemetryOptions struct {
 	AccessibleNamespaces map[string]time.Time
 	Appenders            RequestedAppenders // requested appenders, nil if param not supplied
+	Context              context.Context    // context of the graph request, canceled when the client goes away
 	IncludeIdleEdges     bool               // include edges with request rates of 0
 	InjectServiceNodes   bool               // inject destination service nodes between source and destination nodes.
+	Partial              bool               // return the parts of the graph that could be generated, with warnings, instead of failing
 	Namespaces           NamespaceInfoMap
 	CommonOptions
 	NodeOptions
@@ -111,6 +114,7 @@ func NewOptions(r *net_http.Request) Options {
 	var duration model.Duration
 	var includeIdleEdges bool
 	var injectServiceNodes bool
+	var partial bool
 	var queryTime int64
 	appenders := RequestedAppenders{All: true}
 	boxBy := params.Get("boxBy")
@@ -121,6 +125,7 @@ func NewOptions(r *net_http.Request) Options {
 	includeIdleEdgesString := params.Get("includeIdleEdges")
 	injectServiceNodesString := params.Get("injectServiceNodes")
 	namespaces := params.Get("namespaces") // csl of namespaces
+	partialString := params.Get("partial")
 	queryTimeString := params.Get("queryTime")
 	telemetryVendor := params.Get("telemetryVendor")
 
@@ -192,6 +197,15 @@ func NewOptions(r *net_http.Request) Options {
 			BadRequest(fmt.Sprintf("Invalid injectServiceNodes [%s]", injectServiceNodesString))
 		}
 	}
+	if partialString == "" {
+		partial = config.Get().API.Graph.PartialResults
+	} else {
+		var partialErr error
+		partial, partialErr = strconv.ParseBool(partialString)
+		if partialErr != nil {
+			BadRequest(fmt.Sprintf("Invalid partial [%s]", partialString))
+		}
+	}
 	if queryTimeString == "" {
 		queryTime = time.Now().Unix()
 	} else {
@@ -248,6 +262,13 @@ func NewOptions(r *net_http.Request) Options {
 		}
 	}
 
+	if maxNamespaces := config.Get().API.Graph.MaxNamespaces; maxNamespaces > 0 && len(namespaceMap) > maxNamespaces {
+		BadRequest(fmt.Sprintf("Requested [%d] namespaces, exceeding the limit of [%d] namespaces", len(namespaceMap), maxNamespaces))
+	}
+	if maxDuration := config.Get().API.Graph.MaxDuration; maxDuration > 0 && time.Duration(duration) > time.Duration(maxDuration)*time.Second {
+		BadRequest(fmt.Sprintf("Invalid duration [%s], exceeding the limit of [%ds]", duration, maxDuration))
+	}
+
 	// Service graphs require service injection
 	if graphType == GraphTypeService {
 		injectServiceNodes = true
@@ -268,9 +289,11 @@ func NewOptions(r *net_http.Request) Options {
 		TelemetryOptions: TelemetryOptions{
 			AccessibleNamespaces: accessibleNamespaces,
 			Appenders:            appenders,
+			Context:              r.Context(),
 			IncludeIdleEdges:     includeIdleEdges,
 			InjectServiceNodes:   injectServiceNodes,
 			Namespaces:           namespaceMap,
+			Partial:              partial,
 			CommonOptions: CommonOptions{
 				Duration:  time.Duration(duration),
 				GraphType: graphType,
@@ -4,8 +4,10 @@ package telemetry
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/log"
 )
@@ -198,6 +200,93 @@ func ReduceToServiceGraph(trafficMap graph.TrafficMap) graph.TrafficMap {
 	return reducedTrafficMap
 }
 
+// SummarizeAboveSizeLimits tells if the raw traffic maps of a graph, once merged, exceed the configured number of nodes
+// or edges. In that case the graph is flagged to be reduced to the summary of its namespaces, with a warning: it is
+// called before the appenders, which are skipped for a summary.
+func SummarizeAboveSizeLimits(globalInfo *graph.AppenderGlobalInfo, trafficMaps ...graph.TrafficMap) bool {
+	maxNodes := config.Get().API.Graph.MaxNodes
+	maxEdges := config.Get().API.Graph.MaxEdges
+	if maxNodes <= 0 && maxEdges <= 0 {
+		return false
+	}
+	nodes := make(map[string]bool)
+	edges := make(map[string]bool)
+	for _, trafficMap := range trafficMaps {
+		for id, n := range trafficMap {
+			nodes[id] = true
+			for _, e := range n.Edges {
+				edges[fmt.Sprintf("%s %s %v", id, e.Dest.ID, e.Metadata[graph.ProtocolKey])] = true
+			}
+		}
+	}
+	if (maxNodes <= 0 || len(nodes) <= maxNodes) && (maxEdges <= 0 || len(edges) <= maxEdges) {
+		return false
+	}
+
+	limits := []string{}
+	if maxNodes > 0 {
+		limits = append(limits, fmt.Sprintf("[%d] nodes", maxNodes))
+	}
+	if maxEdges > 0 {
+		limits = append(limits, fmt.Sprintf("[%d] edges", maxEdges))
+	}
+	message := fmt.Sprintf("The graph has [%d] nodes and [%d] edges, exceeding the limit of %s: returning a summary of the namespaces", len(nodes), len(edges), strings.Join(limits, " and "))
+	log.Debug(message)
+	globalInfo.Warnings = append(globalInfo.Warnings, graph.Warning{Message: message})
+	globalInfo.Summary = true
+	return true
+}
+
+// ReduceToNamespaceGraph summarizes a graph too large to be rendered. Each namespace becomes a box node holding the
+// traffic of the namespace nodes, and the edges carry the traffic between namespaces. The traffic inside a namespace
+// is only reflected by the box node.
+func ReduceToNamespaceGraph(trafficMap graph.TrafficMap) graph.TrafficMap {
+	reducedTrafficMap := graph.NewTrafficMap()
+
+	namespaceNode := func(n *graph.Node) *graph.Node {
+		id := fmt.Sprintf("box_%s_%s", n.Cluster, n.Namespace)
+		box, found := reducedTrafficMap[id]
+		if !found {
+			newBox := graph.NewNodeExplicit(id, n.Cluster, n.Namespace, "", "", "", "", graph.NodeTypeBox, "")
+			newBox.Metadata[graph.IsBox] = graph.BoxByNamespace
+			box = &newBox
+			reducedTrafficMap[id] = box
+		}
+		return box
+	}
+
+	for _, n := range trafficMap {
+		source := namespaceNode(n)
+		graph.AggregateNodeTraffic(n, source)
+		for _, key := range []graph.MetadataKey{graph.IsInaccessible, graph.IsOutside} {
+			if val, ok := n.Metadata[key]; ok && val.(bool) {
+				source.Metadata[key] = true
+			}
+		}
+
+		for _, e := range n.Edges {
+			dest := namespaceNode(e.Dest)
+			if dest == source {
+				continue
+			}
+			var edge *graph.Edge
+			for _, sourceEdge := range source.Edges {
+				if sourceEdge.Dest == dest && sourceEdge.Metadata[graph.ProtocolKey] == e.Metadata[graph.ProtocolKey] {
+					edge = sourceEdge
+					break
+				}
+			}
+			if nil == edge {
+				edge = source.AddEdge(dest)
+				edge.Metadata[graph.ProtocolKey] = e.Metadata[graph.ProtocolKey]
+			}
+			graph.AggregateEdgeTraffic(e, edge)
+		}
+	}
+
+	return reducedTrafficMap
+}
+
 func addServiceGraphTraffic(toEdge, fromEdge *graph.Edge) {
 	graph.AddOutgoingEdgeToMetadata(toEdge.Source.Metadata, fromEdge.Metadata)
 	graph.AggregateEdgeTraffic(fromEdge, toEdge)
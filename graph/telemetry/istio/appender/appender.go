@@ -23,6 +23,8 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 			switch appenderName {
 			case AggregateNodeAppenderName:
 				requestedAppenders[AggregateNodeAppenderName] = true
+			case AuthorizationPolicyAppenderName:
+				requestedAppenders[AuthorizationPolicyAppenderName] = true
 			case DeadNodeAppenderName:
 				requestedAppenders[DeadNodeAppenderName] = true
 			case HealthConfigAppenderName:
@@ -104,6 +106,15 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 		}
 		appenders = append(appenders, a)
 	}
+	// Not part of the default appenders, it is expensive
+	if _, ok := requestedAppenders[AuthorizationPolicyAppenderName]; ok {
+		a := AuthorizationPolicyAppender{
+			AccessibleNamespaces: o.AccessibleNamespaces,
+			Namespaces:           o.Namespaces,
+			QueryTime:            o.QueryTime,
+		}
+		appenders = append(appenders, a)
+	}
 	if _, ok := requestedAppenders[ThroughputAppenderName]; ok || o.Appenders.All {
 		throughputType := o.Params.Get("throughputType")
 		if throughputType != "" {
@@ -0,0 +1,296 @@
+package appender
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const AuthorizationPolicyAppenderName = "authorizationPolicy"
+
+// Authorization decisions of an edge
+const (
+	authzAllowed       = "allowed"
+	authzCustom        = "custom"
+	authzDenied        = "denied"
+	authzIndeterminate = "indeterminate"
+	authzMixed         = "mixed"
+	authzUnrestricted  = "unrestricted"
+)
+
+// AuthorizationPolicyAppender is responsible for evaluating, for each edge, the AuthorizationPolicies and
+// PeerAuthentications of the destination workloads against the identities of the source workloads. The source
+// identities are the principals reported by the destination telemetry, so only edges with observed traffic are
+// evaluated. The edge is marked as:
+// - allowed: an ALLOW policy matches the source
+// - custom: a CUSTOM policy matches the source, the external authorizer decides
+// - denied: a DENY policy matches the source, or ALLOW policies exist and none matches it
+// - indeterminate: a policy that could decide has conditions that can't be simulated
+// - unrestricted: no policy decides, the traffic is allowed by default
+// - mixed: the workloads behind the edge get different decisions
+// Edges for which observed traffic contradicts the decision are flagged: denied edges with successful requests, or
+// allowed edges with requests rejected by RBAC (403 response code or RBAC response flag).
+// Request attributes (paths, methods, headers) are unknown at the edge level: rules restricting them don't match.
+// The appender is not part of the default appenders, it must be explicitly requested.
+// Name: authorizationPolicy
+type AuthorizationPolicyAppender struct {
+	AccessibleNamespaces map[string]time.Time
+	Namespaces           map[string]graph.NamespaceInfo
+	QueryTime            int64 // unix time in seconds
+}
+
+// authzObservation is the traffic observed from a source to a destination workload
+type authzObservation struct {
+	sourceNamespace string
+	sourceWorkload  string
+	sourceApp       string
+	sourceVersion   string
+	principal       string
+	allowedRate     float64
+	deniedRate      float64
+}
+
+// authzEvaluator returns the simulated decision of a connection from a principal to a destination workload
+type authzEvaluator func(principal, destWorkload string) (models.AuthorizationSimulation, bool)
+
+// Name implements Appender
+func (a AuthorizationPolicyAppender) Name() string {
+	return AuthorizationPolicyAppenderName
+}
+
+// AppendGraph implements Appender
+func (a AuthorizationPolicyAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+	namespace := namespaceInfo.Namespace
+	if _, ok := a.AccessibleNamespaces[namespace]; !ok {
+		return
+	}
+
+	if globalInfo.PromClient == nil {
+		var err error
+		globalInfo.PromClient, err = prometheus.NewClient()
+		graph.CheckError(err)
+	}
+
+	authzConfig, err := globalInfo.Business.Authorization.GetAuthorizationConfig(namespace)
+	graph.CheckError(err)
+
+	evaluations := make(map[string]models.AuthorizationSimulation)
+	evaluate := func(principal, destWorkload string) (models.AuthorizationSimulation, bool) {
+		key := fmt.Sprintf("%s %s", principal, destWorkload)
+		if simulation, ok := evaluations[key]; ok {
+			return simulation, true
+		}
+		workload, found := getWorkload(namespace, destWorkload, globalInfo)
+		if !found {
+			return models.AuthorizationSimulation{}, false
+		}
+		simulation := authzConfig.SimulatePeer(principal, workload.Labels)
+		evaluations[key] = simulation
+		return simulation, true
+	}
+	destWorkloads := func(app, version string) []string {
+		names := []string{}
+		for _, w := range getAppWorkloads(namespace, app, version, globalInfo) {
+			names = append(names, w.Name)
+		}
+		return names
+	}
+
+	a.appendGraph(trafficMap, namespace, globalInfo.PromClient, evaluate, destWorkloads)
+}
+
+func (a AuthorizationPolicyAppender) appendGraph(trafficMap graph.TrafficMap, namespace string, client *prometheus.Client, evaluate authzEvaluator, appWorkloads func(app, version string) []string) {
+	log.Tracef("Resolving authorization policies for namespace = %v", namespace)
+	duration := a.Namespaces[namespace].Duration
+
+	// use dest telemetry because it reports the source principal and the RBAC denials
+	groupBy := "source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,source_principal,destination_workload,response_code,response_flags"
+	httpQuery := fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_workload_namespace="%v"}[%vs])) by (%s) > 0`,
+		"istio_requests_total",
+		namespace,
+		int(duration.Seconds()), // range duration for the query
+		groupBy)
+	tcpQuery := fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_workload_namespace="%v"}[%vs])) by (%s) > 0`,
+		"istio_tcp_connections_opened_total",
+		namespace,
+		int(duration.Seconds()), // range duration for the query
+		groupBy)
+	query := fmt.Sprintf(`(%s) OR (%s)`, httpQuery, tcpQuery)
+	vector := promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+
+	observations := populateAuthzObservations(&vector)
+	applyAuthorizationPolicies(trafficMap, namespace, observations, evaluate, appWorkloads)
+}
+
+// populateAuthzObservations returns the observed traffic, by destination workload
+func populateAuthzObservations(vector *model.Vector) map[string][]*authzObservation {
+	observations := make(map[string][]*authzObservation)
+	for _, s := range *vector {
+		m := s.Metric
+		lSourceWlNs, sourceWlNsOk := m["source_workload_namespace"]
+		lSourceWl, sourceWlOk := m["source_workload"]
+		lSourceApp, sourceAppOk := m["source_canonical_service"]
+		lSourceVer, sourceVerOk := m["source_canonical_revision"]
+		lSourcePrincipal, sourcePrincipalOk := m["source_principal"]
+		lDestWl, destWlOk := m["destination_workload"]
+
+		if !sourceWlNsOk || !sourceWlOk || !sourceAppOk || !sourceVerOk || !sourcePrincipalOk || !destWlOk {
+			log.Warningf("populateAuthzObservations: Skipping %s, missing expected labels", m.String())
+			continue
+		}
+
+		destWl := string(lDestWl)
+		principal := string(lSourcePrincipal)
+		if principal == graph.Unknown {
+			principal = ""
+		}
+		var observation *authzObservation
+		for _, o := range observations[destWl] {
+			if o.sourceNamespace == string(lSourceWlNs) && o.sourceWorkload == string(lSourceWl) && o.principal == principal {
+				observation = o
+				break
+			}
+		}
+		if observation == nil {
+			observation = &authzObservation{
+				sourceNamespace: string(lSourceWlNs),
+				sourceWorkload:  string(lSourceWl),
+				sourceApp:       string(lSourceApp),
+				sourceVersion:   string(lSourceVer),
+				principal:       principal,
+			}
+			observations[destWl] = append(observations[destWl], observation)
+		}
+
+		// response_code is not reported for TCP
+		if string(m["response_code"]) == "403" || strings.Contains(string(m["response_flags"]), "RBAC") {
+			observation.deniedRate += float64(s.Value)
+		} else {
+			observation.allowedRate += float64(s.Value)
+		}
+	}
+	return observations
+}
+
+func applyAuthorizationPolicies(trafficMap graph.TrafficMap, namespace string, observations map[string][]*authzObservation, evaluate authzEvaluator, appWorkloads func(app, version string) []string) {
+	// index the incoming edges, to resolve the sources of the service nodes
+	incoming := make(map[string][]*graph.Node)
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			incoming[e.Dest.ID] = append(incoming[e.Dest.ID], n)
+		}
+	}
+
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			decisions := make(map[string]bool)
+			policies := make(map[string]bool)
+			mismatch := false
+			for _, destWl := range authzDestWorkloads(e.Dest, namespace, appWorkloads) {
+				for _, o := range observations[destWl] {
+					if !authzSourceMatches(e.Source, o, incoming) {
+						continue
+					}
+					simulation, ok := evaluate(o.principal, destWl)
+					if !ok {
+						continue
+					}
+					decision := authzUnrestricted
+					for _, p := range simulation.Policies {
+						if p.Deciding {
+							decision = authzAllowed
+							policies[fmt.Sprintf("%s/%s", p.Namespace, p.Name)] = true
+						}
+					}
+					switch simulation.Decision {
+					case models.AuthorizationDeny:
+						decision = authzDenied
+						mismatch = mismatch || o.allowedRate > 0
+					case models.AuthorizationAllow:
+						mismatch = mismatch || o.deniedRate > 0
+					case models.AuthorizationCustom:
+						// the external authorizer decides, observed denials don't contradict the policies
+						decision = authzCustom
+					case models.AuthorizationIndeterminate:
+						decision = authzIndeterminate
+					}
+					decisions[decision] = true
+				}
+			}
+			if len(decisions) == 0 {
+				continue
+			}
+			if len(decisions) == 1 {
+				for decision := range decisions {
+					e.Metadata[graph.AuthzDecision] = decision
+				}
+			} else {
+				e.Metadata[graph.AuthzDecision] = authzMixed
+			}
+			if len(policies) > 0 {
+				names := make([]string, 0, len(policies))
+				for name := range policies {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				e.Metadata[graph.AuthzPolicies] = names
+			}
+			if mismatch {
+				e.Metadata[graph.HasAuthzMismatch] = true
+			}
+		}
+	}
+}
+
+// authzDestWorkloads returns the workloads of the namespace behind a destination node
+func authzDestWorkloads(n *graph.Node, namespace string, appWorkloads func(app, version string) []string) []string {
+	switch {
+	case n.Namespace != namespace:
+		return []string{}
+	case n.NodeType == graph.NodeTypeService:
+		workloads := []string{}
+		for _, e := range n.Edges {
+			if e.Dest.NodeType != graph.NodeTypeService {
+				workloads = append(workloads, authzDestWorkloads(e.Dest, namespace, appWorkloads)...)
+			}
+		}
+		return workloads
+	case graph.IsOK(n.Workload):
+		return []string{n.Workload}
+	case n.NodeType == graph.NodeTypeApp:
+		return appWorkloads(n.App, n.Version)
+	}
+	return []string{}
+}
+
+// authzSourceMatches tells whether observed traffic comes from a source node. The sources of a service node are
+// the nodes sending traffic to it.
+func authzSourceMatches(n *graph.Node, o *authzObservation, incoming map[string][]*graph.Node) bool {
+	switch {
+	case n.NodeType == graph.NodeTypeService:
+		for _, source := range incoming[n.ID] {
+			if source.NodeType != graph.NodeTypeService && authzSourceMatches(source, o, incoming) {
+				return true
+			}
+		}
+		return false
+	case n.Namespace != o.sourceNamespace:
+		return false
+	case n.Workload != "":
+		return n.Workload == o.sourceWorkload
+	case n.NodeType == graph.NodeTypeApp:
+		return n.App == o.sourceApp && (!graph.IsOKVersion(n.Version) || n.Version == o.sourceVersion)
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package appender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/models"
+)
+
+func TestAuthorizationPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	q0 := `round((sum(rate(istio_requests_total{reporter="destination",destination_workload_namespace="bookinfo"}[60s])) by (source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,source_principal,destination_workload,response_code,response_flags) > 0) OR (sum(rate(istio_tcp_connections_opened_total{reporter="destination",destination_workload_namespace="bookinfo"}[60s])) by (source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,source_principal,destination_workload,response_code,response_flags) > 0),0.001)`
+	ingressMetric := model.Metric{
+		"source_workload_namespace": "istio-system",
+		"source_workload":           "ingressgateway-unknown",
+		"source_canonical_service":  "ingressgateway",
+		"source_canonical_revision": model.LabelValue(graph.Unknown),
+		"source_principal":          "spiffe://cluster.local/ns/istio-system/sa/ingressgateway",
+		"destination_workload":      "productpage-v1",
+		"response_code":             "200",
+		"response_flags":            "-"}
+	productpageMetric := model.Metric{
+		"source_workload_namespace": "bookinfo",
+		"source_workload":           "productpage-v1",
+		"source_canonical_service":  "productpage",
+		"source_canonical_revision": "v1",
+		"source_principal":          "spiffe://cluster.local/ns/bookinfo/sa/productpage",
+		"destination_workload":      "reviews-v1",
+		"response_code":             "403",
+		"response_flags":            "-"}
+	v0 := model.Vector{
+		&model.Sample{Metric: ingressMetric, Value: 10.0},
+		&model.Sample{Metric: productpageMetric, Value: 5.0}}
+
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mockQuery(api, q0, &v0)
+
+	trafficMap := authorizationPolicyTestTraffic()
+	evaluate := func(principal, destWorkload string) (models.AuthorizationSimulation, bool) {
+		if destWorkload == "productpage-v1" {
+			assert.Equal("spiffe://cluster.local/ns/istio-system/sa/ingressgateway", principal)
+			return models.AuthorizationSimulation{
+				Decision: models.AuthorizationAllow,
+				Policies: []models.AuthorizationPolicyEvaluation{{Name: "allow-ingress", Namespace: "bookinfo", Action: "ALLOW", Matched: true, Deciding: true}},
+			}, true
+		}
+		return models.AuthorizationSimulation{Decision: models.AuthorizationAllow, Policies: []models.AuthorizationPolicyEvaluation{}}, true
+	}
+
+	duration, _ := time.ParseDuration("60s")
+	appender := AuthorizationPolicyAppender{
+		Namespaces: graph.NamespaceInfoMap{
+			"bookinfo": graph.NamespaceInfo{
+				Name:     "bookinfo",
+				Duration: duration,
+			},
+		},
+		QueryTime: time.Now().Unix(),
+	}
+	appender.appendGraph(trafficMap, "bookinfo", client, evaluate, func(app, version string) []string { return []string{} })
+
+	ingressID, _ := graph.Id(graph.Unknown, "istio-system", "", "istio-system", "ingressgateway-unknown", "ingressgateway", graph.Unknown, graph.GraphTypeVersionedApp)
+	ingress := trafficMap[ingressID]
+	assert.Equal(authzAllowed, ingress.Edges[0].Metadata[graph.AuthzDecision])
+	assert.Equal([]string{"bookinfo/allow-ingress"}, ingress.Edges[0].Metadata[graph.AuthzPolicies])
+	assert.Nil(ingress.Edges[0].Metadata[graph.HasAuthzMismatch])
+
+	// service node edges are evaluated against the sources of the service
+	productpagesvc := ingress.Edges[0].Dest
+	assert.Equal(authzAllowed, productpagesvc.Edges[0].Metadata[graph.AuthzDecision])
+
+	productpageID, _ := graph.Id(graph.Unknown, "bookinfo", "productpage", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	productpage := trafficMap[productpageID]
+	assert.Equal(authzUnrestricted, productpage.Edges[0].Metadata[graph.AuthzDecision])
+	assert.Nil(productpage.Edges[0].Metadata[graph.AuthzPolicies])
+	assert.Equal(true, productpage.Edges[0].Metadata[graph.HasAuthzMismatch])
+
+	// CUSTOM policies delegate the decision, rejected requests don't contradict them
+	trafficMap = authorizationPolicyTestTraffic()
+	customEvaluate := func(principal, destWorkload string) (models.AuthorizationSimulation, bool) {
+		return models.AuthorizationSimulation{
+			Decision: models.AuthorizationCustom,
+			Policies: []models.AuthorizationPolicyEvaluation{{Name: "ext-authz", Namespace: "bookinfo", Action: "CUSTOM", Matched: true, Deciding: true}},
+		}, true
+	}
+	appender.appendGraph(trafficMap, "bookinfo", client, customEvaluate, func(app, version string) []string { return []string{} })
+	productpage = trafficMap[productpageID]
+	assert.Equal(authzCustom, productpage.Edges[0].Metadata[graph.AuthzDecision])
+	assert.Equal([]string{"bookinfo/ext-authz"}, productpage.Edges[0].Metadata[graph.AuthzPolicies])
+	assert.Nil(productpage.Edges[0].Metadata[graph.HasAuthzMismatch])
+}
+
+func authorizationPolicyTestTraffic() graph.TrafficMap {
+	ingress := graph.NewNode(graph.Unknown, "istio-system", "", "istio-system", "ingressgateway-unknown", "ingressgateway", graph.Unknown, graph.GraphTypeVersionedApp)
+	productpagesvc := graph.NewNode(graph.Unknown, "bookinfo", "productpage", "bookinfo", "", "", "", graph.GraphTypeVersionedApp)
+	productpage := graph.NewNode(graph.Unknown, "bookinfo", "productpage", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	reviews := graph.NewNode(graph.Unknown, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	trafficMap := graph.NewTrafficMap()
+	trafficMap[ingress.ID] = &ingress
+	trafficMap[productpagesvc.ID] = &productpagesvc
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+
+	ingress.AddEdge(&productpagesvc)
+	productpagesvc.AddEdge(&productpage)
+	productpage.AddEdge(&reviews)
+
+	return trafficMap
+}
@@ -17,6 +17,9 @@ import (
 // package-private util functions (used by multiple files)
 
 func promQuery(query string, queryTime time.Time, ctx context.Context, api prom_v1.API, a graph.Appender) model.Vector {
+	ctx, cancel := graph.QueryContext(ctx)
+	defer cancel()
+
 	// wrap with a round() to be in line with metrics api
 	query = fmt.Sprintf("round(%s,0.001)", query)
 	log.Tracef("Appender query:\n%s&time=%v (now=%v, %v)\n", query, queryTime.Format(graph.TF), time.Now().Format(graph.TF), queryTime.Unix())
@@ -37,4 +37,10 @@ func mockQuery(api *prometheustest.PromAPIMock, query string, ret *model.Vector)
 		query,
 		mock.AnythingOfType("time.Time"),
 	).Return(*ret, nil)
+	api.On(
+		"Query",
+		mock.AnythingOfType("*context.timerCtx"),
+		query,
+		mock.AnythingOfType("time.Time"),
+	).Return(*ret, nil)
 }
@@ -19,12 +19,15 @@ import (
 	"context"
 	"crypto/md5"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/telemetry"
 	"github.com/kiali/kiali/graph/telemetry/istio/appender"
@@ -46,16 +49,30 @@ func BuildNamespacesTrafficMap(o graph.TelemetryOptions, client *prometheus.Clie
 	appenders := appender.ParseAppenders(o)
 	trafficMap := graph.NewTrafficMap()
 
-	for _, namespace := range o.Namespaces {
-		log.Tracef("Build traffic map for namespace [%v]", namespace)
-		namespaceTrafficMap := buildNamespaceTrafficMap(namespace.Name, o, client)
-		namespaceInfo := graph.NewAppenderNamespaceInfo(namespace.Name)
-		for _, a := range appenders {
-			appenderTimer := internalmetrics.GetGraphAppenderTimePrometheusTimer(a.Name())
-			a.AppendGraph(namespaceTrafficMap, globalInfo, namespaceInfo)
-			appenderTimer.ObserveDuration()
+	results := buildNamespaceTrafficMaps(o, client)
+	rawTrafficMaps := make([]graph.TrafficMap, 0, len(results))
+	for _, result := range results {
+		if result.err == nil {
+			rawTrafficMaps = append(rawTrafficMaps, result.trafficMap)
 		}
-		telemetry.MergeTrafficMaps(trafficMap, namespace.Name, namespaceTrafficMap)
+	}
+	summary := telemetry.SummarizeAboveSizeLimits(globalInfo, rawTrafficMaps...)
+
+	for _, result := range results {
+		if result.err != nil {
+			if !o.Partial {
+				graph.CheckUnavailable(result.err)
+			}
+			globalInfo.AddWarning(result.namespace, "", result.err)
+			continue
+		}
+		if !summary {
+			namespaceInfo := graph.NewAppenderNamespaceInfo(result.namespace)
+			for _, a := range appenders {
+				appendGraph(a, result.trafficMap, globalInfo, namespaceInfo, o)
+			}
+		}
+		telemetry.MergeTrafficMaps(trafficMap, result.namespace, result.trafficMap)
 	}
 
 	// The appenders can add/remove/alter nodes. After the manipulations are complete
@@ -65,6 +82,9 @@ func BuildNamespacesTrafficMap(o graph.TelemetryOptions, client *prometheus.Clie
 	telemetry.MarkOutsideOrInaccessible(trafficMap, o)
 	telemetry.MarkTrafficGenerators(trafficMap)
 
+	if summary {
+		return telemetry.ReduceToNamespaceGraph(trafficMap)
+	}
 	if graph.GraphTypeService == o.GraphType {
 		trafficMap = telemetry.ReduceToServiceGraph(trafficMap)
 	}
@@ -72,6 +92,81 @@ func BuildNamespacesTrafficMap(o graph.TelemetryOptions, client *prometheus.Clie
 	return trafficMap
 }
 
+type namespaceTrafficMap struct {
+	namespace  string
+	trafficMap graph.TrafficMap
+	err        error       // failed Prometheus query
+	panic      interface{} // any other failure, to raise in the request goroutine
+}
+
+// buildNamespaceTrafficMaps queries the traffic of the namespaces concurrently, up to the configured number of
+// namespaces at a time. The results are sorted by namespace.
+func buildNamespaceTrafficMaps(o graph.TelemetryOptions, client *prometheus.Client) []namespaceTrafficMap {
+	namespaces := make([]string, 0, len(o.Namespaces))
+	for namespace := range o.Namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	maxConcurrent := config.Get().API.Graph.MaxConcurrentNamespaces
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	semaphore := make(chan struct{}, maxConcurrent)
+	results := make([]namespaceTrafficMap, len(namespaces))
+	var wg sync.WaitGroup
+	for i, namespace := range namespaces {
+		wg.Add(1)
+		go func(result *namespaceTrafficMap) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			defer func() {
+				if r := recover(); r != nil {
+					result.panic = r
+				}
+			}()
+			log.Tracef("Build traffic map for namespace [%v]", result.namespace)
+			result.err = graph.CatchUnavailable(func() {
+				result.trafficMap = buildNamespaceTrafficMap(result.namespace, o, client)
+			})
+		}(&results[i])
+		results[i].namespace = namespace
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		if result.panic != nil {
+			panic(result.panic)
+		}
+	}
+	return results
+}
+
+// appendGraph runs an appender. In partial-result mode, the appender runs on a copy of the traffic map: an appender
+// whose Prometheus queries fail is reported with a warning, and the graph drops what the appender changed before the
+// failure.
+func appendGraph(a graph.Appender, trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo, o graph.TelemetryOptions) {
+	appenderTimer := internalmetrics.GetGraphAppenderTimePrometheusTimer(a.Name())
+	defer appenderTimer.ObserveDuration()
+
+	if !o.Partial {
+		a.AppendGraph(trafficMap, globalInfo, namespaceInfo)
+		return
+	}
+	appended := trafficMap.Copy()
+	if err := graph.CatchUnavailable(func() { a.AppendGraph(appended, globalInfo, namespaceInfo) }); err != nil {
+		globalInfo.AddWarning(namespaceInfo.Namespace, a.Name(), err)
+		return
+	}
+	for id := range trafficMap {
+		delete(trafficMap, id)
+	}
+	for id, n := range appended {
+		trafficMap[id] = n
+	}
+}
+
 // buildNamespaceTrafficMap returns a map of all namespace nodes (key=id).  All
 // nodes either directly send and/or receive requests from a node in the namespace.
 func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client *prometheus.Client) graph.TrafficMap {
@@ -95,7 +190,7 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 		int(duration.Seconds()), // range duration for the query
 		groupBy,
 		idleCondition)
-	incomingVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+	incomingVector := promQuery(query, time.Unix(o.QueryTime, 0), client.GetContext(), client.API())
 	populateTrafficMap(trafficMap, &incomingVector, false, o)
 
 	// 1) Incoming: query destination telemetry to capture namespace services' incoming traffic
@@ -105,7 +200,7 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 		int(duration.Seconds()), // range duration for the query
 		groupBy,
 		idleCondition)
-	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.GetContext(), client.API())
 	populateTrafficMap(trafficMap, &incomingVector, false, o)
 
 	// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
@@ -115,7 +210,7 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 		int(duration.Seconds()), // range duration for the query
 		groupBy,
 		idleCondition)
-	outgoingVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+	outgoingVector := promQuery(query, time.Unix(o.QueryTime, 0), client.GetContext(), client.API())
 	populateTrafficMap(trafficMap, &outgoingVector, false, o)
 
 	// TCP traffic
@@ -130,7 +225,7 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 		int(duration.Seconds()), // range duration for the query
 		groupBy,
 		idleCondition)
-	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.GetContext(), client.API())
 	populateTrafficMap(trafficMap, &incomingVector, true, o)
 
 	// 1) Incoming: query destination telemetry to capture namespace services' incoming traffic	query = fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_service_namespace="%s"} [%vs])) by (%s) %s`,
@@ -140,7 +235,7 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 		int(duration.Seconds()), // range duration for the query
 		groupBy,
 		idleCondition)
-	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.GetContext(), client.API())
 	populateTrafficMap(trafficMap, &incomingVector, true, o)
 
 	// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
@@ -150,7 +245,7 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 		int(duration.Seconds()), // range duration for the query
 		groupBy,
 		idleCondition)
-	outgoingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+	outgoingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.GetContext(), client.API())
 	populateTrafficMap(trafficMap, &outgoingVector, true, o)
 
 	return trafficMap
@@ -329,13 +424,13 @@ func BuildNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, gl
 
 	appenders := appender.ParseAppenders(o)
 	trafficMap := buildNodeTrafficMap(o.Cluster, o.NodeOptions.Namespace, n, o, client)
+	summary := telemetry.SummarizeAboveSizeLimits(globalInfo, trafficMap)
 
-	namespaceInfo := graph.NewAppenderNamespaceInfo(o.NodeOptions.Namespace)
-
-	for _, a := range appenders {
-		appenderTimer := internalmetrics.GetGraphAppenderTimePrometheusTimer(a.Name())
-		a.AppendGraph(trafficMap, globalInfo, namespaceInfo)
-		appenderTimer.ObserveDuration()
+	if !summary {
+		namespaceInfo := graph.NewAppenderNamespaceInfo(o.NodeOptions.Namespace)
+		for _, a := range appenders {
+			appendGraph(a, trafficMap, globalInfo, namespaceInfo, o)
+		}
 	}
 
 	// The appenders can add/remove/alter nodes. After the manipulations are complete
@@ -345,6 +440,10 @@ func BuildNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, gl
 	telemetry.MarkOutsideOrInaccessible(trafficMap, o)
 	telemetry.MarkTrafficGenerators(trafficMap)
 
+	if summary {
+		return telemetry.ReduceToNamespaceGraph(trafficMap)
+	}
+
 	// Note that this is where we would call reduceToServiceGraph for graphTypeService but
 	// the current decision is to not reduce the node graph to provide more detail.  This may be
 	// confusing to users, we'll see...
@@ -421,7 +520,7 @@ func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.Teleme
 			int(duration.Seconds()), // range duration for the query
 			groupBy,
 			idleCondition)
-		vector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+		vector := promQuery(query, time.Unix(o.QueryTime, 0), client.GetContext(), client.API())
 		populateTrafficMap(trafficMap, &vector, false, o)
 
 		// 1.b) query dest telemetry for requests to the service, serviced by service workloads
@@ -437,7 +536,7 @@ func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.Teleme
 	default:
 		graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
 	}
-	inVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+	inVector := promQuery(query, time.Unix(o.QueryTime, 0), client.GetContext(), client.API())
 	populateTrafficMap(trafficMap, &inVector, false, o)
 
 	// 2) query for outbound traffic
@@ -477,7 +576,7 @@ func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.Teleme
 	default:
 		graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
 	}
-	outVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+	outVector := promQuery(query, time.Unix(o.QueryTime, 0), client.GetContext(), client.API())
 	populateTrafficMap(trafficMap, &outVector, false, o)
 
 	// TCP traffic
@@ -529,7 +628,7 @@ func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.Teleme
 	default:
 		graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
 	}
-	tcpInVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+	tcpInVector := promQuery(query, time.Unix(o.QueryTime, 0), client.GetContext(), client.API())
 	populateTrafficMap(trafficMap, &tcpInVector, true, o)
 
 	// 2) query for outbound traffic
@@ -569,7 +668,7 @@ func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.Teleme
 	default:
 		graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
 	}
-	tcpOutVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+	tcpOutVector := promQuery(query, time.Unix(o.QueryTime, 0), client.GetContext(), client.API())
 	populateTrafficMap(trafficMap, &tcpOutVector, true, o)
 
 	return trafficMap
@@ -585,13 +684,13 @@ func handleAggregateNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.
 	}
 	appenders := appender.ParseAppenders(o)
 	trafficMap := buildAggregateNodeTrafficMap(o.NodeOptions.Namespace, n, o, client)
+	summary := telemetry.SummarizeAboveSizeLimits(globalInfo, trafficMap)
 
-	namespaceInfo := graph.NewAppenderNamespaceInfo(o.NodeOptions.Namespace)
-
-	for _, a := range appenders {
-		appenderTimer := internalmetrics.GetGraphAppenderTimePrometheusTimer(a.Name())
-		a.AppendGraph(trafficMap, globalInfo, namespaceInfo)
-		appenderTimer.ObserveDuration()
+	if !summary {
+		namespaceInfo := graph.NewAppenderNamespaceInfo(o.NodeOptions.Namespace)
+		for _, a := range appenders {
+			appendGraph(a, trafficMap, globalInfo, namespaceInfo, o)
+		}
 	}
 
 	// The appenders can add/remove/alter nodes. After the manipulations are complete
@@ -601,6 +700,10 @@ func handleAggregateNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.
 	telemetry.MarkOutsideOrInaccessible(trafficMap, o)
 	telemetry.MarkTrafficGenerators(trafficMap)
 
+	if summary {
+		return telemetry.ReduceToNamespaceGraph(trafficMap)
+	}
+
 	return trafficMap
 }
 
@@ -638,18 +741,18 @@ func buildAggregateNodeTrafficMap(namespace string, n graph.Node, o graph.Teleme
 	query := fmt.Sprintf(`(%s) OR (%s)`, httpQuery, tcpQuery)
 	*/
 	query := httpQuery
-	vector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+	vector := promQuery(query, time.Unix(o.QueryTime, 0), client.GetContext(), client.API())
 	populateTrafficMap(trafficMap, &vector, false, o)
 
 	return trafficMap
 }
 
-func promQuery(query string, queryTime time.Time, api prom_v1.API) model.Vector {
+func promQuery(query string, queryTime time.Time, ctx context.Context, api prom_v1.API) model.Vector {
 	if query == "" {
 		return model.Vector{}
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := graph.QueryContext(ctx)
 	defer cancel()
 
 	// wrap with a round() to be in line with metrics api
@@ -167,6 +167,42 @@ func NewTrafficMap() TrafficMap {
 	return make(map[string]*Node)
 }
 
+// Copy returns a copy of the traffic map, with copies of its nodes, edges and metadata maps. The metadata values are
+// shared with the original map.
+func (tm TrafficMap) Copy() TrafficMap {
+	nodes := make(map[*Node]*Node, len(tm))
+	var copyNode func(n *Node) *Node
+	copyNode = func(n *Node) *Node {
+		if copied, ok := nodes[n]; ok {
+			return copied
+		}
+		copied := *n
+		copied.Metadata = n.Metadata.copy()
+		nodes[n] = &copied
+		copied.Edges = make([]*Edge, len(n.Edges))
+		for i, e := range n.Edges {
+			copied.Edges[i] = &Edge{Source: copyNode(e.Source), Dest: copyNode(e.Dest), Metadata: e.Metadata.copy()}
+		}
+		return &copied
+	}
+	copied := NewTrafficMap()
+	for id, n := range tm {
+		copied[id] = copyNode(n)
+	}
+	return copied
+}
+
+func (m Metadata) copy() Metadata {
+	if m == nil {
+		return nil
+	}
+	copied := make(Metadata, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
+
 // Id returns the unique node ID
 func Id(cluster, serviceNamespace, service, workloadNamespace, workload, app, version, graphType string) (id, nodeType string) {
 	// prefer the workload namespace
@@ -1,7 +1,12 @@
 package graph
 
 import (
+	"context"
+	"errors"
 	nethttp "net/http"
+	"time"
+
+	"github.com/kiali/kiali/config"
 )
 
 type Response struct {
@@ -46,6 +51,39 @@ func CheckUnavailable(err error) {
 	}
 }
 
+// CatchUnavailable runs f and returns as an error a StatusServiceUnavailable (503) panic, i.e. a failed or timed
+// out Prometheus query. Other panics go through.
+func CatchUnavailable(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if response, ok := r.(Response); ok && response.Code == nethttp.StatusServiceUnavailable {
+				err = errors.New(response.Message)
+				return
+			}
+			panic(r)
+		}
+	}()
+	f()
+	return nil
+}
+
+// BudgetContext returns the context bounding the Prometheus queries of a graph request by the configured budget.
+// It is derived from the context of the request, so that the queries stop when the client goes away.
+func BudgetContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if budget := config.Get().API.Graph.QueryBudget; budget > 0 {
+		return context.WithTimeout(parent, time.Duration(budget)*time.Second)
+	}
+	return context.WithCancel(parent)
+}
+
+// QueryContext returns the context of a Prometheus query of a graph, bounded by the configured query timeout
+func QueryContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if timeout := config.Get().API.Graph.QueryTimeout; timeout > 0 {
+		return context.WithTimeout(parent, time.Duration(timeout)*time.Second)
+	}
+	return context.WithCancel(parent)
+}
+
 // IsOK just validates that a telemetry label value is not empty or unknown
 func IsOK(telemetryVal string) bool {
 	return telemetryVal != "" && telemetryVal != Unknown
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+const defaultLeastPrivilegeRateInterval = "24h"
+
+// GenerateAuthorizationPolicies is the API handler to generate the least-privilege AuthorizationPolicies of a
+// namespace from the traffic observed over the rate interval
+func GenerateAuthorizationPolicies(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespace := mux.Vars(r)["namespace"]
+	generated, ok := generateAuthorizationPolicies(w, r, business, namespace)
+	if !ok {
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, generated)
+}
+
+// ApplyAuthorizationPolicies is the API handler to generate the least-privilege AuthorizationPolicies of a
+// namespace, and to create or update them
+func ApplyAuthorizationPolicies(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespace := mux.Vars(r)["namespace"]
+	generated, ok := generateAuthorizationPolicies(w, r, business, namespace)
+	if !ok {
+		return
+	}
+	if err := business.Authorization.ApplyAuthorizationPolicies(namespace, generated); err != nil {
+		if errors.IsConflict(err) {
+			RespondWithError(w, http.StatusConflict, err.Error())
+			return
+		}
+		handleErrorResponse(w, err)
+		return
+	}
+	names := make([]string, 0, len(generated.Policies))
+	for _, p := range generated.Policies {
+		names = append(names, p.Name)
+	}
+	audit(r, "APPLY on Namespace: "+namespace+" AuthorizationPolicies: "+strings.Join(names, ","))
+	RespondWithJSON(w, http.StatusOK, generated)
+}
+
+// generateAuthorizationPolicies parses the parameters of a generation request and generates the policies. It
+// writes the error response and returns false on failure.
+func generateAuthorizationPolicies(w http.ResponseWriter, r *http.Request, business *business.Layer, namespace string) (*models.GeneratedAuthorizationPolicies, bool) {
+	queryParams := r.URL.Query()
+	rateInterval := defaultLeastPrivilegeRateInterval
+	if ri := queryParams.Get("rateInterval"); ri != "" {
+		rateInterval = ri
+	}
+	queryTime := util.Clock.Now()
+	if qt := queryParams.Get("queryTime"); qt != "" {
+		unix, err := strconv.ParseInt(qt, 10, 64)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid queryTime parameter: "+err.Error())
+			return nil, false
+		}
+		queryTime = time.Unix(unix, 0)
+	}
+	denyAll := false
+	if da := queryParams.Get("denyAll"); da != "" {
+		var err error
+		if denyAll, err = strconv.ParseBool(da); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid denyAll parameter: "+err.Error())
+			return nil, false
+		}
+	}
+	rateInterval, err := adjustRateInterval(business, namespace, rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err, "Adjust rate interval error: "+err.Error())
+		return nil, false
+	}
+
+	generated, err := business.Authorization.GenerateAuthorizationPolicies(namespace, rateInterval, queryTime, denyAll)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return nil, false
+	}
+	return generated, true
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/models"
+)
+
+// AuthorizationSimulation is the API handler to simulate a request between two workloads against the
+// AuthorizationPolicies and PeerAuthentications applying to the destination
+func AuthorizationSimulation(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Cannot read body: "+err.Error())
+		return
+	}
+	var request models.AuthorizationSimulationRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid simulation request: "+err.Error())
+		return
+	}
+	simulation, err := business.Authorization.SimulateRequest(request)
+	if errors.IsBadRequest(err) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, simulation)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/business"
+)
+
+// CacheStatus returns the state of the Kiali cache for the namespaces accessible by the user
+func CacheStatus(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	status, err := business.Cache.GetStatus()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, status)
+}
+
+// checkCacheManagement responds with an error and returns false when the user cannot refresh or evict the cache of the namespace
+func checkCacheManagement(w http.ResponseWriter, layer *business.Layer, namespace string) bool {
+	if _, err := checkNamespaceAccess(layer.Namespace, namespace); err != nil {
+		RespondWithError(w, http.StatusForbidden, "Cannot access namespace data: "+err.Error())
+		return false
+	}
+	canManage, err := layer.Cache.CanManage()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return false
+	}
+	if !canManage {
+		RespondWithError(w, http.StatusForbidden, "Managing the Kiali cache requires the permission to patch the Kiali deployment")
+		return false
+	}
+	return true
+}
+
+// handleCacheError responds to the errors of the cache actions
+func handleCacheError(w http.ResponseWriter, err error) {
+	if errors.IsBadRequest(err) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	handleErrorResponse(w, err)
+}
+
+// CacheNamespaceRefresh reloads the cached objects of a namespace
+func CacheNamespaceRefresh(w http.ResponseWriter, r *http.Request) {
+	namespace := mux.Vars(r)["namespace"]
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	if !checkCacheManagement(w, business, namespace) {
+		return
+	}
+
+	if err := business.Cache.RefreshNamespace(namespace); err != nil {
+		handleCacheError(w, err)
+		return
+	}
+	audit(r, "REFRESH on Kiali cache of Namespace: "+namespace)
+	RespondWithCode(w, http.StatusOK)
+}
+
+// CacheNamespaceEvict drops the cached objects of a namespace
+func CacheNamespaceEvict(w http.ResponseWriter, r *http.Request) {
+	namespace := mux.Vars(r)["namespace"]
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	if !checkCacheManagement(w, business, namespace) {
+		return
+	}
+
+	if err := business.Cache.EvictNamespace(namespace); err != nil {
+		handleCacheError(w, err)
+		return
+	}
+	audit(r, "EVICT on Kiali cache of Namespace: "+namespace)
+	RespondWithCode(w, http.StatusOK)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/models"
+)
+
+// Workload certificates are rotated well before their expiry: one expiring within this window indicates a
+// rotation problem
+const defaultCertificatesExpiringWithin = 6 * time.Hour
+
+// WorkloadCertificates is the API handler to fetch the certificates loaded by the proxies of a workload
+func WorkloadCertificates(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	respondWithCertificates(w, r, func(layer *business.Layer, expiringWithin time.Duration) (*models.CertificatesReport, error) {
+		return layer.Certificates.GetWorkloadCertificates(params["namespace"], params["workload"], expiringWithin)
+	})
+}
+
+// NamespaceCertificates is the API handler to fetch the certificates loaded by the proxies of a namespace
+func NamespaceCertificates(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	respondWithCertificates(w, r, func(layer *business.Layer, expiringWithin time.Duration) (*models.CertificatesReport, error) {
+		return layer.Certificates.GetNamespaceCertificates(params["namespace"], expiringWithin)
+	})
+}
+
+// MeshCertificates is the API handler to fetch the certificates loaded by the proxies of the accessible namespaces
+func MeshCertificates(w http.ResponseWriter, r *http.Request) {
+	respondWithCertificates(w, r, func(layer *business.Layer, expiringWithin time.Duration) (*models.CertificatesReport, error) {
+		return layer.Certificates.GetMeshCertificates(expiringWithin)
+	})
+}
+
+func respondWithCertificates(w http.ResponseWriter, r *http.Request, fetch func(layer *business.Layer, expiringWithin time.Duration) (*models.CertificatesReport, error)) {
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	expiringWithin := defaultCertificatesExpiringWithin
+	if ew := r.URL.Query().Get("expiringWithin"); ew != "" {
+		if expiringWithin, err = time.ParseDuration(ew); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid expiringWithin parameter: "+err.Error())
+			return
+		}
+	}
+	report, err := fetch(layer, expiringWithin)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, report)
+}
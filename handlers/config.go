@@ -177,6 +177,12 @@ func getPrometheusConfig() PrometheusConfig {
 		}
 	}
 
+	if backends := config.Get().ExternalServices.Prometheus.Backends; len(backends) > 0 {
+		// The metrics beyond the local retention are read from the federated backends
+		promConfig.StorageTsdbRetention = getFederatedRetention(config.Get().ExternalServices.Prometheus)
+		return promConfig
+	}
+
 	flags, err := client.GetFlags()
 	if checkErr(err, "Failed to fetch Prometheus flags") {
 		if retentionString, ok := flags["storage.tsdb.retention"]; ok {
@@ -190,6 +196,29 @@ func getPrometheusConfig() PrometheusConfig {
 	return promConfig
 }
 
+// getFederatedRetention returns the longest retention of the federated Prometheus backends, in seconds, or zero
+// when a backend has an unlimited retention
+func getFederatedRetention(promConfig config.PrometheusConfig) int64 {
+	retentions := []string{promConfig.Retention}
+	for _, backend := range promConfig.Backends {
+		retentions = append(retentions, backend.Retention)
+	}
+	var longest int64
+	for _, retentionString := range retentions {
+		if retentionString == "" {
+			return 0
+		}
+		retention, err := model.ParseDuration(retentionString)
+		if !checkErr(err, fmt.Sprintf("Invalid Prometheus backend retention [%s]", retentionString)) {
+			continue
+		}
+		if seconds := int64(time.Duration(retention).Seconds()); seconds > longest {
+			longest = seconds
+		}
+	}
+	return longest
+}
+
 func checkErr(err error, message string) bool {
 	if err != nil {
 		log.Errorf("%s: %v", message, err)
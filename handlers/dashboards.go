@@ -63,9 +63,32 @@ func CustomDashboard(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, dashboard)
 }
 
+// CustomDashboardsErrors is the API handler to fetch the validation errors of the dashboards discovered at runtime in a namespace
+func CustomDashboardsErrors(w http.ResponseWriter, r *http.Request) {
+	namespace := mux.Vars(r)["namespace"]
+
+	svc := business.NewDashboardsService()
+	if !svc.CustomEnabled {
+		RespondWithError(w, http.StatusServiceUnavailable, "Custom dashboards are disabled in config")
+		return
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if _, err := checkNamespaceAccess(layer.Namespace, namespace); err != nil {
+		RespondWithError(w, http.StatusForbidden, "Cannot access namespace data: "+err.Error())
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, svc.GetValidationErrors(namespace))
+}
+
 func extractDashboardQueryParams(queryParams url.Values, q *models.DashboardQuery, namespaceInfo *models.Namespace) error {
 	q.FillDefaults()
 	q.LabelsFilters = extractLabelsFilters(queryParams.Get("labelsFilters"))
+	q.Workload = queryParams.Get("workload")
 	additionalLabels := strings.Split(queryParams.Get("additionalLabels"), ",")
 	for _, additionalLabel := range additionalLabels {
 		kvPair := strings.Split(additionalLabel, ":")
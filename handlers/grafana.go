@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"io/ioutil"
 	"net/http"
 
+	"k8s.io/apimachinery/pkg/api/errors"
+
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/log"
 )
@@ -25,3 +28,28 @@ func GetGrafanaInfo(w http.ResponseWriter, r *http.Request) {
 	}
 	RespondWithJSON(w, code, info)
 }
+
+// Largest Grafana dashboard JSON accepted for import
+const maxGrafanaDashboardSize = 10 * 1024 * 1024
+
+// ImportGrafanaDashboard converts the Grafana dashboard JSON of the body into a custom dashboard
+func ImportGrafanaDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.ContentLength > maxGrafanaDashboardSize {
+		RespondWithError(w, http.StatusRequestEntityTooLarge, "The Grafana dashboard exceeds the limit of 10MiB")
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxGrafanaDashboardSize))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Cannot read body: "+err.Error())
+		return
+	}
+	imported, err := business.ImportGrafanaDashboard(r.URL.Query().Get("name"), body)
+	if errors.IsBadRequest(err) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, imported)
+}
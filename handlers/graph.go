@@ -23,25 +23,105 @@ package handlers
 //   graphType:       Determines how to present the telemetry data. app | service | versionedApp | workload (default: workload)
 //   boxBy:           If supported by vendor, visually box by a specified node attribute (default: none)
 //   namespaces:      Comma-separated list of namespace names to use in the graph. Will override namespace path param
+//   partial:         Return the parts of the graph that could be generated, with warnings (default: server config)
 //   queryTime:       Unix time (seconds) for query such that range is queryTime-duration..queryTime (default now)
 //   TelemetryVendor: default: istio
 //
 //  Note: some handlers may ignore some query parameters.
+//  Note: the server configuration may limit the namespaces, the duration, the size of the graph and the requests of a user.
 //  Note: vendors may support additional, vendor-specific query parameters.
 //
 import (
+	"crypto/sha256"
 	"fmt"
+	"net"
 	"net/http"
 	"runtime/debug"
+	"sync"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/api"
 	"github.com/kiali/kiali/log"
 )
 
+// graphRequests counts the graph requests running for each user
+var graphRequests = graphRequestCounter{running: make(map[string]int)}
+
+type graphRequestCounter struct {
+	running map[string]int
+	lock    sync.Mutex
+}
+
+// acquire counts a graph request of the user, unless the user already runs the maximum number of graph requests
+func (c *graphRequestCounter) acquire(user string, max int) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if max > 0 && c.running[user] >= max {
+		return false
+	}
+	c.running[user]++
+	return true
+}
+
+func (c *graphRequestCounter) release(user string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.running[user]--; c.running[user] <= 0 {
+		delete(c.running, user)
+	}
+}
+
+// graphRequestUser identifies the user of a graph request by its name or, when unknown, by its token. It returns false
+// when the request has no identity of its own: without auth info, with anonymous access, or when the users share the
+// Kiali token.
+func graphRequestUser(r *http.Request) (string, bool) {
+	authInfo, err := getAuthInfo(r)
+	if err != nil || authInfo == nil {
+		return "", false
+	}
+	if authInfo.Username != "" {
+		return "user " + authInfo.Username, true
+	}
+	auth := config.Get().Auth
+	if authInfo.Token == "" || auth.Strategy == config.AuthStrategyAnonymous || (auth.Strategy == config.AuthStrategyOpenId && auth.OpenId.DisableRBAC) {
+		return "", false
+	}
+	return fmt.Sprintf("token %x", sha256.Sum256([]byte(authInfo.Token))), true
+}
+
+// graphRequestClient identifies a graph request without user by the address of its client
+func graphRequestClient(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "client " + host
+}
+
+// limitGraphRequests bounds the graph requests running at the same time for the user of the request, or for its
+// client when the request has no user
+func limitGraphRequests(w http.ResponseWriter, r *http.Request) (release func(), ok bool) {
+	user, ok := graphRequestUser(r)
+	if !ok {
+		user = graphRequestClient(r)
+	}
+	max := config.Get().API.Graph.MaxConcurrentRequestsPerUser
+	if !graphRequests.acquire(user, max) {
+		RespondWithError(w, http.StatusTooManyRequests, fmt.Sprintf("Too many graph requests running, the limit is [%d] requests per user", max))
+		return nil, false
+	}
+	return func() { graphRequests.release(user) }, true
+}
+
 // GraphNamespaces is a REST http.HandlerFunc handling graph generation for 1 or more namespaces
 func GraphNamespaces(w http.ResponseWriter, r *http.Request) {
 	defer handlePanic(w)
+	release, ok := limitGraphRequests(w, r)
+	if !ok {
+		return
+	}
+	defer release()
 
 	o := graph.NewOptions(r)
 
@@ -55,6 +135,11 @@ func GraphNamespaces(w http.ResponseWriter, r *http.Request) {
 // GraphNode is a REST http.HandlerFunc handling node-detail graph config generation.
 func GraphNode(w http.ResponseWriter, r *http.Request) {
 	defer handlePanic(w)
+	release, ok := limitGraphRequests(w, r)
+	if !ok {
+		return
+	}
+	defer release()
 
 	o := graph.NewOptions(r)
 
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+const defaultImpactRateInterval = "10m"
+
+// IstioConfigImpact is the API handler to analyze the impact of deleting or patching an Istio object, before the
+// change is applied
+func IstioConfigImpact(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	objectType := params["object_type"]
+	object := params["object"]
+
+	if api := business.GetIstioAPI(objectType); api == "" {
+		RespondWithError(w, http.StatusBadRequest, "Object type not managed: "+objectType)
+		return
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Impact request with bad body: "+err.Error())
+		return
+	}
+	var change models.IstioConfigChange
+	if err := json.Unmarshal(body, &change); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Impact request with bad change: "+err.Error())
+		return
+	}
+
+	queryParams := r.URL.Query()
+	rateInterval := defaultImpactRateInterval
+	if ri := queryParams.Get("rateInterval"); ri != "" {
+		rateInterval = ri
+	}
+	queryTime := util.Clock.Now()
+	if qt := queryParams.Get("queryTime"); qt != "" {
+		unix, err := strconv.ParseInt(qt, 10, 64)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid queryTime parameter: "+err.Error())
+			return
+		}
+		queryTime = time.Unix(unix, 0)
+	}
+	rateInterval, err = adjustRateInterval(layer, namespace, rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err, "Adjust rate interval error: "+err.Error())
+		return
+	}
+
+	impact, err := layer.IstioConfigUsage.GetChangeImpact(namespace, objectType, object, change, rateInterval, queryTime)
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, impact)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/util"
+)
+
+// UnusedIstioConfig is the API handler to find the Istio config of a namespace that had no effect on the traffic
+// observed over the rate interval
+func UnusedIstioConfig(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespace := mux.Vars(r)["namespace"]
+	queryParams := r.URL.Query()
+	rateInterval := defaultObservedTrafficRateInterval
+	if ri := queryParams.Get("rateInterval"); ri != "" {
+		rateInterval = ri
+	}
+	queryTime := util.Clock.Now()
+	if qt := queryParams.Get("queryTime"); qt != "" {
+		unix, err := strconv.ParseInt(qt, 10, 64)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid queryTime parameter: "+err.Error())
+			return
+		}
+		queryTime = time.Unix(unix, 0)
+	}
+	rateInterval, err = adjustRateInterval(business, namespace, rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err, "Adjust rate interval error: "+err.Error())
+		return
+	}
+
+	report, err := business.IstioConfigUsage.GetUnusedIstioConfig(namespace, rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, report)
+}
@@ -24,6 +24,7 @@ func GetJaegerInfo(w http.ResponseWriter, r *http.Request) {
 			Integration:          jaegerConfig.InClusterURL != "",
 			URL:                  jaegerConfig.URL,
 			NamespaceSelector:    jaegerConfig.NamespaceSelector,
+			Provider:             jaegerConfig.Provider,
 			WhiteListIstioSystem: jaegerConfig.WhiteListIstioSystem,
 		}
 	} else {
@@ -270,3 +271,109 @@ func readQuery(values url.Values) (models.TracingQuery, error) {
 	}
 	return q, nil
 }
+
+// TraceAnalysis is the API handler to compute the critical path and the per-service self time of a trace
+func TraceAnalysis(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Trace Analysis initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	traceID := params["traceID"]
+	analysis, err := business.Jaeger.GetTraceAnalysis(traceID)
+	if err != nil {
+		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if analysis == nil {
+		RespondWithError(w, http.StatusNotFound, fmt.Sprintf("Trace %s not found", traceID))
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, analysis)
+}
+
+// TraceComparison is the API handler to compare a trace with a baseline trace, or with the median of
+// similar traces of an app when no baseline is provided
+func TraceComparison(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Trace Comparison initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	traceID := params["traceID"]
+	queryParams := r.URL.Query()
+	var comparison *models.TraceComparison
+	if baseline := queryParams.Get("baseline"); baseline != "" {
+		comparison, err = business.Jaeger.CompareTraces(traceID, baseline)
+	} else {
+		namespace := queryParams.Get("namespace")
+		app := queryParams.Get("app")
+		if namespace == "" || app == "" {
+			RespondWithError(w, http.StatusBadRequest, "Either 'baseline' or both 'namespace' and 'app' parameters are required")
+			return
+		}
+		q, errQuery := readQuery(queryParams)
+		if errQuery != nil {
+			RespondWithError(w, http.StatusBadRequest, errQuery.Error())
+			return
+		}
+		if q.Start.IsZero() {
+			// Default to the last hour
+			q.Start = q.End.Add(-time.Hour)
+		}
+		comparison, err = business.Jaeger.CompareTraceToMedian(namespace, app, traceID, q)
+	}
+	if err != nil {
+		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if comparison == nil {
+		RespondWithError(w, http.StatusNotFound, fmt.Sprintf("Trace %s or its baseline not found", traceID))
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, comparison)
+}
+
+// maxTraceTimeBuckets bounds the heatmap of the trace statistics, computed per time bucket and per span
+const maxTraceTimeBuckets = 500
+
+// AppTracesStats is the API handler to compute per-operation statistics and a latency heatmap from the spans of an app
+func AppTracesStats(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Traces Stats initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	app := params["app"]
+	queryParams := r.URL.Query()
+	q, err := readQuery(queryParams)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if q.Start.IsZero() {
+		// Default to the last hour
+		q.Start = q.End.Add(-time.Hour)
+	}
+	if !q.Start.Before(q.End) {
+		RespondWithError(w, http.StatusBadRequest, "Invalid parameters 'startMicros' and 'endMicros': start must be before end")
+		return
+	}
+	timeBuckets := 20
+	if v := queryParams.Get("timeBuckets"); v != "" {
+		if timeBuckets, err = strconv.Atoi(v); err != nil || timeBuckets <= 0 || timeBuckets > maxTraceTimeBuckets {
+			RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse parameter 'timeBuckets': must be an integer between 1 and %d", maxTraceTimeBuckets))
+			return
+		}
+	}
+	stats, err := business.Jaeger.GetAppTracesStats(namespace, app, q, timeBuckets)
+	if err != nil {
+		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, stats)
+}
@@ -48,7 +48,7 @@ func getAppMetrics(w http.ResponseWriter, r *http.Request, promSupplier promClie
 		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
 		return
 	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metrics)
 }
 
 // WorkloadMetrics is the API handler to fetch metrics to be displayed, related to a single workload
@@ -80,7 +80,7 @@ func getWorkloadMetrics(w http.ResponseWriter, r *http.Request, promSupplier pro
 		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
 		return
 	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metrics)
 }
 
 // ServiceMetrics is the API handler to fetch metrics to be displayed, related to a single service
@@ -112,7 +112,7 @@ func getServiceMetrics(w http.ResponseWriter, r *http.Request, promSupplier prom
 		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
 		return
 	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metrics)
 }
 
 // AggregateMetrics is the API handler to fetch metrics to be displayed, related to a single aggregate
@@ -153,7 +153,7 @@ func getAggregateMetrics(w http.ResponseWriter, r *http.Request, promSupplier pr
 		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
 		return
 	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metrics)
 }
 
 // NamespaceMetrics is the API handler to fetch metrics to be displayed, related to all
@@ -185,12 +185,51 @@ func getNamespaceMetrics(w http.ResponseWriter, r *http.Request, promSupplier pr
 		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
 		return
 	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metrics)
+}
+
+// respondWithMetrics writes the metrics in the format requested by the "format" query parameter:
+// JSON by default, CSV or OpenMetrics text for exports
+func respondWithMetrics(w http.ResponseWriter, r *http.Request, metrics models.MetricsMap) {
+	format, err := extractMetricsFormat(r.URL.Query())
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	switch format {
+	case models.MetricsFormatCSV:
+		csv, err := models.MetricsToCSV(metrics)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(csv)
+	case models.MetricsFormatOpenMetrics:
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(models.MetricsToOpenMetrics(metrics))
+	default:
+		RespondWithJSON(w, http.StatusOK, metrics)
+	}
+}
+
+func extractMetricsFormat(queryParams url.Values) (string, error) {
+	format := queryParams.Get("format")
+	if format != "" && format != "json" && format != models.MetricsFormatCSV && format != models.MetricsFormatOpenMetrics {
+		return "", fmt.Errorf("bad request, query parameter 'format' must be json, %s or %s", models.MetricsFormatCSV, models.MetricsFormatOpenMetrics)
+	}
+	return format, nil
 }
 
 func extractIstioMetricsQueryParams(r *http.Request, q *models.IstioMetricsQuery, namespaceInfo *models.Namespace) error {
 	q.FillDefaults()
 	queryParams := r.URL.Query()
+	// The export format is checked before querying Prometheus
+	if _, err := extractMetricsFormat(queryParams); err != nil {
+		return err
+	}
 	if filters, ok := queryParams["filters[]"]; ok && len(filters) > 0 {
 		q.Filters = filters
 	}
@@ -213,6 +252,13 @@ func extractIstioMetricsQueryParams(r *http.Request, q *models.IstioMetricsQuery
 		}
 		q.Reporter = reporter
 	}
+	if exemplars := queryParams.Get("exemplars"); exemplars != "" {
+		if b, err := strconv.ParseBool(exemplars); err == nil {
+			q.Exemplars = b
+		} else {
+			return errors.New("bad request, cannot parse query parameter 'exemplars'")
+		}
+	}
 	return extractBaseMetricsQueryParams(queryParams, &q.RangeQuery, namespaceInfo)
 }
 
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// MetricsQuery is the API handler to run a PromQL query restricted to the namespaces the user can access
+func MetricsQuery(w http.ResponseWriter, r *http.Request) {
+	getMetricsQuery(w, r, defaultPromClientSupplier)
+}
+
+// getMetricsQuery (mock-friendly version)
+func getMetricsQuery(w http.ResponseWriter, r *http.Request, promSupplier promClientSupplier) {
+	queryParams := r.URL.Query()
+	query := queryParams.Get("query")
+	if strings.TrimSpace(query) == "" {
+		RespondWithError(w, http.StatusBadRequest, "Query parameter 'query' is missing")
+		return
+	}
+	if _, err := extractMetricsFormat(queryParams); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespaces := []string{}
+	if nsParam := queryParams.Get("namespaces"); nsParam != "" {
+		for _, ns := range strings.Split(nsParam, ",") {
+			ns = strings.TrimSpace(ns)
+			if _, err := checkNamespaceAccess(layer.Namespace, ns); err != nil {
+				RespondWithError(w, http.StatusForbidden, "Cannot access namespace data: "+err.Error())
+				return
+			}
+			namespaces = append(namespaces, ns)
+		}
+	} else {
+		accessible, err := layer.Namespace.GetNamespaces()
+		if err != nil {
+			handleErrorResponse(w, err)
+			return
+		}
+		for _, ns := range accessible {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+
+	prom, err := promSupplier()
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusServiceUnavailable, "Prometheus client error: "+err.Error())
+		return
+	}
+	rangeQuery := prometheus.RangeQuery{}
+	rangeQuery.FillDefaults()
+	// The query is not bound to the creation of a namespace
+	if err := extractBaseMetricsQueryParams(queryParams, &rangeQuery, &models.Namespace{}); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	metrics, err := business.NewMetricsService(prom).QueryScopedMetrics(query, queryParams.Get("namespaceLabel"), namespaces, &rangeQuery)
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+	respondWithMetrics(w, r, models.MetricsMap{"query": metrics})
+}
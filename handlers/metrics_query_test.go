@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/mux"
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestMetricsQueryScopedToNamespaces(t *testing.T) {
+	ts, xapi := setupMetricsQueryEndpoint(t)
+	defer ts.Close()
+
+	var query string
+	xapi.On("QueryRange", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Run(func(args mock.Arguments) {
+		query = args[1].(string)
+	}).Return(model.Matrix{
+		&model.SampleStream{
+			Metric: model.Metric{"destination_workload_namespace": "ns", "response_code": "200"},
+			Values: []model.SamplePair{{Timestamp: 1500000000000, Value: 2.5}},
+		},
+	}, nil)
+
+	params := url.Values{}
+	params.Set("query", `sum(rate(istio_requests_total{response_code="200"}[5m])) by (response_code)`)
+	params.Set("namespaces", "ns")
+	params.Set("format", "openmetrics")
+	resp, err := http.Get(ts.URL + "/api/metrics/query?" + params.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `sum(rate(istio_requests_total{response_code="200",destination_workload_namespace=~"ns"}[5m])) by (response_code)`, query)
+	assert.Equal(t, `# TYPE query gauge
+query{destination_workload_namespace="ns",response_code="200"} 2.5 1500000000
+# EOF
+`, string(actual))
+}
+
+func TestMetricsQueryBadRequests(t *testing.T) {
+	ts, _ := setupMetricsQueryEndpoint(t)
+	defer ts.Close()
+
+	for params, message := range map[string]string{
+		"namespaces=ns": "Query parameter 'query' is missing",
+		"query=up&namespaces=ns&namespaceLabel=app": `label \"app\" cannot scope a query`,
+		"query=sum(up&namespaces=ns":                "unbalanced parenthesis",
+		"query=up&namespaces=ns&format=xml":         "query parameter 'format' must be json, csv or openmetrics",
+	} {
+		resp, err := http.Get(ts.URL + "/api/metrics/query?" + params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, _ := ioutil.ReadAll(resp.Body)
+		assert.Equal(t, 400, resp.StatusCode, params)
+		assert.Contains(t, string(actual), message, params)
+	}
+}
+
+func setupMetricsQueryEndpoint(t *testing.T) (*httptest.Server, *prometheustest.PromAPIMock) {
+	config.Set(config.NewConfig())
+	xapi := new(prometheustest.PromAPIMock)
+	k8s := kubetest.NewK8SClientMock()
+	prom, err := prometheus.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prom.Inject(xapi)
+	k8s.On("GetProject", "ns").Return(&osproject_v1.Project{ObjectMeta: meta_v1.ObjectMeta{Name: "ns"}}, nil)
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/metrics/query", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			getMetricsQuery(w, r.WithContext(context), func() (*prometheus.Client, error) {
+				return prom, nil
+			})
+		}))
+
+	ts := httptest.NewServer(mr)
+
+	mockClientFactory := kubetest.NewK8SClientFactoryMock(k8s)
+	business.SetWithBackends(mockClientFactory, prom)
+
+	return ts, xapi
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/notifications"
+)
+
+// NotificationEvents is the API handler to fetch the recent notification events of the namespaces accessible by the user
+func NotificationEvents(w http.ResponseWriter, r *http.Request) {
+	notifier := notifications.Get()
+	if notifier == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Notifications are disabled")
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespaces, err := business.Namespace.GetNamespaces()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	accessible := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		accessible[ns.Name] = true
+	}
+	RespondWithJSON(w, http.StatusOK, notifier.GetEvents(accessible))
+}
+
+// NotificationSilences is the API handler to list the active and pending silences of the namespaces accessible by the user
+func NotificationSilences(w http.ResponseWriter, r *http.Request) {
+	notifier := notifications.Get()
+	if notifier == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Notifications are disabled")
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespaces, err := business.Namespace.GetNamespaces()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	accessible := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		accessible[ns.Name] = true
+	}
+	silences := []models.NotificationSilence{}
+	for _, s := range notifier.GetSilences() {
+		if accessible[s.Namespace] {
+			silences = append(silences, s)
+		}
+	}
+	RespondWithJSON(w, http.StatusOK, silences)
+}
+
+// NotificationSilenceCreate is the API handler to silence notifications of a namespace
+func NotificationSilenceCreate(w http.ResponseWriter, r *http.Request) {
+	notifier := notifications.Get()
+	if notifier == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Notifications are disabled")
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Cannot read body: "+err.Error())
+		return
+	}
+	var silence models.NotificationSilence
+	if err := json.Unmarshal(body, &silence); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid silence: "+err.Error())
+		return
+	}
+	if silence.Namespace == "" {
+		RespondWithError(w, http.StatusBadRequest, "Invalid silence: namespace is required")
+		return
+	}
+	// Users can only silence namespaces they have access to
+	if _, err := business.Namespace.GetNamespace(silence.Namespace); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	// The author is the authenticated user, whatever the body says
+	silence.CreatedBy = ""
+	if authInfo, err := getAuthInfo(r); err == nil {
+		silence.CreatedBy = authInfo.Username
+	}
+	created, err := notifier.AddSilence(silence)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid silence: "+err.Error())
+		return
+	}
+	audit(r, "CREATE on Namespace: "+created.Namespace+" Notification silence: "+created.ID)
+	RespondWithJSON(w, http.StatusOK, created)
+}
+
+// NotificationSilenceDelete is the API handler to remove a silence
+func NotificationSilenceDelete(w http.ResponseWriter, r *http.Request) {
+	notifier := notifications.Get()
+	if notifier == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Notifications are disabled")
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	id := mux.Vars(r)["id"]
+	for _, s := range notifier.GetSilences() {
+		if s.ID != id {
+			continue
+		}
+		if _, err := business.Namespace.GetNamespace(s.Namespace); err != nil {
+			handleErrorResponse(w, err)
+			return
+		}
+		notifier.DeleteSilence(id)
+		audit(r, "DELETE on Namespace: "+s.Namespace+" Notification silence: "+id)
+		RespondWithCode(w, http.StatusOK)
+		return
+	}
+	RespondWithError(w, http.StatusNotFound, "Silence not found: "+id)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/rollouts"
+)
+
+// RolloutsList is the API handler to fetch the rollouts of a namespace
+func RolloutsList(w http.ResponseWriter, r *http.Request) {
+	namespace := mux.Vars(r)["namespace"]
+	controller := rollouts.Get()
+	if controller == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Rollouts controller is not running")
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	if _, err := business.Namespace.GetNamespace(namespace); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, controller.List(map[string]bool{namespace: true}))
+}
+
+// RolloutDetails is the API handler to fetch a rollout with its history
+func RolloutDetails(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	controller := rollouts.Get()
+	if controller == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Rollouts controller is not running")
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	if _, err := business.Namespace.GetNamespace(namespace); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	rollout, ok := controller.Get(namespace, params["rollout"])
+	if !ok {
+		RespondWithError(w, http.StatusNotFound, "Rollout not found: "+params["rollout"])
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+// RolloutCreate is the API handler to start a rollout. The user must be allowed to update the rollout VirtualService,
+// whose next steps are applied by the controller with the Kiali service account.
+func RolloutCreate(w http.ResponseWriter, r *http.Request) {
+	namespace := mux.Vars(r)["namespace"]
+	controller := rollouts.Get()
+	if controller == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Rollouts controller is not running")
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Cannot read body: "+err.Error())
+		return
+	}
+	var spec models.RolloutSpec
+	if err := json.Unmarshal(body, &spec); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid rollout: "+err.Error())
+		return
+	}
+	spec.Namespace = namespace
+	if err := spec.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid rollout: "+err.Error())
+		return
+	}
+	if ok := checkVirtualServiceUpdate(w, business, namespace, spec.VirtualService); !ok {
+		return
+	}
+	createdBy := ""
+	if authInfo, err := getAuthInfo(r); err == nil {
+		createdBy = authInfo.Username
+	}
+	rollout, err := controller.Create(spec, createdBy)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Cannot start rollout: "+err.Error())
+		return
+	}
+	audit(r, "CREATE on Namespace: "+namespace+" Rollout: "+spec.Name+" VirtualService: "+spec.VirtualService)
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+// RolloutAction is the API handler to pause, resume or abort a rollout
+func RolloutAction(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	controller := rollouts.Get()
+	if controller == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Rollouts controller is not running")
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	if _, err := business.Namespace.GetNamespace(namespace); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	current, ok := controller.Get(namespace, params["rollout"])
+	if !ok {
+		RespondWithError(w, http.StatusNotFound, "Rollout not found: "+params["rollout"])
+		return
+	}
+	if ok := checkVirtualServiceUpdate(w, business, namespace, current.Spec.VirtualService); !ok {
+		return
+	}
+	var action func(string, string) (models.Rollout, error)
+	switch params["action"] {
+	case "pause":
+		action = controller.Pause
+	case "resume":
+		action = controller.Resume
+	case "abort":
+		action = controller.Abort
+	default:
+		RespondWithError(w, http.StatusBadRequest, "Unknown rollout action: "+params["action"])
+		return
+	}
+	rollout, err := action(namespace, params["rollout"])
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	audit(r, strings.ToUpper(params["action"])+" on Namespace: "+namespace+" Rollout: "+params["rollout"]+" VirtualService: "+current.Spec.VirtualService)
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+func checkVirtualServiceUpdate(w http.ResponseWriter, business *business.Layer, namespace, virtualService string) bool {
+	details, err := business.IstioConfig.GetIstioConfigDetails(namespace, kubernetes.VirtualServices, virtualService)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return false
+	}
+	if !details.Permissions.Update {
+		RespondWithError(w, http.StatusForbidden, "User is not allowed to update VirtualService "+virtualService)
+		return false
+	}
+	return true
+}
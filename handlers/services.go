@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/gorilla/mux"
@@ -154,3 +157,58 @@ func ServiceUpdate(w http.ResponseWriter, r *http.Request) {
 	audit(r, "UPDATE on Namespace: "+namespace+" Service name: "+service+" Patch: "+jsonPatch)
 	RespondWithJSON(w, http.StatusOK, serviceDetails)
 }
+
+// ServiceTrafficTemplate is the API handler to generate, and optionally apply, the Istio configuration implementing
+// a traffic intent for a service. The configuration is applied only when it has no validation errors, otherwise it is
+// returned with a 422 status.
+func ServiceTrafficTemplate(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	service := params["service"]
+	apply := false
+	if applyParam := r.URL.Query().Get("apply"); applyParam != "" {
+		if apply, err = strconv.ParseBool(applyParam); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid apply parameter: "+err.Error())
+			return
+		}
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Cannot read body: "+err.Error())
+		return
+	}
+	var intent models.TrafficIntent
+	if err := json.Unmarshal(body, &intent); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid traffic intent: "+err.Error())
+		return
+	}
+	trafficConfig, err := business.Traffic.GenerateTrafficConfig(namespace, service, intent)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	if apply {
+		for _, v := range trafficConfig.Validations {
+			if !v.Valid {
+				// The objects are returned with their validations, nothing is applied
+				RespondWithJSON(w, http.StatusUnprocessableEntity, trafficConfig)
+				return
+			}
+		}
+		if err := business.Traffic.ApplyTrafficConfig(namespace, trafficConfig); err != nil {
+			handleErrorResponse(w, err)
+			return
+		}
+		applied := []string{trafficConfig.VirtualService.Name, trafficConfig.DestinationRule.Name}
+		if trafficConfig.Gateway != nil {
+			applied = append(applied, trafficConfig.Gateway.Name)
+		}
+		audit(r, "APPLY on Namespace: "+namespace+" Service name: "+service+" Traffic config: "+strings.Join(applied, ","))
+	}
+	RespondWithJSON(w, http.StatusOK, trafficConfig)
+}
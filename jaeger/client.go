@@ -26,7 +26,8 @@ import (
 	"github.com/kiali/kiali/util/httputil"
 )
 
-// ClientInterface for mocks (only mocked function are necessary here)
+// ClientInterface is the tracing client abstraction, implemented for Jaeger, Zipkin and Tempo backends.
+// Whatever the backend, traces are converted to the Jaeger model.
 type ClientInterface interface {
 	GetAppTraces(ns, app string, query models.TracingQuery) (traces *JaegerResponse, err error)
 	GetTraceDetail(traceId string) (*JaegerSingleTrace, error)
@@ -42,7 +43,8 @@ type Client struct {
 	ctx        context.Context
 }
 
-func NewClient(token string) (*Client, error) {
+// NewClient creates a client for the configured tracing backend (Jaeger, Zipkin or Tempo)
+func NewClient(token string) (ClientInterface, error) {
 	cfg := config.Get()
 	cfgTracing := cfg.ExternalServices.Tracing
 
@@ -64,6 +66,27 @@ func NewClient(token string) (*Client, error) {
 			return nil, errParse
 		}
 
+		switch cfgTracing.Provider {
+		case config.TracingProviderJaeger, "":
+			// Jaeger client, GRPC or HTTP, see below
+		case config.TracingProviderZipkin:
+			log.Tracef("Using Zipkin client for tracing: url=%v, auth.type=%s", u, auth.Type)
+			client, err := newHTTPClient(&auth)
+			if err != nil {
+				return nil, err
+			}
+			return &ZipkinClient{httpClient: client, baseURL: u}, nil
+		case config.TracingProviderTempo:
+			log.Tracef("Using Tempo client for tracing: url=%v, auth.type=%s", u, auth.Type)
+			client, err := newHTTPClient(&auth)
+			if err != nil {
+				return nil, err
+			}
+			return &TempoClient{httpClient: client, baseURL: u}, nil
+		default:
+			return nil, fmt.Errorf("unsupported tracing provider: %s", cfgTracing.Provider)
+		}
+
 		if cfgTracing.UseGRPC {
 			// GRPC client
 
@@ -101,17 +124,24 @@ func NewClient(token string) (*Client, error) {
 		} else {
 			// Legacy HTTP client
 			log.Tracef("Using legacy HTTP client for Jaeger: url=%v, auth.type=%s", u, auth.Type)
-			timeout := time.Duration(5000 * time.Millisecond)
-			transport, err := httputil.CreateTransport(&auth, &http.Transport{}, timeout)
+			client, err := newHTTPClient(&auth)
 			if err != nil {
 				return nil, err
 			}
-			client := http.Client{Transport: transport, Timeout: timeout}
 			return &Client{httpClient: client, baseURL: u, ctx: ctx}, nil
 		}
 	}
 }
 
+func newHTTPClient(auth *config.Auth) (http.Client, error) {
+	timeout := time.Duration(5000 * time.Millisecond)
+	transport, err := httputil.CreateTransport(auth, &http.Transport{}, timeout)
+	if err != nil {
+		return http.Client{}, err
+	}
+	return http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
 // GetAppTraces fetches traces of an app
 func (in *Client) GetAppTraces(namespace, app string, q models.TracingQuery) (*JaegerResponse, error) {
 	if in.grpcClient == nil {
@@ -187,13 +217,17 @@ func (in *Client) GetTraceDetail(strTraceID string) (*JaegerSingleTrace, error)
 // GetErrorTraces fetches number of traces in error for the given app
 func (in *Client) GetErrorTraces(ns, app string, duration time.Duration) (int, error) {
 	// Note: grpc vs http switch is performed in subsequent call 'GetAppTraces'
+	return getErrorTraces(in, ns, app, duration)
+}
+
+func getErrorTraces(client ClientInterface, ns, app string, duration time.Duration) (int, error) {
 	now := time.Now()
 	query := models.TracingQuery{
 		Start: now.Add(-duration),
 		End:   now,
 		Tags:  map[string]string{"error": "true"},
 	}
-	traces, err := in.GetAppTraces(ns, app, query)
+	traces, err := client.GetAppTraces(ns, app, query)
 	if err != nil {
 		return 0, err
 	}
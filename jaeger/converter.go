@@ -0,0 +1,57 @@
+package jaeger
+
+import (
+	"fmt"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+)
+
+// traceBuilder helps converting traces from other tracing backends (Zipkin, Tempo) into the Jaeger model.
+// Processes are identified by their service name.
+type traceBuilder struct {
+	trace     jaegerModels.Trace
+	processes map[string]jaegerModels.ProcessID
+}
+
+func newTraceBuilder(traceID string) *traceBuilder {
+	return &traceBuilder{
+		trace: jaegerModels.Trace{
+			TraceID:   jaegerModels.TraceID(traceID),
+			Spans:     []jaegerModels.Span{},
+			Processes: make(map[jaegerModels.ProcessID]jaegerModels.Process),
+		},
+		processes: make(map[string]jaegerModels.ProcessID),
+	}
+}
+
+// addSpan adds a span to the trace, creating its process if needed
+func (b *traceBuilder) addSpan(span jaegerModels.Span, serviceName string, processTags []jaegerModels.KeyValue) {
+	pID, ok := b.processes[serviceName]
+	if !ok {
+		pID = jaegerModels.ProcessID(fmt.Sprintf("p%d", len(b.processes)+1))
+		b.processes[serviceName] = pID
+		b.trace.Processes[pID] = jaegerModels.Process{ServiceName: serviceName, Tags: processTags}
+	}
+	span.TraceID = b.trace.TraceID
+	span.ProcessID = pID
+	b.trace.Spans = append(b.trace.Spans, span)
+}
+
+func stringTag(key, value string) jaegerModels.KeyValue {
+	return jaegerModels.KeyValue{Key: key, Type: jaegerModels.StringType, Value: value}
+}
+
+func errorTag() jaegerModels.KeyValue {
+	return jaegerModels.KeyValue{Key: "error", Type: jaegerModels.BoolType, Value: true}
+}
+
+func childOf(traceID, parentID string) []jaegerModels.Reference {
+	if parentID == "" {
+		return []jaegerModels.Reference{}
+	}
+	return []jaegerModels.Reference{{
+		RefType: jaegerModels.ChildOf,
+		TraceID: jaegerModels.TraceID(traceID),
+		SpanID:  jaegerModels.SpanID(parentID),
+	}}
+}
@@ -0,0 +1,99 @@
+package jaeger
+
+import (
+	"encoding/json"
+	"testing"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZipkinToJaeger(t *testing.T) {
+	assert := assert.New(t)
+
+	raw := `[{
+		"traceId": "5af7183fb1d4cf5f",
+		"id": "6b221d5bc9e6496c",
+		"name": "productpage.bookinfo.svc.cluster.local:9080/productpage",
+		"kind": "SERVER",
+		"timestamp": 1556604172355737,
+		"duration": 1431,
+		"localEndpoint": {"serviceName": "productpage.bookinfo", "ipv4": "10.0.0.1"},
+		"tags": {"node_id": "sidecar~10.0.0.1~productpage-v1-123.bookinfo~bookinfo.svc.cluster.local", "error": "500"}
+	}, {
+		"traceId": "5af7183fb1d4cf5f",
+		"parentId": "6b221d5bc9e6496c",
+		"id": "352bff9a74ca9ad2",
+		"name": "reviews.bookinfo.svc.cluster.local:9080/*",
+		"kind": "CLIENT",
+		"timestamp": 1556604172355800,
+		"duration": 1000,
+		"localEndpoint": {"serviceName": "productpage.bookinfo"},
+		"annotations": [{"timestamp": 1556604172355900, "value": "retry"}]
+	}]`
+	var spans []zipkinSpan
+	assert.NoError(json.Unmarshal([]byte(raw), &spans))
+
+	trace := zipkinToJaeger(spans)
+	assert.Equal(jaegerModels.TraceID("5af7183fb1d4cf5f"), trace.TraceID)
+	assert.Len(trace.Processes, 1)
+	assert.Equal("productpage.bookinfo", trace.Processes["p1"].ServiceName)
+	assert.Len(trace.Spans, 2)
+
+	root := trace.Spans[0]
+	assert.Equal(jaegerModels.SpanID("6b221d5bc9e6496c"), root.SpanID)
+	assert.Equal(jaegerModels.ProcessID("p1"), root.ProcessID)
+	assert.Equal(uint64(1556604172355737), root.StartTime)
+	assert.Equal(uint64(1431), root.Duration)
+	assert.Empty(root.References)
+	assert.Contains(root.Tags, stringTag("span.kind", "server"))
+	assert.Contains(root.Tags, errorTag())
+	assert.Contains(root.Tags, stringTag("error.message", "500"))
+
+	child := trace.Spans[1]
+	assert.Equal([]jaegerModels.Reference{{RefType: jaegerModels.ChildOf, TraceID: "5af7183fb1d4cf5f", SpanID: "6b221d5bc9e6496c"}}, child.References)
+	assert.Len(child.Logs, 1)
+	assert.Equal(uint64(1556604172355900), child.Logs[0].Timestamp)
+}
+
+func TestOtlpToJaeger(t *testing.T) {
+	assert := assert.New(t)
+
+	raw := `{"batches": [{
+		"resource": {"attributes": [
+			{"key": "service.name", "value": {"stringValue": "reviews.bookinfo"}},
+			{"key": "hostname", "value": {"stringValue": "reviews-v1-123"}}
+		]},
+		"instrumentationLibrarySpans": [{"spans": [{
+			"traceId": "AAAAAAAAAABa9xg/sdTPXw==",
+			"spanId": "ayIdW8nmSWw=",
+			"parentSpanId": "NSv/mnTKmtI=",
+			"name": "reviews.bookinfo.svc.cluster.local:9080/*",
+			"kind": "SPAN_KIND_SERVER",
+			"startTimeUnixNano": "1556604172355737000",
+			"endTimeUnixNano": "1556604172357168000",
+			"attributes": [
+				{"key": "http.status_code", "value": {"intValue": "503"}},
+				{"key": "upstream_cluster", "value": {"stringValue": "inbound|9080||"}}
+			],
+			"status": {"code": "STATUS_CODE_ERROR"}
+		}]}]
+	}]}`
+	var otlp otlpTrace
+	assert.NoError(json.Unmarshal([]byte(raw), &otlp))
+
+	trace := otlpToJaeger("5af7183fb1d4cf5f", &otlp)
+	assert.Len(trace.Spans, 1)
+	assert.Equal("reviews.bookinfo", trace.Processes["p1"].ServiceName)
+	assert.Equal([]jaegerModels.KeyValue{stringTag("hostname", "reviews-v1-123")}, trace.Processes["p1"].Tags)
+
+	span := trace.Spans[0]
+	assert.Equal(jaegerModels.TraceID("5af7183fb1d4cf5f"), span.TraceID)
+	assert.Equal(jaegerModels.SpanID("6b221d5bc9e6496c"), span.SpanID)
+	assert.Equal(jaegerModels.SpanID("352bff9a74ca9ad2"), span.References[0].SpanID)
+	assert.Equal(uint64(1556604172355737), span.StartTime)
+	assert.Equal(uint64(1431), span.Duration)
+	assert.Contains(span.Tags, stringTag("span.kind", "server"))
+	assert.Contains(span.Tags, jaegerModels.KeyValue{Key: "http.status_code", Type: jaegerModels.Int64Type, Value: int64(503)})
+	assert.Contains(span.Tags, errorTag())
+}
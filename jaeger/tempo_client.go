@@ -0,0 +1,318 @@
+package jaeger
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// Maximum number of traces fetched by ID at the same time from Tempo
+const maxConcurrentTraceFetches = 10
+
+// TempoClient is a tracing client for the Grafana Tempo search and trace-by-id API
+type TempoClient struct {
+	httpClient http.Client
+	baseURL    *url.URL
+}
+
+type tempoSearchResponse struct {
+	Traces []struct {
+		TraceID string `json:"traceID"`
+	} `json:"traces"`
+}
+
+// OTLP JSON model, as returned by Tempo
+type otlpTrace struct {
+	Batches       []otlpResourceSpans `json:"batches"`
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	} `json:"resource"`
+	InstrumentationLibrarySpans []otlpScopeSpans `json:"instrumentationLibrarySpans"`
+	ScopeSpans                  []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpScopeSpans struct {
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId"`
+	Name              string         `json:"name"`
+	Kind              interface{}    `json:"kind"`
+	StartTimeUnixNano otlpUint64     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   otlpUint64     `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes"`
+	Events            []struct {
+		TimeUnixNano otlpUint64     `json:"timeUnixNano"`
+		Name         string         `json:"name"`
+		Attributes   []otlpKeyValue `json:"attributes"`
+	} `json:"events"`
+	Status struct {
+		Code interface{} `json:"code"`
+	} `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+// otlpUint64 is a uint64 that can be JSON-encoded either as a number or as a string
+type otlpUint64 uint64
+
+func (o *otlpUint64) UnmarshalJSON(b []byte) error {
+	v, err := strconv.ParseUint(strings.Trim(string(b), `"`), 10, 64)
+	if err != nil {
+		return err
+	}
+	*o = otlpUint64(v)
+	return nil
+}
+
+// GetAppTraces fetches traces of an app. Tempo search only returns trace summaries,
+// so the full traces are then fetched by ID.
+func (in *TempoClient) GetAppTraces(namespace, app string, q models.TracingQuery) (*JaegerResponse, error) {
+	u := *in.baseURL
+	u.Path = path.Join(u.Path, "/api/search")
+	serviceName := buildJaegerServiceName(namespace, app)
+	prepareTempoQuery(&u, serviceName, q)
+	resp, code, err := makeRequest(in.httpClient, u.String(), nil)
+	if err == nil && code != http.StatusOK {
+		err = fmt.Errorf("unexpected status code %d: %s", code, string(resp))
+	}
+	if err != nil {
+		log.Errorf("Tempo query error: %s [code: %d, URL: %v]", err, code, u)
+		return nil, err
+	}
+	var search tempoSearchResponse
+	if err := json.Unmarshal(resp, &search); err != nil {
+		log.Errorf("Error unmarshalling Tempo response: %s [URL: %v]", err, u)
+		return nil, err
+	}
+
+	traces := make([]*JaegerSingleTrace, len(search.Traces))
+	errs := make([]error, len(search.Traces))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxConcurrentTraceFetches)
+	for i, t := range search.Traces {
+		wg.Add(1)
+		go func(i int, traceID string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			traces[i], errs[i] = in.GetTraceDetail(traceID)
+		}(i, t.TraceID)
+	}
+	wg.Wait()
+
+	r := JaegerResponse{
+		Data:              []jaegerModels.Trace{},
+		Errors:            []structuredError{},
+		JaegerServiceName: serviceName,
+	}
+	for i, t := range traces {
+		if errs[i] != nil {
+			r.Errors = append(r.Errors, structuredError{Msg: errs[i].Error(), TraceID: search.Traces[i].TraceID})
+		} else if t != nil {
+			r.Data = append(r.Data, t.Data)
+		}
+	}
+	return &r, nil
+}
+
+// GetTraceDetail fetches a specific trace from its ID
+func (in *TempoClient) GetTraceDetail(traceID string) (*JaegerSingleTrace, error) {
+	u := *in.baseURL
+	u.Path = path.Join(u.Path, "/api/traces", traceID)
+	resp, code, err := makeRequest(in.httpClient, u.String(), nil)
+	if err != nil {
+		log.Errorf("Tempo query error: %s [code: %d, URL: %v]", err, code, u)
+		return nil, err
+	}
+	if code == http.StatusNotFound {
+		return nil, nil
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", code, string(resp))
+	}
+	var trace otlpTrace
+	if err := json.Unmarshal(resp, &trace); err != nil {
+		log.Errorf("Error unmarshalling Tempo response: %s [URL: %v]", err, u)
+		return nil, err
+	}
+	converted := otlpToJaeger(traceID, &trace)
+	if len(converted.Spans) == 0 {
+		return nil, nil
+	}
+	return &JaegerSingleTrace{Data: converted}, nil
+}
+
+// GetErrorTraces fetches number of traces in error for the given app
+func (in *TempoClient) GetErrorTraces(ns, app string, duration time.Duration) (int, error) {
+	return getErrorTraces(in, ns, app, duration)
+}
+
+func prepareTempoQuery(u *url.URL, serviceName string, query models.TracingQuery) {
+	q := url.Values{}
+	// Tags are logfmt-encoded
+	tags := []string{logfmtPair("service.name", serviceName)}
+	for k, v := range query.Tags {
+		tags = append(tags, logfmtPair(k, v))
+	}
+	sort.Strings(tags[1:])
+	q.Set("tags", strings.Join(tags, " "))
+	if !query.Start.IsZero() {
+		q.Set("start", strconv.FormatInt(query.Start.Unix(), 10))
+		q.Set("end", strconv.FormatInt(query.End.Unix(), 10))
+	}
+	if query.MinDuration > 0 {
+		q.Set("minDuration", query.MinDuration.String())
+	}
+	if query.Limit > 0 {
+		q.Set("limit", strconv.Itoa(query.Limit))
+	}
+	u.RawQuery = q.Encode()
+	log.Debugf("Prepared Tempo query: %v", u)
+}
+
+func logfmtPair(key, value string) string {
+	if strings.ContainsAny(value, " =\"") {
+		value = strconv.Quote(value)
+	}
+	return key + "=" + value
+}
+
+func otlpToJaeger(traceID string, trace *otlpTrace) jaegerModels.Trace {
+	builder := newTraceBuilder(traceID)
+	for _, batch := range append(trace.Batches, trace.ResourceSpans...) {
+		serviceName := ""
+		processTags := []jaegerModels.KeyValue{}
+		for _, attr := range batch.Resource.Attributes {
+			if attr.Key == "service.name" {
+				serviceName = fmt.Sprintf("%v", otlpValue(attr.Value))
+			} else {
+				processTags = append(processTags, otlpTag(attr))
+			}
+		}
+		for _, scope := range append(batch.InstrumentationLibrarySpans, batch.ScopeSpans...) {
+			for _, s := range scope.Spans {
+				builder.addSpan(otlpSpanToJaeger(traceID, &s), serviceName, processTags)
+			}
+		}
+	}
+	return builder.trace
+}
+
+func otlpSpanToJaeger(traceID string, s *otlpSpan) jaegerModels.Span {
+	span := jaegerModels.Span{
+		SpanID:        jaegerModels.SpanID(otlpID(s.SpanID)),
+		OperationName: s.Name,
+		References:    childOf(traceID, otlpID(s.ParentSpanID)),
+		StartTime:     uint64(s.StartTimeUnixNano) / uint64(time.Microsecond),
+		Tags:          []jaegerModels.KeyValue{},
+		Logs:          []jaegerModels.Log{},
+	}
+	if s.EndTimeUnixNano > s.StartTimeUnixNano {
+		span.Duration = uint64(s.EndTimeUnixNano-s.StartTimeUnixNano) / uint64(time.Microsecond)
+	}
+	if kind := otlpSpanKind(s.Kind); kind != "" {
+		span.Tags = append(span.Tags, stringTag("span.kind", kind))
+	}
+	for _, attr := range s.Attributes {
+		span.Tags = append(span.Tags, otlpTag(attr))
+	}
+	if code := fmt.Sprintf("%v", s.Status.Code); code == "2" || code == "STATUS_CODE_ERROR" {
+		span.Tags = append(span.Tags, errorTag())
+	}
+	for _, e := range s.Events {
+		fields := []jaegerModels.KeyValue{stringTag("event", e.Name)}
+		for _, attr := range e.Attributes {
+			fields = append(fields, otlpTag(attr))
+		}
+		span.Logs = append(span.Logs, jaegerModels.Log{
+			Timestamp: uint64(e.TimeUnixNano) / uint64(time.Microsecond),
+			Fields:    fields,
+		})
+	}
+	return span
+}
+
+// otlpID converts base64-encoded OTLP IDs to their hex representation, which Jaeger uses
+func otlpID(id string) string {
+	if b, err := base64.StdEncoding.DecodeString(id); err == nil && (len(b) == 8 || len(b) == 16) {
+		return hex.EncodeToString(b)
+	}
+	return id
+}
+
+func otlpSpanKind(kind interface{}) string {
+	switch fmt.Sprintf("%v", kind) {
+	case "2", "SPAN_KIND_SERVER":
+		return "server"
+	case "3", "SPAN_KIND_CLIENT":
+		return "client"
+	case "4", "SPAN_KIND_PRODUCER":
+		return "producer"
+	case "5", "SPAN_KIND_CONSUMER":
+		return "consumer"
+	}
+	return ""
+}
+
+func otlpTag(attr otlpKeyValue) jaegerModels.KeyValue {
+	v := otlpValue(attr.Value)
+	switch v.(type) {
+	case bool:
+		return jaegerModels.KeyValue{Key: attr.Key, Type: jaegerModels.BoolType, Value: v}
+	case int64:
+		return jaegerModels.KeyValue{Key: attr.Key, Type: jaegerModels.Int64Type, Value: v}
+	case float64:
+		return jaegerModels.KeyValue{Key: attr.Key, Type: jaegerModels.Float64Type, Value: v}
+	}
+	return stringTag(attr.Key, fmt.Sprintf("%v", v))
+}
+
+func otlpValue(value map[string]interface{}) interface{} {
+	if v, ok := value["stringValue"]; ok {
+		return v
+	}
+	if v, ok := value["boolValue"]; ok {
+		return v
+	}
+	if v, ok := value["intValue"]; ok {
+		// int64 values are usually string-encoded in OTLP JSON
+		switch i := v.(type) {
+		case float64:
+			return int64(i)
+		case string:
+			if parsed, err := strconv.ParseInt(i, 10, 64); err == nil {
+				return parsed
+			}
+		}
+		return v
+	}
+	if v, ok := value["doubleValue"]; ok {
+		return v
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package jaeger
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestTempoGetAppTraces(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.ExternalServices.Tracing.NamespaceSelector = true
+	config.Set(conf)
+
+	traceIDs := []string{}
+	for i := 0; i < 3*maxConcurrentTraceFetches; i++ {
+		traceIDs = append(traceIDs, fmt.Sprintf("%016x", i+1))
+	}
+	var lock sync.Mutex
+	running, maxRunning := 0, 0
+	var searchQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/search" {
+			searchQuery = r.URL.Query()
+			traces := []string{}
+			for _, id := range traceIDs {
+				traces = append(traces, fmt.Sprintf(`{"traceID": "%s"}`, id))
+			}
+			fmt.Fprintf(w, `{"traces": [%s]}`, strings.Join(traces, ","))
+			return
+		}
+		lock.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		lock.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		lock.Lock()
+		running--
+		lock.Unlock()
+
+		switch strings.TrimPrefix(r.URL.Path, "/api/traces/") {
+		case traceIDs[0]:
+			w.WriteHeader(http.StatusNotFound)
+		case traceIDs[1]:
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			fmt.Fprint(w, `{"batches": [{
+				"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "reviews.bookinfo"}}]},
+				"scopeSpans": [{"spans": [{
+					"spanId": "ayIdW8nmSWw=",
+					"name": "reviews.bookinfo.svc.cluster.local:9080/*",
+					"startTimeUnixNano": "1556604172355737000",
+					"endTimeUnixNano": "1556604172357168000"
+				}]}]
+			}]}`)
+		}
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL)
+	client := TempoClient{httpClient: http.Client{Timeout: time.Second}, baseURL: baseURL}
+	r, err := client.GetAppTraces("bookinfo", "reviews", models.TracingQuery{Limit: 50})
+	assert.NoError(err)
+	assert.Equal("service.name=reviews.bookinfo", searchQuery.Get("tags"))
+	assert.Equal("50", searchQuery.Get("limit"))
+	assert.Equal("reviews.bookinfo", r.JaegerServiceName)
+	// Missing traces are skipped, failed fetches are reported
+	assert.Len(r.Data, len(traceIDs)-2)
+	assert.Len(r.Errors, 1)
+	assert.Equal(traceIDs[1], r.Errors[0].TraceID)
+	assert.True(maxRunning <= maxConcurrentTraceFetches)
+}
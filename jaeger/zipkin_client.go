@@ -0,0 +1,189 @@
+package jaeger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// ZipkinClient is a tracing client for the Zipkin v2 API
+type ZipkinClient struct {
+	httpClient http.Client
+	baseURL    *url.URL
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+	IPv4        string `json:"ipv4,omitempty"`
+	IPv6        string `json:"ipv6,omitempty"`
+	Port        int    `json:"port,omitempty"`
+}
+
+type zipkinAnnotation struct {
+	Timestamp uint64 `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+type zipkinSpan struct {
+	TraceID        string             `json:"traceId"`
+	ID             string             `json:"id"`
+	ParentID       string             `json:"parentId,omitempty"`
+	Name           string             `json:"name"`
+	Kind           string             `json:"kind,omitempty"`
+	Timestamp      uint64             `json:"timestamp"`
+	Duration       uint64             `json:"duration"`
+	LocalEndpoint  *zipkinEndpoint    `json:"localEndpoint,omitempty"`
+	RemoteEndpoint *zipkinEndpoint    `json:"remoteEndpoint,omitempty"`
+	Annotations    []zipkinAnnotation `json:"annotations,omitempty"`
+	Tags           map[string]string  `json:"tags,omitempty"`
+}
+
+// GetAppTraces fetches traces of an app
+func (in *ZipkinClient) GetAppTraces(namespace, app string, q models.TracingQuery) (*JaegerResponse, error) {
+	u := *in.baseURL
+	u.Path = path.Join(u.Path, "/api/v2/traces")
+	serviceName := buildJaegerServiceName(namespace, app)
+	prepareZipkinQuery(&u, serviceName, q)
+	resp, code, err := makeRequest(in.httpClient, u.String(), nil)
+	if err == nil && code != http.StatusOK {
+		err = fmt.Errorf("unexpected status code %d: %s", code, string(resp))
+	}
+	if err != nil {
+		log.Errorf("Zipkin query error: %s [code: %d, URL: %v]", err, code, u)
+		return nil, err
+	}
+	var traces [][]zipkinSpan
+	if err := json.Unmarshal(resp, &traces); err != nil {
+		log.Errorf("Error unmarshalling Zipkin response: %s [URL: %v]", err, u)
+		return nil, err
+	}
+	r := JaegerResponse{
+		Data:              []jaegerModels.Trace{},
+		JaegerServiceName: serviceName,
+	}
+	for _, spans := range traces {
+		if len(spans) > 0 {
+			r.Data = append(r.Data, zipkinToJaeger(spans))
+		}
+	}
+	return &r, nil
+}
+
+// GetTraceDetail fetches a specific trace from its ID
+func (in *ZipkinClient) GetTraceDetail(traceID string) (*JaegerSingleTrace, error) {
+	u := *in.baseURL
+	u.Path = path.Join(u.Path, "/api/v2/trace", traceID)
+	resp, code, err := makeRequest(in.httpClient, u.String(), nil)
+	if err != nil {
+		log.Errorf("Zipkin query error: %s [code: %d, URL: %v]", err, code, u)
+		return nil, err
+	}
+	if code == http.StatusNotFound {
+		return nil, nil
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", code, string(resp))
+	}
+	var spans []zipkinSpan
+	if err := json.Unmarshal(resp, &spans); err != nil {
+		log.Errorf("Error unmarshalling Zipkin response: %s [URL: %v]", err, u)
+		return nil, err
+	}
+	if len(spans) == 0 {
+		return nil, nil
+	}
+	return &JaegerSingleTrace{Data: zipkinToJaeger(spans)}, nil
+}
+
+// GetErrorTraces fetches number of traces in error for the given app
+func (in *ZipkinClient) GetErrorTraces(ns, app string, duration time.Duration) (int, error) {
+	return getErrorTraces(in, ns, app, duration)
+}
+
+func prepareZipkinQuery(u *url.URL, serviceName string, query models.TracingQuery) {
+	q := url.Values{}
+	q.Set("serviceName", serviceName)
+	q.Set("endTs", strconv.FormatInt(query.End.UnixNano()/int64(time.Millisecond), 10))
+	if !query.Start.IsZero() {
+		q.Set("lookback", strconv.FormatInt(query.End.Sub(query.Start).Milliseconds(), 10))
+	}
+	if len(query.Tags) > 0 {
+		// Zipkin annotation query, e.g: "error and http.method=GET"
+		// Zipkin error tags hold the error message, so the Jaeger-like "error=true" is turned into a presence check
+		terms := []string{}
+		for k, v := range query.Tags {
+			if k == "error" && v == "true" {
+				terms = append(terms, k)
+			} else {
+				terms = append(terms, k+"="+v)
+			}
+		}
+		sort.Strings(terms)
+		q.Set("annotationQuery", strings.Join(terms, " and "))
+	}
+	if query.MinDuration > 0 {
+		q.Set("minDuration", strconv.FormatInt(query.MinDuration.Microseconds(), 10))
+	}
+	if query.Limit > 0 {
+		q.Set("limit", strconv.Itoa(query.Limit))
+	}
+	u.RawQuery = q.Encode()
+	log.Debugf("Prepared Zipkin query: %v", u)
+}
+
+func zipkinToJaeger(spans []zipkinSpan) jaegerModels.Trace {
+	builder := newTraceBuilder(spans[0].TraceID)
+	for _, zs := range spans {
+		span := jaegerModels.Span{
+			SpanID:        jaegerModels.SpanID(zs.ID),
+			OperationName: zs.Name,
+			References:    childOf(zs.TraceID, zs.ParentID),
+			StartTime:     zs.Timestamp,
+			Duration:      zs.Duration,
+			Tags:          []jaegerModels.KeyValue{},
+			Logs:          []jaegerModels.Log{},
+		}
+		if zs.Kind != "" {
+			span.Tags = append(span.Tags, stringTag("span.kind", strings.ToLower(zs.Kind)))
+		}
+		keys := make([]string, 0, len(zs.Tags))
+		for k := range zs.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if k == "error" {
+				span.Tags = append(span.Tags, errorTag(), stringTag("error.message", zs.Tags[k]))
+			} else {
+				span.Tags = append(span.Tags, stringTag(k, zs.Tags[k]))
+			}
+		}
+		for _, a := range zs.Annotations {
+			span.Logs = append(span.Logs, jaegerModels.Log{
+				Timestamp: a.Timestamp,
+				Fields:    []jaegerModels.KeyValue{stringTag("event", a.Value)},
+			})
+		}
+		serviceName := ""
+		processTags := []jaegerModels.KeyValue{}
+		if zs.LocalEndpoint != nil {
+			serviceName = zs.LocalEndpoint.ServiceName
+			if zs.LocalEndpoint.IPv4 != "" {
+				processTags = append(processTags, stringTag("ip", zs.LocalEndpoint.IPv4))
+			}
+		}
+		builder.addSpan(span, serviceName, processTags)
+	}
+	return builder.trace
+}
@@ -25,6 +25,7 @@ import (
 	"strings"
 
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/config/dashboards"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 	"github.com/kiali/kiali/server"
@@ -40,7 +41,9 @@ var (
 
 // Command line arguments
 var (
-	argConfigFile = flag.String("config", "", "Path to the YAML configuration file. If not specified, environment variables will be used for configuration.")
+	argConfigFile             = flag.String("config", "", "Path to the YAML configuration file. If not specified, environment variables will be used for configuration.")
+	argImportGrafanaDashboard = flag.String("import-grafana-dashboard", "", "Path to a Grafana dashboard JSON file to convert into a custom dashboard. The custom dashboard YAML is printed and Kiali exits.")
+	argDashboardName          = flag.String("dashboard-name", "", "Name of the custom dashboard converted with -import-grafana-dashboard. Default is the uid of the Grafana dashboard.")
 )
 
 func init() {
@@ -58,6 +61,11 @@ func main() {
 	flag.Parse()
 	validateFlags()
 
+	// offline conversion of a Grafana dashboard, no config nor cluster is needed
+	if *argImportGrafanaDashboard != "" {
+		os.Exit(importGrafanaDashboard(*argImportGrafanaDashboard, *argDashboardName))
+	}
+
 	// log startup information
 	log.Infof("Kiali: Version: %v, Commit: %v\n", version, commitHash)
 	log.Debugf("Kiali: Command line: [%v]", strings.Join(os.Args, " "))
@@ -174,6 +182,34 @@ func validateFlags() {
 	}
 }
 
+// importGrafanaDashboard prints the custom dashboard converted from a Grafana dashboard JSON file, and the panels that
+// could not be converted on stderr. It returns the exit code.
+func importGrafanaDashboard(file, name string) int {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot read the Grafana dashboard: %v\n", err)
+		return 1
+	}
+	converted, err := dashboards.ConvertGrafanaDashboard(name, content)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	out, err := converted.YAML()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Print(out)
+	for _, w := range converted.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	for _, p := range converted.Untranslated {
+		fmt.Fprintf(os.Stderr, "Panel %q (id %d) not converted: %s\n", p.Title, p.ID, p.Reason)
+	}
+	return 0
+}
+
 // determineConsoleVersion will return the version of the UI console the server will serve to clients.
 // Note this method requires the configuration to be loaded and available via config.Get()
 func determineConsoleVersion() string {
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"time"
+)
+
+type (
+	// In cluster-wide mode, the informers hold the objects of every namespace, read with the Kiali ServiceAccount.
+	// AccessCache keeps the results of the access reviews of the users, per namespace and resource type, for the
+	// duration of the namespaces of a token.
+	AccessCache interface {
+		IsClusterScoped() bool
+		SetAccess(token string, namespace string, resourceType string, allowed bool)
+		GetAccess(token string, namespace string, resourceType string) (allowed bool, found bool)
+	}
+
+	accessReview struct {
+		created time.Time
+		allowed bool
+	}
+)
+
+func (c *kialiCacheImpl) IsClusterScoped() bool {
+	return c.clusterScoped
+}
+
+func (c *kialiCacheImpl) SetAccess(token string, namespace string, resourceType string, allowed bool) {
+	defer c.accessLock.Unlock()
+	c.accessLock.Lock()
+	now := time.Now()
+	// The expired reviews are swept at most once per duration, the reviews of the tokens no longer used don't pile up
+	if now.Sub(c.accessSwept) > c.tokenNamespaceDuration {
+		for t, reviews := range c.tokenAccess {
+			for key, review := range reviews {
+				if now.Sub(review.created) > c.tokenNamespaceDuration {
+					delete(reviews, key)
+				}
+			}
+			if len(reviews) == 0 {
+				delete(c.tokenAccess, t)
+			}
+		}
+		c.accessSwept = now
+	}
+	if _, exist := c.tokenAccess[token]; !exist {
+		c.tokenAccess[token] = make(map[string]accessReview)
+	}
+	c.tokenAccess[token][namespace+"/"+resourceType] = accessReview{
+		created: now,
+		allowed: allowed,
+	}
+}
+
+func (c *kialiCacheImpl) GetAccess(token string, namespace string, resourceType string) (bool, bool) {
+	defer c.accessLock.RUnlock()
+	c.accessLock.RLock()
+	review, exist := c.tokenAccess[token][namespace+"/"+resourceType]
+	if !exist || time.Since(review.created) > c.tokenNamespaceDuration {
+		return false, false
+	}
+	return review.allowed, true
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kube "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -14,6 +15,7 @@ import (
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
 )
 
 // Istio uses caches for pods and controllers.
@@ -31,9 +33,12 @@ type (
 		// Stop all caches
 		Stop()
 
+		StatusCache
+
 		KubernetesCache
 		IstioCache
 		NamespacesCache
+		AccessCache
 		ProxyStatusCache
 		RegistryStatusCache
 	}
@@ -56,18 +61,25 @@ type (
 
 	kialiCacheImpl struct {
 		istioClient            kubernetes.K8SClient
+		clusterScoped          bool
 		k8sApi                 kube.Interface
 		istioNetworkingGetter  cache.Getter
 		istioSecurityGetter    cache.Getter
 		refreshDuration        time.Duration
 		cacheNamespaces        []string
 		cacheIstioTypes        map[string]bool
+		cacheKubernetesTypes   map[string]bool
 		stopChan               map[string]chan struct{}
 		nsCache                map[string]typeCache
 		cacheLock              sync.RWMutex
+		statsLock              sync.Mutex
+		stats                  map[string]map[string]*typeStats
 		tokenLock              sync.RWMutex
 		tokenNamespaces        map[string]namespaceCache
 		tokenNamespaceDuration time.Duration
+		accessLock             sync.RWMutex
+		tokenAccess            map[string]map[string]accessReview
+		accessSwept            time.Time
 		proxyStatusLock        sync.RWMutex
 		proxyStatusCreated     *time.Time
 		proxyStatusNamespaces  map[string]map[string]podProxyStatus
@@ -111,10 +123,22 @@ func NewKialiCache() (KialiCache, error) {
 
 	refreshDuration := time.Duration(kConfig.KubernetesConfig.CacheDuration) * time.Second
 	tokenNamespaceDuration := time.Duration(kConfig.KubernetesConfig.CacheTokenNamespaceDuration) * time.Second
+	clusterScoped := kConfig.KubernetesConfig.CacheClusterScoped
 	cacheNamespaces := kConfig.KubernetesConfig.CacheNamespaces
 	cacheIstioTypes := make(map[string]bool)
-	for _, iType := range kConfig.KubernetesConfig.CacheIstioTypes {
-		cacheIstioTypes[iType] = true
+	if clusterScoped {
+		// Every Istio type read by Kiali and served by the API server is cached cluster-wide
+		for _, resourceType := range istioResourceTypes {
+			if istioClient.HasIstioResource(resourceType) {
+				cacheIstioTypes[kubernetes.PluralType[resourceType]] = true
+			} else {
+				log.Debugf("[Kiali Cache] [resourceType: %s] is not served by the API server, it is not cached", resourceType)
+			}
+		}
+	} else {
+		for _, iType := range kConfig.KubernetesConfig.CacheIstioTypes {
+			cacheIstioTypes[iType] = true
+		}
 	}
 	log.Tracef("[Kiali Cache] cacheIstioTypes %v", cacheIstioTypes)
 
@@ -126,26 +150,37 @@ func NewKialiCache() (KialiCache, error) {
 
 	kialiCacheImpl := kialiCacheImpl{
 		istioClient:            *istioClient,
+		clusterScoped:          clusterScoped,
 		refreshDuration:        refreshDuration,
 		cacheNamespaces:        cacheNamespaces,
 		cacheIstioTypes:        cacheIstioTypes,
 		stopChan:               stopChan,
 		nsCache:                make(map[string]typeCache),
+		stats:                  make(map[string]map[string]*typeStats),
 		tokenNamespaces:        make(map[string]namespaceCache),
 		tokenNamespaceDuration: tokenNamespaceDuration,
+		tokenAccess:            make(map[string]map[string]accessReview),
 		proxyStatusNamespaces:  make(map[string]map[string]podProxyStatus),
 	}
 
 	kialiCacheImpl.k8sApi = istioClient.GetK8sApi()
+	kialiCacheImpl.cacheKubernetesTypes = servedKubernetesTypes(kialiCacheImpl.k8sApi)
 	kialiCacheImpl.istioNetworkingGetter = istioClient.GetIstioNetworkingApi()
 	kialiCacheImpl.istioSecurityGetter = istioClient.GetIstioSecurityApi()
 
-	log.Infof("Kiali Cache is active for namespaces %v", cacheNamespaces)
+	if clusterScoped {
+		log.Infof("Kiali Cache is active cluster-wide")
+	} else {
+		log.Infof("Kiali Cache is active for namespaces %v", cacheNamespaces)
+	}
 	return &kialiCacheImpl, nil
 }
 
 // It will indicate if a namespace should have a cache
 func (c *kialiCacheImpl) isCached(namespace string) bool {
+	if c.clusterScoped {
+		return true
+	}
 	for _, cacheNs := range c.cacheNamespaces {
 		if matches, _ := regexp.MatchString(strings.TrimSpace(cacheNs), namespace); matches {
 			return true
@@ -154,6 +189,32 @@ func (c *kialiCacheImpl) isCached(namespace string) bool {
 	return false
 }
 
+// cacheKey returns the key of the informers holding the namespace: the namespace itself, or the empty key of the
+// informers watching all namespaces in cluster-wide mode
+func (c *kialiCacheImpl) cacheKey(namespace string) string {
+	if c.clusterScoped {
+		return meta_v1.NamespaceAll
+	}
+	return namespace
+}
+
+// namespaceCache returns the informers holding the namespace, if they are created
+func (c *kialiCacheImpl) namespaceCache(namespace string) (typeCache, bool) {
+	c.cacheLock.RLock()
+	defer c.cacheLock.RUnlock()
+	nsCache, ok := c.nsCache[c.cacheKey(namespace)]
+	return nsCache, ok
+}
+
+// listNamespace lists the objects of a namespace held by an informer. In cluster-wide mode, the informer watches all
+// namespaces and the objects of the namespace are read from its namespace index.
+func (c *kialiCacheImpl) listNamespace(informer cache.SharedIndexInformer, namespace string) ([]interface{}, error) {
+	if !c.clusterScoped {
+		return informer.GetStore().List(), nil
+	}
+	return informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+}
+
 func (c *kialiCacheImpl) createCache(namespace string) bool {
 	if _, exist := c.nsCache[namespace]; exist {
 		return true
@@ -166,6 +227,9 @@ func (c *kialiCacheImpl) createCache(namespace string) bool {
 	if _, exist := c.stopChan[namespace]; !exist {
 		c.stopChan[namespace] = make(chan struct{})
 	}
+	for resourceType, typeInformer := range informer {
+		typeInformer.AddEventHandler(c.statsEventHandler(resourceType, c.stopChan[namespace]))
+	}
 
 	go func(stopCh <-chan struct{}) {
 		for _, informer := range c.nsCache[namespace] {
@@ -183,7 +247,11 @@ func (c *kialiCacheImpl) createCache(namespace string) bool {
 		}
 		return hasSynced
 	}
-	if synced := cache.WaitForCacheSync(c.stopChan[namespace], isSynced); !synced {
+	synced := cache.WaitForCacheSync(c.stopChan[namespace], isSynced)
+	for resourceType := range informer {
+		internalmetrics.SetCacheSynced(namespace, resourceType, synced)
+	}
+	if !synced {
 		c.stopChan[namespace] <- struct{}{}
 		log.Errorf("Kiali cache for [namespace: %s] sync failure", namespace)
 		return false
@@ -202,26 +270,33 @@ func (c *kialiCacheImpl) CheckNamespace(namespace string) bool {
 		return false
 	}
 
+	key := c.cacheKey(namespace)
 	c.cacheLock.RLock()
-	_, isNsCached := c.nsCache[namespace]
+	_, isNsCached := c.nsCache[key]
 	c.cacheLock.RUnlock()
 
 	if !isNsCached {
 		defer c.cacheLock.Unlock()
 		c.cacheLock.Lock()
-		return c.createCache(namespace)
+		return c.createCache(key)
 	}
-	return c.isKubernetesSynced(namespace) && c.isIstioSynced(namespace)
+	return c.isKubernetesSynced(key) && c.isIstioSynced(key)
 }
 
 // RefreshNamespace will delete the specific namespace's cache and create a new one.
+// In cluster-wide mode, the watchers deliver the changes: recreating the informers of the whole cluster on every
+// update would defeat the cache.
 func (c *kialiCacheImpl) RefreshNamespace(namespace string) {
+	if c.clusterScoped {
+		return
+	}
 	defer c.cacheLock.Unlock()
 	c.cacheLock.Lock()
 	if nsChan, exist := c.stopChan[namespace]; exist {
 		close(nsChan)
 		delete(c.stopChan, namespace)
 	}
+	c.clearStats(namespace, c.nsCache[namespace])
 	delete(c.nsCache, namespace)
 	c.createCache(namespace)
 }
@@ -235,7 +310,8 @@ func (c *kialiCacheImpl) Stop() {
 		delete(c.stopChan, namespace)
 	}
 	log.Infof("Clearing Kiali Cache")
-	for ns := range c.nsCache {
+	for ns, informers := range c.nsCache {
+		c.clearStats(ns, informers)
 		delete(c.nsCache, ns)
 	}
 }
@@ -2,10 +2,16 @@ package cache
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	batch_v1 "k8s.io/api/batch/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
 )
 
 func TestNewKialiCache_isCached(t *testing.T) {
@@ -26,3 +32,162 @@ func TestNewKialiCache_isCached(t *testing.T) {
 	assert.False(kialiCacheImpl.isCached("bbcdefghi"))
 	assert.True(kialiCacheImpl.isCached("galicia"))
 }
+
+func TestClusterScopedCache(t *testing.T) {
+	assert := assert.New(t)
+
+	k8sApi := fake.NewSimpleClientset(
+		&core_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1", Namespace: "bookinfo", Labels: map[string]string{"app": "reviews"}}},
+		&core_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "ratings-v1", Namespace: "bookinfo", Labels: map[string]string{"app": "ratings"}}},
+		&core_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "istiod", Namespace: "istio-system"}},
+		&batch_v1.Job{ObjectMeta: meta_v1.ObjectMeta{Name: "migration", Namespace: "bookinfo"}},
+	)
+	// The API server serves Jobs, neither ReplicationControllers nor CronJobs of batch/v1beta1
+	k8sApi.Resources = []*meta_v1.APIResourceList{
+		{GroupVersion: "batch/v1", APIResources: []meta_v1.APIResource{{Name: "jobs"}}},
+	}
+	kialiCacheImpl := kialiCacheImpl{
+		k8sApi:               k8sApi,
+		clusterScoped:        true,
+		cacheNamespaces:      []string{"bookinfo"},
+		cacheIstioTypes:      map[string]bool{},
+		cacheKubernetesTypes: servedKubernetesTypes(k8sApi),
+		stopChan:             make(map[string]chan struct{}),
+		nsCache:              make(map[string]typeCache),
+	}
+	defer kialiCacheImpl.Stop()
+
+	assert.True(kialiCacheImpl.isCached("other"))
+	assert.True(kialiCacheImpl.CheckNamespace("bookinfo"))
+	assert.True(kialiCacheImpl.CheckNamespace("istio-system"))
+	// A single set of informers watches all namespaces
+	assert.Len(kialiCacheImpl.nsCache, 1)
+
+	pods, err := kialiCacheImpl.GetPods("bookinfo", "app=reviews")
+	assert.NoError(err)
+	assert.Len(pods, 1)
+	assert.Equal("reviews-v1", pods[0].Name)
+	pods, err = kialiCacheImpl.GetPods("istio-system", "")
+	assert.NoError(err)
+	assert.Len(pods, 1)
+
+	jobs, err := kialiCacheImpl.GetJobs("bookinfo")
+	assert.NoError(err)
+	assert.Len(jobs, 1)
+	jobs, err = kialiCacheImpl.GetJobs("istio-system")
+	assert.NoError(err)
+	assert.Empty(jobs)
+
+	// The types not served have no informer
+	assert.True(kialiCacheImpl.CheckKubernetesType(kubernetes.JobType))
+	assert.False(kialiCacheImpl.CheckKubernetesType(kubernetes.CronJobType))
+	assert.True(kialiCacheImpl.CheckKubernetesType(kubernetes.PodType))
+	assert.NotContains(kialiCacheImpl.nsCache[""], kubernetes.CronJobType)
+	cronJobs, err := kialiCacheImpl.GetCronJobs("bookinfo")
+	assert.NoError(err)
+	assert.Empty(cronJobs)
+
+	// Refreshing a namespace doesn't recreate the cluster-wide informers
+	kialiCacheImpl.RefreshNamespace("bookinfo")
+	assert.Len(kialiCacheImpl.nsCache, 1)
+}
+
+func TestCacheStatusAndEviction(t *testing.T) {
+	assert := assert.New(t)
+
+	k8sApi := fake.NewSimpleClientset(
+		&core_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1", Namespace: "bookinfo", ResourceVersion: "1"}},
+		&core_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "ratings-v1", Namespace: "bookinfo", ResourceVersion: "1"}},
+		&core_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Name: "istiod", Namespace: "istio-system", ResourceVersion: "1"}},
+	)
+	kialiCacheImpl := kialiCacheImpl{
+		k8sApi:          k8sApi,
+		clusterScoped:   true,
+		cacheIstioTypes: map[string]bool{},
+		stopChan:        make(map[string]chan struct{}),
+		nsCache:         make(map[string]typeCache),
+	}
+	defer kialiCacheImpl.Stop()
+
+	assert.True(kialiCacheImpl.CheckNamespace("bookinfo"))
+	podStatus := func(namespace string) *models.CacheTypeStatus {
+		for _, nsStatus := range kialiCacheImpl.GetStatus().Namespaces {
+			for _, typeStatus := range nsStatus.Types {
+				if nsStatus.Namespace == namespace && typeStatus.Type == kubernetes.PodType {
+					return &typeStatus
+				}
+			}
+		}
+		return nil
+	}
+	// Events are delivered to the handlers asynchronously
+	assert.Eventually(func() bool {
+		return podStatus("bookinfo") != nil && podStatus("istio-system") != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	status := kialiCacheImpl.GetStatus()
+	assert.True(status.Enabled)
+	assert.True(status.ClusterScoped)
+	assert.Len(status.Namespaces, 2)
+	assert.Equal("bookinfo", status.Namespaces[0].Namespace)
+	assert.Equal("istio-system", status.Namespaces[1].Namespace)
+	pods := podStatus("bookinfo")
+	assert.True(pods.Synced)
+	assert.Equal(2, pods.Objects)
+	assert.NotNil(pods.LastEvent)
+	assert.True(pods.MemoryBytes > 0)
+
+	// The cluster-wide informers cannot be evicted
+	assert.False(kialiCacheImpl.EvictNamespace("bookinfo"))
+	assert.Len(kialiCacheImpl.nsCache, 1)
+
+	// Evicting a namespace drops its informers
+	kialiCacheImpl.Stop()
+	kialiCacheImpl.clusterScoped = false
+	kialiCacheImpl.cacheNamespaces = []string{"bookinfo", "istio-system"}
+	assert.True(kialiCacheImpl.CheckNamespace("bookinfo"))
+	assert.True(kialiCacheImpl.CheckNamespace("istio-system"))
+	assert.True(kialiCacheImpl.EvictNamespace("bookinfo"))
+	assert.Len(kialiCacheImpl.nsCache, 1)
+	assert.False(kialiCacheImpl.EvictNamespace("bookinfo"))
+
+	// The namespace is cached again on its next read
+	assert.True(kialiCacheImpl.CheckNamespace("bookinfo"))
+	assert.Eventually(func() bool {
+		pods := podStatus("bookinfo")
+		return pods != nil && pods.Objects == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestAccessCache(t *testing.T) {
+	assert := assert.New(t)
+
+	kialiCacheImpl := kialiCacheImpl{
+		tokenAccess:            make(map[string]map[string]accessReview),
+		tokenNamespaceDuration: time.Minute,
+	}
+
+	_, found := kialiCacheImpl.GetAccess("user", "bookinfo", "pods")
+	assert.False(found)
+
+	kialiCacheImpl.SetAccess("user", "bookinfo", "pods", true)
+	kialiCacheImpl.SetAccess("user", "bookinfo", "deployments", false)
+	allowed, found := kialiCacheImpl.GetAccess("user", "bookinfo", "pods")
+	assert.True(found)
+	assert.True(allowed)
+	allowed, found = kialiCacheImpl.GetAccess("user", "bookinfo", "deployments")
+	assert.True(found)
+	assert.False(allowed)
+	_, found = kialiCacheImpl.GetAccess("other", "bookinfo", "pods")
+	assert.False(found)
+
+	// The reviews expire with the namespaces of the tokens
+	kialiCacheImpl.tokenNamespaceDuration = 0
+	_, found = kialiCacheImpl.GetAccess("user", "bookinfo", "pods")
+	assert.False(found)
+
+	// The expired reviews are evicted
+	kialiCacheImpl.SetAccess("other", "bookinfo", "pods", true)
+	assert.Len(kialiCacheImpl.tokenAccess, 1)
+	assert.Contains(kialiCacheImpl.tokenAccess, "other")
+}
@@ -26,82 +26,54 @@ func (c *kialiCacheImpl) CheckIstioResource(resourceType string) bool {
 	return exist
 }
 
-func (c *kialiCacheImpl) createIstioInformers(namespace string, informer *typeCache) {
+// istioResourceTypes lists the Istio types read by Kiali
+var istioResourceTypes = []string{
 	// Networking API
-	if c.CheckIstioResource(kubernetes.VirtualServices) {
-		(*informer)[kubernetes.VirtualServices] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.VirtualServices, c.refreshDuration, namespace)
-	}
-	if c.CheckIstioResource(kubernetes.DestinationRules) {
-		(*informer)[kubernetes.DestinationRules] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.DestinationRules, c.refreshDuration, namespace)
-	}
-	if c.CheckIstioResource(kubernetes.Gateways) {
-		(*informer)[kubernetes.Gateways] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.Gateways, c.refreshDuration, namespace)
-	}
-	if c.CheckIstioResource(kubernetes.ServiceEntries) {
-		(*informer)[kubernetes.ServiceEntries] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.ServiceEntries, c.refreshDuration, namespace)
-	}
-	if c.CheckIstioResource(kubernetes.Sidecars) {
-		(*informer)[kubernetes.Sidecars] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.Sidecars, c.refreshDuration, namespace)
-	}
-	if c.CheckIstioResource(kubernetes.WorkloadEntries) {
-		(*informer)[kubernetes.WorkloadEntries] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.WorkloadEntries, c.refreshDuration, namespace)
-	}
-	if c.CheckIstioResource(kubernetes.WorkloadGroups) {
-		(*informer)[kubernetes.WorkloadGroups] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.WorkloadGroups, c.refreshDuration, namespace)
-	}
-	if c.CheckIstioResource(kubernetes.EnvoyFilters) {
-		(*informer)[kubernetes.EnvoyFilters] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.EnvoyFilters, c.refreshDuration, namespace)
-	}
-	if c.CheckIstioResource(kubernetes.PeerAuthentications) {
-		(*informer)[kubernetes.PeerAuthentications] = createIstioIndexInformer(c.istioSecurityGetter, kubernetes.PeerAuthentications, c.refreshDuration, namespace)
-	}
-	if c.CheckIstioResource(kubernetes.RequestAuthentications) {
-		(*informer)[kubernetes.RequestAuthentications] = createIstioIndexInformer(c.istioSecurityGetter, kubernetes.RequestAuthentications, c.refreshDuration, namespace)
-	}
-	if c.CheckIstioResource(kubernetes.AuthorizationPolicies) {
-		(*informer)[kubernetes.AuthorizationPolicies] = createIstioIndexInformer(c.istioSecurityGetter, kubernetes.AuthorizationPolicies, c.refreshDuration, namespace)
-	}
+	kubernetes.VirtualServices,
+	kubernetes.DestinationRules,
+	kubernetes.Gateways,
+	kubernetes.ServiceEntries,
+	kubernetes.Sidecars,
+	kubernetes.WorkloadEntries,
+	kubernetes.WorkloadGroups,
+	kubernetes.EnvoyFilters,
+	// Security API
+	kubernetes.PeerAuthentications,
+	kubernetes.RequestAuthentications,
+	kubernetes.AuthorizationPolicies,
 }
 
-func (c *kialiCacheImpl) isIstioSynced(namespace string) bool {
-	var isSynced bool
-	if nsCache, exist := c.nsCache[namespace]; exist {
-		isSynced = true
-		if c.CheckIstioResource(kubernetes.VirtualServices) {
-			isSynced = isSynced && nsCache[kubernetes.VirtualServices].HasSynced()
-		}
-		if c.CheckIstioResource(kubernetes.DestinationRules) {
-			isSynced = isSynced && nsCache[kubernetes.DestinationRules].HasSynced()
-		}
-		if c.CheckIstioResource(kubernetes.Gateways) {
-			isSynced = isSynced && nsCache[kubernetes.Gateways].HasSynced()
-		}
-		if c.CheckIstioResource(kubernetes.ServiceEntries) {
-			isSynced = isSynced && nsCache[kubernetes.ServiceEntries].HasSynced()
-		}
-		if c.CheckIstioResource(kubernetes.Sidecars) {
-			isSynced = isSynced && nsCache[kubernetes.Sidecars].HasSynced()
-		}
-		if c.CheckIstioResource(kubernetes.PeerAuthentications) {
-			isSynced = isSynced && nsCache[kubernetes.PeerAuthentications].HasSynced()
+func (c *kialiCacheImpl) createIstioInformers(namespace string, informer *typeCache) {
+	for _, resourceType := range istioResourceTypes {
+		if !c.CheckIstioResource(resourceType) {
+			continue
 		}
-		if c.CheckIstioResource(kubernetes.RequestAuthentications) {
-			isSynced = isSynced && nsCache[kubernetes.RequestAuthentications].HasSynced()
+		getter := c.istioNetworkingGetter
+		if kubernetes.ResourceTypesToAPI[resourceType] == kubernetes.SecurityGroupVersion.Group {
+			getter = c.istioSecurityGetter
 		}
-		if c.CheckIstioResource(kubernetes.AuthorizationPolicies) {
-			isSynced = isSynced && nsCache[kubernetes.AuthorizationPolicies].HasSynced()
+		(*informer)[resourceType] = createIstioIndexInformer(getter, resourceType, c.refreshDuration, namespace)
+	}
+}
+
+func (c *kialiCacheImpl) isIstioSynced(namespace string) bool {
+	nsCache, exist := c.namespaceCache(namespace)
+	if !exist {
+		return false
+	}
+	for _, resourceType := range istioResourceTypes {
+		if c.CheckIstioResource(resourceType) && !nsCache[resourceType].HasSynced() {
+			return false
 		}
-	} else {
-		isSynced = false
 	}
-	return isSynced
+	return true
 }
 
 func createIstioIndexInformer(getter cache.Getter, resourceType string, refreshDuration time.Duration, namespace string) cache.SharedIndexInformer {
 	return cache.NewSharedIndexInformer(cache.NewListWatchFromClient(getter, resourceType, namespace, fields.Everything()),
 		&kubernetes.GenericIstioObject{},
 		refreshDuration,
-		cache.Indexers{},
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 	)
 }
 
@@ -109,8 +81,11 @@ func (c *kialiCacheImpl) GetIstioObjects(namespace string, resourceType string,
 	if !c.CheckIstioResource(resourceType) {
 		return nil, fmt.Errorf("Kiali cache doesn't support [resourceType: %s]", resourceType)
 	}
-	if nsCache, nsOk := c.nsCache[namespace]; nsOk {
-		resources := nsCache[resourceType].GetStore().List()
+	if nsCache, nsOk := c.namespaceCache(namespace); nsOk {
+		resources, err := c.listNamespace(nsCache[resourceType], namespace)
+		if err != nil {
+			return nil, err
+		}
 		lenResources := len(resources)
 		if lenResources > 0 {
 			_, ok := resources[0].(*kubernetes.GenericIstioObject)
@@ -5,9 +5,13 @@ import (
 	"fmt"
 
 	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
+	batch_v1beta1 "k8s.io/api/batch/v1beta1"
 	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/informers"
+	kube "k8s.io/client-go/kubernetes"
 
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
@@ -27,9 +31,50 @@ type (
 		GetService(namespace string, name string) (*core_v1.Service, error)
 		GetPods(namespace, labelSelector string) ([]core_v1.Pod, error)
 		GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet, error)
+		GetReplicationControllers(namespace string) ([]core_v1.ReplicationController, error)
+		GetJobs(namespace string) ([]batch_v1.Job, error)
+		GetCronJobs(namespace string) ([]batch_v1beta1.CronJob, error)
+		// CheckKubernetesType tells if the objects of a type are cached: the optional types not served by the API
+		// server are not
+		CheckKubernetesType(resourceType string) bool
 	}
 )
 
+// optionalKubernetesTypes are the cached types whose API may not be served, e.g. CronJobs of batch/v1beta1 are no
+// longer served from Kubernetes 1.25
+var optionalKubernetesTypes = map[string]schema.GroupVersionResource{
+	kubernetes.ReplicationControllerType: {Version: "v1", Resource: "replicationcontrollers"},
+	kubernetes.JobType:                   {Group: "batch", Version: "v1", Resource: "jobs"},
+	kubernetes.CronJobType:               {Group: "batch", Version: "v1beta1", Resource: "cronjobs"},
+}
+
+// servedKubernetesTypes returns the optional types served by the API server
+func servedKubernetesTypes(k8sApi kube.Interface) map[string]bool {
+	served := make(map[string]bool, len(optionalKubernetesTypes))
+	for resourceType, gvr := range optionalKubernetesTypes {
+		resources, err := k8sApi.Discovery().ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+		if err == nil {
+			for _, resource := range resources.APIResources {
+				if resource.Name == gvr.Resource {
+					served[resourceType] = true
+					break
+				}
+			}
+		}
+		if !served[resourceType] {
+			log.Debugf("[Kiali Cache] [resourceType: %s] is not served by the API server, it is not cached", resourceType)
+		}
+	}
+	return served
+}
+
+func (c *kialiCacheImpl) CheckKubernetesType(resourceType string) bool {
+	if _, optional := optionalKubernetesTypes[resourceType]; optional {
+		return c.cacheKubernetesTypes[resourceType]
+	}
+	return true
+}
+
 func (c *kialiCacheImpl) createKubernetesInformers(namespace string, informer *typeCache) {
 	sharedInformers := informers.NewSharedInformerFactoryWithOptions(c.k8sApi, c.refreshDuration, informers.WithNamespace(namespace))
 	(*informer)[kubernetes.DeploymentType] = sharedInformers.Apps().V1().Deployments().Informer()
@@ -40,11 +85,20 @@ func (c *kialiCacheImpl) createKubernetesInformers(namespace string, informer *t
 	(*informer)[kubernetes.PodType] = sharedInformers.Core().V1().Pods().Informer()
 	(*informer)[kubernetes.ConfigMapType] = sharedInformers.Core().V1().ConfigMaps().Informer()
 	(*informer)[kubernetes.EndpointsType] = sharedInformers.Core().V1().Endpoints().Informer()
+	if c.CheckKubernetesType(kubernetes.ReplicationControllerType) {
+		(*informer)[kubernetes.ReplicationControllerType] = sharedInformers.Core().V1().ReplicationControllers().Informer()
+	}
+	if c.CheckKubernetesType(kubernetes.JobType) {
+		(*informer)[kubernetes.JobType] = sharedInformers.Batch().V1().Jobs().Informer()
+	}
+	if c.CheckKubernetesType(kubernetes.CronJobType) {
+		(*informer)[kubernetes.CronJobType] = sharedInformers.Batch().V1beta1().CronJobs().Informer()
+	}
 }
 
 func (c *kialiCacheImpl) isKubernetesSynced(namespace string) bool {
 	var isSynced bool
-	if nsCache, exist := c.nsCache[namespace]; exist {
+	if nsCache, exist := c.namespaceCache(namespace); exist {
 		isSynced = nsCache[kubernetes.DeploymentType].HasSynced() &&
 			nsCache[kubernetes.StatefulSetType].HasSynced() &&
 			nsCache[kubernetes.ReplicaSetType].HasSynced() &&
@@ -53,6 +107,12 @@ func (c *kialiCacheImpl) isKubernetesSynced(namespace string) bool {
 			nsCache[kubernetes.PodType].HasSynced() &&
 			nsCache[kubernetes.ConfigMapType].HasSynced() &&
 			nsCache[kubernetes.EndpointsType].HasSynced()
+		// The optional types not served have no informer
+		for resourceType := range optionalKubernetesTypes {
+			if informer, ok := nsCache[resourceType]; ok {
+				isSynced = isSynced && informer.HasSynced()
+			}
+		}
 	} else {
 		isSynced = false
 	}
@@ -60,7 +120,7 @@ func (c *kialiCacheImpl) isKubernetesSynced(namespace string) bool {
 }
 
 func (c *kialiCacheImpl) GetConfigMap(namespace, name string) (*core_v1.ConfigMap, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.namespaceCache(namespace); ok {
 		// Cache stores natively items with namespace/name pattern, we can skip the Indexer by name and make a direct call
 		key := namespace + "/" + name
 		obj, exist, err := nsCache[kubernetes.ConfigMapType].GetStore().GetByKey(key)
@@ -80,8 +140,11 @@ func (c *kialiCacheImpl) GetConfigMap(namespace, name string) (*core_v1.ConfigMa
 }
 
 func (c *kialiCacheImpl) GetDaemonSets(namespace string) ([]apps_v1.DaemonSet, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
-		daeset := nsCache[kubernetes.DaemonSetType].GetStore().List()
+	if nsCache, ok := c.namespaceCache(namespace); ok {
+		daeset, err := c.listNamespace(nsCache[kubernetes.DaemonSetType], namespace)
+		if err != nil {
+			return nil, err
+		}
 		lenDaeSet := len(daeset)
 		if lenDaeSet > 0 {
 			_, ok := daeset[0].(*apps_v1.DaemonSet)
@@ -100,7 +163,7 @@ func (c *kialiCacheImpl) GetDaemonSets(namespace string) ([]apps_v1.DaemonSet, e
 }
 
 func (c *kialiCacheImpl) GetDaemonSet(namespace, name string) (*apps_v1.DaemonSet, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.namespaceCache(namespace); ok {
 		// Cache stores natively items with namespace/name pattern, we can skip the Indexer by name and make a direct call
 		key := namespace + "/" + name
 		obj, exist, err := nsCache[kubernetes.DaemonSetType].GetStore().GetByKey(key)
@@ -120,8 +183,11 @@ func (c *kialiCacheImpl) GetDaemonSet(namespace, name string) (*apps_v1.DaemonSe
 }
 
 func (c *kialiCacheImpl) GetDeployments(namespace string) ([]apps_v1.Deployment, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
-		deps := nsCache[kubernetes.DeploymentType].GetStore().List()
+	if nsCache, ok := c.namespaceCache(namespace); ok {
+		deps, err := c.listNamespace(nsCache[kubernetes.DeploymentType], namespace)
+		if err != nil {
+			return nil, err
+		}
 		lenDeps := len(deps)
 		if lenDeps > 0 {
 			_, ok := deps[0].(*apps_v1.Deployment)
@@ -140,7 +206,7 @@ func (c *kialiCacheImpl) GetDeployments(namespace string) ([]apps_v1.Deployment,
 }
 
 func (c *kialiCacheImpl) GetDeployment(namespace, name string) (*apps_v1.Deployment, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.namespaceCache(namespace); ok {
 		// Cache stores natively items with namespace/name pattern, we can skip the Indexer by name and make a direct call
 		key := namespace + "/" + name
 		obj, exist, err := nsCache[kubernetes.DeploymentType].GetStore().GetByKey(key)
@@ -160,7 +226,7 @@ func (c *kialiCacheImpl) GetDeployment(namespace, name string) (*apps_v1.Deploym
 }
 
 func (c *kialiCacheImpl) GetEndpoints(namespace, name string) (*core_v1.Endpoints, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.namespaceCache(namespace); ok {
 		// Cache stores natively items with namespace/name pattern, we can skip the Indexer by name and make a direct call
 		key := namespace + "/" + name
 		obj, exist, err := nsCache[kubernetes.EndpointsType].GetStore().GetByKey(key)
@@ -180,8 +246,11 @@ func (c *kialiCacheImpl) GetEndpoints(namespace, name string) (*core_v1.Endpoint
 }
 
 func (c *kialiCacheImpl) GetStatefulSets(namespace string) ([]apps_v1.StatefulSet, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
-		ss := nsCache[kubernetes.StatefulSetType].GetStore().List()
+	if nsCache, ok := c.namespaceCache(namespace); ok {
+		ss, err := c.listNamespace(nsCache[kubernetes.StatefulSetType], namespace)
+		if err != nil {
+			return nil, err
+		}
 		lenSs := len(ss)
 		if lenSs > 0 {
 			_, ok := ss[0].(*apps_v1.StatefulSet)
@@ -200,7 +269,7 @@ func (c *kialiCacheImpl) GetStatefulSets(namespace string) ([]apps_v1.StatefulSe
 }
 
 func (c *kialiCacheImpl) GetStatefulSet(namespace, name string) (*apps_v1.StatefulSet, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.namespaceCache(namespace); ok {
 		// Cache stores natively items with namespace/name pattern, we can skip the Indexer by name and make a direct call
 		key := namespace + "/" + name
 		obj, exist, err := nsCache[kubernetes.StatefulSetType].GetStore().GetByKey(key)
@@ -220,8 +289,11 @@ func (c *kialiCacheImpl) GetStatefulSet(namespace, name string) (*apps_v1.Statef
 }
 
 func (c *kialiCacheImpl) GetServices(namespace string, selectorLabels map[string]string) ([]core_v1.Service, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
-		services := nsCache[kubernetes.ServiceType].GetStore().List()
+	if nsCache, ok := c.namespaceCache(namespace); ok {
+		services, err := c.listNamespace(nsCache[kubernetes.ServiceType], namespace)
+		if err != nil {
+			return nil, err
+		}
 		lenServices := len(services)
 		if lenServices > 0 {
 			_, ok := services[0].(*core_v1.Service)
@@ -251,7 +323,7 @@ func (c *kialiCacheImpl) GetServices(namespace string, selectorLabels map[string
 }
 
 func (c *kialiCacheImpl) GetService(namespace, name string) (*core_v1.Service, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
+	if nsCache, ok := c.namespaceCache(namespace); ok {
 		// Cache stores natively items with namespace/name pattern, we can skip the Indexer by name and make a direct call
 		key := namespace + "/" + name
 		obj, exist, err := nsCache[kubernetes.ServiceType].GetStore().GetByKey(key)
@@ -271,8 +343,11 @@ func (c *kialiCacheImpl) GetService(namespace, name string) (*core_v1.Service, e
 }
 
 func (c *kialiCacheImpl) GetPods(namespace, labelSelector string) ([]core_v1.Pod, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
-		pods := nsCache[kubernetes.PodType].GetStore().List()
+	if nsCache, ok := c.namespaceCache(namespace); ok {
+		pods, err := c.listNamespace(nsCache[kubernetes.PodType], namespace)
+		if err != nil {
+			return nil, err
+		}
 		lenPods := len(pods)
 		if lenPods > 0 {
 			_, ok := pods[0].(*core_v1.Pod)
@@ -304,8 +379,11 @@ func (c *kialiCacheImpl) GetPods(namespace, labelSelector string) ([]core_v1.Pod
 }
 
 func (c *kialiCacheImpl) GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet, error) {
-	if nsCache, ok := c.nsCache[namespace]; ok {
-		reps := nsCache[kubernetes.ReplicaSetType].GetStore().List()
+	if nsCache, ok := c.namespaceCache(namespace); ok {
+		reps, err := c.listNamespace(nsCache[kubernetes.ReplicaSetType], namespace)
+		if err != nil {
+			return nil, err
+		}
 		lenReps := len(reps)
 		if lenReps > 0 {
 			_, ok := reps[0].(*apps_v1.ReplicaSet)
@@ -322,3 +400,72 @@ func (c *kialiCacheImpl) GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet,
 	}
 	return []apps_v1.ReplicaSet{}, nil
 }
+
+func (c *kialiCacheImpl) GetReplicationControllers(namespace string) ([]core_v1.ReplicationController, error) {
+	if nsCache, ok := c.namespaceCache(namespace); ok && nsCache[kubernetes.ReplicationControllerType] != nil {
+		rcs, err := c.listNamespace(nsCache[kubernetes.ReplicationControllerType], namespace)
+		if err != nil {
+			return nil, err
+		}
+		lenRcs := len(rcs)
+		if lenRcs > 0 {
+			_, ok := rcs[0].(*core_v1.ReplicationController)
+			if !ok {
+				return nil, errors.New("bad ReplicationController type found in cache")
+			}
+			nsRcs := make([]core_v1.ReplicationController, lenRcs)
+			for i, rc := range rcs {
+				nsRcs[i] = *(rc.(*core_v1.ReplicationController))
+			}
+			log.Tracef("[Kiali Cache] Get [resource: ReplicationController] for [namespace: %s] = %d", namespace, lenRcs)
+			return nsRcs, nil
+		}
+	}
+	return []core_v1.ReplicationController{}, nil
+}
+
+func (c *kialiCacheImpl) GetJobs(namespace string) ([]batch_v1.Job, error) {
+	if nsCache, ok := c.namespaceCache(namespace); ok && nsCache[kubernetes.JobType] != nil {
+		jobs, err := c.listNamespace(nsCache[kubernetes.JobType], namespace)
+		if err != nil {
+			return nil, err
+		}
+		lenJobs := len(jobs)
+		if lenJobs > 0 {
+			_, ok := jobs[0].(*batch_v1.Job)
+			if !ok {
+				return nil, errors.New("bad Job type found in cache")
+			}
+			nsJobs := make([]batch_v1.Job, lenJobs)
+			for i, job := range jobs {
+				nsJobs[i] = *(job.(*batch_v1.Job))
+			}
+			log.Tracef("[Kiali Cache] Get [resource: Job] for [namespace: %s] = %d", namespace, lenJobs)
+			return nsJobs, nil
+		}
+	}
+	return []batch_v1.Job{}, nil
+}
+
+func (c *kialiCacheImpl) GetCronJobs(namespace string) ([]batch_v1beta1.CronJob, error) {
+	if nsCache, ok := c.namespaceCache(namespace); ok && nsCache[kubernetes.CronJobType] != nil {
+		cjs, err := c.listNamespace(nsCache[kubernetes.CronJobType], namespace)
+		if err != nil {
+			return nil, err
+		}
+		lenCjs := len(cjs)
+		if lenCjs > 0 {
+			_, ok := cjs[0].(*batch_v1beta1.CronJob)
+			if !ok {
+				return nil, errors.New("bad CronJob type found in cache")
+			}
+			nsCjs := make([]batch_v1beta1.CronJob, lenCjs)
+			for i, cj := range cjs {
+				nsCjs[i] = *(cj.(*batch_v1beta1.CronJob))
+			}
+			log.Tracef("[Kiali Cache] Get [resource: CronJob] for [namespace: %s] = %d", namespace, lenCjs)
+			return nsCjs, nil
+		}
+	}
+	return []batch_v1beta1.CronJob{}, nil
+}
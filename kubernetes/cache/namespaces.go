@@ -65,4 +65,7 @@ func (c *kialiCacheImpl) RefreshTokenNamespaces() {
 	defer c.tokenLock.Unlock()
 	c.tokenLock.Lock()
 	c.tokenNamespaces = make(map[string]namespaceCache)
+	c.accessLock.Lock()
+	c.tokenAccess = make(map[string]map[string]accessReview)
+	c.accessLock.Unlock()
 }
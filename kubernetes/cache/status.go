@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+type (
+	StatusCache interface {
+		// Report the informers of the cached namespaces
+		GetStatus() models.CacheStatus
+		// Drop a namespace's cache, it is created again on the next read of the namespace
+		EvictNamespace(namespace string) bool
+	}
+
+	// typeStats holds the events received by the informer of a type, for a namespace
+	typeStats struct {
+		memory    int64
+		lastEvent time.Time
+	}
+)
+
+// GetStatus reports, per namespace and type, the state of the informers and the objects they hold.
+// In cluster-wide mode, the namespaces are those the informers received objects for.
+func (c *kialiCacheImpl) GetStatus() models.CacheStatus {
+	status := models.CacheStatus{
+		Enabled:       true,
+		ClusterScoped: c.clusterScoped,
+		Namespaces:    []models.CacheNamespaceStatus{},
+	}
+	c.cacheLock.RLock()
+	defer c.cacheLock.RUnlock()
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+
+	for key, informers := range c.nsCache {
+		namespaces := []string{key}
+		if c.clusterScoped {
+			namespaces = make([]string, 0, len(c.stats))
+			for namespace := range c.stats {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+		for _, namespace := range namespaces {
+			nsStatus := models.CacheNamespaceStatus{Namespace: namespace, Types: []models.CacheTypeStatus{}}
+			for resourceType, informer := range informers {
+				typeStatus := models.CacheTypeStatus{Type: resourceType, Synced: informer.HasSynced()}
+				if objects, err := c.listNamespace(informer, namespace); err == nil {
+					typeStatus.Objects = len(objects)
+				} else {
+					log.Errorf("Error listing the cached [resource: %s] for [namespace: %s]: %s", resourceType, namespace, err)
+				}
+				if stats, ok := c.stats[namespace][resourceType]; ok {
+					lastEvent := stats.lastEvent
+					typeStatus.LastEvent = &lastEvent
+					typeStatus.MemoryBytes = stats.memory
+				}
+				nsStatus.Types = append(nsStatus.Types, typeStatus)
+			}
+			sort.Slice(nsStatus.Types, func(i, j int) bool {
+				return nsStatus.Types[i].Type < nsStatus.Types[j].Type
+			})
+			status.Namespaces = append(status.Namespaces, nsStatus)
+		}
+	}
+	sort.Slice(status.Namespaces, func(i, j int) bool {
+		return status.Namespaces[i].Namespace < status.Namespaces[j].Namespace
+	})
+	return status
+}
+
+// EvictNamespace stops the informers holding the namespace and drops their objects. It returns false when the namespace
+// is not cached. In cluster-wide mode, the informers hold all namespaces and are not evicted: dropping them would reload
+// the objects of the whole cluster.
+func (c *kialiCacheImpl) EvictNamespace(namespace string) bool {
+	if c.clusterScoped {
+		return false
+	}
+	defer c.cacheLock.Unlock()
+	c.cacheLock.Lock()
+	informers, exist := c.nsCache[namespace]
+	if !exist {
+		return false
+	}
+	if nsChan, exist := c.stopChan[namespace]; exist {
+		close(nsChan)
+		delete(c.stopChan, namespace)
+	}
+	c.clearStats(namespace, informers)
+	delete(c.nsCache, namespace)
+	log.Infof("Kiali cache for [namespace: %s] evicted", namespace)
+	return true
+}
+
+// statsEventHandler records the events of the informer of a type. Events delivered after the informer is stopped
+// are ignored, they would be counted in the stats of the informer replacing it.
+func (c *kialiCacheImpl) statsEventHandler(resourceType string, stopCh <-chan struct{}) cache.ResourceEventHandler {
+	isStopped := func() bool {
+		select {
+		case <-stopCh:
+			return true
+		default:
+			return false
+		}
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if !isStopped() {
+				c.recordEvent(resourceType, obj, 1, estimateSize(obj))
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if isStopped() || sameResourceVersion(oldObj, newObj) {
+				// Periodic resyncs don't change the objects
+				return
+			}
+			c.recordEvent(resourceType, newObj, 0, estimateSize(newObj)-estimateSize(oldObj))
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if !isStopped() {
+				c.recordEvent(resourceType, obj, -1, -estimateSize(obj))
+			}
+		},
+	}
+}
+
+func (c *kialiCacheImpl) recordEvent(resourceType string, obj interface{}, objectsDelta int, memoryDelta int64) {
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	namespace := objMeta.GetNamespace()
+	now := time.Now()
+
+	c.statsLock.Lock()
+	if c.stats == nil {
+		c.stats = make(map[string]map[string]*typeStats)
+	}
+	if _, exist := c.stats[namespace]; !exist {
+		c.stats[namespace] = make(map[string]*typeStats)
+	}
+	stats, exist := c.stats[namespace][resourceType]
+	if !exist {
+		stats = &typeStats{}
+		c.stats[namespace][resourceType] = stats
+	}
+	stats.memory += memoryDelta
+	stats.lastEvent = now
+	c.statsLock.Unlock()
+
+	internalmetrics.UpdateCacheObjects(namespace, resourceType, objectsDelta, memoryDelta, now)
+}
+
+// clearStats drops the stats and internal metrics of the informers of a cache key: the namespace itself, or all
+// namespaces in cluster-wide mode
+func (c *kialiCacheImpl) clearStats(key string, informers typeCache) {
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+	namespaces := []string{key}
+	if c.clusterScoped {
+		for namespace := range c.stats {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	for _, namespace := range namespaces {
+		delete(c.stats, namespace)
+		for resourceType := range informers {
+			internalmetrics.DeleteCacheMetrics(namespace, resourceType)
+		}
+	}
+}
+
+func sameResourceVersion(oldObj, newObj interface{}) bool {
+	oldMeta, err := meta.Accessor(oldObj)
+	if err != nil {
+		return false
+	}
+	newMeta, err := meta.Accessor(newObj)
+	if err != nil {
+		return false
+	}
+	return oldMeta.GetResourceVersion() != "" && oldMeta.GetResourceVersion() == newMeta.GetResourceVersion()
+}
+
+// estimateSize estimates the memory held by a cached object from its protobuf size, or from its JSON size for the
+// Istio objects
+func estimateSize(obj interface{}) int64 {
+	if sized, ok := obj.(interface{ Size() int }); ok {
+		return int64(sized.Size())
+	}
+	if bytes, err := json.Marshal(obj); err == nil {
+		return int64(len(bytes))
+	}
+	return 0
+}
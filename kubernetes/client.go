@@ -54,6 +54,7 @@ type K8SClient struct {
 	istioNetworkingApi *rest.RESTClient
 	istioSecurityApi   *rest.RESTClient
 	iter8Api           *rest.RESTClient
+	monitoringApi      *rest.RESTClient
 	// Used in REST queries after bump to client-go v0.20.x
 	ctx context.Context
 	// isOpenShift private variable will check if kiali is deployed under an OpenShift cluster or not
@@ -66,6 +67,11 @@ type K8SClient struct {
 	// See iter8.go#IsIter8Api() for more details
 	isIter8Api *bool
 
+	// isMonitoringApi private variable will check if the MonitoringDashboard CRD is present.
+	// It is represented as a pointer to include the initialization phase.
+	// See dashboards.go#IsMonitoringApi() for more details
+	isMonitoringApi *bool
+
 	// networkingResources private variable will check which resources kiali has access to from networking.istio.io group
 	// It is represented as a pointer to include the initialization phase.
 	// See istio_details_service.go#hasNetworkingResource() for more details.
@@ -92,6 +98,11 @@ func (client *K8SClient) GetIstioSecurityApi() *rest.RESTClient {
 	return client.istioSecurityApi
 }
 
+// GetMonitoringApi returns the Kiali monitoring rest client
+func (client *K8SClient) GetMonitoringApi() *rest.RESTClient {
+	return client.monitoringApi
+}
+
 // GetToken returns the BearerToken used from the config
 func (client *K8SClient) GetToken() string {
 	return client.token
@@ -204,9 +215,14 @@ func NewClientFromConfig(config *rest.Config) (*K8SClient, error) {
 				scheme.AddKnownTypeWithName(Iter8GroupVersion.WithKind(rt.collectionKind), &Iter8ExperimentObjectList{})
 			}
 
+			// Register Kiali monitoring types
+			scheme.AddKnownTypeWithName(MonitoringGroupVersion.WithKind(MonitoringDashboardType), &GenericIstioObject{})
+			scheme.AddKnownTypeWithName(MonitoringGroupVersion.WithKind(MonitoringDashboardTypeList), &GenericIstioObjectList{})
+
 			meta_v1.AddToGroupVersion(scheme, NetworkingGroupVersion)
 			meta_v1.AddToGroupVersion(scheme, SecurityGroupVersion)
 			meta_v1.AddToGroupVersion(scheme, Iter8GroupVersion)
+			meta_v1.AddToGroupVersion(scheme, MonitoringGroupVersion)
 			return nil
 		})
 
@@ -230,9 +246,15 @@ func NewClientFromConfig(config *rest.Config) (*K8SClient, error) {
 		return nil, err
 	}
 
+	monitoringApi, err := newClientForAPI(config, MonitoringGroupVersion, types)
+	if err != nil {
+		return nil, err
+	}
+
 	client.istioNetworkingApi = istioNetworkingAPI
 	client.istioSecurityApi = istioSecurityApi
 	client.iter8Api = iter8Api
+	client.monitoringApi = monitoringApi
 	client.ctx = context.Background()
 	return &client, nil
 }
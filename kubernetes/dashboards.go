@@ -0,0 +1,14 @@
+package kubernetes
+
+// IsMonitoringApi tells whether the MonitoringDashboard CRD is installed
+func (in *K8SClient) IsMonitoringApi() bool {
+	if in.isMonitoringApi == nil {
+		isMonitoringApi := false
+		_, err := in.k8s.RESTClient().Get().AbsPath("/apis/" + MonitoringGroupVersion.Group).Do(in.ctx).Raw()
+		if err == nil {
+			isMonitoringApi = true
+		}
+		in.isMonitoringApi = &isMonitoringApi
+	}
+	return *in.isMonitoringApi
+}
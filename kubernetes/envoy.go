@@ -112,6 +112,32 @@ type FilterChainMatch struct {
 	} `mapstructure:"prefix_ranges"`
 }
 
+type SecretDump struct {
+	DynamicActiveSecrets []EnvoySecretWrapper `mapstructure:"dynamic_active_secrets"`
+	StaticSecrets        []EnvoySecretWrapper `mapstructure:"static_secrets"`
+}
+
+type EnvoySecretWrapper struct {
+	Name        string      `mapstructure:"name"`
+	LastUpdated string      `mapstructure:"last_updated"`
+	Secret      EnvoySecret `mapstructure:"secret"`
+}
+
+type EnvoySecret struct {
+	Name           string `mapstructure:"name"`
+	TLSCertificate *struct {
+		CertificateChain *EnvoyDataSource `mapstructure:"certificate_chain,omitempty"`
+	} `mapstructure:"tls_certificate,omitempty"`
+	ValidationContext *struct {
+		TrustedCA *EnvoyDataSource `mapstructure:"trusted_ca,omitempty"`
+	} `mapstructure:"validation_context,omitempty"`
+}
+
+// EnvoyDataSource holds base64 encoded inline data
+type EnvoyDataSource struct {
+	InlineBytes string `mapstructure:"inline_bytes"`
+}
+
 func (cd *ConfigDump) GetListeners() (*ListenerDump, error) {
 	listenersDumpRaw := cd.GetConfig("type.googleapis.com/envoy.admin.v3.ListenersConfigDump")
 	var listenersDump ListenerDump
@@ -130,6 +156,12 @@ func (cd *ConfigDump) GetRoutes() (*RouteDump, error) {
 	return &routeDump, mapstructure.Decode(routeDumpRaw, &routeDump)
 }
 
+func (cd *ConfigDump) GetSecrets() (*SecretDump, error) {
+	secretDumpRaw := cd.GetConfig("type.googleapis.com/envoy.admin.v3.SecretsConfigDump")
+	var secretDump SecretDump
+	return &secretDump, mapstructure.Decode(secretDumpRaw, &secretDump)
+}
+
 func (cd *ConfigDump) GetConfig(objectType string) map[string]interface{} {
 	for _, configRaw := range cd.Configs {
 		conf, ok := configRaw.(map[string]interface{})
@@ -377,6 +377,17 @@ func (in *K8SClient) EnvoyForward(namespace, podName, path string) ([]byte, erro
 	return resp, err
 }
 
+// HasIstioResource tells if the API server serves the Istio resource type
+func (in *K8SClient) HasIstioResource(resourceType string) bool {
+	switch ResourceTypesToAPI[resourceType] {
+	case NetworkingGroupVersion.Group:
+		return in.hasNetworkingResource(resourceType)
+	case SecurityGroupVersion.Group:
+		return in.hasSecurityResource(resourceType)
+	}
+	return false
+}
+
 func (in *K8SClient) hasNetworkingResource(resource string) bool {
 	return in.getNetworkingResources()[resource]
 }
@@ -78,6 +78,12 @@ const (
 	Iter8ExperimentType     = "Experiment"
 	Iter8ExperimentTypeList = "ExperimentList"
 	Iter8ConfigMap          = "iter8config-metrics"
+
+	// Kiali monitoring types
+
+	MonitoringDashboards        = "monitoringdashboards"
+	MonitoringDashboardType     = "MonitoringDashboard"
+	MonitoringDashboardTypeList = "MonitoringDashboardList"
 )
 
 var (
@@ -100,6 +106,12 @@ var (
 	}
 	ApiIter8Version = Iter8GroupVersion.Group + "/" + Iter8GroupVersion.Version
 
+	MonitoringGroupVersion = schema.GroupVersion{
+		Group:   "monitoring.kiali.io",
+		Version: "v1alpha1",
+	}
+	ApiMonitoringVersion = MonitoringGroupVersion.Group + "/" + MonitoringGroupVersion.Version
+
 	networkingTypes = []struct {
 		objectKind     string
 		collectionKind string
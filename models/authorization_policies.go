@@ -0,0 +1,20 @@
+package models
+
+import (
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// GeneratedAuthorizationPolicies are the least-privilege AuthorizationPolicies of a namespace, permitting exactly
+// the traffic observed over an interval
+type GeneratedAuthorizationPolicies struct {
+	Namespace    string `json:"namespace"`
+	RateInterval string `json:"rateInterval"`
+	// One ALLOW policy per workload that received traffic, plus the allow-nothing policy when requested
+	Policies []*kubernetes.GenericIstioObject `json:"policies"`
+	// The policies, as a multi-document YAML for review
+	YAML string `json:"yaml"`
+	// Observed traffic that the policies can't permit, e.g. plain text traffic without peer identity
+	Warnings []string `json:"warnings"`
+	// Applied is true when the policies have been created or updated in the cluster
+	Applied bool `json:"applied"`
+}
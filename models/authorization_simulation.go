@@ -0,0 +1,74 @@
+package models
+
+// Authorization decisions
+const (
+	AuthorizationAllow  = "ALLOW"
+	AuthorizationDeny   = "DENY"
+	AuthorizationCustom = "CUSTOM"
+	// AuthorizationIndeterminate is the decision when a policy that could change it has conditions that can't be
+	// simulated
+	AuthorizationIndeterminate = "INDETERMINATE"
+)
+
+// AuthorizationSource is the caller of a simulated request. When Workload is set, the service account and the
+// sidecar presence are resolved from its pods. Without Namespace, the caller is considered outside of the mesh.
+type AuthorizationSource struct {
+	Namespace      string `json:"namespace,omitempty"`
+	Workload       string `json:"workload,omitempty"`
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// JWT identity of the request, "<iss>/<sub>", when authenticated by a RequestAuthentication
+	RequestPrincipal string `json:"requestPrincipal,omitempty"`
+	IP               string `json:"ip,omitempty"`
+}
+
+// AuthorizationDestination is the workload receiving a simulated request
+type AuthorizationDestination struct {
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload"`
+}
+
+// AuthorizationRequestAttributes are the attributes of a simulated request
+type AuthorizationRequestAttributes struct {
+	Host    string            `json:"host,omitempty"`
+	Method  string            `json:"method,omitempty"`
+	Path    string            `json:"path,omitempty"`
+	Port    int               `json:"port,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// AuthorizationSimulationRequest asks whether a source can call a destination workload with a given request
+type AuthorizationSimulationRequest struct {
+	Source      AuthorizationSource            `json:"source"`
+	Destination AuthorizationDestination       `json:"destination"`
+	Request     AuthorizationRequestAttributes `json:"request"`
+}
+
+// AuthorizationPolicyEvaluation is the result of the evaluation of an AuthorizationPolicy applying to the destination
+type AuthorizationPolicyEvaluation struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Action    string `json:"action"`
+	Matched   bool   `json:"matched"`
+	// Indeterminate is true when the policy doesn't match for sure, but a rule has conditions that can't be simulated
+	Indeterminate bool `json:"indeterminate"`
+	// Index of the first matching rule
+	MatchedRule *int `json:"matchedRule,omitempty"`
+	// Deciding is true for the policies that determined the decision
+	Deciding bool `json:"deciding"`
+	// Reasons why rules didn't match
+	Details []string `json:"details"`
+}
+
+// AuthorizationSimulation is the decision of a simulated request, with the policies that led to it
+type AuthorizationSimulation struct {
+	Decision string `json:"decision"`
+	Reason   string `json:"reason"`
+	// Peer identity of the source, empty when the connection is not mutual TLS
+	SourcePrincipal string `json:"sourcePrincipal"`
+	// Effective PeerAuthentication mode of the destination: STRICT, PERMISSIVE or DISABLE
+	MtlsMode string                          `json:"mtlsMode"`
+	Mtls     bool                            `json:"mtls"`
+	Policies []AuthorizationPolicyEvaluation `json:"policies"`
+	// Warnings about the parts of the policies that can't be simulated
+	Warnings []string `json:"warnings"`
+}
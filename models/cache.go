@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// CacheStatus describes the content of the Kiali cache
+type CacheStatus struct {
+	// Whether the Kiali cache is enabled
+	// required: true
+	Enabled bool `json:"enabled"`
+	// Whether the informers watch all namespaces
+	// required: true
+	ClusterScoped bool `json:"clusterScoped"`
+	// The cached namespaces
+	// required: true
+	Namespaces []CacheNamespaceStatus `json:"namespaces"`
+}
+
+// CacheNamespaceStatus describes the cached types of a namespace
+type CacheNamespaceStatus struct {
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+	// required: true
+	Types []CacheTypeStatus `json:"types"`
+}
+
+// CacheTypeStatus describes the informer of a type in a namespace
+type CacheTypeStatus struct {
+	// The cached type, i.e. Deployment or virtualservices
+	// required: true
+	Type string `json:"type"`
+	// Whether the informer completed its initial list
+	// required: true
+	Synced bool `json:"synced"`
+	// The number of cached objects
+	// required: true
+	Objects int `json:"objects"`
+	// The time of the last add, update or delete event received by the informer
+	LastEvent *time.Time `json:"lastEvent,omitempty"`
+	// An estimate of the memory held by the cached objects, in bytes
+	// required: true
+	MemoryBytes int64 `json:"memoryBytes"`
+}
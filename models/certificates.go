@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// Certificate types of a proxy
+const (
+	CertificateTypeWorkload = "workload"
+	CertificateTypeRoot     = "root"
+)
+
+// ProxyCertificate is a certificate loaded by the proxy of a pod, as reported by its Envoy secrets config dump
+type ProxyCertificate struct {
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload"`
+	Pod       string `json:"pod"`
+	// Name of the Envoy secret, e.g. "default" for the workload certificate, "ROOTCA" for the root certificate
+	SecretName string `json:"secretName"`
+	// One of: workload, root
+	Type string `json:"type"`
+	// SPIFFE identity, e.g. "spiffe://cluster.local/ns/bookinfo/sa/default"
+	Identity        string    `json:"identity"`
+	TrustDomain     string    `json:"trustDomain"`
+	Issuer          string    `json:"issuer"`
+	SerialNumber    string    `json:"serialNumber"`
+	ValidFrom       time.Time `json:"validFrom"`
+	ValidUntil      time.Time `json:"validUntil"`
+	DaysUntilExpiry float64   `json:"daysUntilExpiry"`
+	// The certificate expires within the reporting window
+	ExpiringSoon bool `json:"expiringSoon"`
+	// The trust domain of the workload certificate differs from the mesh trust domain
+	UnexpectedTrustDomain bool `json:"unexpectedTrustDomain"`
+}
+
+// ProxyCertificatesError is a failure to read the certificates of a pod
+type ProxyCertificatesError struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Message   string `json:"message"`
+}
+
+// CertificatesReport lists the certificates of the proxies of a namespace, or of the mesh, and those needing attention
+type CertificatesReport struct {
+	ExpectedTrustDomain   string                   `json:"expectedTrustDomain"`
+	ExpiringWithin        string                   `json:"expiringWithin"`
+	Certificates          []ProxyCertificate       `json:"certificates"`
+	ExpiringSoon          int                      `json:"expiringSoon"`
+	UnexpectedTrustDomain int                      `json:"unexpectedTrustDomain"`
+	Errors                []ProxyCertificatesError `json:"errors"`
+	// Warnings about the namespaces whose proxies couldn't be listed
+	Warnings []string `json:"warnings"`
+}
@@ -12,6 +12,7 @@ import (
 type DashboardQuery struct {
 	prometheus.RangeQuery
 	Namespace         string
+	Workload          string
 	LabelsFilters     map[string]string
 	AdditionalLabels  []Aggregation
 	RawDataAggregator string
@@ -106,6 +107,18 @@ type DashboardRef struct {
 	Title    string `json:"title"`
 }
 
+// DashboardValidationError reports a dashboard discovered at runtime that could not be loaded
+type DashboardValidationError struct {
+	// Kind of the resource holding the dashboard: MonitoringDashboard or ConfigMap
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	// Name of the resource holding the dashboard
+	Name string `json:"name"`
+	// Name of the dashboard, empty when the resource could not be parsed
+	Dashboard string `json:"dashboard"`
+	Message   string `json:"message"`
+}
+
 func buildIstioAggregations(local, remote string) []Aggregation {
 	aggs := []Aggregation{
 		{
@@ -1,6 +1,22 @@
 package models
 
+import (
+	"github.com/kiali/kiali/config/dashboards"
+)
+
 // GrafanaInfo provides information to access Grafana dashboards
 type GrafanaInfo struct {
 	ExternalLinks []ExternalLink `json:"externalLinks"`
 }
+
+// GrafanaDashboardImport is a Grafana dashboard converted into a custom dashboard
+type GrafanaDashboardImport struct {
+	// Name of the custom dashboard
+	Name string `json:"name"`
+	// The custom dashboard, as a YAML list for the config or a ConfigMap
+	YAML string `json:"yaml"`
+	// Number of panels converted into charts
+	Charts       int                            `json:"charts"`
+	Untranslated []dashboards.UntranslatedPanel `json:"untranslated"`
+	Warnings     []string                       `json:"warnings"`
+}
@@ -0,0 +1,56 @@
+package models
+
+// Actions of a proposed Istio config change
+const (
+	IstioConfigChangeDelete = "delete"
+	IstioConfigChangePatch  = "patch"
+)
+
+// Aspects of the traffic an Istio object acts on
+const (
+	ImpactRouting  = "routing"
+	ImpactSecurity = "security"
+	ImpactSidecar  = "sidecar"
+)
+
+// IstioConfigChange is a proposed change of an Istio object
+type IstioConfigChange struct {
+	// delete or patch
+	Action string `json:"action"`
+	// JSON merge patch, as sent to update the object
+	Patch string `json:"patch,omitempty"`
+}
+
+// ImpactedService is a host whose traffic an Istio config change would affect
+type ImpactedService struct {
+	Host string `json:"host"`
+	// Namespace and name of the service, unset for hosts outside the registry of services
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Aspect    string `json:"aspect"`
+	// Requests and TCP connections per second to the host
+	Rate float64 `json:"rate"`
+}
+
+// ImpactedWorkload is a workload whose proxy configuration an Istio config change would affect
+type ImpactedWorkload struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Aspect    string `json:"aspect"`
+	// Requests and TCP connections per second received by the workload, or sent for the sidecar aspect
+	Rate float64 `json:"rate"`
+}
+
+// IstioConfigImpact is the blast radius of a proposed Istio config change
+type IstioConfigImpact struct {
+	Namespace    string             `json:"namespace"`
+	ObjectType   string             `json:"objectType"`
+	Name         string             `json:"name"`
+	Change       IstioConfigChange  `json:"change"`
+	RateInterval string             `json:"rateInterval"`
+	Services     []ImpactedService  `json:"services"`
+	Workloads    []ImpactedWorkload `json:"workloads"`
+	// Checks that would appear on the other objects of the namespace
+	NewValidations IstioValidations `json:"newValidations"`
+	Warnings       []string         `json:"warnings"`
+}
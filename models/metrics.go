@@ -28,6 +28,7 @@ type IstioMetricsQuery struct {
 	Reporter        string // source | destination, defaults to source if not provided
 	Aggregate       string
 	AggregateValue  string
+	Exemplars       bool // when true, exemplars are fetched for the request duration histogram
 }
 
 // FillDefaults fills the struct with default parameters
@@ -118,6 +119,15 @@ type Metric struct {
 	Datapoints []Datapoint       `json:"datapoints"`
 	Stat       string            `json:"stat,omitempty"`
 	Name       string            `json:"name"`
+	Exemplars  []Exemplar        `json:"exemplars,omitempty"`
+}
+
+// Exemplar is a sample of a series linked to a trace. Timestamp is in milliseconds.
+type Exemplar struct {
+	Labels    map[string]string `json:"labels"`
+	TraceID   string            `json:"traceId,omitempty"`
+	Timestamp int64             `json:"timestamp"`
+	Value     float64           `json:"value"`
 }
 
 type Datapoint struct {
@@ -237,3 +247,57 @@ func convertSamplePair(from *pmod.SamplePair, scale float64) Datapoint {
 		Value:     scale * float64(from.Value),
 	}
 }
+
+// Labels that may hold the trace ID of an exemplar, depending on the instrumentation
+var exemplarTraceIDLabels = []pmod.LabelName{"trace_id", "traceID", "traceId", "trace-id"}
+
+// AttachExemplars adds exemplars to the series they belong to, i.e. the series whose labels are all found in the
+// exemplar series labels. To avoid duplicates, exemplars are only attached to the first stat of each series.
+func AttachExemplars(series []Metric, from []prometheus.ExemplarQueryResult, scale float64) {
+	attached := make(map[string]bool)
+	for i := range series {
+		key := fmt.Sprintf("%v", series[i].Labels)
+		if attached[key] {
+			continue
+		}
+		attached[key] = true
+		for _, result := range from {
+			if !labelsMatch(series[i].Labels, result.SeriesLabels) {
+				continue
+			}
+			for _, e := range result.Exemplars {
+				series[i].Exemplars = append(series[i].Exemplars, convertExemplar(e, scale))
+			}
+		}
+		sort.SliceStable(series[i].Exemplars, func(a, b int) bool {
+			return series[i].Exemplars[a].Timestamp < series[i].Exemplars[b].Timestamp
+		})
+	}
+}
+
+func labelsMatch(labels map[string]string, seriesLabels pmod.LabelSet) bool {
+	for k, v := range labels {
+		if string(seriesLabels[pmod.LabelName(k)]) != v {
+			return false
+		}
+	}
+	return true
+}
+
+func convertExemplar(from prometheus.Exemplar, scale float64) Exemplar {
+	e := Exemplar{
+		Labels:    make(map[string]string, len(from.Labels)),
+		Timestamp: int64(from.Timestamp),
+		Value:     scale * float64(from.Value),
+	}
+	for k, v := range from.Labels {
+		e.Labels[string(k)] = string(v)
+	}
+	for _, l := range exemplarTraceIDLabels {
+		if traceID, ok := from.Labels[l]; ok {
+			e.TraceID = string(traceID)
+			break
+		}
+	}
+	return e
+}
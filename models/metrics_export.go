@@ -0,0 +1,156 @@
+package models
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MetricsFormatCSV exports metrics as CSV, one row per datapoint
+	MetricsFormatCSV = "csv"
+	// MetricsFormatOpenMetrics exports metrics in the OpenMetrics text format
+	MetricsFormatOpenMetrics = "openmetrics"
+)
+
+var (
+	invalidMetricNameCharRE = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+	invalidLabelNameCharRE  = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// MetricsToCSV exports the series of the metrics as CSV, with a "timestamp,metric,stat,value" header followed by the sorted
+// union of the series labels. Timestamps are in milliseconds.
+func MetricsToCSV(metrics MetricsMap) ([]byte, error) {
+	names := sortedMetricNames(metrics)
+	labelSet := make(map[string]bool)
+	for _, name := range names {
+		for _, series := range metrics[name] {
+			for label := range series.Labels {
+				labelSet[label] = true
+			}
+		}
+	}
+	labels := make([]string, 0, len(labelSet))
+	for label := range labelSet {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(append([]string{"timestamp", "metric", "stat", "value"}, labels...)); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		for _, series := range metrics[name] {
+			for _, dp := range series.Datapoints {
+				row := []string{strconv.FormatInt(dp.Timestamp, 10), name, series.Stat, formatValue(dp.Value)}
+				for _, label := range labels {
+					row = append(row, series.Labels[label])
+				}
+				if err := writer.Write(row); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// MetricsToOpenMetrics exports the series of the metrics in the OpenMetrics text format, as gauges.
+// The stat of histogram series (avg, quantiles) is kept in a "stat" label. Names are sanitized: the metrics whose
+// names become identical are exported in the same family, with their original name in a "metric" label.
+func MetricsToOpenMetrics(metrics MetricsMap) []byte {
+	families := make(map[string][]string)
+	for _, name := range sortedMetricNames(metrics) {
+		family := sanitizeOpenMetricsName(name, invalidMetricNameCharRE)
+		families[family] = append(families[family], name)
+	}
+	familyNames := make([]string, 0, len(families))
+	for family := range families {
+		familyNames = append(familyNames, family)
+	}
+	sort.Strings(familyNames)
+
+	var buf bytes.Buffer
+	for _, family := range familyNames {
+		names := families[family]
+		fmt.Fprintf(&buf, "# TYPE %s gauge\n", family)
+		for _, name := range names {
+			for _, series := range metrics[name] {
+				labels := openMetricsLabels(series.Labels)
+				if series.Stat != "" {
+					labels["stat"] = series.Stat
+				}
+				if len(names) > 1 {
+					labels["metric"] = name
+				}
+				pairs := make([]string, 0, len(labels))
+				for label, value := range labels {
+					pairs = append(pairs, fmt.Sprintf(`%s="%s"`, label, escapeOpenMetricsLabel(value)))
+				}
+				sort.Strings(pairs)
+				selector := ""
+				if len(pairs) > 0 {
+					selector = "{" + strings.Join(pairs, ",") + "}"
+				}
+				for _, dp := range series.Datapoints {
+					// OpenMetrics timestamps are in seconds
+					fmt.Fprintf(&buf, "%s%s %s %s\n", family, selector, formatValue(dp.Value), strconv.FormatFloat(float64(dp.Timestamp)/1000, 'f', -1, 64))
+				}
+			}
+		}
+	}
+	buf.WriteString("# EOF\n")
+	return buf.Bytes()
+}
+
+// openMetricsLabels sanitizes the label names of a series. When two names become identical, the first in order wins.
+func openMetricsLabels(seriesLabels map[string]string) map[string]string {
+	names := make([]string, 0, len(seriesLabels))
+	for label := range seriesLabels {
+		if label != "__name__" {
+			names = append(names, label)
+		}
+	}
+	sort.Strings(names)
+	labels := make(map[string]string, len(names))
+	for _, label := range names {
+		sanitized := sanitizeOpenMetricsName(label, invalidLabelNameCharRE)
+		if _, exists := labels[sanitized]; !exists {
+			labels[sanitized] = seriesLabels[label]
+		}
+	}
+	return labels
+}
+
+// sanitizeOpenMetricsName replaces the invalid characters of a name by underscores, names can't start with a digit
+func sanitizeOpenMetricsName(name string, invalidChars *regexp.Regexp) string {
+	sanitized := invalidChars.ReplaceAllString(name, "_")
+	if sanitized == "" || (sanitized[0] >= '0' && sanitized[0] <= '9') {
+		sanitized = "_" + sanitized
+	}
+	return sanitized
+}
+
+func sortedMetricNames(metrics MetricsMap) []string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escapeOpenMetricsLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
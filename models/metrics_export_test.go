@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeExportedMetrics() MetricsMap {
+	return MetricsMap{
+		"request_count": {
+			{Name: "request_count", Labels: map[string]string{"response_code": "200"}, Datapoints: []Datapoint{{Timestamp: 1000, Value: 1.5}, {Timestamp: 16000, Value: 2}}},
+		},
+		"request_duration_millis": {
+			{Name: "request_duration_millis", Stat: "0.99", Labels: map[string]string{"app": `say "hi"`}, Datapoints: []Datapoint{{Timestamp: 1000, Value: 12}}},
+		},
+	}
+}
+
+func TestMetricsToCSV(t *testing.T) {
+	csv, err := MetricsToCSV(fakeExportedMetrics())
+	assert.NoError(t, err)
+	assert.Equal(t, `timestamp,metric,stat,value,app,response_code
+1000,request_count,,1.5,,200
+16000,request_count,,2,,200
+1000,request_duration_millis,0.99,12,"say ""hi""",
+`, string(csv))
+}
+
+func TestMetricsToOpenMetrics(t *testing.T) {
+	assert.Equal(t, `# TYPE request_count gauge
+request_count{response_code="200"} 1.5 1
+request_count{response_code="200"} 2 16
+# TYPE request_duration_millis gauge
+request_duration_millis{app="say \"hi\"",stat="0.99"} 12 1
+# EOF
+`, string(MetricsToOpenMetrics(fakeExportedMetrics())))
+}
+
+func TestMetricsToOpenMetricsSanitizesNames(t *testing.T) {
+	metrics := MetricsMap{
+		"tcp-sent": {
+			{Name: "tcp-sent", Labels: map[string]string{"app.kubernetes.io/name": "reviews", "9lives": "x"}, Datapoints: []Datapoint{{Timestamp: 1000, Value: 1}}},
+		},
+		"tcp_sent": {
+			{Name: "tcp_sent", Labels: map[string]string{"app_kubernetes_io_name": "reviews"}, Datapoints: []Datapoint{{Timestamp: 1000, Value: 2}}},
+		},
+	}
+	assert.Equal(t, `# TYPE tcp_sent gauge
+tcp_sent{_9lives="x",app_kubernetes_io_name="reviews",metric="tcp-sent"} 1 1
+tcp_sent{app_kubernetes_io_name="reviews",metric="tcp_sent"} 2 1
+# EOF
+`, string(MetricsToOpenMetrics(metrics)))
+}
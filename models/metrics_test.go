@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+
+	pmod "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/prometheus"
+)
+
+func TestAttachExemplars(t *testing.T) {
+	assert := assert.New(t)
+
+	series := []Metric{
+		{Name: "request_duration_millis", Stat: "0.99", Labels: map[string]string{"source_workload": "a"}},
+		{Name: "request_duration_millis", Stat: "avg", Labels: map[string]string{"source_workload": "a"}},
+		{Name: "request_duration_millis", Stat: "0.99", Labels: map[string]string{"source_workload": "b"}},
+	}
+	exemplars := []prometheus.ExemplarQueryResult{{
+		SeriesLabels: pmod.LabelSet{"source_workload": "a", "le": "100"},
+		Exemplars: []prometheus.Exemplar{
+			{Labels: pmod.LabelSet{"trace_id": "t2"}, Value: 80, Timestamp: 2000},
+			{Labels: pmod.LabelSet{"trace_id": "t1"}, Value: 60, Timestamp: 1000},
+		},
+	}, {
+		SeriesLabels: pmod.LabelSet{"source_workload": "c", "le": "100"},
+		Exemplars: []prometheus.Exemplar{
+			{Labels: pmod.LabelSet{"traceID": "t3"}, Value: 90, Timestamp: 1500},
+		},
+	}}
+
+	AttachExemplars(series, exemplars, 0.001)
+
+	assert.Len(series[0].Exemplars, 2)
+	assert.Equal("t1", series[0].Exemplars[0].TraceID)
+	assert.Equal(int64(1000), series[0].Exemplars[0].Timestamp)
+	assert.Equal(0.06, series[0].Exemplars[0].Value)
+	assert.Equal("t2", series[0].Exemplars[1].TraceID)
+	// Only attached to the first stat
+	assert.Empty(series[1].Exemplars)
+	assert.Empty(series[2].Exemplars)
+}
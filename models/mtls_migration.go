@@ -0,0 +1,50 @@
+package models
+
+import (
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// MtlsMigrationClient is a client of a workload sending it plain text traffic
+type MtlsMigrationClient struct {
+	// Namespace and name of the client workload, "unknown" for clients outside the mesh
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload"`
+	// Requests and TCP connections per second
+	Rate float64 `json:"rate"`
+	// Why the client sends plain text
+	Reason string `json:"reason"`
+}
+
+// WorkloadMtlsMigration is the mTLS state of the inbound traffic of a workload
+type WorkloadMtlsMigration struct {
+	Workload   string `json:"workload"`
+	HasSidecar bool   `json:"hasSidecar"`
+	// Effective PeerAuthentication mode: STRICT, PERMISSIVE or DISABLE
+	Mode string `json:"mode"`
+	// Workload-level PeerAuthentication setting the mode, if any: the workload doesn't follow the namespace mode
+	PeerAuthentication string `json:"peerAuthentication,omitempty"`
+	// Inbound requests and TCP connections per second, with and without mTLS
+	MtlsRate         float64               `json:"mtlsRate"`
+	PlaintextRate    float64               `json:"plaintextRate"`
+	PlaintextClients []MtlsMigrationClient `json:"plaintextClients"`
+	// Whether switching the namespace to STRICT would reject some of the current clients
+	BreaksOnStrict bool `json:"breaksOnStrict"`
+}
+
+// MtlsMigrationReport is the migration of the workloads of a namespace to STRICT mTLS, from the traffic observed over
+// an interval
+type MtlsMigrationReport struct {
+	Namespace    string `json:"namespace"`
+	RateInterval string `json:"rateInterval"`
+	// Effective mode of the workloads without workload-level PeerAuthentication
+	NamespaceMode  string                  `json:"namespaceMode"`
+	AutoMtls       bool                    `json:"autoMtls"`
+	Workloads      []WorkloadMtlsMigration `json:"workloads"`
+	MtlsRate       float64                 `json:"mtlsRate"`
+	PlaintextRate  float64                 `json:"plaintextRate"`
+	ReadyForStrict bool                    `json:"readyForStrict"`
+	// PeerAuthentications of the next migration step, as objects and as YAML for review
+	PeerAuthentications []*kubernetes.GenericIstioObject `json:"peerAuthentications"`
+	YAML                string                           `json:"yaml"`
+	Warnings            []string                         `json:"warnings"`
+}
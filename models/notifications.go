@@ -0,0 +1,77 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+// Notification event types
+const (
+	NotificationHealth     = "health"
+	NotificationValidation = "validation"
+)
+
+// NotificationEvent is a health transition or a new validation error detected by the background evaluator
+type NotificationEvent struct {
+	// Fingerprint identifies the event source and state, it is used for deduplication
+	Fingerprint string `json:"fingerprint"`
+	// Type is either "health" or "validation"
+	Type       string `json:"type"`
+	Namespace  string `json:"namespace"`
+	ObjectType string `json:"objectType"`
+	Name       string `json:"name"`
+	// PreviousStatus and Status hold the health statuses, for health events
+	PreviousStatus string `json:"previousStatus,omitempty"`
+	Status         string `json:"status,omitempty"`
+	// Code holds the KIA check code, for validation events
+	Code      string    `json:"code,omitempty"`
+	Message   string    `json:"message"`
+	Severity  string    `json:"severity"`
+	Silenced  bool      `json:"silenced"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NotificationSilence mutes notifications matching the given namespace, object type and name during a period of time.
+// Empty matchers match everything; non empty matchers are regular expressions.
+type NotificationSilence struct {
+	ID         string    `json:"id"`
+	Namespace  string    `json:"namespace,omitempty"`
+	ObjectType string    `json:"objectType,omitempty"`
+	Name       string    `json:"name,omitempty"`
+	Comment    string    `json:"comment,omitempty"`
+	CreatedBy  string    `json:"createdBy,omitempty"`
+	StartsAt   time.Time `json:"startsAt"`
+	EndsAt     time.Time `json:"endsAt"`
+}
+
+// IsActive tells whether the silence applies at the given time
+func (s *NotificationSilence) IsActive(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+// Matches tells whether the silence applies to the given event
+func (s *NotificationSilence) Matches(e *NotificationEvent) bool {
+	return silenceMatcher(s.Namespace, e.Namespace) && silenceMatcher(s.ObjectType, e.ObjectType) && silenceMatcher(s.Name, e.Name)
+}
+
+// Validate checks silence matchers and period
+func (s *NotificationSilence) Validate() error {
+	for _, expr := range []string{s.Namespace, s.ObjectType, s.Name} {
+		if _, err := regexp.Compile("^(?:" + expr + ")$"); err != nil {
+			return err
+		}
+	}
+	if !s.EndsAt.After(s.StartsAt) {
+		return errors.New("silence must end after it starts")
+	}
+	return nil
+}
+
+func silenceMatcher(expr, value string) bool {
+	if expr == "" {
+		return true
+	}
+	matched, err := regexp.MatchString("^(?:"+expr+")$", value)
+	return err == nil && matched
+}
@@ -28,6 +28,7 @@ type Pod struct {
 	VersionLabel        bool              `json:"versionLabel"`
 	Annotations         map[string]string `json:"annotations"`
 	ProxyStatus         *ProxyStatus      `json:"proxyStatus"`
+	ServiceAccountName  string            `json:"serviceAccountName"`
 }
 
 // Reference holds some information on the pod creator
@@ -68,6 +69,7 @@ func (pod *Pod) Parse(p *core_v1.Pod) {
 	pod.Name = p.Name
 	pod.Labels = p.Labels
 	pod.Annotations = p.Annotations
+	pod.ServiceAccountName = p.Spec.ServiceAccountName
 	pod.CreatedAt = formatTime(p.CreationTimestamp.Time)
 	for _, ref := range p.OwnerReferences {
 		pod.CreatedBy = append(pod.CreatedBy, Reference{
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Rollout phases
+const (
+	RolloutProgressing = "Progressing"
+	RolloutPaused      = "Paused"
+	RolloutSucceeded   = "Succeeded"
+	RolloutRolledBack  = "RolledBack"
+	RolloutAborted     = "Aborted"
+)
+
+// RolloutCriteria are the success criteria evaluated on the candidate at every step
+type RolloutCriteria struct {
+	// Maximum error rate (5xx responses), in percent. Ignored when 0.
+	MaxErrorRate float64 `json:"maxErrorRate"`
+	// Maximum p95 response time, in milliseconds. Ignored when 0.
+	MaxP95 float64 `json:"maxP95"`
+}
+
+// RolloutSpec defines a canary rollout, shifting traffic of a service from a baseline to a candidate subset
+type RolloutSpec struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Service   string `json:"service"`
+	// VirtualService whose routes to the service are weighted
+	VirtualService string `json:"virtualService"`
+	Baseline       string `json:"baseline"`
+	Candidate      string `json:"candidate"`
+	// Value of the version label of the candidate workloads, used for analysis. Defaults to the candidate subset name.
+	CandidateVersion string `json:"candidateVersion,omitempty"`
+	// Candidate weights of the successive steps, e.g. [10, 25, 50, 100]. The last step must be 100.
+	Steps []int `json:"steps"`
+	// Time spent on each step before analysis, in seconds. At least MinRolloutInterval.
+	Interval int             `json:"interval"`
+	Criteria RolloutCriteria `json:"criteria"`
+}
+
+// RolloutEvent is an entry of the rollout history
+type RolloutEvent struct {
+	Timestamp       time.Time `json:"timestamp"`
+	Step            int       `json:"step"`
+	CandidateWeight int       `json:"candidateWeight"`
+	// Analysis results of the candidate, when available
+	ErrorRate *float64 `json:"errorRate,omitempty"`
+	P95       *float64 `json:"p95,omitempty"`
+	Message   string   `json:"message"`
+}
+
+// Rollout is a canary rollout with its current state and history
+type Rollout struct {
+	Spec            RolloutSpec    `json:"spec"`
+	Phase           string         `json:"phase"`
+	CurrentStep     int            `json:"currentStep"`
+	CandidateWeight int            `json:"candidateWeight"`
+	CreatedBy       string         `json:"createdBy,omitempty"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	LastStepAt      time.Time      `json:"lastStepAt"`
+	History         []RolloutEvent `json:"history"`
+}
+
+// MinRolloutInterval is the shortest step interval, in seconds, leaving room for a few scrapes to feed the analysis
+const MinRolloutInterval = 30
+
+var rolloutNameRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Validate checks the rollout definition
+func (s *RolloutSpec) Validate() error {
+	if s.Name == "" || s.Namespace == "" || s.Service == "" || s.VirtualService == "" {
+		return errors.New("name, namespace, service and virtualService are required")
+	}
+	// The name labels the VirtualService
+	if !rolloutNameRE.MatchString(s.Name) || len(s.Name) > 63 {
+		return fmt.Errorf("name must be a DNS label: %s", s.Name)
+	}
+	if s.Baseline == "" || s.Candidate == "" || s.Baseline == s.Candidate {
+		return errors.New("baseline and candidate must be different subsets")
+	}
+	if len(s.Steps) == 0 {
+		return errors.New("at least one step is required")
+	}
+	previous := 0
+	for _, w := range s.Steps {
+		if w <= previous || w > 100 {
+			return fmt.Errorf("step weights must be increasing and between 1 and 100: %v", s.Steps)
+		}
+		previous = w
+	}
+	if previous != 100 {
+		return fmt.Errorf("the last step must send all the traffic to the candidate: %v", s.Steps)
+	}
+	if s.Interval < MinRolloutInterval {
+		return fmt.Errorf("interval must be at least %d seconds", MinRolloutInterval)
+	}
+	return nil
+}
+
+// IsFinished tells whether the rollout reached a terminal phase
+func (r *Rollout) IsFinished() bool {
+	return r.Phase == RolloutSucceeded || r.Phase == RolloutRolledBack || r.Phase == RolloutAborted
+}
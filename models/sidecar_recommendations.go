@@ -0,0 +1,30 @@
+package models
+
+import (
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// SidecarRecommendation is the minimal Sidecar egress configuration of a workload, permitting the hosts it called
+type SidecarRecommendation struct {
+	Workload string `json:"workload"`
+	// Egress hosts, in the "<namespace>/<dnsName>" format
+	Hosts   []string                       `json:"hosts"`
+	Sidecar *kubernetes.GenericIstioObject `json:"sidecar"`
+	// Sidecar currently scoping the workload, if any
+	CurrentSidecar string `json:"currentSidecar,omitempty"`
+	// Envoy clusters of a proxy of the workload, currently and with the recommended Sidecar. Unset when the
+	// config dump can't be read.
+	CurrentClusters   *int `json:"currentClusters,omitempty"`
+	EstimatedClusters *int `json:"estimatedClusters,omitempty"`
+}
+
+// SidecarRecommendations are the Sidecar recommendations of the workloads of a namespace, from the traffic
+// observed over an interval
+type SidecarRecommendations struct {
+	Namespace       string                  `json:"namespace"`
+	RateInterval    string                  `json:"rateInterval"`
+	Recommendations []SidecarRecommendation `json:"recommendations"`
+	// The Sidecars, as a multi-document YAML for review
+	YAML     string   `json:"yaml"`
+	Warnings []string `json:"warnings"`
+}
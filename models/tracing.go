@@ -7,6 +7,7 @@ type JaegerInfo struct {
 	Integration          bool     `json:"integration"`
 	URL                  string   `json:"url"`
 	NamespaceSelector    bool     `json:"namespaceSelector"`
+	Provider             string   `json:"provider"`
 	WhiteListIstioSystem []string `json:"whiteListIstioSystem"`
 }
 
@@ -17,3 +18,94 @@ type TracingQuery struct {
 	MinDuration time.Duration
 	Limit       int
 }
+
+// TraceAnalysis holds the critical path and the per-service self time breakdown of a trace
+type TraceAnalysis struct {
+	TraceID      string             `json:"traceID"`
+	Duration     uint64             `json:"duration"`
+	CriticalPath []CriticalPathStep `json:"criticalPath"`
+	Services     []ServiceSelfTime  `json:"services"`
+}
+
+// CriticalPathStep is a section of a span that lies on the critical path of a trace.
+// Start and Duration are in microseconds; Start is relative to the trace start.
+type CriticalPathStep struct {
+	SpanID    string `json:"spanID"`
+	Service   string `json:"service"`
+	Operation string `json:"operation"`
+	Start     uint64 `json:"start"`
+	Duration  uint64 `json:"duration"`
+}
+
+// ServiceSelfTime is the time spent in a service, excluding the time spent waiting on its children spans.
+// Durations are in microseconds.
+type ServiceSelfTime struct {
+	Service          string  `json:"service"`
+	Spans            int     `json:"spans"`
+	SelfTime         uint64  `json:"selfTime"`
+	CriticalPathTime uint64  `json:"criticalPathTime"`
+	Percent          float64 `json:"percent"`
+}
+
+// TraceComparison compares a trace against a baseline, which is either a single trace or
+// the median of several similar traces
+type TraceComparison struct {
+	TraceID          string           `json:"traceID"`
+	Baseline         []string         `json:"baseline"`
+	Duration         uint64           `json:"duration"`
+	BaselineDuration uint64           `json:"baselineDuration"`
+	Spans            []SpanComparison `json:"spans"`
+}
+
+// Span comparison statuses
+const (
+	SpanSlower    = "slower"
+	SpanFaster    = "faster"
+	SpanUnchanged = "unchanged"
+	SpanMissing   = "missing"
+	SpanAdded     = "added"
+)
+
+// SpanComparison compares spans of a same service and operation between a trace and its baseline.
+// Durations are cumulated per trace, in microseconds.
+type SpanComparison struct {
+	Service          string  `json:"service"`
+	Operation        string  `json:"operation"`
+	Count            int     `json:"count"`
+	BaselineCount    int     `json:"baselineCount"`
+	Duration         uint64  `json:"duration"`
+	BaselineDuration uint64  `json:"baselineDuration"`
+	Delta            int64   `json:"delta"`
+	DeltaPercent     float64 `json:"deltaPercent"`
+	Status           string  `json:"status"`
+}
+
+// TracingStats holds statistics computed from the spans of an app over a time range
+type TracingStats struct {
+	Traces     int              `json:"traces"`
+	Spans      int              `json:"spans"`
+	Operations []OperationStats `json:"operations"`
+	Heatmap    SpansHeatmap     `json:"heatmap"`
+}
+
+// OperationStats holds span statistics for a single operation. Durations are in microseconds.
+type OperationStats struct {
+	Operation   string            `json:"operation"`
+	Count       int               `json:"count"`
+	Errors      int               `json:"errors"`
+	ErrorRatio  float64           `json:"errorRatio"`
+	Min         uint64            `json:"min"`
+	Max         uint64            `json:"max"`
+	Avg         float64           `json:"avg"`
+	Percentiles map[string]uint64 `json:"percentiles"`
+}
+
+// SpansHeatmap is a time x duration histogram of spans.
+// Counts[i][j] is the number of spans started in the time bucket i with a duration in the duration bucket j.
+// TimeBuckets are the buckets start times, in unix milliseconds. DurationBuckets are the buckets upper bounds,
+// in milliseconds; the last bucket has no upper bound and is reported as -1.
+type SpansHeatmap struct {
+	TimeBuckets     []int64   `json:"timeBuckets"`
+	DurationBuckets []float64 `json:"durationBuckets"`
+	Counts          [][]int   `json:"counts"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// WorkloadWeight is the percentage of traffic sent to a workload of a service
+type WorkloadWeight struct {
+	Workload string `json:"workload"`
+	Weight   int    `json:"weight"`
+}
+
+// HeaderRoute sends requests with a matching header to a workload of a service
+type HeaderRoute struct {
+	Header string `json:"header"`
+	// One of: exact, prefix, regex. Default: exact
+	MatchType string `json:"matchType,omitempty"`
+	Value     string `json:"value"`
+	Workload  string `json:"workload"`
+}
+
+// FaultIntent injects delays and/or aborts in a percentage of requests
+type FaultIntent struct {
+	DelayPercent float64 `json:"delayPercent,omitempty"`
+	// Delay duration, e.g. "5s"
+	FixedDelay   string  `json:"fixedDelay,omitempty"`
+	AbortPercent float64 `json:"abortPercent,omitempty"`
+	HTTPStatus   int     `json:"httpStatus,omitempty"`
+}
+
+// RetryIntent configures retries of failed requests
+type RetryIntent struct {
+	Attempts      int    `json:"attempts"`
+	PerTryTimeout string `json:"perTryTimeout,omitempty"`
+	RetryOn       string `json:"retryOn,omitempty"`
+}
+
+// CircuitBreakerIntent configures connection limits and outlier detection of a service
+type CircuitBreakerIntent struct {
+	MaxConnections           int    `json:"maxConnections,omitempty"`
+	HTTP1MaxPendingRequests  int    `json:"http1MaxPendingRequests,omitempty"`
+	MaxRequestsPerConnection int    `json:"maxRequestsPerConnection,omitempty"`
+	ConsecutiveErrors        int    `json:"consecutiveErrors,omitempty"`
+	Interval                 string `json:"interval,omitempty"`
+	BaseEjectionTime         string `json:"baseEjectionTime,omitempty"`
+	MaxEjectionPercent       int    `json:"maxEjectionPercent,omitempty"`
+}
+
+// GatewayIntent exposes a service through the ingress gateway
+type GatewayIntent struct {
+	Hosts []string `json:"hosts"`
+	Port  int      `json:"port,omitempty"`
+}
+
+// TrafficIntent is a high-level description of the traffic management of a service.
+// Routing is defined by one of WeightedRoutes, HeaderRoutes or TCPRoutes; the other settings are optional.
+type TrafficIntent struct {
+	WeightedRoutes []WorkloadWeight      `json:"weightedRoutes,omitempty"`
+	HeaderRoutes   []HeaderRoute         `json:"headerRoutes,omitempty"`
+	TCPRoutes      []WorkloadWeight      `json:"tcpRoutes,omitempty"`
+	Fault          *FaultIntent          `json:"fault,omitempty"`
+	Timeout        string                `json:"timeout,omitempty"`
+	Retries        *RetryIntent          `json:"retries,omitempty"`
+	CircuitBreaker *CircuitBreakerIntent `json:"circuitBreaker,omitempty"`
+	Gateway        *GatewayIntent        `json:"gateway,omitempty"`
+}
+
+// TrafficConfig is the set of Istio objects generated from a traffic intent, with their validations
+type TrafficConfig struct {
+	VirtualService  *kubernetes.GenericIstioObject `json:"virtualService"`
+	DestinationRule *kubernetes.GenericIstioObject `json:"destinationRule"`
+	Gateway         *kubernetes.GenericIstioObject `json:"gateway,omitempty"`
+	Validations     IstioValidations               `json:"validations"`
+	// Applied is true when the objects have been created or updated in the cluster
+	Applied bool `json:"applied"`
+}
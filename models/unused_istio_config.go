@@ -0,0 +1,17 @@
+package models
+
+// UnusedIstioConfig is an Istio config object, or a part of it, that has no effect on the traffic observed
+type UnusedIstioConfig struct {
+	ObjectType string `json:"objectType"`
+	Name       string `json:"name"`
+	// Subset of a DestinationRule, when only the subset is unused
+	Subset string `json:"subset,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// UnusedIstioConfigReport lists the unused Istio config of a namespace, given the traffic observed over an interval
+type UnusedIstioConfigReport struct {
+	Namespace    string              `json:"namespace"`
+	RateInterval string              `json:"rateInterval"`
+	Unused       []UnusedIstioConfig `json:"unused"`
+}
@@ -0,0 +1,376 @@
+// notifications periodically evaluates health and validations of the configured namespaces,
+// and notifies sinks about health transitions and new validation errors.
+package notifications
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	// Number of recent events kept in memory
+	maxRecentEvents = 500
+	// Evaluation interval used when the configured one is not positive, in seconds
+	defaultEvaluationInterval = 60
+)
+
+// Notifier holds the last known state of the evaluated objects, silences and recent events
+type Notifier struct {
+	lock           sync.RWMutex
+	sinks          []Sink
+	repeatInterval time.Duration
+	// Last known health status, per object key
+	healthStates map[string]string
+	// Last known validation errors, per object key
+	validationErrors map[string]map[string]bool
+	// Last time an event was sent, per fingerprint
+	lastSent     map[string]time.Time
+	silences     map[string]models.NotificationSilence
+	recentEvents []models.NotificationEvent
+	silenceSeq   int
+	stop         chan struct{}
+}
+
+var (
+	notifier     *Notifier
+	notifierLock sync.RWMutex
+)
+
+// NewNotifier creates a notifier sending events to the given sinks
+func NewNotifier(sinks []Sink, repeatInterval time.Duration) *Notifier {
+	return &Notifier{
+		sinks:            sinks,
+		repeatInterval:   repeatInterval,
+		healthStates:     make(map[string]string),
+		validationErrors: make(map[string]map[string]bool),
+		lastSent:         make(map[string]time.Time),
+		silences:         make(map[string]models.NotificationSilence),
+		recentEvents:     []models.NotificationEvent{},
+	}
+}
+
+// Start runs the background evaluation, when enabled in the configuration
+func Start() {
+	conf := config.Get().Notifications
+	if !conf.Enabled {
+		return
+	}
+	sinks := []Sink{}
+	for _, sinkConf := range conf.Sinks {
+		sink, err := NewSink(sinkConf)
+		if err != nil {
+			log.Errorf("Ignoring notification sink: %v", err)
+			continue
+		}
+		sinks = append(sinks, sink)
+	}
+	evaluationInterval := conf.EvaluationInterval
+	if evaluationInterval <= 0 {
+		log.Warningf("Invalid notifications evaluation interval [%d], using %ds", evaluationInterval, defaultEvaluationInterval)
+		evaluationInterval = defaultEvaluationInterval
+	}
+	started := NewNotifier(sinks, time.Duration(conf.RepeatInterval)*time.Second)
+	started.stop = make(chan struct{})
+
+	notifierLock.Lock()
+	defer notifierLock.Unlock()
+	if notifier != nil {
+		close(notifier.stop)
+	}
+	notifier = started
+	log.Infof("Notifications evaluation will run every %ds with %d sink(s)", evaluationInterval, len(sinks))
+	go started.run(time.Duration(evaluationInterval)*time.Second, conf.Namespaces, conf.RateInterval)
+}
+
+// Stop ends the background evaluation
+func Stop() {
+	notifierLock.Lock()
+	defer notifierLock.Unlock()
+	if notifier != nil && notifier.stop != nil {
+		close(notifier.stop)
+		notifier = nil
+	}
+}
+
+// Get returns the running notifier, or nil when notifications are disabled
+func Get() *Notifier {
+	notifierLock.RLock()
+	defer notifierLock.RUnlock()
+	return notifier
+}
+
+func (in *Notifier) run(interval time.Duration, namespaces []string, rateInterval string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		in.evaluate(namespaces, rateInterval)
+		select {
+		case <-in.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (in *Notifier) evaluate(namespaces []string, rateInterval string) {
+	// Evaluation is done on behalf of Kiali, not of a user
+	kialiToken, err := kubernetes.GetKialiToken()
+	if err != nil {
+		log.Errorf("Notifications: cannot get Kiali token: %v", err)
+		return
+	}
+	layer, err := business.Get(&api.AuthInfo{Token: kialiToken})
+	if err != nil {
+		log.Errorf("Notifications: cannot initialize business layer: %v", err)
+		return
+	}
+	if len(namespaces) == 0 {
+		nss, err := layer.Namespace.GetNamespaces()
+		if err != nil {
+			log.Errorf("Notifications: cannot list namespaces: %v", err)
+			return
+		}
+		for _, ns := range nss {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+
+	now := time.Now()
+	events := []models.NotificationEvent{}
+	for _, ns := range namespaces {
+		workloadsHealth, err := layer.Health.GetNamespaceWorkloadHealth(ns, rateInterval, now)
+		if err != nil {
+			log.Warningf("Notifications: cannot evaluate workloads health of namespace [%s]: %v", ns, err)
+		} else {
+			for name, h := range workloadsHealth {
+				if e := in.observeHealth(ns, "workload", name, business.EvaluateWorkloadHealth(ns, name, h), now); e != nil {
+					events = append(events, *e)
+				}
+			}
+		}
+		servicesHealth, err := layer.Health.GetNamespaceServiceHealth(ns, rateInterval, now)
+		if err != nil {
+			log.Warningf("Notifications: cannot evaluate services health of namespace [%s]: %v", ns, err)
+		} else {
+			for name, h := range servicesHealth {
+				if e := in.observeHealth(ns, "service", name, business.EvaluateRequestHealth(ns, "service", name, h.Requests), now); e != nil {
+					events = append(events, *e)
+				}
+			}
+		}
+		validations, err := layer.Validations.GetValidations(ns, "")
+		if err != nil {
+			log.Warningf("Notifications: cannot evaluate validations of namespace [%s]: %v", ns, err)
+		} else {
+			events = append(events, in.observeValidations(ns, validations, now)...)
+		}
+	}
+	in.dispatch(events, now)
+}
+
+func objectKey(namespace, objectType, name string) string {
+	return namespace + "/" + objectType + "/" + name
+}
+
+// observeHealth records the health status of an object, and returns an event when it is a notifiable transition:
+// any degradation, or a recovery from a degraded or failing state.
+func (in *Notifier) observeHealth(namespace, objectType, name, status string, now time.Time) *models.NotificationEvent {
+	key := objectKey(namespace, objectType, name)
+	in.lock.Lock()
+	previous, known := in.healthStates[key]
+	in.healthStates[key] = status
+	in.lock.Unlock()
+	if !known {
+		previous = business.HealthStatusNA
+	}
+	if previous == status || status == business.HealthStatusNA {
+		return nil
+	}
+	severity := "info"
+	switch status {
+	case business.HealthStatusFailure:
+		severity = string(models.ErrorSeverity)
+	case business.HealthStatusDegraded:
+		severity = string(models.WarningSeverity)
+	case business.HealthStatusHealthy:
+		if previous == business.HealthStatusNA {
+			// Not a recovery
+			return nil
+		}
+	}
+	return &models.NotificationEvent{
+		Fingerprint:    fmt.Sprintf("%s/%s/%s", models.NotificationHealth, key, status),
+		Type:           models.NotificationHealth,
+		Namespace:      namespace,
+		ObjectType:     objectType,
+		Name:           name,
+		PreviousStatus: previous,
+		Status:         status,
+		Message:        fmt.Sprintf("Health changed from %s to %s", previous, status),
+		Severity:       severity,
+		Timestamp:      now,
+	}
+}
+
+// observeValidations records the error-level checks of a namespace, and returns events for the new ones
+func (in *Notifier) observeValidations(namespace string, validations models.IstioValidations, now time.Time) []models.NotificationEvent {
+	events := []models.NotificationEvent{}
+	current := make(map[string]map[string]bool)
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	for vKey, validation := range validations {
+		if vKey.Namespace != namespace {
+			continue
+		}
+		key := objectKey(vKey.Namespace, vKey.ObjectType, vKey.Name)
+		for _, check := range validation.Checks {
+			if check == nil || check.Severity != models.ErrorSeverity {
+				continue
+			}
+			code := checkCode(check.Message)
+			if current[key] == nil {
+				current[key] = make(map[string]bool)
+			}
+			current[key][code] = true
+			if in.validationErrors[key][code] {
+				continue
+			}
+			events = append(events, models.NotificationEvent{
+				Fingerprint: fmt.Sprintf("%s/%s/%s", models.NotificationValidation, key, code),
+				Type:        models.NotificationValidation,
+				Namespace:   vKey.Namespace,
+				ObjectType:  vKey.ObjectType,
+				Name:        vKey.Name,
+				Code:        code,
+				Message:     check.Message,
+				Severity:    string(check.Severity),
+				Timestamp:   now,
+			})
+		}
+	}
+	// Replace known errors of the namespace, so that resolved errors are notified again if they come back
+	for key := range in.validationErrors {
+		if strings.HasPrefix(key, namespace+"/") {
+			delete(in.validationErrors, key)
+		}
+	}
+	for key, codes := range current {
+		in.validationErrors[key] = codes
+	}
+	return events
+}
+
+// checkCode extracts the KIA code from a check message, e.g. "KIA0101 No matching workload found..."
+func checkCode(message string) string {
+	if fields := strings.Fields(message); len(fields) > 0 && strings.HasPrefix(fields[0], "KIA") {
+		return fields[0]
+	}
+	return message
+}
+
+// dispatch records events, then sends to sinks the ones that are neither silenced nor recently sent
+func (in *Notifier) dispatch(events []models.NotificationEvent, now time.Time) {
+	toSend := []models.NotificationEvent{}
+	in.lock.Lock()
+	for _, e := range events {
+		for _, s := range in.silences {
+			if s.IsActive(now) && s.Matches(&e) {
+				e.Silenced = true
+				break
+			}
+		}
+		if !e.Silenced {
+			if last, ok := in.lastSent[e.Fingerprint]; !ok || now.Sub(last) >= in.repeatInterval {
+				in.lastSent[e.Fingerprint] = now
+				toSend = append(toSend, e)
+			}
+		}
+		in.recentEvents = append(in.recentEvents, e)
+	}
+	if len(in.recentEvents) > maxRecentEvents {
+		in.recentEvents = in.recentEvents[len(in.recentEvents)-maxRecentEvents:]
+	}
+	for fingerprint, last := range in.lastSent {
+		if now.Sub(last) >= in.repeatInterval {
+			delete(in.lastSent, fingerprint)
+		}
+	}
+	for id, s := range in.silences {
+		if !now.Before(s.EndsAt) {
+			delete(in.silences, id)
+		}
+	}
+	in.lock.Unlock()
+
+	if len(toSend) == 0 {
+		return
+	}
+	for _, sink := range in.sinks {
+		if err := sink.Send(toSend); err != nil {
+			log.Errorf("Notifications: cannot send to sink [%s]: %v", sink.Name(), err)
+		}
+	}
+}
+
+// GetEvents returns the recent events of the given namespaces, most recent first
+func (in *Notifier) GetEvents(namespaces map[string]bool) []models.NotificationEvent {
+	in.lock.RLock()
+	defer in.lock.RUnlock()
+	events := []models.NotificationEvent{}
+	for i := len(in.recentEvents) - 1; i >= 0; i-- {
+		if namespaces[in.recentEvents[i].Namespace] {
+			events = append(events, in.recentEvents[i])
+		}
+	}
+	return events
+}
+
+// GetSilences returns the silences that are not expired
+func (in *Notifier) GetSilences() []models.NotificationSilence {
+	in.lock.RLock()
+	defer in.lock.RUnlock()
+	now := time.Now()
+	silences := []models.NotificationSilence{}
+	for _, s := range in.silences {
+		if now.Before(s.EndsAt) {
+			silences = append(silences, s)
+		}
+	}
+	return silences
+}
+
+// AddSilence validates and registers a silence, and returns it with its generated ID
+func (in *Notifier) AddSilence(silence models.NotificationSilence) (models.NotificationSilence, error) {
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = time.Now()
+	}
+	if err := silence.Validate(); err != nil {
+		return silence, err
+	}
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	in.silenceSeq++
+	silence.ID = strconv.Itoa(in.silenceSeq)
+	in.silences[silence.ID] = silence
+	return silence, nil
+}
+
+// DeleteSilence removes a silence, and tells whether it existed
+func (in *Notifier) DeleteSilence(id string) bool {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	_, ok := in.silences[id]
+	delete(in.silences, id)
+	return ok
+}
@@ -0,0 +1,117 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/models"
+)
+
+type fakeSink struct {
+	sent [][]models.NotificationEvent
+}
+
+func (in *fakeSink) Name() string {
+	return "fake"
+}
+
+func (in *fakeSink) Send(events []models.NotificationEvent) error {
+	in.sent = append(in.sent, events)
+	return nil
+}
+
+func TestObserveHealthTransitions(t *testing.T) {
+	assert := assert.New(t)
+	n := NewNotifier(nil, time.Hour)
+	now := time.Now()
+
+	// First observation as healthy is not a transition
+	assert.Nil(n.observeHealth("bookinfo", "workload", "reviews-v1", business.HealthStatusHealthy, now))
+
+	e := n.observeHealth("bookinfo", "workload", "reviews-v1", business.HealthStatusDegraded, now)
+	assert.NotNil(e)
+	assert.Equal(business.HealthStatusHealthy, e.PreviousStatus)
+	assert.Equal(business.HealthStatusDegraded, e.Status)
+	assert.Equal("warning", e.Severity)
+
+	assert.Nil(n.observeHealth("bookinfo", "workload", "reviews-v1", business.HealthStatusDegraded, now))
+
+	e = n.observeHealth("bookinfo", "workload", "reviews-v1", business.HealthStatusFailure, now)
+	assert.NotNil(e)
+	assert.Equal("error", e.Severity)
+
+	// Recovery
+	e = n.observeHealth("bookinfo", "workload", "reviews-v1", business.HealthStatusHealthy, now)
+	assert.NotNil(e)
+	assert.Equal("info", e.Severity)
+
+	// First observation as failing is notified
+	assert.NotNil(n.observeHealth("bookinfo", "workload", "ratings-v1", business.HealthStatusFailure, now))
+}
+
+func TestObserveValidations(t *testing.T) {
+	assert := assert.New(t)
+	n := NewNotifier(nil, time.Hour)
+	now := time.Now()
+
+	key := models.IstioValidationKey{ObjectType: "virtualservice", Name: "reviews", Namespace: "bookinfo"}
+	validations := models.IstioValidations{
+		key: &models.IstioValidation{
+			Name:       "reviews",
+			ObjectType: "virtualservice",
+			Checks: []*models.IstioCheck{
+				{Message: "KIA1101 DestinationWeight on route doesn't have a valid service", Severity: models.ErrorSeverity},
+				{Message: "KIA1105 This host has no matching entry in the service registry", Severity: models.WarningSeverity},
+			},
+		},
+	}
+	events := n.observeValidations("bookinfo", validations, now)
+	assert.Len(events, 1)
+	assert.Equal("KIA1101", events[0].Code)
+	assert.Equal("validation/bookinfo/virtualservice/reviews/KIA1101", events[0].Fingerprint)
+
+	// Already known
+	assert.Empty(n.observeValidations("bookinfo", validations, now))
+
+	// Resolved, then back again
+	assert.Empty(n.observeValidations("bookinfo", models.IstioValidations{}, now))
+	assert.Len(n.observeValidations("bookinfo", validations, now), 1)
+}
+
+func TestDispatchDedupAndSilences(t *testing.T) {
+	assert := assert.New(t)
+	sink := &fakeSink{}
+	n := NewNotifier([]Sink{sink}, time.Hour)
+	now := time.Now()
+
+	e := models.NotificationEvent{Fingerprint: "health/bookinfo/workload/reviews-v1/Failure", Namespace: "bookinfo", ObjectType: "workload", Name: "reviews-v1"}
+	n.dispatch([]models.NotificationEvent{e}, now)
+	assert.Len(sink.sent, 1)
+
+	// Deduplicated within the repeat interval
+	n.dispatch([]models.NotificationEvent{e}, now.Add(time.Minute))
+	assert.Len(sink.sent, 1)
+	n.dispatch([]models.NotificationEvent{e}, now.Add(2*time.Hour))
+	assert.Len(sink.sent, 2)
+
+	// Silenced
+	silence, err := n.AddSilence(models.NotificationSilence{Namespace: "bookinfo", Name: "reviews-.*", StartsAt: now, EndsAt: now.Add(10 * time.Hour)})
+	assert.NoError(err)
+	n.dispatch([]models.NotificationEvent{e}, now.Add(4*time.Hour))
+	assert.Len(sink.sent, 2)
+
+	events := n.GetEvents(map[string]bool{"bookinfo": true})
+	assert.Len(events, 4)
+	assert.True(events[0].Silenced)
+	assert.Empty(n.GetEvents(map[string]bool{"default": true}))
+
+	assert.True(n.DeleteSilence(silence.ID))
+	n.dispatch([]models.NotificationEvent{e}, now.Add(5*time.Hour))
+	assert.Len(sink.sent, 3)
+
+	_, err = n.AddSilence(models.NotificationSilence{Namespace: "bookinfo", StartsAt: now, EndsAt: now.Add(-time.Hour)})
+	assert.Error(err)
+}
@@ -0,0 +1,147 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+// Sink types
+const (
+	SinkWebhook = "webhook"
+	SinkSlack   = "slack"
+	SinkEmail   = "email"
+)
+
+// Sink delivers notification events to an external system
+type Sink interface {
+	Name() string
+	Send(events []models.NotificationEvent) error
+}
+
+// NewSink creates a sink from its configuration
+func NewSink(conf config.NotificationSink) (Sink, error) {
+	switch conf.Type {
+	case SinkWebhook:
+		if conf.URL == "" {
+			return nil, fmt.Errorf("notification sink [%s]: url is required", conf.Name)
+		}
+		return &webhookSink{name: conf.Name, url: conf.URL, httpClient: newHTTPClient()}, nil
+	case SinkSlack:
+		if conf.URL == "" {
+			return nil, fmt.Errorf("notification sink [%s]: url is required", conf.Name)
+		}
+		return &slackSink{webhookSink{name: conf.Name, url: conf.URL, httpClient: newHTTPClient()}}, nil
+	case SinkEmail:
+		if conf.SMTPHost == "" || conf.From == "" || len(conf.To) == 0 {
+			return nil, fmt.Errorf("notification sink [%s]: smtp_host, from and to are required", conf.Name)
+		}
+		return &emailSink{conf: conf}, nil
+	}
+	return nil, fmt.Errorf("notification sink [%s]: unknown type [%s]", conf.Name, conf.Type)
+}
+
+func newHTTPClient() http.Client {
+	return http.Client{Timeout: 10 * time.Second}
+}
+
+// webhookSink posts events as JSON to a generic webhook
+type webhookSink struct {
+	name       string
+	url        string
+	httpClient http.Client
+}
+
+type webhookPayload struct {
+	Events []models.NotificationEvent `json:"events"`
+}
+
+func (in *webhookSink) Name() string {
+	return in.name
+}
+
+func (in *webhookSink) Send(events []models.NotificationEvent) error {
+	return in.post(webhookPayload{Events: events})
+}
+
+func (in *webhookSink) post(payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := in.httpClient.Post(in.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification sink [%s]: unexpected status code %d", in.name, resp.StatusCode)
+	}
+	return nil
+}
+
+// slackSink posts events as text to a Slack-compatible incoming webhook
+type slackSink struct {
+	webhookSink
+}
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+func (in *slackSink) Send(events []models.NotificationEvent) error {
+	lines := make([]string, len(events))
+	for i := range events {
+		lines[i] = formatEvent(&events[i])
+	}
+	return in.post(slackPayload{Text: strings.Join(lines, "\n")})
+}
+
+// emailSink sends events by email through a SMTP server
+type emailSink struct {
+	conf config.NotificationSink
+}
+
+func (in *emailSink) Name() string {
+	return in.conf.Name
+}
+
+func (in *emailSink) Send(events []models.NotificationEvent) error {
+	port := in.conf.SMTPPort
+	if port == 0 {
+		port = 25
+	}
+	addr := fmt.Sprintf("%s:%d", in.conf.SMTPHost, port)
+	var auth smtp.Auth
+	if in.conf.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", in.conf.SMTPUsername, in.conf.SMTPPassword, in.conf.SMTPHost)
+	}
+	return smtp.SendMail(addr, auth, in.conf.From, in.conf.To, buildEmail(in.conf.From, in.conf.To, events))
+}
+
+func buildEmail(from string, to []string, events []models.NotificationEvent) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: [Kiali] %d new notification(s)\r\n", len(events))
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	for i := range events {
+		b.WriteString(formatEvent(&events[i]))
+		b.WriteString("\r\n")
+	}
+	return []byte(b.String())
+}
+
+func formatEvent(e *models.NotificationEvent) string {
+	if e.Type == models.NotificationHealth {
+		return fmt.Sprintf("[%s] %s %s/%s health changed from %s to %s", strings.ToUpper(e.Severity), e.ObjectType, e.Namespace, e.Name, e.PreviousStatus, e.Status)
+	}
+	return fmt.Sprintf("[%s] %s %s/%s: %s", strings.ToUpper(e.Severity), e.ObjectType, e.Namespace, e.Name, e.Message)
+}
@@ -27,14 +27,20 @@ var (
 
 // ClientInterface for mocks (only mocked function are necessary here)
 type ClientInterface interface {
+	FetchExemplars(query string, start, end time.Time) ([]ExemplarQueryResult, error)
 	FetchHistogramRange(metricName, labels, grouping string, q *RangeQuery) Histogram
 	FetchHistogramValues(metricName, labels, grouping, rateInterval string, avg bool, quantiles []string, queryTime time.Time) (map[string]model.Vector, error)
+	FetchQueryRange(query string, q *RangeQuery) Metric
 	FetchRange(metricName, labels, grouping, aggregator string, q *RangeQuery) Metric
 	FetchRateRange(metricName string, labels []string, grouping string, q *RangeQuery) Metric
 	GetAllRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetAppRequestRates(namespace, app, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error)
 	GetConfiguration() (prom_v1.ConfigResult, error)
+	GetDestinationServiceRates(namespaces, hosts []string, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetFlags() (prom_v1.FlagsResult, error)
+	GetNamespaceInboundSecurityRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
+	GetNamespaceOutboundRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
+	GetNamespacePeerRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetNamespaceServicesRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetServiceRequestRates(namespace, service, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetWorkloadRequestRates(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error)
@@ -52,11 +58,16 @@ type Client struct {
 
 var once sync.Once
 var promCache PromCache
+var queryCache *QueryCache
 
 func initPromCache() {
-	if config.Get().ExternalServices.Prometheus.CacheEnabled {
+	promConfig := config.Get().ExternalServices.Prometheus
+	if promConfig.CacheEnabled {
 		log.Infof("[Prom Cache] Enabled")
 		promCache = NewPromCache()
+		if promConfig.CacheMaxEntries > 0 && promConfig.CacheDuration > 0 {
+			queryCache = NewQueryCache(time.Duration(promConfig.CacheDuration)*time.Second, promConfig.CacheMaxEntries)
+		}
 	} else {
 		log.Infof("[Prom Cache] Disabled")
 	}
@@ -71,13 +82,32 @@ func NewClient() (*Client, error) {
 // NewClient creates a new client to the Prometheus API.
 // It returns an error on any problem.
 func NewClientForConfig(cfg config.PrometheusConfig) (*Client, error) {
-	clientConfig := api.Config{Address: cfg.URL}
-
 	// Prom Cache will be initialized once at first use of Prometheus Client
 	once.Do(initPromCache)
 
-	// Be sure to copy config.Auth and not modify the existing
-	auth := cfg.Auth
+	p8s, err := newAPIClient(cfg.URL, cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+	client := Client{p8s: p8s, api: prom_v1.NewAPI(p8s), ctx: context.Background()}
+	if len(cfg.Backends) > 0 {
+		federated, err := newFederatedAPI(cfg, client.api)
+		if err != nil {
+			return nil, err
+		}
+		client.api = federated
+	}
+	if queryCache != nil {
+		client.api = queryCache.wrap(client.api, cfg.URL)
+	}
+	return &client, nil
+}
+
+// newAPIClient creates the HTTP client of a Prometheus server
+func newAPIClient(url string, auth config.Auth) (api.Client, error) {
+	clientConfig := api.Config{Address: url}
+
+	// auth is a copy of the configuration, it can be modified
 	if auth.UseKialiToken {
 		// Note: if we are using the 'bearer' authentication method then we want to use the Kiali
 		// service account token and not the user's token. This is because Kiali does filtering based
@@ -111,8 +141,14 @@ func NewClientForConfig(cfg config.PrometheusConfig) (*Client, error) {
 	if err != nil {
 		return nil, errors.NewServiceUnavailable(err.Error())
 	}
-	client := Client{p8s: p8s, api: prom_v1.NewAPI(p8s), ctx: context.Background()}
-	return &client, nil
+	return p8s, nil
+}
+
+// WithContext returns a copy of the client running its queries with the given context, i.e. to bound them by a deadline
+func (in *Client) WithContext(ctx context.Context) *Client {
+	client := *in
+	client.ctx = ctx
+	return &client
 }
 
 // Inject allows for replacing the API with a mock For testing
@@ -164,6 +200,39 @@ func (in *Client) GetNamespaceServicesRequestRates(namespace string, ratesInterv
 	return result, nil
 }
 
+// GetDestinationServiceRates queries Prometheus to fetch, over a time interval, the request and TCP connection rates
+// of the services of the namespaces and of the given hosts, which may be wildcard hosts. Rates are grouped by reporter,
+// destination service, destination service namespace and destination workload. Traffic from outside the mesh is only
+// reported by the destination, traffic to external services only by the source.
+func (in *Client) GetDestinationServiceRates(namespaces, hosts []string, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	log.Tracef("GetDestinationServiceRates [namespaces: %v] [hosts: %v] [ratesInterval: %s] [queryTime: %s]", namespaces, hosts, ratesInterval, queryTime.String())
+	return getDestinationServiceRates(in.ctx, in.api, namespaces, hosts, queryTime, ratesInterval)
+}
+
+// GetNamespaceInboundSecurityRates queries Prometheus to fetch, over a time interval, the request and TCP connection
+// rates received by the workloads of the namespace, as reported by the destination. Rates are grouped by destination
+// workload, source workload and connection security policy, telling mTLS from plain text traffic.
+func (in *Client) GetNamespaceInboundSecurityRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	log.Tracef("GetNamespaceInboundSecurityRates [namespace: %s] [ratesInterval: %s] [queryTime: %s]", namespace, ratesInterval, queryTime.String())
+	return getNamespaceInboundSecurityRates(in.ctx, in.api, namespace, queryTime, ratesInterval)
+}
+
+// GetNamespaceOutboundRates queries Prometheus to fetch, over a time interval, the request and TCP connection rates
+// sent by the workloads of the namespace, as reported by the source. Rates are grouped by source workload and
+// destination service.
+func (in *Client) GetNamespaceOutboundRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	log.Tracef("GetNamespaceOutboundRates [namespace: %s] [ratesInterval: %s] [queryTime: %s]", namespace, ratesInterval, queryTime.String())
+	return getNamespaceOutboundRates(in.ctx, in.api, namespace, queryTime, ratesInterval)
+}
+
+// GetNamespacePeerRates queries Prometheus to fetch, over a time interval, the request and TCP connection rates
+// received by the workloads of the namespace, as reported by the destination. Rates are grouped by source principal,
+// source workload, destination workload and, for requests, request operation.
+func (in *Client) GetNamespacePeerRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	log.Tracef("GetNamespacePeerRates [namespace: %s] [ratesInterval: %s] [queryTime: %s]", namespace, ratesInterval, queryTime.String())
+	return getNamespacePeerRates(in.ctx, in.api, namespace, queryTime, ratesInterval)
+}
+
 // GetServiceRequestRates queries Prometheus to fetch request counters rates over a time interval
 // for a given service (hence only inbound). Note that it does not discriminate on "reporter", so rates can
 // be inflated due to duplication, and therefore should be used mainly for calculating ratios
@@ -240,6 +309,11 @@ func (in *Client) FetchRange(metricName, labels, grouping, aggregator string, q
 	return fetchRange(in.ctx, in.api, query, q.Range)
 }
 
+// FetchQueryRange fetches the result of an arbitrary PromQL query in given range
+func (in *Client) FetchQueryRange(query string, q *RangeQuery) Metric {
+	return fetchRange(in.ctx, in.api, query, q.Range)
+}
+
 // FetchRateRange fetches a counter's rate in given range
 func (in *Client) FetchRateRange(metricName string, labels []string, grouping string, q *RangeQuery) Metric {
 	return fetchRateRange(in.ctx, in.api, metricName, labels, grouping, q)
@@ -255,6 +329,14 @@ func (in *Client) FetchHistogramValues(metricName, labels, grouping, rateInterva
 	return fetchHistogramValues(in.ctx, in.api, metricName, labels, grouping, rateInterval, avg, quantiles, queryTime)
 }
 
+// FetchExemplars fetches the exemplars of the series matching the query, in given range
+func (in *Client) FetchExemplars(query string, start, end time.Time) ([]ExemplarQueryResult, error) {
+	if in.p8s == nil {
+		return nil, fmt.Errorf("exemplars cannot be fetched without a Prometheus HTTP client")
+	}
+	return fetchExemplars(in.ctx, in.p8s, query, start, end)
+}
+
 // API returns the Prometheus V1 HTTP API for performing calls not supported natively by this client
 func (in *Client) API() prom_v1.API {
 	return in.api
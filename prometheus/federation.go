@@ -0,0 +1,326 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+// clusterMatcherRE matches the equality matchers of the "cluster" label, the label usually holding the cluster of the
+// series in federated setups (i.e. a Prometheus external label)
+var clusterMatcherRE = regexp.MustCompile(`[{,]\s*cluster\s*=\s*"((?:[^"\\]|\\.)*)"`)
+
+// clusterLabel is the label identifying the cluster of the series merged from several clusters
+const clusterLabel = model.LabelName("cluster")
+
+// The HTTP clients of the backends, built once for all the Prometheus clients
+var (
+	backendClients     = map[backendClientKey]api.Client{}
+	backendClientsLock sync.Mutex
+)
+
+type (
+	federatedBackend struct {
+		name string
+		api  prom_v1.API
+		// Age of the oldest metrics, zero when unlimited
+		retention time.Duration
+	}
+
+	// federatedAPI routes the queries of the Prometheus API to several backends, by cluster and by time range, and
+	// merges their results. The other calls go to the main Prometheus server.
+	federatedAPI struct {
+		prom_v1.API
+		// The backends of each cluster, by ascending retention
+		clusters map[string][]federatedBackend
+		now      func() time.Time
+	}
+
+	// rangePart is the part of a range query served by a backend
+	rangePart struct {
+		backend federatedBackend
+		r       prom_v1.Range
+	}
+
+	backendClientKey struct {
+		url  string
+		auth config.Auth
+	}
+)
+
+func newFederatedAPI(cfg config.PrometheusConfig, mainAPI prom_v1.API) (*federatedAPI, error) {
+	main, err := newFederatedBackend("default", cfg.Retention, mainAPI)
+	if err != nil {
+		return nil, err
+	}
+	federated := &federatedAPI{
+		API:      mainAPI,
+		clusters: map[string][]federatedBackend{cfg.Cluster: {main}},
+		now:      time.Now,
+	}
+	for _, backendCfg := range cfg.Backends {
+		name := backendCfg.Name
+		if name == "" {
+			name = backendCfg.URL
+		}
+		p8s, err := backendClient(backendCfg)
+		if err != nil {
+			return nil, err
+		}
+		backend, err := newFederatedBackend(name, backendCfg.Retention, prom_v1.NewAPI(p8s))
+		if err != nil {
+			return nil, err
+		}
+		cluster := backendCfg.Cluster
+		if cluster == "" {
+			cluster = cfg.Cluster
+		}
+		federated.clusters[cluster] = append(federated.clusters[cluster], backend)
+	}
+	for _, backends := range federated.clusters {
+		sortByRetention(backends)
+	}
+	return federated, nil
+}
+
+// backendClient returns the HTTP client of a backend, creating it on first use
+func backendClient(cfg config.PrometheusBackendConfig) (api.Client, error) {
+	key := backendClientKey{url: cfg.URL, auth: cfg.Auth}
+	backendClientsLock.Lock()
+	defer backendClientsLock.Unlock()
+	if p8s, ok := backendClients[key]; ok {
+		return p8s, nil
+	}
+	p8s, err := newAPIClient(cfg.URL, cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+	backendClients[key] = p8s
+	return p8s, nil
+}
+
+func newFederatedBackend(name, retention string, api prom_v1.API) (federatedBackend, error) {
+	backend := federatedBackend{name: name, api: api}
+	if retention != "" {
+		duration, err := model.ParseDuration(retention)
+		if err != nil {
+			return backend, fmt.Errorf("invalid retention [%s] of Prometheus backend [%s]: %v", retention, name, err)
+		}
+		backend.retention = time.Duration(duration)
+	}
+	return backend, nil
+}
+
+// sortByRetention sorts the backends by ascending retention, the unlimited ones last
+func sortByRetention(backends []federatedBackend) {
+	sort.SliceStable(backends, func(i, j int) bool {
+		if backends[j].retention == 0 {
+			return backends[i].retention != 0
+		}
+		return backends[i].retention != 0 && backends[i].retention < backends[j].retention
+	})
+}
+
+// clustersOf returns the clusters a query is routed to: the cluster its selectors are restricted to, or all the
+// clusters
+func (in *federatedAPI) clustersOf(query string) []string {
+	if matches := clusterMatcherRE.FindAllStringSubmatch(query, -1); len(matches) > 0 {
+		cluster := matches[0][1]
+		single := true
+		for _, match := range matches[1:] {
+			single = single && match[1] == cluster
+		}
+		if _, ok := in.clusters[cluster]; single && ok {
+			return []string{cluster}
+		}
+	}
+	names := make([]string, 0, len(in.clusters))
+	for name := range in.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// backendAt returns the backend with the shortest retention holding the metrics of the given time, or the backend
+// with the longest retention when none holds them
+func (in *federatedAPI) backendAt(backends []federatedBackend, queryTime time.Time) federatedBackend {
+	age := in.now().Sub(queryTime)
+	for _, backend := range backends {
+		if backend.retention == 0 || age <= backend.retention {
+			return backend
+		}
+	}
+	return backends[len(backends)-1]
+}
+
+// splitRange assigns each point of a range query to the backend with the shortest retention holding it
+func (in *federatedAPI) splitRange(backends []federatedBackend, r prom_v1.Range) []rangePart {
+	if r.Step <= 0 || len(backends) == 1 {
+		return []rangePart{{backend: in.backendAt(backends, r.Start), r: r}}
+	}
+	now := in.now()
+	parts := []rangePart{}
+	end := r.End
+	for i, backend := range backends {
+		if end.Before(r.Start) {
+			break
+		}
+		start := r.Start
+		if backend.retention != 0 && i < len(backends)-1 {
+			// First point of the range held by the backend
+			if oldest := now.Add(-backend.retention); oldest.After(start) {
+				steps := (oldest.Sub(start) + r.Step - 1) / r.Step
+				start = start.Add(steps * r.Step)
+			}
+		}
+		if !start.After(end) {
+			parts = append(parts, rangePart{backend: backend, r: prom_v1.Range{Start: start, End: end, Step: r.Step}})
+			end = start.Add(-r.Step)
+		}
+	}
+	return parts
+}
+
+// Query runs an instant query on the backend holding the query time, of each cluster the query is routed to
+func (in *federatedAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, prom_v1.Warnings, error) {
+	clusters := in.clustersOf(query)
+	if len(clusters) == 1 {
+		return in.backendAt(in.clusters[clusters[0]], ts).api.Query(ctx, query, ts)
+	}
+
+	values := make([]model.Value, len(clusters))
+	warnings := make([]prom_v1.Warnings, len(clusters))
+	errs := make([]error, len(clusters))
+	var wg sync.WaitGroup
+	for i, cluster := range clusters {
+		wg.Add(1)
+		go func(i int, backend federatedBackend) {
+			defer wg.Done()
+			values[i], warnings[i], errs[i] = backend.api.Query(ctx, query, ts)
+		}(i, in.backendAt(in.clusters[cluster], ts))
+	}
+	wg.Wait()
+	return mergeClusterValues(clusters, values, warnings, errs)
+}
+
+// QueryRange runs a range query on each cluster the query is routed to, splitting the range between the backends of
+// the cluster by retention
+func (in *federatedAPI) QueryRange(ctx context.Context, query string, r prom_v1.Range) (model.Value, prom_v1.Warnings, error) {
+	clusters := in.clustersOf(query)
+	partsByCluster := make([][]rangePart, len(clusters))
+	for i, cluster := range clusters {
+		partsByCluster[i] = in.splitRange(in.clusters[cluster], r)
+	}
+	if len(clusters) == 1 && len(partsByCluster[0]) == 1 {
+		part := partsByCluster[0][0]
+		return part.backend.api.QueryRange(ctx, query, part.r)
+	}
+
+	values := make([]model.Value, len(clusters))
+	warnings := make([]prom_v1.Warnings, len(clusters))
+	errs := make([]error, len(clusters))
+	var wg sync.WaitGroup
+	for i, parts := range partsByCluster {
+		wg.Add(1)
+		go func(i int, parts []rangePart) {
+			defer wg.Done()
+			values[i], warnings[i], errs[i] = queryRangeParts(ctx, query, parts)
+		}(i, parts)
+	}
+	wg.Wait()
+	return mergeClusterValues(clusters, values, warnings, errs)
+}
+
+// queryRangeParts runs the parts of a range query and joins the series of the parts
+func queryRangeParts(ctx context.Context, query string, parts []rangePart) (model.Value, prom_v1.Warnings, error) {
+	series := map[model.Fingerprint]*model.SampleStream{}
+	order := []model.Fingerprint{}
+	var warnings prom_v1.Warnings
+	for _, part := range parts {
+		value, partWarnings, err := part.backend.api.QueryRange(ctx, query, part.r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("prometheus backend [%s]: %v", part.backend.name, err)
+		}
+		warnings = append(warnings, partWarnings...)
+		matrix, ok := value.(model.Matrix)
+		if !ok {
+			return nil, nil, fmt.Errorf("prometheus backend [%s]: matrix expected", part.backend.name)
+		}
+		for _, stream := range matrix {
+			fingerprint := stream.Metric.Fingerprint()
+			if joined, ok := series[fingerprint]; ok {
+				joined.Values = append(joined.Values, stream.Values...)
+			} else {
+				series[fingerprint] = &model.SampleStream{Metric: stream.Metric, Values: append([]model.SamplePair{}, stream.Values...)}
+				order = append(order, fingerprint)
+			}
+		}
+	}
+	matrix := make(model.Matrix, len(order))
+	for i, fingerprint := range order {
+		stream := series[fingerprint]
+		sort.Slice(stream.Values, func(i, j int) bool {
+			return stream.Values[i].Timestamp < stream.Values[j].Timestamp
+		})
+		matrix[i] = stream
+	}
+	return matrix, warnings, nil
+}
+
+// mergeClusterValues concatenates the vectors or matrices returned by the clusters. Series of different clusters
+// are kept apart, even with the same labels: the series without a cluster label get the label of their cluster.
+func mergeClusterValues(clusters []string, values []model.Value, warnings []prom_v1.Warnings, errs []error) (model.Value, prom_v1.Warnings, error) {
+	var allWarnings prom_v1.Warnings
+	for i, err := range errs {
+		if err != nil {
+			return nil, nil, err
+		}
+		allWarnings = append(allWarnings, warnings[i]...)
+	}
+	switch values[0].(type) {
+	case model.Vector:
+		merged := model.Vector{}
+		for i, value := range values {
+			if vector, ok := value.(model.Vector); ok {
+				for _, sample := range vector {
+					merged = append(merged, &model.Sample{Metric: withCluster(sample.Metric, clusters[i]), Value: sample.Value, Timestamp: sample.Timestamp})
+				}
+			}
+		}
+		return merged, allWarnings, nil
+	case model.Matrix:
+		merged := model.Matrix{}
+		for i, value := range values {
+			if matrix, ok := value.(model.Matrix); ok {
+				for _, stream := range matrix {
+					merged = append(merged, &model.SampleStream{Metric: withCluster(stream.Metric, clusters[i]), Values: stream.Values})
+				}
+			}
+		}
+		return merged, allWarnings, nil
+	}
+	// Scalars and strings can't be merged, they don't depend on the cluster
+	log.Tracef("[Prom Federation] Keeping the result of the first cluster for a %s", values[0].Type())
+	return values[0], allWarnings, nil
+}
+
+// withCluster returns the labels of a series with the label of its cluster, unless it already has one
+func withCluster(metric model.Metric, cluster string) model.Metric {
+	if _, ok := metric[clusterLabel]; ok || cluster == "" {
+		return metric
+	}
+	labeled := metric.Clone()
+	labeled[clusterLabel] = model.LabelValue(cluster)
+	return labeled
+}
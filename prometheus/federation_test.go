@@ -0,0 +1,122 @@
+package prometheus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+// backendAPI answers the queries with a series per backend, holding a point per step of the queried range
+type backendAPI struct {
+	prom_v1.API
+	name   string
+	lock   sync.Mutex
+	ranges []prom_v1.Range
+}
+
+func (in *backendAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, prom_v1.Warnings, error) {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	in.ranges = append(in.ranges, prom_v1.Range{Start: ts, End: ts})
+	return model.Vector{&model.Sample{Metric: model.Metric{"backend": model.LabelValue(in.name)}, Timestamp: model.TimeFromUnixNano(ts.UnixNano())}}, nil, nil
+}
+
+func (in *backendAPI) QueryRange(ctx context.Context, query string, r prom_v1.Range) (model.Value, prom_v1.Warnings, error) {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	in.ranges = append(in.ranges, r)
+	stream := &model.SampleStream{Metric: model.Metric{"app": "reviews"}}
+	for t := r.Start; !t.After(r.End); t = t.Add(r.Step) {
+		stream.Values = append(stream.Values, model.SamplePair{Timestamp: model.TimeFromUnixNano(t.UnixNano()), Value: model.SampleValue(len(in.name))})
+	}
+	return model.Matrix{stream}, nil, nil
+}
+
+func TestFederatedQueryRoutedByCluster(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Unix(1600000000, 0)
+	east, west := &backendAPI{name: "east"}, &backendAPI{name: "west"}
+	federated := &federatedAPI{
+		clusters: map[string][]federatedBackend{
+			"east": {{name: "east", api: east}},
+			"west": {{name: "west", api: west}},
+		},
+		now: func() time.Time { return now },
+	}
+
+	value, _, err := federated.Query(context.Background(), `sum(rate(istio_requests_total{cluster="west",app="reviews"}[1m]))`, now)
+	assert.NoError(err)
+	assert.Len(value.(model.Vector), 1)
+	assert.Equal(model.LabelValue("west"), value.(model.Vector)[0].Metric["backend"])
+	assert.Empty(east.ranges)
+
+	// Without a cluster, or with several clusters, all of them are queried
+	value, _, err = federated.Query(context.Background(), `sum(rate(istio_requests_total{cluster=~"east|west"}[1m]))`, now)
+	assert.NoError(err)
+	assert.Len(value.(model.Vector), 2)
+	assert.Equal(model.LabelValue("east"), value.(model.Vector)[0].Metric["backend"])
+	assert.Equal(model.LabelValue("west"), value.(model.Vector)[1].Metric["backend"])
+	// The series of each cluster are labeled with their cluster
+	assert.Equal(model.LabelValue("east"), value.(model.Vector)[0].Metric["cluster"])
+	assert.Equal(model.LabelValue("west"), value.(model.Vector)[1].Metric["cluster"])
+
+	matrix, _, err := federated.QueryRange(context.Background(), "up", prom_v1.Range{Start: now.Add(-time.Minute), End: now, Step: time.Minute})
+	assert.NoError(err)
+	assert.Len(matrix.(model.Matrix), 2)
+	assert.Equal(model.Metric{"app": "reviews", "cluster": "east"}, matrix.(model.Matrix)[0].Metric)
+	assert.Equal(model.Metric{"app": "reviews", "cluster": "west"}, matrix.(model.Matrix)[1].Metric)
+}
+
+func TestFederatedRangeSplitByRetention(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Unix(1600000000, 0)
+	local, thanos := &backendAPI{name: "local"}, &backendAPI{name: "thanos"}
+	backends := []federatedBackend{{name: "thanos", api: thanos}, {name: "local", api: local, retention: 2 * time.Hour}}
+	sortByRetention(backends)
+	assert.Equal("local", backends[0].name)
+	federated := &federatedAPI{
+		clusters: map[string][]federatedBackend{"": backends},
+		now:      func() time.Time { return now },
+	}
+
+	// Recent data only comes from the local Prometheus
+	_, _, err := federated.QueryRange(context.Background(), "up", prom_v1.Range{Start: now.Add(-time.Hour), End: now, Step: time.Minute})
+	assert.NoError(err)
+	assert.Len(local.ranges, 1)
+	assert.Empty(thanos.ranges)
+
+	// Older data comes from Thanos, each point being read from a single backend
+	start := now.Add(-3*time.Hour - 30*time.Second)
+	value, _, err := federated.QueryRange(context.Background(), "up", prom_v1.Range{Start: start, End: now, Step: time.Minute})
+	assert.NoError(err)
+	assert.Equal(prom_v1.Range{Start: start.Add(61 * time.Minute), End: now, Step: time.Minute}, local.ranges[1])
+	assert.Equal(prom_v1.Range{Start: start, End: start.Add(60 * time.Minute), Step: time.Minute}, thanos.ranges[0])
+
+	matrix := value.(model.Matrix)
+	assert.Len(matrix, 1)
+	assert.Len(matrix[0].Values, 181)
+	assert.Equal(model.SampleValue(len("thanos")), matrix[0].Values[0].Value)
+	assert.Equal(model.SampleValue(len("local")), matrix[0].Values[180].Value)
+	for i := 1; i < len(matrix[0].Values); i++ {
+		assert.Equal(time.Minute, matrix[0].Values[i].Timestamp.Time().Sub(matrix[0].Values[i-1].Timestamp.Time()))
+	}
+}
+
+func TestBackendClientIsReused(t *testing.T) {
+	assert := assert.New(t)
+	first, err := backendClient(config.PrometheusBackendConfig{Name: "thanos", URL: "http://thanos:9090"})
+	assert.NoError(err)
+	second, err := backendClient(config.PrometheusBackendConfig{Name: "thanos-west", Cluster: "west", URL: "http://thanos:9090"})
+	assert.NoError(err)
+	assert.True(first == second)
+	other, err := backendClient(config.PrometheusBackendConfig{Name: "thanos", URL: "http://thanos:9091"})
+	assert.NoError(err)
+	assert.False(first == other)
+}
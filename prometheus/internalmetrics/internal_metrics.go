@@ -3,6 +3,7 @@ package internalmetrics
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	// Because this package is used all throughout the codebase, be VERY careful adding new
@@ -18,6 +19,9 @@ const (
 	labelAppender         = "appender"
 	labelRoute            = "route"
 	labelQueryGroup       = "query_group"
+	labelNamespace        = "namespace"
+	labelCacheType        = "type"
+	labelQueryType        = "query_type"
 )
 
 // MetricsType defines all of Kiali's own internal metrics.
@@ -30,6 +34,12 @@ type MetricsType struct {
 	PrometheusProcessingTime *prometheus.HistogramVec
 	KubernetesClients        *prometheus.GaugeVec
 	APIFailures              *prometheus.CounterVec
+	CacheSynced              *prometheus.GaugeVec
+	CacheObjects             *prometheus.GaugeVec
+	CacheMemory              *prometheus.GaugeVec
+	CacheLastEvent           *prometheus.GaugeVec
+	PrometheusCacheHits      *prometheus.CounterVec
+	PrometheusCacheMisses    *prometheus.CounterVec
 }
 
 // Metrics contains all of Kiali's own internal metrics.
@@ -92,6 +102,48 @@ var Metrics = MetricsType{
 		},
 		[]string{labelRoute},
 	),
+	CacheSynced: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kiali_cache_synced",
+			Help: "Whether the Kiali cache informer of a type is synced (1) or not (0). The namespace is empty for the informers watching all namespaces.",
+		},
+		[]string{labelNamespace, labelCacheType},
+	),
+	CacheObjects: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kiali_cache_objects",
+			Help: "The number of objects of a type held by the Kiali cache for a namespace.",
+		},
+		[]string{labelNamespace, labelCacheType},
+	),
+	CacheMemory: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kiali_cache_memory_bytes",
+			Help: "An estimate of the memory held by the objects of a type in the Kiali cache for a namespace.",
+		},
+		[]string{labelNamespace, labelCacheType},
+	),
+	CacheLastEvent: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kiali_cache_last_event_timestamp_seconds",
+			Help: "The time of the last event received by the Kiali cache for the objects of a type in a namespace.",
+		},
+		[]string{labelNamespace, labelCacheType},
+	),
+	PrometheusCacheHits: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kiali_prometheus_query_cache_hits_total",
+			Help: "Counts the Prometheus queries served by the query cache.",
+		},
+		[]string{labelQueryType},
+	),
+	PrometheusCacheMisses: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kiali_prometheus_query_cache_misses_total",
+			Help: "Counts the Prometheus queries not found in the query cache.",
+		},
+		[]string{labelQueryType},
+	),
 }
 
 // SuccessOrFailureMetricType let's you capture metrics for both successes and failures,
@@ -148,6 +200,12 @@ func RegisterInternalMetrics() {
 		Metrics.PrometheusProcessingTime,
 		Metrics.KubernetesClients,
 		Metrics.APIFailures,
+		Metrics.CacheSynced,
+		Metrics.CacheObjects,
+		Metrics.CacheMemory,
+		Metrics.CacheLastEvent,
+		Metrics.PrometheusCacheHits,
+		Metrics.PrometheusCacheMisses,
 	)
 }
 
@@ -250,7 +308,55 @@ func GetAPIFailureMetric(route string) prometheus.Counter {
 	})
 }
 
+// GetPrometheusQueryCacheHitsMetric returns the counter of the queries of a type (instant or range) served by the
+// Prometheus query cache
+func GetPrometheusQueryCacheHitsMetric(queryType string) prometheus.Counter {
+	return Metrics.PrometheusCacheHits.With(prometheus.Labels{
+		labelQueryType: queryType,
+	})
+}
+
+// GetPrometheusQueryCacheMissesMetric returns the counter of the queries of a type (instant or range) not found in
+// the Prometheus query cache
+func GetPrometheusQueryCacheMissesMetric(queryType string) prometheus.Counter {
+	return Metrics.PrometheusCacheMisses.With(prometheus.Labels{
+		labelQueryType: queryType,
+	})
+}
+
 // SetKubernetesClients sets the kubernetes client count
 func SetKubernetesClients(clientCount int) {
 	Metrics.KubernetesClients.With(prometheus.Labels{}).Set(float64(clientCount))
 }
+
+// SetCacheSynced sets whether the cache informer of a type is synced
+func SetCacheSynced(namespace string, resourceType string, synced bool) {
+	value := 0.0
+	if synced {
+		value = 1.0
+	}
+	Metrics.CacheSynced.With(prometheus.Labels{
+		labelNamespace: namespace,
+		labelCacheType: resourceType,
+	}).Set(value)
+}
+
+// UpdateCacheObjects records an event of the cache informer of a type: the objects and memory deltas are added
+// to the current values and the event time is set
+func UpdateCacheObjects(namespace string, resourceType string, objectsDelta int, memoryDelta int64, eventTime time.Time) {
+	labels := prometheus.Labels{
+		labelNamespace: namespace,
+		labelCacheType: resourceType,
+	}
+	Metrics.CacheObjects.With(labels).Add(float64(objectsDelta))
+	Metrics.CacheMemory.With(labels).Add(float64(memoryDelta))
+	Metrics.CacheLastEvent.With(labels).Set(float64(eventTime.UnixNano()) / 1e9)
+}
+
+// DeleteCacheMetrics removes the cache metrics of a type in a namespace, when the namespace is evicted from the cache
+func DeleteCacheMetrics(namespace string, resourceType string) {
+	Metrics.CacheSynced.DeleteLabelValues(namespace, resourceType)
+	Metrics.CacheObjects.DeleteLabelValues(namespace, resourceType)
+	Metrics.CacheMemory.DeleteLabelValues(namespace, resourceType)
+	Metrics.CacheLastEvent.DeleteLabelValues(namespace, resourceType)
+}
@@ -2,10 +2,14 @@ package prometheus
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/api"
 	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -110,6 +114,37 @@ func fetchRange(ctx context.Context, api prom_v1.API, query string, bounds prom_
 	return Metric{Err: fmt.Errorf("invalid query, matrix expected: %s", query)}
 }
 
+// fetchExemplars queries the Prometheus exemplars API, which is not supported by the prom_v1.API client
+func fetchExemplars(ctx context.Context, client api.Client, query string, start, end time.Time) ([]ExemplarQueryResult, error) {
+	log.Tracef("[Prom] fetchExemplars: %s", query)
+	u := client.URL("/api/v1/query_exemplars", nil)
+	q := u.Query()
+	q.Set("query", query)
+	q.Set("start", start.Format(time.RFC3339Nano))
+	q.Set("end", end.Format(time.RFC3339Nano))
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, body, err := client.Do(ctx, req)
+	if err != nil {
+		return nil, errors.NewServiceUnavailable(err.Error())
+	}
+	var result struct {
+		Status string                `json:"status"`
+		Data   []ExemplarQueryResult `json:"data"`
+		Error  string                `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("cannot parse exemplars response (code %d): %v", resp.StatusCode, err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("exemplars query failed (code %d): %s", resp.StatusCode, result.Error)
+	}
+	return result.Data, nil
+}
+
 // getAllRequestRates retrieves traffic rates for requests entering, internal to, or exiting the namespace.
 // Note that it does not discriminate on "reporter", so rates can be inflated due to duplication, and therefore
 // should be used mainly for calculating ratios (e.g total rates / error rates)
@@ -144,6 +179,95 @@ func getNamespaceServicesRequestRates(ctx context.Context, api prom_v1.API, name
 	return ns, nil
 }
 
+// getDestinationServiceRates retrieves request and TCP connection rates of the services of the namespaces, and of
+// the given hosts, by reporter, destination service and its namespace, and destination workload
+func getDestinationServiceRates(ctx context.Context, api prom_v1.API, namespaces, hosts []string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
+	groupBy := "reporter,destination_service,destination_service_namespace,destination_workload,destination_workload_namespace"
+	selectors := []string{}
+	if len(namespaces) > 0 {
+		quoted := make([]string, 0, len(namespaces))
+		for _, ns := range namespaces {
+			quoted = append(quoted, regexp.QuoteMeta(ns))
+		}
+		selectors = append(selectors, fmt.Sprintf(`destination_service_namespace=~"%s"`, promRegexp(quoted)))
+	}
+	// Hosts of other namespaces and external hosts, the "*" host is covered by the namespaces
+	patterns := []string{}
+	for _, h := range hosts {
+		switch {
+		case h == "*":
+			continue
+		case strings.HasPrefix(h, "*"):
+			patterns = append(patterns, ".*"+regexp.QuoteMeta(strings.TrimPrefix(h, "*")))
+		default:
+			patterns = append(patterns, regexp.QuoteMeta(h))
+		}
+	}
+	if len(patterns) > 0 {
+		selectors = append(selectors, fmt.Sprintf(`destination_service=~"%s"`, promRegexp(patterns)))
+	}
+
+	seen := make(map[model.Fingerprint]bool)
+	result := model.Vector{}
+	for _, selector := range selectors {
+		lbl := selector + `,destination_service!="",destination_service!="unknown"`
+		rates, err := queryNamespaceRates(ctx, api, "GetDestinationServiceRates", lbl, groupBy, groupBy, queryTime, ratesInterval)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range rates {
+			if fp := s.Metric.Fingerprint(); !seen[fp] {
+				seen[fp] = true
+				result = append(result, s)
+			}
+		}
+	}
+	return result, nil
+}
+
+// promRegexp joins regular expressions into an alternative, escaped for a PromQL string
+func promRegexp(patterns []string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(strings.Join(patterns, "|"))
+}
+
+// getNamespaceInboundSecurityRates retrieves request and TCP connection rates received by the workloads of the namespace, by connection security policy
+func getNamespaceInboundSecurityRates(ctx context.Context, api prom_v1.API, namespace string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
+	lbl := fmt.Sprintf(`reporter="destination",destination_workload_namespace="%s"`, namespace)
+	groupBy := "destination_workload,source_workload_namespace,source_workload,connection_security_policy"
+	return queryNamespaceRates(ctx, api, "GetNamespaceInboundSecurityRates", lbl, groupBy, groupBy, queryTime, ratesInterval)
+}
+
+// getNamespaceOutboundRates retrieves request and TCP connection rates sent by the workloads of the namespace, by destination service
+func getNamespaceOutboundRates(ctx context.Context, api prom_v1.API, namespace string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
+	lbl := fmt.Sprintf(`reporter="source",source_workload_namespace="%s"`, namespace)
+	groupBy := "source_workload,destination_service,destination_service_namespace"
+	return queryNamespaceRates(ctx, api, "GetNamespaceOutboundRates", lbl, groupBy, groupBy, queryTime, ratesInterval)
+}
+
+// getNamespacePeerRates retrieves request and TCP connection rates received by the workloads of the namespace, by peer
+func getNamespacePeerRates(ctx context.Context, api prom_v1.API, namespace string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
+	lbl := fmt.Sprintf(`reporter="destination",destination_workload_namespace="%s"`, namespace)
+	groupBy := "source_principal,source_workload_namespace,source_workload,destination_workload"
+	return queryNamespaceRates(ctx, api, "GetNamespacePeerRates", lbl, groupBy+",request_operation", groupBy, queryTime, ratesInterval)
+}
+
+// queryNamespaceRates retrieves request rates and TCP connection rates, with their own grouping
+func queryNamespaceRates(ctx context.Context, api prom_v1.API, name, labels, requestsGroupBy, tcpGroupBy string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
+	query := fmt.Sprintf("(sum(rate(istio_requests_total{%s}[%s])) by (%s) > 0) OR (sum(rate(istio_tcp_connections_opened_total{%s}[%s])) by (%s) > 0)",
+		labels, ratesInterval, requestsGroupBy, labels, ratesInterval, tcpGroupBy)
+	log.Tracef("[Prom] %s: %s", name, query)
+	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-" + name)
+	result, warnings, err := api.Query(ctx, query, queryTime)
+	if warnings != nil && len(warnings) > 0 {
+		log.Warningf("%s. Prometheus Warnings: [%s]", name, strings.Join(warnings, ","))
+	}
+	if err != nil {
+		return model.Vector{}, errors.NewServiceUnavailable(err.Error())
+	}
+	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+	return result.(model.Vector), nil
+}
+
 // getServiceRequestRates retrieves traffic rates for requests entering, or internal to the namespace, for a specific service name
 // Note that it does not discriminate on "reporter", so rates can be inflated due to duplication, and therefore
 // should be used mainly for calculating ratios (e.g total rates / error rates)
@@ -0,0 +1,42 @@
+package prometheus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// queryRecorderAPI records the queries and answers them with the same sample
+type queryRecorderAPI struct {
+	prom_v1.API
+	queries []string
+}
+
+func (in *queryRecorderAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, prom_v1.Warnings, error) {
+	in.queries = append(in.queries, query)
+	return model.Vector{&model.Sample{Metric: model.Metric{"destination_service": "reviews.bookinfo.svc.cluster.local"}, Value: 1}}, nil, nil
+}
+
+func TestGetDestinationServiceRatesScope(t *testing.T) {
+	assert := assert.New(t)
+	api := &queryRecorderAPI{}
+
+	rates, err := getDestinationServiceRates(context.Background(), api, []string{"bookinfo"}, []string{"*", "*.example.com", "ratings.other.svc.cluster.local"}, time.Now(), "1h")
+	assert.NoError(err)
+	// The series returned by both queries is reported once
+	assert.Len(rates, 1)
+	assert.Len(api.queries, 2)
+	assert.Contains(api.queries[0], `istio_requests_total{destination_service_namespace=~"bookinfo",destination_service!="",destination_service!="unknown"}[1h]`)
+	assert.Contains(api.queries[1], `istio_requests_total{destination_service=~".*\\.example\\.com|ratings\\.other\\.svc\\.cluster\\.local",destination_service!="",destination_service!="unknown"}[1h]`)
+
+	// Without namespaces nor hosts, nothing is queried
+	api.queries = nil
+	rates, err = getDestinationServiceRates(context.Background(), api, nil, []string{"*"}, time.Now(), "1h")
+	assert.NoError(err)
+	assert.Empty(rates)
+	assert.Empty(api.queries)
+}
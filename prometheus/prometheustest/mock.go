@@ -281,6 +281,26 @@ func (o *PromClientMock) GetFlags() (prom_v1.FlagsResult, error) {
 	return args.Get(0).(prom_v1.FlagsResult), args.Error(1)
 }
 
+func (o *PromClientMock) GetDestinationServiceRates(namespaces, hosts []string, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(namespaces, hosts, ratesInterval, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
+func (o *PromClientMock) GetNamespaceInboundSecurityRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(namespace, ratesInterval, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
+func (o *PromClientMock) GetNamespaceOutboundRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(namespace, ratesInterval, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
+func (o *PromClientMock) GetNamespacePeerRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(namespace, ratesInterval, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
 func (o *PromClientMock) GetNamespaceServicesRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
 	args := o.Called(namespace, ratesInterval, queryTime)
 	return args.Get(0).(model.Vector), args.Error(1)
@@ -311,6 +331,11 @@ func (o *PromClientMock) FetchRateRange(metricName string, labels []string, grou
 	return args.Get(0).(prometheus.Metric)
 }
 
+func (o *PromClientMock) FetchQueryRange(query string, q *prometheus.RangeQuery) prometheus.Metric {
+	args := o.Called(query, q)
+	return args.Get(0).(prometheus.Metric)
+}
+
 func (o *PromClientMock) FetchHistogramRange(metricName, labels, grouping string, q *prometheus.RangeQuery) prometheus.Histogram {
 	args := o.Called(metricName, labels, grouping, q)
 	return args.Get(0).(prometheus.Histogram)
@@ -321,6 +346,11 @@ func (o *PromClientMock) FetchHistogramValues(metricName, labels, grouping, rate
 	return args.Get(0).(map[string]model.Vector), args.Error((1))
 }
 
+func (o *PromClientMock) FetchExemplars(query string, start, end time.Time) ([]prometheus.ExemplarQueryResult, error) {
+	args := o.Called(query, start, end)
+	return args.Get(0).([]prometheus.ExemplarQueryResult), args.Error(1)
+}
+
 func (o *PromClientMock) GetMetricsForLabels(labels []string) ([]string, error) {
 	args := o.Called(labels)
 	return args.Get(0).([]string), args.Error(1)
@@ -338,6 +368,10 @@ func (o *PromClientMock) MockMetric(name string, labels string, q *prometheus.Ra
 	o.On("FetchRateRange", name, []string{labels}, "", q).Return(fakeMetric(value))
 }
 
+func (o *PromClientMock) MockQuery(query string, q *prometheus.RangeQuery, value float64) {
+	o.On("FetchQueryRange", query, q).Return(fakeMetric(value))
+}
+
 func (o *PromClientMock) MockHistogram(name string, labels string, q *prometheus.RangeQuery, avg, p99 float64) {
 	o.On("FetchHistogramRange", name, labels, "", q).Return(fakeHistogram(avg, p99))
 }
@@ -0,0 +1,263 @@
+package prometheus
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+const (
+	instantQuery = "instant"
+	rangeQuery   = "range"
+
+	// Timeout of a query shared by the concurrent callers of a key
+	sharedQueryTimeout = 30 * time.Second
+)
+
+type (
+	// QueryCache is a size-bounded LRU cache of Prometheus query results, keyed by the normalized PromQL and the
+	// query times. Instant query times are aligned on the cache duration and range queries are aligned on their step,
+	// so that users asking for the same data within the same interval share the results.
+	// The cached results are shared: they must not be modified.
+	QueryCache struct {
+		duration   time.Duration
+		maxEntries int
+		entries    map[string]*list.Element
+		lru        *list.List
+		lock       sync.Mutex
+		inFlight   map[string]*queryCall
+		timeout    time.Duration
+	}
+
+	queryCacheEntry struct {
+		key      string
+		value    model.Value
+		warnings prom_v1.Warnings
+		created  time.Time
+	}
+
+	// queryCall is a query shared by the concurrent callers of a key. It is canceled when all its callers are gone.
+	queryCall struct {
+		done     chan struct{}
+		value    model.Value
+		warnings prom_v1.Warnings
+		err      error
+		waiters  int
+		cancel   context.CancelFunc
+	}
+
+	// cachedAPI serves the queries of a Prometheus API from the query cache. The other calls go to the API.
+	cachedAPI struct {
+		prom_v1.API
+		cache *QueryCache
+		// The Prometheus server URL: the same query on another server is another entry
+		address string
+	}
+)
+
+// NewQueryCache creates a query cache holding up to maxEntries results, each of them for the given duration
+func NewQueryCache(duration time.Duration, maxEntries int) *QueryCache {
+	return &QueryCache{
+		duration:   duration,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		inFlight:   make(map[string]*queryCall),
+		timeout:    sharedQueryTimeout,
+	}
+}
+
+// wrap returns the API querying through the cache
+func (c *QueryCache) wrap(api prom_v1.API, address string) prom_v1.API {
+	return cachedAPI{API: api, cache: c, address: address}
+}
+
+// alignTime aligns an instant query time on the cache duration
+func (c *QueryCache) alignTime(queryTime time.Time) time.Time {
+	if c.duration <= 0 {
+		return queryTime
+	}
+	return queryTime.Truncate(c.duration)
+}
+
+// alignRange aligns the bounds of a range query on its step
+func alignRange(r prom_v1.Range) prom_v1.Range {
+	if r.Step <= 0 {
+		return r
+	}
+	return prom_v1.Range{Start: r.Start.Truncate(r.Step), End: r.End.Truncate(r.Step), Step: r.Step}
+}
+
+func (c *QueryCache) get(key string) (model.Value, prom_v1.Warnings, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, nil, false
+	}
+	entry := elem.Value.(*queryCacheEntry)
+	if time.Since(entry.created) >= c.duration {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		return nil, nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.value, entry.warnings, true
+}
+
+func (c *QueryCache) set(key string, value model.Value, warnings prom_v1.Warnings) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*queryCacheEntry)
+		entry.value = value
+		entry.warnings = warnings
+		entry.created = time.Now()
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&queryCacheEntry{key: key, value: value, warnings: warnings, created: time.Now()})
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*queryCacheEntry).key)
+	}
+}
+
+// fetch returns the cached result of a key, or runs the query once for all the concurrent callers of the key.
+// The query is shared: it runs with its own timeout rather than the context of the first caller. Each caller stops
+// waiting for it when its own context is done, and the query is canceled when no caller waits for it anymore.
+func (c *QueryCache) fetch(ctx context.Context, queryType, key string, query func(context.Context) (model.Value, prom_v1.Warnings, error)) (model.Value, prom_v1.Warnings, error) {
+	if value, warnings, ok := c.get(key); ok {
+		internalmetrics.GetPrometheusQueryCacheHitsMetric(queryType).Inc()
+		log.Tracef("[Prom Query Cache] Hit: %s", key)
+		return value, warnings, nil
+	}
+	internalmetrics.GetPrometheusQueryCacheMissesMetric(queryType).Inc()
+
+	c.lock.Lock()
+	call, ok := c.inFlight[key]
+	if !ok {
+		queryCtx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		call = &queryCall{done: make(chan struct{}), cancel: cancel}
+		c.inFlight[key] = call
+		go c.run(queryCtx, key, call, query)
+	}
+	call.waiters++
+	c.lock.Unlock()
+
+	select {
+	case <-call.done:
+		c.leave(key, call)
+		return call.value, call.warnings, call.err
+	case <-ctx.Done():
+		c.leave(key, call)
+		return nil, nil, ctx.Err()
+	}
+}
+
+// run runs a shared query and caches its result
+func (c *QueryCache) run(queryCtx context.Context, key string, call *queryCall, query func(context.Context) (model.Value, prom_v1.Warnings, error)) {
+	defer call.cancel()
+	call.value, call.warnings, call.err = query(queryCtx)
+	if call.err == nil {
+		c.set(key, call.value, call.warnings)
+	}
+	c.lock.Lock()
+	if c.inFlight[key] == call {
+		delete(c.inFlight, key)
+	}
+	c.lock.Unlock()
+	close(call.done)
+}
+
+// leave removes a caller of a shared query, canceling the query when it was the last one
+func (c *QueryCache) leave(key string, call *queryCall) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	// A new caller of the key runs a new query rather than joining the canceled one
+	if c.inFlight[key] == call {
+		delete(c.inFlight, key)
+	}
+	call.cancel()
+}
+
+func (in cachedAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, prom_v1.Warnings, error) {
+	ts = in.cache.alignTime(ts)
+	key := fmt.Sprintf("%s|%s|%d", in.address, normalizeQuery(query), ts.UnixNano())
+	return in.cache.fetch(ctx, instantQuery, key, func(queryCtx context.Context) (model.Value, prom_v1.Warnings, error) {
+		return in.API.Query(queryCtx, query, ts)
+	})
+}
+
+func (in cachedAPI) QueryRange(ctx context.Context, query string, r prom_v1.Range) (model.Value, prom_v1.Warnings, error) {
+	r = alignRange(r)
+	key := fmt.Sprintf("%s|%s|%d|%d|%d", in.address, normalizeQuery(query), r.Start.UnixNano(), r.End.UnixNano(), r.Step)
+	return in.cache.fetch(ctx, rangeQuery, key, func(queryCtx context.Context) (model.Value, prom_v1.Warnings, error) {
+		return in.API.QueryRange(queryCtx, query, r)
+	})
+}
+
+// normalizeQuery collapses the whitespaces of a PromQL query outside of string literals, and drops them next to
+// parentheses, braces, brackets and commas, so that queries only differing by their formatting share a cache entry
+func normalizeQuery(query string) string {
+	var out strings.Builder
+	pendingSpace := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			pendingSpace = out.Len() > 0
+			continue
+		case c == '"' || c == '\'' || c == '`':
+			end := i + 1
+			for end < len(query) && query[end] != c {
+				if query[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			if end >= len(query) {
+				end = len(query) - 1
+			}
+			if pendingSpace && !isQueryDelimiter(lastByte(&out)) {
+				out.WriteByte(' ')
+			}
+			pendingSpace = false
+			out.WriteString(query[i : end+1])
+			i = end
+			continue
+		}
+		if pendingSpace && !isQueryDelimiter(c) && !isQueryDelimiter(lastByte(&out)) {
+			out.WriteByte(' ')
+		}
+		pendingSpace = false
+		out.WriteByte(c)
+	}
+	return out.String()
+}
+
+func isQueryDelimiter(c byte) bool {
+	return strings.IndexByte("(){}[],", c) >= 0
+}
+
+func lastByte(out *strings.Builder) byte {
+	s := out.String()
+	if s == "" {
+		return 0
+	}
+	return s[len(s)-1]
+}
@@ -0,0 +1,171 @@
+package prometheus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingAPI answers every query with the number of calls received so far
+type countingAPI struct {
+	prom_v1.API
+	calls   int
+	queries []string
+	times   []time.Time
+}
+
+func (in *countingAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, prom_v1.Warnings, error) {
+	in.calls++
+	in.queries = append(in.queries, query)
+	in.times = append(in.times, ts)
+	return model.Vector{&model.Sample{Value: model.SampleValue(in.calls)}}, prom_v1.Warnings{query}, nil
+}
+
+func (in *countingAPI) QueryRange(ctx context.Context, query string, r prom_v1.Range) (model.Value, prom_v1.Warnings, error) {
+	in.calls++
+	in.queries = append(in.queries, query)
+	in.times = append(in.times, r.Start, r.End)
+	return model.Matrix{}, nil, nil
+}
+
+func TestQueryCacheSharesAlignedQueries(t *testing.T) {
+	assert := assert.New(t)
+	backend := &countingAPI{}
+	api := NewQueryCache(10*time.Second, 10).wrap(backend, "http://prometheus:9090")
+
+	// Queries differing by their formatting and by their time, within the same interval, share a result
+	queryTime := time.Now().Truncate(10 * time.Second)
+	value, _, err := api.Query(context.Background(), `sum(rate(istio_requests_total{namespace="a b"}[1m])) by (app)`, queryTime.Add(time.Second))
+	assert.NoError(err)
+	assert.Equal(model.SampleValue(1), value.(model.Vector)[0].Value)
+	value, _, err = api.Query(context.Background(), ` sum (rate(istio_requests_total{ namespace="a b" } [1m]))  by (app)`, queryTime.Add(9*time.Second))
+	assert.NoError(err)
+	assert.Equal(model.SampleValue(1), value.(model.Vector)[0].Value)
+	assert.Equal(1, backend.calls)
+	assert.Equal(queryTime, backend.times[0])
+
+	// The warnings are cached with the result
+	_, warnings, _ := api.Query(context.Background(), `sum(rate(istio_requests_total{namespace="a b"}[1m])) by (app)`, queryTime)
+	assert.Equal(prom_v1.Warnings{`sum(rate(istio_requests_total{namespace="a b"}[1m])) by (app)`}, warnings)
+	assert.Equal(1, backend.calls)
+
+	// Spaces in strings are meaningful
+	_, _, _ = api.Query(context.Background(), `sum(rate(istio_requests_total{namespace="a  b"}[1m])) by (app)`, queryTime)
+	assert.Equal(2, backend.calls)
+	// Next interval
+	_, _, _ = api.Query(context.Background(), `sum(rate(istio_requests_total{namespace="a b"}[1m])) by (app)`, queryTime.Add(10*time.Second))
+	assert.Equal(3, backend.calls)
+
+	// Range queries are aligned on their step
+	start := time.Unix(1600000005, 0)
+	_, _, _ = api.QueryRange(context.Background(), "up", prom_v1.Range{Start: start.Add(3 * time.Second), End: start.Add(time.Hour + 5*time.Second), Step: 15 * time.Second})
+	_, _, _ = api.QueryRange(context.Background(), "up", prom_v1.Range{Start: start.Add(7 * time.Second), End: start.Add(time.Hour + 9*time.Second), Step: 15 * time.Second})
+	assert.Equal(4, backend.calls)
+	assert.Equal(start, backend.times[3])
+}
+
+func TestQueryCacheIsBounded(t *testing.T) {
+	assert := assert.New(t)
+	backend := &countingAPI{}
+	api := NewQueryCache(time.Minute, 2).wrap(backend, "http://prometheus:9090")
+
+	queryTime := time.Now()
+	for _, query := range []string{"a", "b", "a", "c", "b", "a"} {
+		_, _, err := api.Query(context.Background(), query, queryTime)
+		assert.NoError(err)
+	}
+	// "b" is evicted by "c", being the least recently used; "a" is then evicted by "b"
+	assert.Equal([]string{"a", "b", "c", "b", "a"}, backend.queries)
+}
+
+// blockingAPI answers the queries once released, unless their context is done before
+type blockingAPI struct {
+	prom_v1.API
+	started  chan struct{}
+	release  chan struct{}
+	canceled chan struct{}
+}
+
+func (in *blockingAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, prom_v1.Warnings, error) {
+	close(in.started)
+	select {
+	case <-in.release:
+		return model.Vector{&model.Sample{Value: 1}}, nil, nil
+	case <-ctx.Done():
+		close(in.canceled)
+		return nil, nil, ctx.Err()
+	}
+}
+
+// waitForWaiters waits until a number of callers wait for the shared query of a key
+func waitForWaiters(cache *QueryCache, waiters int) {
+	for {
+		cache.lock.Lock()
+		joined := 0
+		for _, call := range cache.inFlight {
+			joined = call.waiters
+		}
+		cache.lock.Unlock()
+		if joined == waiters {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestQueryCacheSharedQueryContext(t *testing.T) {
+	assert := assert.New(t)
+	backend := &blockingAPI{started: make(chan struct{}), release: make(chan struct{}), canceled: make(chan struct{})}
+	cache := NewQueryCache(time.Minute, 10)
+	api := cache.wrap(backend, "http://prometheus:9090")
+
+	// The first caller gives up, the query goes on for the other callers
+	queryTime := time.Now()
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, _, err := api.Query(firstCtx, "up", queryTime)
+		firstErr <- err
+	}()
+	<-backend.started
+	secondValue := make(chan model.Value)
+	go func() {
+		value, _, _ := api.Query(context.Background(), "up", queryTime)
+		secondValue <- value
+	}()
+	waitForWaiters(cache, 2)
+	cancel()
+	assert.Equal(context.Canceled, <-firstErr)
+	close(backend.release)
+	value := <-secondValue
+	assert.NotNil(value)
+	assert.Equal(model.SampleValue(1), value.(model.Vector)[0].Value)
+}
+
+func TestQueryCacheCancelsAbandonedQuery(t *testing.T) {
+	assert := assert.New(t)
+	backend := &blockingAPI{started: make(chan struct{}), release: make(chan struct{}), canceled: make(chan struct{})}
+	cache := NewQueryCache(time.Minute, 10)
+	api := cache.wrap(backend, "http://prometheus:9090")
+
+	// The query is canceled when its only caller gives up
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() {
+		_, _, err := api.Query(ctx, "up", time.Now())
+		errs <- err
+	}()
+	<-backend.started
+	cancel()
+	assert.Equal(context.Canceled, <-errs)
+	<-backend.canceled
+	assert.Empty(cache.inFlight)
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	assert.Equal(t, `sum(rate(m{a="x  y",b=~'\' '}[5m]))by(app)/ 2`, normalizeQuery("  sum ( rate(m{ a=\"x  y\" , b=~'\\' ' } [5m] ) )\n by (app)  /  2 "))
+}
@@ -41,3 +41,16 @@ type Metric struct {
 
 // Histogram contains Metric objects for several histogram-kind statistics
 type Histogram = map[string]Metric
+
+// Exemplar is a single sample attached to a series, typically carrying a trace ID in its labels
+type Exemplar struct {
+	Labels    model.LabelSet    `json:"labels"`
+	Value     model.SampleValue `json:"value"`
+	Timestamp model.Time        `json:"timestamp"`
+}
+
+// ExemplarQueryResult holds the exemplars found for a series
+type ExemplarQueryResult struct {
+	SeriesLabels model.LabelSet `json:"seriesLabels"`
+	Exemplars    []Exemplar     `json:"exemplars"`
+}
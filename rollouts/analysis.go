@@ -0,0 +1,83 @@
+package rollouts
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// analysisResult holds the candidate metrics over the last step. Nil values mean no traffic.
+type analysisResult struct {
+	errorRate *float64
+	p95       *float64
+}
+
+// analyze fetches the candidate error rate and p95 response time over the last interval
+func analyze(prom prometheus.ClientInterface, spec *models.RolloutSpec, now time.Time) (analysisResult, error) {
+	result := analysisResult{}
+	interval := fmt.Sprintf("%ds", spec.Interval)
+
+	rates, err := prom.GetServiceRequestRates(spec.Namespace, spec.Service, interval, now)
+	if err != nil {
+		return result, err
+	}
+	result.errorRate = errorRate(rates, spec.CandidateVersion)
+
+	labels := fmt.Sprintf(`{reporter="destination",destination_service_name="%s",destination_service_namespace="%s",destination_version="%s"}`,
+		spec.Service, spec.Namespace, spec.CandidateVersion)
+	stats, err := prom.FetchHistogramValues("istio_request_duration_milliseconds", labels, "", interval, false, []string{"0.95"}, now)
+	if err != nil {
+		return result, err
+	}
+	for _, sample := range stats["0.95"] {
+		if v := float64(sample.Value); !math.IsNaN(v) {
+			result.p95 = &v
+			break
+		}
+	}
+	return result, nil
+}
+
+// errorRate computes the percentage of 5xx responses of the given version, or nil without traffic
+func errorRate(rates model.Vector, version string) *float64 {
+	total, errors := 0.0, 0.0
+	for _, sample := range rates {
+		if string(sample.Metric["destination_version"]) != version {
+			continue
+		}
+		v := float64(sample.Value)
+		if math.IsNaN(v) {
+			continue
+		}
+		total += v
+		if strings.HasPrefix(string(sample.Metric["response_code"]), "5") {
+			errors += v
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	rate := 100 * errors / total
+	return &rate
+}
+
+// failure returns the reason why the criteria are not met, or an empty string if they are.
+// A candidate without traffic cannot be validated and is considered as failing.
+func (in analysisResult) failure(criteria *models.RolloutCriteria) string {
+	if in.errorRate == nil {
+		return "no traffic received by the candidate"
+	}
+	if criteria.MaxErrorRate > 0 && *in.errorRate > criteria.MaxErrorRate {
+		return fmt.Sprintf("error rate %.2f%% exceeds %.2f%%", *in.errorRate, criteria.MaxErrorRate)
+	}
+	if criteria.MaxP95 > 0 && in.p95 != nil && *in.p95 > criteria.MaxP95 {
+		return fmt.Sprintf("p95 response time %.0fms exceeds %.0fms", *in.p95, criteria.MaxP95)
+	}
+	return ""
+}
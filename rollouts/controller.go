@@ -0,0 +1,412 @@
+// rollouts is a lightweight progressive delivery controller: it shifts traffic of a service from a baseline
+// to a candidate subset step by step, by patching VirtualService weights, and rolls back when the candidate
+// doesn't meet its success criteria.
+package rollouts
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const (
+	// Period of the reconciliation loop
+	reconcileInterval = 10 * time.Second
+	// Label of the VirtualServices driven by a rollout, set to the rollout name
+	rolloutLabel = "kiali.io/rollout"
+	// Annotation holding the state of the rollout on its VirtualService, so that rollouts survive restarts
+	rolloutAnnotation = "kiali.io/rollout-state"
+)
+
+// ClientsLoader provides the clients used by the controller to patch VirtualServices and analyze metrics
+type ClientsLoader func() (kubernetes.ClientInterface, prometheus.ClientInterface, error)
+
+// Controller holds the rollouts and drives their progression
+type Controller struct {
+	// Guards the rollouts map and the rollout states. It is never held during Kubernetes or Prometheus calls.
+	lock     sync.Mutex
+	loader   ClientsLoader
+	rollouts map[string]*rolloutEntry
+	stop     chan struct{}
+}
+
+// rolloutEntry holds the state of a rollout. The changes of a rollout are serialized by the entry lock, held while
+// the VirtualService is patched, and the new state replaces the previous one once the patch succeeded.
+type rolloutEntry struct {
+	changes sync.Mutex
+	rollout *models.Rollout
+	removed bool
+}
+
+var controller *Controller
+
+// NewController creates a controller using the given clients
+func NewController(loader ClientsLoader) *Controller {
+	return &Controller{
+		loader:   loader,
+		rollouts: make(map[string]*rolloutEntry),
+	}
+}
+
+// Start runs the background reconciliation loop, when enabled in the configuration. Rollouts are applied with the
+// Kiali service account; the rollouts stored on the VirtualServices are restored first.
+func Start() {
+	if !config.Get().Rollouts.Enabled {
+		return
+	}
+	controller = NewController(kialiClients)
+	controller.stop = make(chan struct{})
+	go func(c *Controller) {
+		c.restore()
+		c.run()
+	}(controller)
+}
+
+// Stop ends the background reconciliation loop
+func Stop() {
+	if controller != nil && controller.stop != nil {
+		close(controller.stop)
+		controller = nil
+	}
+}
+
+// Get returns the running controller, or nil when not started
+func Get() *Controller {
+	return controller
+}
+
+func kialiClients() (kubernetes.ClientInterface, prometheus.ClientInterface, error) {
+	clientFactory, err := kubernetes.GetClientFactory()
+	if err != nil {
+		return nil, nil, err
+	}
+	kialiToken, err := kubernetes.GetKialiToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	k8s, err := clientFactory.GetClient(&api.AuthInfo{Token: kialiToken})
+	if err != nil {
+		return nil, nil, err
+	}
+	prom, err := prometheus.NewClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	return k8s, prom, nil
+}
+
+func (in *Controller) run() {
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-in.stop:
+			return
+		case <-ticker.C:
+			in.reconcile(time.Now())
+		}
+	}
+}
+
+func rolloutKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// restore loads the rollouts stored on the VirtualServices, so that the rollouts in progress go on after a restart
+func (in *Controller) restore() {
+	k8s, _, err := in.loader()
+	if err != nil {
+		log.Errorf("Rollouts: cannot initialize clients: %v", err)
+		return
+	}
+	virtualServices, err := k8s.GetIstioObjects("", kubernetes.VirtualServices, rolloutLabel)
+	if err != nil {
+		log.Errorf("Rollouts: cannot list the VirtualServices of rollouts: %v", err)
+		return
+	}
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	for _, vs := range virtualServices {
+		meta := vs.GetObjectMeta()
+		state, ok := meta.Annotations[rolloutAnnotation]
+		if !ok {
+			continue
+		}
+		var r models.Rollout
+		if err := json.Unmarshal([]byte(state), &r); err != nil || r.Spec.Namespace != meta.Namespace || r.Spec.VirtualService != meta.Name {
+			log.Warningf("Rollouts: ignoring invalid rollout state of VirtualService %s", rolloutKey(meta.Namespace, meta.Name))
+			continue
+		}
+		key := rolloutKey(r.Spec.Namespace, r.Spec.Name)
+		if _, ok := in.rollouts[key]; !ok {
+			in.rollouts[key] = &rolloutEntry{rollout: &r}
+			log.Infof("Rollouts: restored rollout %s, %s at step %d", key, r.Phase, r.CurrentStep+1)
+		}
+	}
+}
+
+// Create validates a rollout, applies its first step and registers it
+func (in *Controller) Create(spec models.RolloutSpec, createdBy string) (models.Rollout, error) {
+	if err := spec.Validate(); err != nil {
+		return models.Rollout{}, err
+	}
+	if spec.CandidateVersion == "" {
+		spec.CandidateVersion = spec.Candidate
+	}
+	k8s, _, err := in.loader()
+	if err != nil {
+		return models.Rollout{}, err
+	}
+
+	now := time.Now()
+	rollout := &models.Rollout{
+		Spec:            spec,
+		Phase:           models.RolloutProgressing,
+		CandidateWeight: spec.Steps[0],
+		CreatedBy:       createdBy,
+		CreatedAt:       now,
+		LastStepAt:      now,
+		History:         []models.RolloutEvent{},
+	}
+	record(rollout, now, nil, nil, "Rollout started")
+
+	// Register the rollout before patching, so that concurrent rollouts of the VirtualService are rejected
+	key := rolloutKey(spec.Namespace, spec.Name)
+	in.lock.Lock()
+	previous, exists := in.rollouts[key]
+	if exists && !previous.rollout.IsFinished() {
+		in.lock.Unlock()
+		return models.Rollout{}, fmt.Errorf("rollout %s is already in progress", key)
+	}
+	for _, e := range in.rollouts {
+		if r := e.rollout; !r.IsFinished() && r.Spec.Namespace == spec.Namespace && r.Spec.VirtualService == spec.VirtualService {
+			in.lock.Unlock()
+			return models.Rollout{}, fmt.Errorf("VirtualService %s is already managed by rollout %s", spec.VirtualService, r.Spec.Name)
+		}
+	}
+	entry := &rolloutEntry{rollout: rollout}
+	entry.changes.Lock()
+	defer entry.changes.Unlock()
+	in.rollouts[key] = entry
+	in.lock.Unlock()
+
+	if err := persist(k8s, rollout, true); err != nil {
+		in.lock.Lock()
+		entry.removed = true
+		if exists {
+			in.rollouts[key] = previous
+		} else {
+			delete(in.rollouts, key)
+		}
+		in.lock.Unlock()
+		return models.Rollout{}, err
+	}
+	return copyRollout(rollout), nil
+}
+
+// Get returns a rollout by namespace and name
+func (in *Controller) Get(namespace, name string) (models.Rollout, bool) {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	e, ok := in.rollouts[rolloutKey(namespace, name)]
+	if !ok {
+		return models.Rollout{}, false
+	}
+	return copyRollout(e.rollout), true
+}
+
+// List returns the rollouts of the given namespaces, sorted by namespace and name
+func (in *Controller) List(namespaces map[string]bool) []models.Rollout {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	list := []models.Rollout{}
+	for _, e := range in.rollouts {
+		if namespaces[e.rollout.Spec.Namespace] {
+			list = append(list, copyRollout(e.rollout))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return rolloutKey(list[i].Spec.Namespace, list[i].Spec.Name) < rolloutKey(list[j].Spec.Namespace, list[j].Spec.Name)
+	})
+	return list
+}
+
+// Pause suspends the progression of a rollout, keeping the current weights
+func (in *Controller) Pause(namespace, name string) (models.Rollout, error) {
+	return in.transition(namespace, name, func(r *models.Rollout, now time.Time) (bool, error) {
+		if r.Phase != models.RolloutProgressing {
+			return false, fmt.Errorf("rollout is %s, it can't be paused", r.Phase)
+		}
+		r.Phase = models.RolloutPaused
+		record(r, now, nil, nil, "Rollout paused")
+		return false, nil
+	})
+}
+
+// Resume restarts the progression of a paused rollout. The current step is analyzed after a full interval.
+func (in *Controller) Resume(namespace, name string) (models.Rollout, error) {
+	return in.transition(namespace, name, func(r *models.Rollout, now time.Time) (bool, error) {
+		if r.Phase != models.RolloutPaused {
+			return false, fmt.Errorf("rollout is %s, it can't be resumed", r.Phase)
+		}
+		r.Phase = models.RolloutProgressing
+		r.LastStepAt = now
+		record(r, now, nil, nil, "Rollout resumed")
+		return false, nil
+	})
+}
+
+// Abort stops a rollout and sends all traffic back to the baseline
+func (in *Controller) Abort(namespace, name string) (models.Rollout, error) {
+	return in.transition(namespace, name, func(r *models.Rollout, now time.Time) (bool, error) {
+		if r.IsFinished() {
+			return false, fmt.Errorf("rollout is already %s", r.Phase)
+		}
+		r.Phase = models.RolloutAborted
+		r.CandidateWeight = 0
+		record(r, now, nil, nil, "Rollout aborted, traffic sent back to baseline")
+		return true, nil
+	})
+}
+
+// transition applies a change to a copy of a rollout, then stores it on the VirtualService, with the weights when
+// the change tells so, and finally replaces the rollout state
+func (in *Controller) transition(namespace, name string, change func(*models.Rollout, time.Time) (bool, error)) (models.Rollout, error) {
+	k8s, _, err := in.loader()
+	if err != nil {
+		return models.Rollout{}, err
+	}
+	in.lock.Lock()
+	entry, ok := in.rollouts[rolloutKey(namespace, name)]
+	in.lock.Unlock()
+	if !ok {
+		return models.Rollout{}, fmt.Errorf("rollout %s not found", rolloutKey(namespace, name))
+	}
+
+	entry.changes.Lock()
+	defer entry.changes.Unlock()
+	r, ok := in.snapshot(entry)
+	if !ok {
+		return models.Rollout{}, fmt.Errorf("rollout %s not found", rolloutKey(namespace, name))
+	}
+	weights, err := change(&r, time.Now())
+	if err != nil {
+		return models.Rollout{}, err
+	}
+	if err := persist(k8s, &r, weights); err != nil {
+		return models.Rollout{}, err
+	}
+	in.commit(entry, &r)
+	return copyRollout(&r), nil
+}
+
+// snapshot returns a copy of the state of a rollout, unless the rollout was removed
+func (in *Controller) snapshot(entry *rolloutEntry) (models.Rollout, bool) {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	if entry.removed {
+		return models.Rollout{}, false
+	}
+	return copyRollout(entry.rollout), true
+}
+
+// commit replaces the state of a rollout
+func (in *Controller) commit(entry *rolloutEntry, r *models.Rollout) {
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	entry.rollout = r
+}
+
+// reconcile analyzes the rollouts whose current step is over, then promotes or rolls them back
+func (in *Controller) reconcile(now time.Time) {
+	in.lock.Lock()
+	due := []*rolloutEntry{}
+	for _, e := range in.rollouts {
+		if isDue(e.rollout, now) {
+			due = append(due, e)
+		}
+	}
+	in.lock.Unlock()
+	if len(due) == 0 {
+		return
+	}
+
+	k8s, prom, err := in.loader()
+	if err != nil {
+		log.Errorf("Rollouts: cannot initialize clients: %v", err)
+		return
+	}
+	for _, entry := range due {
+		in.progress(k8s, prom, entry, now)
+	}
+}
+
+func isDue(r *models.Rollout, now time.Time) bool {
+	return r.Phase == models.RolloutProgressing && now.Sub(r.LastStepAt) >= time.Duration(r.Spec.Interval)*time.Second
+}
+
+func (in *Controller) progress(k8s kubernetes.ClientInterface, prom prometheus.ClientInterface, entry *rolloutEntry, now time.Time) {
+	entry.changes.Lock()
+	defer entry.changes.Unlock()
+	// The rollout may have been changed since it was found due
+	r, ok := in.snapshot(entry)
+	if !ok || !isDue(&r, now) {
+		return
+	}
+	key := rolloutKey(r.Spec.Namespace, r.Spec.Name)
+
+	result, err := analyze(prom, &r.Spec, now)
+	if err != nil {
+		// Analysis is retried at the next reconciliation
+		log.Warningf("Rollouts: cannot analyze rollout %s: %v", key, err)
+		return
+	}
+	weights := true
+	if failure := result.failure(&r.Spec.Criteria); failure != "" {
+		r.Phase = models.RolloutRolledBack
+		r.CandidateWeight = 0
+		record(&r, now, result.errorRate, result.p95, "Rolled back: "+failure)
+	} else if r.CurrentStep == len(r.Spec.Steps)-1 {
+		r.Phase = models.RolloutSucceeded
+		record(&r, now, result.errorRate, result.p95, "Rollout succeeded")
+		weights = false
+	} else {
+		r.CurrentStep++
+		r.CandidateWeight = r.Spec.Steps[r.CurrentStep]
+		r.LastStepAt = now
+		record(&r, now, result.errorRate, result.p95, fmt.Sprintf("Promoted to step %d", r.CurrentStep+1))
+	}
+	if err := persist(k8s, &r, weights); err != nil {
+		log.Errorf("Rollouts: cannot apply the %s phase of rollout %s: %v", r.Phase, key, err)
+		return
+	}
+	in.commit(entry, &r)
+}
+
+func record(r *models.Rollout, now time.Time, errorRate, p95 *float64, message string) {
+	r.History = append(r.History, models.RolloutEvent{
+		Timestamp:       now,
+		Step:            r.CurrentStep,
+		CandidateWeight: r.CandidateWeight,
+		ErrorRate:       errorRate,
+		P95:             p95,
+		Message:         message,
+	})
+}
+
+func copyRollout(r *models.Rollout) models.Rollout {
+	c := *r
+	c.Spec.Steps = append([]int{}, r.Spec.Steps...)
+	c.History = append([]models.RolloutEvent{}, r.History...)
+	return c
+}
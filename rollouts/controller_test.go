@@ -0,0 +1,231 @@
+package rollouts
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func fakeReviewsVirtualService() kubernetes.IstioObject {
+	return &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec: map[string]interface{}{
+			"hosts": []interface{}{"reviews"},
+			"http": []interface{}{
+				map[string]interface{}{
+					"match": []interface{}{map[string]interface{}{"uri": map[string]interface{}{"prefix": "/admin"}}},
+					"route": []interface{}{
+						map[string]interface{}{"destination": map[string]interface{}{"host": "admin"}},
+					},
+				},
+				map[string]interface{}{
+					"route": []interface{}{
+						map[string]interface{}{"destination": map[string]interface{}{"host": "reviews.bookinfo.svc.cluster.local", "subset": "v1", "port": map[string]interface{}{"number": 9080}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func fakeRolloutSpec() models.RolloutSpec {
+	return models.RolloutSpec{
+		Name:           "reviews-v2",
+		Namespace:      "bookinfo",
+		Service:        "reviews",
+		VirtualService: "reviews",
+		Baseline:       "v1",
+		Candidate:      "v2",
+		Steps:          []int{20, 100},
+		Interval:       60,
+		Criteria:       models.RolloutCriteria{MaxErrorRate: 5, MaxP95: 200},
+	}
+}
+
+func fakeRates(total, errors float64) model.Vector {
+	return model.Vector{
+		&model.Sample{Metric: model.Metric{"destination_version": "v2", "response_code": "200"}, Value: model.SampleValue(total - errors)},
+		&model.Sample{Metric: model.Metric{"destination_version": "v2", "response_code": "503"}, Value: model.SampleValue(errors)},
+		&model.Sample{Metric: model.Metric{"destination_version": "v1", "response_code": "503"}, Value: 100},
+	}
+}
+
+func setupController(rates model.Vector, p95 float64) (*Controller, *kubetest.K8SClientMock, *[]string) {
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(fakeReviewsVirtualService(), nil)
+	patches := []string{}
+	k8s.On("UpdateIstioObject", "networking.istio.io", "bookinfo", kubernetes.VirtualServices, "reviews", mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { patches = append(patches, args.String(4)) }).
+		Return(fakeReviewsVirtualService(), nil)
+
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetServiceRequestRates", "bookinfo", "reviews", "60s", mock.AnythingOfType("time.Time")).Return(rates, nil)
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", mock.AnythingOfType("string"), "", "60s", false, []string{"0.95"}, mock.AnythingOfType("time.Time")).
+		Return(map[string]model.Vector{"0.95": {&model.Sample{Value: model.SampleValue(p95)}}}, nil)
+
+	c := NewController(func() (kubernetes.ClientInterface, prometheus.ClientInterface, error) {
+		return k8s, prom, nil
+	})
+	return c, k8s, &patches
+}
+
+func parsePatch(t *testing.T, patch string) map[string]map[string]interface{} {
+	var p map[string]map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(patch), &p))
+	return p
+}
+
+func candidateWeight(t *testing.T, patch string) float64 {
+	p := parsePatch(t, patch)
+	routes := p["spec"]["http"].([]interface{})[1].(map[string]interface{})["route"].([]interface{})
+	return routes[1].(map[string]interface{})["weight"].(float64)
+}
+
+func TestRolloutPatch(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	r := models.Rollout{Spec: fakeRolloutSpec(), Phase: models.RolloutProgressing, CandidateWeight: 20}
+
+	patch, err := rolloutPatch(fakeReviewsVirtualService(), &r, true)
+	assert.NoError(err)
+	p := parsePatch(t, patch)
+	assert.Equal(map[string]interface{}{"kiali.io/rollout": "reviews-v2"}, p["metadata"]["labels"])
+	var state models.Rollout
+	assert.NoError(json.Unmarshal([]byte(p["metadata"]["annotations"].(map[string]interface{})["kiali.io/rollout-state"].(string)), &state))
+	assert.Equal(r.Spec, state.Spec)
+	assert.Equal(20, state.CandidateWeight)
+	spec, _ := json.Marshal(p["spec"])
+	assert.JSONEq(`{"http": [
+		{"match": [{"uri": {"prefix": "/admin"}}], "route": [{"destination": {"host": "admin"}}]},
+		{"route": [
+			{"destination": {"host": "reviews.bookinfo.svc.cluster.local", "subset": "v1", "port": {"number": 9080}}, "weight": 80},
+			{"destination": {"host": "reviews.bookinfo.svc.cluster.local", "subset": "v2", "port": {"number": 9080}}, "weight": 20}
+		]}
+	]}`, string(spec))
+
+	// State only
+	patch, err = rolloutPatch(fakeReviewsVirtualService(), &r, false)
+	assert.NoError(err)
+	assert.NotContains(parsePatch(t, patch), "spec")
+
+	r.Spec.Service = "ratings"
+	_, err = rolloutPatch(fakeReviewsVirtualService(), &r, true)
+	assert.Error(err)
+}
+
+func TestRolloutSucceeds(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	c, _, patches := setupController(fakeRates(100, 1), 150)
+
+	r, err := c.Create(fakeRolloutSpec(), "jdoe")
+	assert.NoError(err)
+	assert.Equal(models.RolloutProgressing, r.Phase)
+	assert.Equal(20, r.CandidateWeight)
+	assert.Equal(float64(20), candidateWeight(t, (*patches)[0]))
+
+	// Another rollout can't manage the same VirtualService
+	other := fakeRolloutSpec()
+	other.Name = "other"
+	_, err = c.Create(other, "jdoe")
+	assert.Error(err)
+
+	// Step not over yet
+	c.reconcile(r.LastStepAt.Add(30 * time.Second))
+	assert.Len(*patches, 1)
+
+	c.reconcile(r.LastStepAt.Add(61 * time.Second))
+	r, _ = c.Get("bookinfo", "reviews-v2")
+	assert.Equal(1, r.CurrentStep)
+	assert.Equal(100, r.CandidateWeight)
+	assert.Equal(float64(100), candidateWeight(t, (*patches)[1]))
+	assert.InDelta(1.0, *r.History[1].ErrorRate, 0.001)
+
+	c.reconcile(r.LastStepAt.Add(61 * time.Second))
+	r, _ = c.Get("bookinfo", "reviews-v2")
+	assert.Equal(models.RolloutSucceeded, r.Phase)
+	assert.Len(*patches, 3)
+	assert.NotContains(parsePatch(t, (*patches)[2]), "spec")
+	assert.Len(r.History, 3)
+}
+
+func TestRolloutRollsBack(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	c, _, patches := setupController(fakeRates(100, 10), 150)
+
+	r, err := c.Create(fakeRolloutSpec(), "jdoe")
+	assert.NoError(err)
+	c.reconcile(r.LastStepAt.Add(61 * time.Second))
+	r, _ = c.Get("bookinfo", "reviews-v2")
+	assert.Equal(models.RolloutRolledBack, r.Phase)
+	assert.Equal(0, r.CandidateWeight)
+	assert.Equal(float64(0), candidateWeight(t, (*patches)[1]))
+	assert.Contains(r.History[1].Message, "error rate 10.00% exceeds 5.00%")
+}
+
+func TestRolloutPauseAndAbort(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	c, _, patches := setupController(fakeRates(100, 0), 150)
+
+	r, err := c.Create(fakeRolloutSpec(), "jdoe")
+	assert.NoError(err)
+	_, err = c.Pause("bookinfo", "reviews-v2")
+	assert.NoError(err)
+
+	// Paused rollouts don't progress
+	c.reconcile(r.LastStepAt.Add(61 * time.Second))
+	r, _ = c.Get("bookinfo", "reviews-v2")
+	assert.Equal(models.RolloutPaused, r.Phase)
+	assert.Equal(0, r.CurrentStep)
+
+	r, err = c.Abort("bookinfo", "reviews-v2")
+	assert.NoError(err)
+	assert.Equal(models.RolloutAborted, r.Phase)
+	assert.Len(*patches, 3)
+	assert.Equal(float64(0), candidateWeight(t, (*patches)[2]))
+
+	_, err = c.Resume("bookinfo", "reviews-v2")
+	assert.Error(err)
+}
+
+func TestRolloutRestore(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	c, k8s, patches := setupController(fakeRates(100, 0), 150)
+	r, err := c.Create(fakeRolloutSpec(), "jdoe")
+	assert.NoError(err)
+
+	// A new controller finds the rollout on its VirtualService
+	vs := fakeReviewsVirtualService().(*kubernetes.GenericIstioObject)
+	vs.ObjectMeta.Annotations = map[string]string{
+		"kiali.io/rollout-state": parsePatch(t, (*patches)[0])["metadata"]["annotations"].(map[string]interface{})["kiali.io/rollout-state"].(string),
+	}
+	k8s.On("GetIstioObjects", "", kubernetes.VirtualServices, "kiali.io/rollout").Return([]kubernetes.IstioObject{vs}, nil)
+	restarted := NewController(c.loader)
+	restarted.restore()
+
+	restored, ok := restarted.Get("bookinfo", "reviews-v2")
+	assert.True(ok)
+	assert.Equal(models.RolloutProgressing, restored.Phase)
+	assert.Equal(20, restored.CandidateWeight)
+	assert.Equal("jdoe", restored.CreatedBy)
+
+	restarted.reconcile(r.LastStepAt.Add(61 * time.Second))
+	restored, _ = restarted.Get("bookinfo", "reviews-v2")
+	assert.Equal(1, restored.CurrentStep)
+	assert.Equal(float64(100), candidateWeight(t, (*patches)[1]))
+}
@@ -0,0 +1,123 @@
+package rollouts
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// persist patches the rollout VirtualService with the rollout state and, when asked, with routes sending the
+// candidate weight of the traffic to the candidate subset, and the rest to the baseline subset
+func persist(k8s kubernetes.ClientInterface, r *models.Rollout, weights bool) error {
+	vs, err := k8s.GetIstioObject(r.Spec.Namespace, kubernetes.VirtualServices, r.Spec.VirtualService)
+	if err != nil {
+		return err
+	}
+	patch, err := rolloutPatch(vs, r, weights)
+	if err != nil {
+		return err
+	}
+	_, err = k8s.UpdateIstioObject(kubernetes.NetworkingGroupVersion.Group, r.Spec.Namespace, kubernetes.VirtualServices, r.Spec.VirtualService, patch)
+	return err
+}
+
+// rolloutPatch builds a merge patch of the VirtualService labelling it with the rollout, and storing the rollout
+// state in an annotation. With weights, the http routes are patched too.
+func rolloutPatch(vs kubernetes.IstioObject, r *models.Rollout, weights bool) (string, error) {
+	state, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      map[string]interface{}{rolloutLabel: r.Spec.Name},
+			"annotations": map[string]interface{}{rolloutAnnotation: string(state)},
+		},
+	}
+	if weights {
+		httpRoutes, err := weightedRoutes(vs, &r.Spec, r.CandidateWeight)
+		if err != nil {
+			return "", err
+		}
+		patch["spec"] = map[string]interface{}{
+			"http": httpRoutes,
+		}
+	}
+	bytes, err := json.Marshal(patch)
+	return string(bytes), err
+}
+
+// weightedRoutes returns the VirtualService http routes. Routes whose destinations target the rollout service are
+// replaced by a weighted baseline and candidate pair; other routes are kept as they are.
+func weightedRoutes(vs kubernetes.IstioObject, spec *models.RolloutSpec, weight int) ([]interface{}, error) {
+	httpRoutes, ok := vs.GetSpec()["http"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("VirtualService %s has no http routes", spec.VirtualService)
+	}
+	patched := make([]interface{}, 0, len(httpRoutes))
+	found := false
+	for _, h := range httpRoutes {
+		httpRoute, ok := h.(map[string]interface{})
+		if !ok {
+			patched = append(patched, h)
+			continue
+		}
+		destination := serviceDestination(httpRoute, spec)
+		if destination == nil {
+			patched = append(patched, httpRoute)
+			continue
+		}
+		found = true
+		newRoute := make(map[string]interface{}, len(httpRoute))
+		for k, v := range httpRoute {
+			newRoute[k] = v
+		}
+		newRoute["route"] = []interface{}{
+			weightedDestination(destination, spec.Baseline, 100-weight),
+			weightedDestination(destination, spec.Candidate, weight),
+		}
+		patched = append(patched, newRoute)
+	}
+	if !found {
+		return nil, fmt.Errorf("VirtualService %s has no http route to service %s", spec.VirtualService, spec.Service)
+	}
+	return patched, nil
+}
+
+// serviceDestination returns the destination of the first route targeting the rollout service, if any
+func serviceDestination(httpRoute map[string]interface{}, spec *models.RolloutSpec) map[string]interface{} {
+	routes, ok := httpRoute["route"].([]interface{})
+	if !ok {
+		return nil
+	}
+	for _, r := range routes {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		destination, ok := route["destination"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if host, ok := destination["host"].(string); ok && kubernetes.FilterByHost(host, spec.Service, spec.Namespace) {
+			return destination
+		}
+	}
+	return nil
+}
+
+func weightedDestination(destination map[string]interface{}, subset string, weight int) map[string]interface{} {
+	d := map[string]interface{}{
+		"host":   destination["host"],
+		"subset": subset,
+	}
+	if port, ok := destination["port"]; ok {
+		d["port"] = port
+	}
+	return map[string]interface{}{
+		"destination": d,
+		"weight":      weight,
+	}
+}
@@ -309,6 +309,34 @@ func NewRoutes() (r *Routes) {
 			handlers.IstioConfigUpdate,
 			true,
 		},
+		// swagger:route POST /namespaces/{namespace}/istio/{object_type}/{object}/impact config istioConfigImpact
+		// ---
+		// Analyze the impact of deleting or patching an Istio object before applying it: the services and workloads
+		// whose routing, security or sidecar scope would change, with their current traffic rates, and the validation
+		// checks that would appear on other objects.
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: istioConfigImpactResponse
+		//
+		{
+			"IstioConfigImpact",
+			"POST",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/impact",
+			handlers.IstioConfigImpact,
+			true,
+		},
 		// swagger:route POST /namespaces/{namespace}/istio/{object_type} config istioConfigCreate
 		// ---
 		// Endpoint to create an Istio object by using an Istio Config item
@@ -398,6 +426,35 @@ func NewRoutes() (r *Routes) {
 			handlers.ServiceUpdate,
 			true,
 		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/traffic_templates services serviceTrafficTemplate
+		// ---
+		// Endpoint to generate the VirtualService, DestinationRule and Gateway implementing a traffic intent for a service
+		// (weighted, header-based or TCP routing, fault injection, timeouts and retries, circuit breaking, gateway exposure).
+		// The generated objects are validated against the namespace configuration. With apply=true, they are created
+		// or updated when they have no validation errors, otherwise they are returned with a 422 status.
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      422: trafficConfigResponse
+		//      500: internalError
+		//      200: trafficConfigResponse
+		//
+		{
+			"ServiceTrafficTemplate",
+			"POST",
+			"/api/namespaces/{namespace}/services/{service}/traffic_templates",
+			handlers.ServiceTrafficTemplate,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/apps/{app}/spans traces appSpans
 		// ---
 		// Endpoint to get Jaeger spans for a given app
@@ -476,6 +533,27 @@ func NewRoutes() (r *Routes) {
 			handlers.AppTraces,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/traces/stats traces appTracesStats
+		// ---
+		// Endpoint to get span statistics per operation and a latency heatmap of a given app, computed from its traces
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      200: tracesStatsResponse
+		//
+		{
+			"AppTracesStats",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/traces/stats",
+			handlers.AppTracesStats,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/services/{service}/traces traces serviceTraces
 		// ---
 		// Endpoint to get the traces of a given service
@@ -560,6 +638,49 @@ func NewRoutes() (r *Routes) {
 			handlers.TraceDetails,
 			true,
 		},
+		// swagger:route GET /traces/{traceID}/analysis traces traceAnalysis
+		// ---
+		// Endpoint to get the critical path and the per-service self time of a trace
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: traceAnalysisResponse
+		//
+		{
+			"TraceAnalysis",
+			"GET",
+			"/api/traces/{traceID}/analysis",
+			handlers.TraceAnalysis,
+			true,
+		},
+		// swagger:route GET /traces/{traceID}/compare traces traceComparison
+		// ---
+		// Endpoint to compare a trace with a baseline trace, or with the median of similar traces of an app
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: traceComparisonResponse
+		//
+		{
+			"TraceComparison",
+			"GET",
+			"/api/traces/{traceID}/compare",
+			handlers.TraceComparison,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/workloads workloads workloadList
 		// ---
 		// Endpoint to get the list of workloads for a namespace
@@ -880,6 +1001,27 @@ func NewRoutes() (r *Routes) {
 			handlers.CustomDashboard,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/customdashboards/errors dashboards customDashboardsErrors
+		// ---
+		// Endpoint to fetch the validation errors of the custom dashboards discovered at runtime in a namespace
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: dashboardValidationErrorsResponse
+		//
+		{
+			"CustomDashboardsErrors",
+			"GET",
+			"/api/namespaces/{namespace}/customdashboards/errors",
+			handlers.CustomDashboardsErrors,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/services/{service}/health services serviceHealth
 		// ---
 		// Get health associated to the given service
@@ -964,6 +1106,30 @@ func NewRoutes() (r *Routes) {
 			handlers.NamespaceMetrics,
 			true,
 		},
+		// swagger:route GET /metrics/query metrics metricsQuery
+		// ---
+		// Endpoint to run a PromQL query restricted to the namespaces accessible to the user
+		//
+		//     Produces:
+		//     - application/json
+		//     - text/csv
+		//     - application/openmetrics-text
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: metricsResponse
+		//
+		{
+			"MetricsQuery",
+			"GET",
+			"/api/metrics/query",
+			handlers.MetricsQuery,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/health namespaces namespaceHealth
 		// ---
 		// Get health for all objects in the given namespace
@@ -1048,6 +1214,191 @@ func NewRoutes() (r *Routes) {
 			handlers.NamespaceTls,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/tls/migration tls namespaceMtlsMigration
+		// ---
+		// Get the migration report of a namespace to STRICT mTLS: the PeerAuthentication mode of each workload, its
+		// mTLS and plain text inbound traffic over the rate interval, the clients still sending plain text, and the
+		// PeerAuthentications of the next step
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: mtlsMigrationResponse
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//
+		{
+			"NamespaceMtlsMigration",
+			"GET",
+			"/api/namespaces/{namespace}/tls/migration",
+			handlers.NamespaceMtlsMigration,
+			true,
+		},
+		// swagger:route GET /mesh/certificates tls meshCertificates
+		// ---
+		// Get the certificates loaded by the proxies of the accessible namespaces, and those expiring soon or with an unexpected trust domain
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: certificatesReportResponse
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"MeshCertificates",
+			"GET",
+			"/api/mesh/certificates",
+			handlers.MeshCertificates,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/certificates tls namespaceCertificates
+		// ---
+		// Get the certificates loaded by the proxies of a namespace, and those expiring soon or with an unexpected trust domain
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: certificatesReportResponse
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"NamespaceCertificates",
+			"GET",
+			"/api/namespaces/{namespace}/certificates",
+			handlers.NamespaceCertificates,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/certificates tls workloadCertificates
+		// ---
+		// Get the certificates loaded by the proxies of a workload: SPIFFE identity, issuer and validity window
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: certificatesReportResponse
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"WorkloadCertificates",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/certificates",
+			handlers.WorkloadCertificates,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/unusedconfig config namespaceUnusedIstioConfig
+		// ---
+		// Find the Istio config of a namespace that had no effect over the rate interval: VirtualServices and
+		// ServiceEntries whose hosts received no traffic, DestinationRule subsets never reached, Gateways bound by
+		// no VirtualService and AuthorizationPolicies matching no workload
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: unusedIstioConfigResponse
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//
+		{
+			"NamespaceUnusedIstioConfig",
+			"GET",
+			"/api/namespaces/{namespace}/unusedconfig",
+			handlers.UnusedIstioConfig,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/sidecars/recommended config sidecarRecommendations
+		// ---
+		// Recommend, for each workload of a namespace, a Sidecar whose egress permits only the hosts called over the
+		// rate interval, with the estimated reduction of Envoy clusters of its proxies
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: sidecarRecommendationsResponse
+		//      400: badRequestError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//
+		{
+			"SidecarRecommendations",
+			"GET",
+			"/api/namespaces/{namespace}/sidecars/recommended",
+			handlers.SidecarRecommendations,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/authorizationpolicies/generated authorization authorizationPoliciesGenerate
+		// ---
+		// Generate the least-privilege AuthorizationPolicies of a namespace: one ALLOW policy per workload, permitting
+		// exactly the peer identities and the classified operations observed over the rate interval, as objects and as
+		// YAML for review.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: generatedAuthorizationPoliciesResponse
+		//      400: badRequestError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//
+		{
+			"AuthorizationPoliciesGenerate",
+			"GET",
+			"/api/namespaces/{namespace}/authorizationpolicies/generated",
+			handlers.GenerateAuthorizationPolicies,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/authorizationpolicies/generated authorization authorizationPoliciesApply
+		// ---
+		// Generate the least-privilege AuthorizationPolicies of a namespace, and create or update them
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: generatedAuthorizationPoliciesResponse
+		//      400: badRequestError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//
+		{
+			"AuthorizationPoliciesApply",
+			"POST",
+			"/api/namespaces/{namespace}/authorizationpolicies/generated",
+			handlers.ApplyAuthorizationPolicies,
+			true,
+		},
 		// swagger:route GET /istio/status status istioStatus
 		// ---
 		// Get the status of each components needed in the control plane
@@ -1069,6 +1420,74 @@ func NewRoutes() (r *Routes) {
 			handlers.IstioStatus,
 			true,
 		},
+		// swagger:route GET /cache status cacheStatus
+		// ---
+		// Get, per namespace and type, the state of the Kiali cache informers and the objects they hold
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: cacheStatusResponse
+		//      500: internalError
+		//
+		{
+			"CacheStatus",
+			"GET",
+			"/api/cache",
+			handlers.CacheStatus,
+			true,
+		},
+		// swagger:route POST /cache/namespaces/{namespace}/refresh status cacheNamespaceRefresh
+		// ---
+		// Drop the cached objects of a namespace and wait for the Kiali cache to load them again
+		// Requires the permission to patch the Kiali deployment, not available in cluster-wide mode
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200
+		//
+		{
+			"CacheNamespaceRefresh",
+			"POST",
+			"/api/cache/namespaces/{namespace}/refresh",
+			handlers.CacheNamespaceRefresh,
+			true,
+		},
+		// swagger:route DELETE /cache/namespaces/{namespace} status cacheNamespaceEvict
+		// ---
+		// Drop the cached objects of a namespace, they are loaded again on the next read of the namespace
+		// Requires the permission to patch the Kiali deployment, not available in cluster-wide mode
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200
+		//
+		{
+			"CacheNamespaceEvict",
+			"DELETE",
+			"/api/cache/namespaces/{namespace}",
+			handlers.CacheNamespaceEvict,
+			true,
+		},
 		// swagger:route GET /namespaces/graph graphs graphNamespaces
 		// ---
 		// The backing JSON for a namespaces graph.
@@ -1241,6 +1660,30 @@ func NewRoutes() (r *Routes) {
 			handlers.GetGrafanaInfo,
 			true,
 		},
+		// swagger:route POST /grafana/dashboards/import integrations grafanaDashboardImport
+		// ---
+		// Convert a Grafana dashboard JSON into a custom dashboard, reporting the panels that can't be converted
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      200: grafanaDashboardImportResponse
+		//
+		{
+			"GrafanaDashboardImport",
+			"POST",
+			"/api/grafana/dashboards/import",
+			handlers.ImportGrafanaDashboard,
+			true,
+		},
 		// swagger:route GET /jaeger integrations jaegerInfo
 		// ---
 		// Get the jaeger URL and other descriptors
@@ -1540,6 +1983,196 @@ func NewRoutes() (r *Routes) {
 			handlers.GetClusters,
 			true,
 		},
+		// swagger:route POST /authorization/simulation authorization authorizationSimulation
+		// ---
+		// Endpoint to simulate a request from a source to a destination workload. It evaluates the AuthorizationPolicies
+		// and PeerAuthentications applying to the destination, and returns the decision with the policies leading to it.
+		//
+		//              Consumes:
+		//              - application/json
+		//
+		//              Produces:
+		//              - application/json
+		//
+		//              Schemes: http, https
+		//
+		// responses:
+		//              400: badRequestError
+		//              404: notFoundError
+		//              500: internalError
+		//              200: authorizationSimulationResponse
+		{
+			"AuthorizationSimulation",
+			"POST",
+			"/api/authorization/simulation",
+			handlers.AuthorizationSimulation,
+			true,
+		},
+		// swagger:route GET /notifications notifications notificationEvents
+		// ---
+		// Endpoint to get the recent notification events (health transitions and new validation errors) of the accessible namespaces
+		//
+		//              Produces:
+		//              - application/json
+		//
+		//              Schemes: http, https
+		//
+		// responses:
+		//              500: internalError
+		//              503: serviceUnavailableError
+		//              200: notificationEventsResponse
+		{
+			"NotificationEvents",
+			"GET",
+			"/api/notifications",
+			handlers.NotificationEvents,
+			true,
+		},
+		// swagger:route GET /notifications/silences notifications notificationSilences
+		// ---
+		// Endpoint to get the notification silences that are not expired
+		//
+		//              Produces:
+		//              - application/json
+		//
+		//              Schemes: http, https
+		//
+		// responses:
+		//              503: serviceUnavailableError
+		//              200: notificationSilencesResponse
+		{
+			"NotificationSilences",
+			"GET",
+			"/api/notifications/silences",
+			handlers.NotificationSilences,
+			true,
+		},
+		// swagger:route POST /notifications/silences notifications notificationSilenceCreate
+		// ---
+		// Endpoint to silence notifications of a namespace during a period of time
+		//
+		//              Produces:
+		//              - application/json
+		//
+		//              Schemes: http, https
+		//
+		// responses:
+		//              400: badRequestError
+		//              500: internalError
+		//              503: serviceUnavailableError
+		//              200: notificationSilenceResponse
+		{
+			"NotificationSilenceCreate",
+			"POST",
+			"/api/notifications/silences",
+			handlers.NotificationSilenceCreate,
+			true,
+		},
+		// swagger:route DELETE /notifications/silences/{id} notifications notificationSilenceDelete
+		// ---
+		// Endpoint to remove a notification silence
+		//
+		//              Produces:
+		//              - application/json
+		//
+		//              Schemes: http, https
+		//
+		// responses:
+		//              404: notFoundError
+		//              500: internalError
+		//              503: serviceUnavailableError
+		//              200
+		{
+			"NotificationSilenceDelete",
+			"DELETE",
+			"/api/notifications/silences/{id}",
+			handlers.NotificationSilenceDelete,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/rollouts rollouts rollouts
+		// ---
+		// Endpoint to get the rollouts of a namespace
+		//
+		//              Produces:
+		//              - application/json
+		//
+		//              Schemes: http, https
+		//
+		// responses:
+		//              500: internalError
+		//              503: serviceUnavailableError
+		//              200: rolloutsResponse
+		{
+			"RolloutsList",
+			"GET",
+			"/api/namespaces/{namespace}/rollouts",
+			handlers.RolloutsList,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/rollouts rollouts rolloutCreate
+		// ---
+		// Endpoint to start a canary rollout, shifting traffic of a service from a baseline to a candidate subset step by step
+		//
+		//              Produces:
+		//              - application/json
+		//
+		//              Schemes: http, https
+		//
+		// responses:
+		//              400: badRequestError
+		//              500: internalError
+		//              503: serviceUnavailableError
+		//              200: rolloutResponse
+		{
+			"RolloutCreate",
+			"POST",
+			"/api/namespaces/{namespace}/rollouts",
+			handlers.RolloutCreate,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/rollouts/{rollout} rollouts rolloutDetails
+		// ---
+		// Endpoint to get a rollout with its history
+		//
+		//              Produces:
+		//              - application/json
+		//
+		//              Schemes: http, https
+		//
+		// responses:
+		//              404: notFoundError
+		//              500: internalError
+		//              503: serviceUnavailableError
+		//              200: rolloutResponse
+		{
+			"RolloutDetails",
+			"GET",
+			"/api/namespaces/{namespace}/rollouts/{rollout}",
+			handlers.RolloutDetails,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/rollouts/{rollout}/{action} rollouts rolloutAction
+		// ---
+		// Endpoint to pause, resume or abort a rollout. Aborting sends all traffic back to the baseline.
+		//
+		//              Produces:
+		//              - application/json
+		//
+		//              Schemes: http, https
+		//
+		// responses:
+		//              400: badRequestError
+		//              404: notFoundError
+		//              500: internalError
+		//              503: serviceUnavailableError
+		//              200: rolloutResponse
+		{
+			"RolloutAction",
+			"POST",
+			"/api/namespaces/{namespace}/rollouts/{rollout}/{action}",
+			handlers.RolloutAction,
+			true,
+		},
 	}
 
 	return
@@ -12,6 +12,8 @@ import (
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/notifications"
+	"github.com/kiali/kiali/rollouts"
 	"github.com/kiali/kiali/routing"
 )
 
@@ -87,11 +89,19 @@ func (s *Server) Start() {
 	if conf.Server.MetricsEnabled {
 		StartMetricsServer()
 	}
+
+	// Start the background notifications evaluation
+	notifications.Start()
+
+	// Start the rollouts controller
+	rollouts.Start()
 }
 
 // Stop the HTTP server
 func (s *Server) Stop() {
 	StopMetricsServer()
+	notifications.Stop()
+	rollouts.Stop()
 	business.Stop()
 	log.Infof("Server endpoint will stop at [%v]", s.httpServer.Addr)
 	s.httpServer.Close()
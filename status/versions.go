@@ -249,7 +249,14 @@ func jaegerVersion() (*ExternalServiceInfo, error) {
 		return nil, nil
 	}
 	product := ExternalServiceInfo{}
-	product.Name = "Jaeger"
+	switch jaegerConfig.Provider {
+	case config.TracingProviderZipkin:
+		product.Name = "Zipkin"
+	case config.TracingProviderTempo:
+		product.Name = "Tempo"
+	default:
+		product.Name = "Jaeger"
+	}
 	product.Url = jaegerConfig.URL
 
 	return &product, nil